@@ -0,0 +1,71 @@
+// Package apperrors gives domain errors a transport-neutral code, so adapters can map them
+// to a status without knowing each error by name.
+package apperrors
+
+import "errors"
+
+// Code classifies a domain error
+type Code int
+
+const (
+	// CodeInternal is for failures the caller cannot fix; errors without a code map to it
+	CodeInternal Code = iota
+	// CodeInvalidArgument is for input that fails validation
+	CodeInvalidArgument
+	// CodeNotFound is for a missing resource
+	CodeNotFound
+	// CodeAlreadyExists is for creating something that already exists
+	CodeAlreadyExists
+	// CodeUnauthenticated is for missing, invalid or expired credentials
+	CodeUnauthenticated
+	// CodePermissionDenied is for a known caller who may not do this
+	CodePermissionDenied
+	// CodeFailedPrecondition is for a request the resource's current state rules out
+	CodeFailedPrecondition
+	// CodeConflict is for losing a race with a concurrent change; retrying may succeed
+	CodeConflict
+)
+
+var codeNames = map[Code]string{
+	CodeInternal:           "internal",
+	CodeInvalidArgument:    "invalid_argument",
+	CodeNotFound:           "not_found",
+	CodeAlreadyExists:      "already_exists",
+	CodeUnauthenticated:    "unauthenticated",
+	CodePermissionDenied:   "permission_denied",
+	CodeFailedPrecondition: "failed_precondition",
+	CodeConflict:           "conflict",
+}
+
+// String returns the snake_case name of c
+func (c Code) String() string {
+	if name, ok := codeNames[c]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// DomainError is an error with a Code. Declare them as sentinels and wrap them with
+// fmt.Errorf's %w to add detail; errors.Is and CodeOf both see through the wrapping.
+type DomainError struct {
+	Code    Code
+	Message string
+}
+
+// New creates a domain error
+func New(code Code, message string) *DomainError {
+	return &DomainError{Code: code, Message: message}
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+// CodeOf returns the code of the first DomainError in err's chain, or CodeInternal if there is none
+func CodeOf(err error) Code {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return CodeInternal
+}
@@ -0,0 +1,52 @@
+package apperrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/floroz/gavel/pkg/apperrors"
+)
+
+func TestConnectCode(t *testing.T) {
+	tests := []struct {
+		code apperrors.Code
+		want connect.Code
+	}{
+		{code: apperrors.CodeInternal, want: connect.CodeInternal},
+		{code: apperrors.CodeInvalidArgument, want: connect.CodeInvalidArgument},
+		{code: apperrors.CodeNotFound, want: connect.CodeNotFound},
+		{code: apperrors.CodeAlreadyExists, want: connect.CodeAlreadyExists},
+		{code: apperrors.CodeUnauthenticated, want: connect.CodeUnauthenticated},
+		{code: apperrors.CodePermissionDenied, want: connect.CodePermissionDenied},
+		{code: apperrors.CodeFailedPrecondition, want: connect.CodeFailedPrecondition},
+		{code: apperrors.CodeConflict, want: connect.CodeAborted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			err := apperrors.New(tt.code, "something went wrong")
+			assert.Equal(t, tt.want, apperrors.ConnectCode(err))
+
+			// Wrapping keeps the code
+			assert.Equal(t, tt.want, apperrors.ConnectCode(fmt.Errorf("context: %w", err)))
+		})
+	}
+}
+
+func TestConnectCode_PlainErrorsAreInternal(t *testing.T) {
+	assert.Equal(t, connect.CodeInternal, apperrors.ConnectCode(errors.New("boom")))
+	assert.Equal(t, connect.CodeInternal, apperrors.ConnectCode(apperrors.New(apperrors.Code(99), "unknown code")))
+	assert.Equal(t, "unknown", apperrors.Code(99).String())
+}
+
+func TestToConnectError(t *testing.T) {
+	errNotFound := apperrors.New(apperrors.CodeNotFound, "user not found")
+	err := apperrors.ToConnectError(fmt.Errorf("get profile: %w", errNotFound))
+
+	assert.Equal(t, connect.CodeNotFound, err.Code())
+	assert.ErrorIs(t, err, errNotFound)
+	assert.Equal(t, "get profile: user not found", err.Message())
+}
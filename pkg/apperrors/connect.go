@@ -0,0 +1,27 @@
+package apperrors
+
+import "connectrpc.com/connect"
+
+var connectCodes = map[Code]connect.Code{
+	CodeInternal:           connect.CodeInternal,
+	CodeInvalidArgument:    connect.CodeInvalidArgument,
+	CodeNotFound:           connect.CodeNotFound,
+	CodeAlreadyExists:      connect.CodeAlreadyExists,
+	CodeUnauthenticated:    connect.CodeUnauthenticated,
+	CodePermissionDenied:   connect.CodePermissionDenied,
+	CodeFailedPrecondition: connect.CodeFailedPrecondition,
+	CodeConflict:           connect.CodeAborted,
+}
+
+// ConnectCode maps the code of err to a Connect code
+func ConnectCode(err error) connect.Code {
+	if code, ok := connectCodes[CodeOf(err)]; ok {
+		return code
+	}
+	return connect.CodeInternal
+}
+
+// ToConnectError wraps err in a Connect error with the code ConnectCode maps it to
+func ToConnectError(err error) *connect.Error {
+	return connect.NewError(ConnectCode(err), err)
+}
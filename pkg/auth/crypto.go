@@ -3,6 +3,7 @@ package auth
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -18,24 +19,64 @@ const (
 	keyLen       = 32
 )
 
-// HashPassword hashes a password using Argon2id.
+// HashParams controls the cost of Argon2id hashing.
+// Higher values are slower to compute, both for us and for an attacker.
+type HashParams struct {
+	Memory  uint32 // KiB
+	Time    uint32 // Number of passes over the memory
+	Threads uint8
+}
+
+// DefaultHashParams are used by HashPassword
+var DefaultHashParams = HashParams{
+	Memory:  argonMemory,
+	Time:    argonTime,
+	Threads: argonThreads,
+}
+
+// Validate rejects parameters Argon2id cannot work with
+func (p HashParams) Validate() error {
+	if p.Time < 1 {
+		return errors.New("argon2 time must be at least 1")
+	}
+	if p.Threads < 1 {
+		return errors.New("argon2 threads must be at least 1")
+	}
+	if p.Memory < 8*uint32(p.Threads) {
+		return errors.New("argon2 memory must be at least 8 KiB per thread")
+	}
+	return nil
+}
+
+// HashPassword hashes a password using Argon2id with DefaultHashParams.
 func HashPassword(password string) (string, error) {
+	return HashPasswordWithParams(password, DefaultHashParams)
+}
+
+// HashPasswordWithParams hashes a password using Argon2id with the given cost.
+// The parameters are encoded in the hash, so VerifyPassword does not need them.
+func HashPasswordWithParams(password string, params HashParams) (string, error) {
+	if err := params.Validate(); err != nil {
+		return "", err
+	}
+
 	salt := make([]byte, saltLen)
 	if _, err := rand.Read(salt); err != nil {
 		return "", err
 	}
 
-	hash := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, keyLen)
+	hash := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, keyLen)
 
 	// Format: $argon2id$v=19$m=65536,t=1,p=4$salt$hash
 	b64Salt := base64.RawStdEncoding.EncodeToString(salt)
 	b64Hash := base64.RawStdEncoding.EncodeToString(hash)
 
 	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
-		argon2.Version, argonMemory, argonTime, argonThreads, b64Salt, b64Hash), nil
+		argon2.Version, params.Memory, params.Time, params.Threads, b64Salt, b64Hash), nil
 }
 
 // VerifyPassword compares a password with an Argon2id hash.
+// The cost is read from the hash itself, so hashes created with any parameters verify.
 func VerifyPassword(encodedHash, password string) (bool, error) {
 	parts := strings.Split(encodedHash, "$")
 	if len(parts) != 6 {
@@ -161,3 +161,75 @@ func TestVerifyPassword_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestHashPasswordWithParams(t *testing.T) {
+	password := "supersecret123"
+	params := HashParams{Memory: 8 * 1024, Time: 2, Threads: 1}
+
+	hash, err := HashPasswordWithParams(password, params)
+	if err != nil {
+		t.Fatalf("HashPasswordWithParams failed: %v", err)
+	}
+
+	if !strings.Contains(hash, "$m=8192,t=2,p=1$") {
+		t.Errorf("Expected params to be encoded in hash, got: %s", hash)
+	}
+
+	// Verification reads the cost from the hash, not from DefaultHashParams
+	match, err := VerifyPassword(hash, password)
+	if err != nil {
+		t.Fatalf("VerifyPassword failed: %v", err)
+	}
+	if !match {
+		t.Error("VerifyPassword returned false for correct password")
+	}
+}
+
+func TestHashParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  HashParams
+		wantErr bool
+	}{
+		{name: "Defaults", params: DefaultHashParams, wantErr: false},
+		{name: "Zero time", params: HashParams{Memory: 8 * 1024, Time: 0, Threads: 1}, wantErr: true},
+		{name: "Zero threads", params: HashParams{Memory: 8 * 1024, Time: 1, Threads: 0}, wantErr: true},
+		{name: "Too little memory", params: HashParams{Memory: 8, Time: 1, Threads: 2}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.wantErr && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// BenchmarkHashPassword measures hashing latency across costs so operators can tune
+// ARGON2_TIME and ARGON2_MEMORY_KB. Run with: go test -bench=HashPassword ./pkg/auth
+func BenchmarkHashPassword(b *testing.B) {
+	benchmarks := []struct {
+		name   string
+		params HashParams
+	}{
+		{name: "Default", params: DefaultHashParams},
+		{name: "m=19MiB,t=2", params: HashParams{Memory: 19 * 1024, Time: 2, Threads: 1}},
+		{name: "m=64MiB,t=3", params: HashParams{Memory: 64 * 1024, Time: 3, Threads: 4}},
+		{name: "m=128MiB,t=4", params: HashParams{Memory: 128 * 1024, Time: 4, Threads: 4}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := HashPasswordWithParams("correct-horse-battery-staple", bm.params); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
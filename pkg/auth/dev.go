@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+)
+
+// DevIssuer is the issuer of tokens signed by a NewDevSigner
+const DevIssuer = "gavel-dev"
+
+// devKeyBits matches the size of the keys provisioned for real deployments
+const devKeyBits = 2048
+
+// NewDevSigner creates a Signer with a freshly generated, in-memory RSA key pair, for local
+// development and tests that should not need keys provisioned on disk.
+//
+// It is NOT safe for production: the key lives only as long as the process, so tokens stop
+// validating on restart, and no other service can verify them without fetching the key
+// from this one's JWKS.
+func NewDevSigner() (*Signer, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, devKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate dev signing key: %w", err)
+	}
+	return newSigner(priv, &priv.PublicKey, DevIssuer), nil
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewDevSigner(t *testing.T) {
+	signer, err := NewDevSigner()
+	if err != nil {
+		t.Fatalf("NewDevSigner failed: %v", err)
+	}
+
+	userID := uuid.New()
+	tokens, err := signer.GenerateTokens(userID, "dev@example.com", "Dev User", "user", []string{"bids:place"})
+	if err != nil {
+		t.Fatalf("GenerateTokens failed: %v", err)
+	}
+	claims, err := signer.ValidateToken(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateToken failed: %v", err)
+	}
+	if claims.Sub != userID.String() || claims.Iss != DevIssuer {
+		t.Errorf("claims = sub %q, iss %q; want sub %q, iss %q", claims.Sub, claims.Iss, userID, DevIssuer)
+	}
+
+	// Every DevSigner has its own key, so another one rejects the token
+	other, err := NewDevSigner()
+	if err != nil {
+		t.Fatalf("NewDevSigner failed: %v", err)
+	}
+	if _, err := other.ValidateToken(tokens.AccessToken); err == nil {
+		t.Error("a token from another DevSigner validated")
+	}
+}
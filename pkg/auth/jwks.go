@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"encoding/base64"
+	"math/big"
+)
+
+// JWK is a single RSA public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKS is a JSON Web Key Set document.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns every public key the Signer accepts, in the order they were registered.
+// Rotated-in keys appear as soon as they are added with AddPublicKey.
+func (s *Signer) JWKS() JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]JWK, 0, len(s.keyOrder))
+	for _, kid := range s.keyOrder {
+		pub := s.verificationKeys[kid]
+		keys = append(keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		})
+	}
+	return JWKS{Keys: keys}
+}
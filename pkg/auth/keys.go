@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Key loading errors
+var (
+	ErrKeyNotConfigured = errors.New("key not configured")
+	ErrMalformedKey     = errors.New("malformed key")
+)
+
+// KeySource says where a PEM-encoded key is read from: a file, or the base64 of the PEM
+// itself, e.g. a secret injected as an environment variable. Exactly one must be set.
+type KeySource struct {
+	Path   string
+	Base64 string
+}
+
+// KeySourceFromEnv reads the key's path from <name>_PATH and its base64 PEM from <name>,
+// e.g. JWT_PRIVATE_KEY_PATH and JWT_PRIVATE_KEY
+func KeySourceFromEnv(name string) KeySource {
+	return KeySource{
+		Path:   os.Getenv(name + "_PATH"),
+		Base64: os.Getenv(name),
+	}
+}
+
+// IsSet returns true if either a path or a base64 PEM is given
+func (s KeySource) IsSet() bool {
+	return s.Path != "" || s.Base64 != ""
+}
+
+// Load returns the PEM-encoded key, or ErrKeyNotConfigured if neither source is set.
+// It only checks that a PEM block is there; parsing the key is left to the Signer.
+func (s KeySource) Load() ([]byte, error) {
+	var keyPEM []byte
+	switch {
+	case s.Path != "" && s.Base64 != "":
+		return nil, errors.New("both a key path and a base64 key are set, only one may be")
+	case s.Path != "":
+		b, err := os.ReadFile(s.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		keyPEM = b
+	case s.Base64 != "":
+		b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s.Base64))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid base64: %w", ErrMalformedKey, err)
+		}
+		keyPEM = b
+	default:
+		return nil, ErrKeyNotConfigured
+	}
+
+	if block, _ := pem.Decode(keyPEM); block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found", ErrMalformedKey)
+	}
+	return keyPEM, nil
+}
+
+// LoadSigner builds a Signer that signs and validates tokens from a key pair, checking that
+// the public key belongs to the private key
+func LoadSigner(private, public KeySource, issuer string) (*Signer, error) {
+	privateKeyPEM, err := private.Load()
+	if err != nil {
+		return nil, fmt.Errorf("private key: %w", err)
+	}
+	publicKeyPEM, err := public.Load()
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+
+	signer, err := NewSigner(privateKeyPEM, publicKeyPEM, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedKey, err)
+	}
+	if !signer.privateKey.PublicKey.Equal(signer.publicKey) {
+		return nil, errors.New("public key does not match private key")
+	}
+	return signer, nil
+}
+
+// LoadVerifier builds a Signer that only validates tokens, for services that do not issue them
+func LoadVerifier(public KeySource, issuer string) (*Signer, error) {
+	publicKeyPEM, err := public.Load()
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+
+	signer, err := NewSignerFromPublicKey(publicKeyPEM, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedKey, err)
+	}
+	return signer, nil
+}
+
+// PublicKeyPEM returns the PEM encoding of the key new tokens are signed with
+func (s *Signer) PublicKeyPEM() []byte {
+	der, err := x509.MarshalPKIXPublicKey(s.publicKey)
+	if err != nil {
+		// Cannot happen: the key was parsed from PKIX in the first place
+		panic(fmt.Sprintf("auth: marshal public key: %v", err))
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// writeKey writes keyPEM to a file in a temporary directory and returns its path
+func writeKey(t *testing.T, name string, keyPEM []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, keyPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return path
+}
+
+func TestLoadSigner_FromFiles(t *testing.T) {
+	privPEM, pubPEM := generateTestKeys(t)
+
+	signer, err := LoadSigner(
+		KeySource{Path: writeKey(t, "private.pem", privPEM)},
+		KeySource{Path: writeKey(t, "public.pem", pubPEM)},
+		"test-issuer",
+	)
+	if err != nil {
+		t.Fatalf("LoadSigner failed: %v", err)
+	}
+
+	tokens, err := signer.GenerateTokens(uuid.New(), "test@example.com", "Test User", "user", nil)
+	if err != nil {
+		t.Fatalf("GenerateTokens failed: %v", err)
+	}
+	if _, err := signer.ValidateToken(tokens.AccessToken); err != nil {
+		t.Errorf("ValidateToken failed: %v", err)
+	}
+	if !bytes.Equal(signer.PublicKeyPEM(), pubPEM) {
+		t.Errorf("PublicKeyPEM = %q, want %q", signer.PublicKeyPEM(), pubPEM)
+	}
+}
+
+func TestLoadSigner_FromEnv(t *testing.T) {
+	privPEM, pubPEM := generateTestKeys(t)
+	t.Setenv("TEST_PRIVATE_KEY", base64.StdEncoding.EncodeToString(privPEM))
+	t.Setenv("TEST_PRIVATE_KEY_PATH", "")
+	t.Setenv("TEST_PUBLIC_KEY", base64.StdEncoding.EncodeToString(pubPEM))
+	t.Setenv("TEST_PUBLIC_KEY_PATH", "")
+
+	signer, err := LoadSigner(KeySourceFromEnv("TEST_PRIVATE_KEY"), KeySourceFromEnv("TEST_PUBLIC_KEY"), "test-issuer")
+	if err != nil {
+		t.Fatalf("LoadSigner failed: %v", err)
+	}
+
+	verifier, err := LoadVerifier(KeySourceFromEnv("TEST_PUBLIC_KEY"), "test-issuer")
+	if err != nil {
+		t.Fatalf("LoadVerifier failed: %v", err)
+	}
+	tokens, err := signer.GenerateTokens(uuid.New(), "test@example.com", "Test User", "user", nil)
+	if err != nil {
+		t.Fatalf("GenerateTokens failed: %v", err)
+	}
+	if _, err := verifier.ValidateToken(tokens.AccessToken); err != nil {
+		t.Errorf("verifier rejected a token from the signer: %v", err)
+	}
+}
+
+func TestLoadSigner_Errors(t *testing.T) {
+	privPEM, pubPEM := generateTestKeys(t)
+	_, otherPubPEM := generateTestKeys(t)
+	private := KeySource{Base64: base64.StdEncoding.EncodeToString(privPEM)}
+	public := KeySource{Base64: base64.StdEncoding.EncodeToString(pubPEM)}
+
+	// A PEM block whose contents are not a key
+	garbled := []byte("-----BEGIN PUBLIC KEY-----\nbm90IGEga2V5\n-----END PUBLIC KEY-----\n")
+
+	tests := []struct {
+		name     string
+		private  KeySource
+		public   KeySource
+		wantErr  error
+		contains string
+	}{
+		{name: "missing private key", public: public, wantErr: ErrKeyNotConfigured, contains: "private key"},
+		{name: "missing public key", private: private, wantErr: ErrKeyNotConfigured, contains: "public key"},
+		{
+			name:     "not PEM",
+			private:  private,
+			public:   KeySource{Path: writeKey(t, "public.pem", []byte("not a key"))},
+			wantErr:  ErrMalformedKey,
+			contains: "no PEM block",
+		},
+		{
+			name:     "malformed PEM",
+			private:  private,
+			public:   KeySource{Base64: base64.StdEncoding.EncodeToString(garbled)},
+			wantErr:  ErrMalformedKey,
+			contains: "failed to parse public key",
+		},
+		{name: "invalid base64", private: KeySource{Base64: "%%%"}, public: public, wantErr: ErrMalformedKey},
+		{name: "missing file", private: KeySource{Path: filepath.Join(t.TempDir(), "absent.pem")}, public: public, wantErr: os.ErrNotExist},
+		{name: "both sources set", private: KeySource{Path: "private.pem", Base64: private.Base64}, public: public, contains: "only one"},
+		{name: "mismatched pair", private: private, public: KeySource{Base64: base64.StdEncoding.EncodeToString(otherPubPEM)}, contains: "does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSigner(tt.private, tt.public, "test-issuer")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error %q does not wrap %q", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("error %q does not mention %q", err, tt.contains)
+			}
+		})
+	}
+}
@@ -40,7 +40,7 @@ func NewAuthInterceptor(signer *Signer) connect.UnaryInterceptorFunc {
 			// Inject info into context
 			ctx = context.WithValue(ctx, UserClaimsKey, claims)
 			ctx = context.WithValue(ctx, UserIDKey, claims.Sub)
-			// We will use this for the permissions check later in Phase 2
+			// Checked by NewPermissionInterceptor and RequirePermission
 			ctx = context.WithValue(ctx, PermissionsKey, claims.Permissions)
 
 			return next(ctx, req)
@@ -14,7 +14,7 @@ func TestAuthMiddleware(t *testing.T) {
 
 	// Generate a valid token
 	userID := uuid.New()
-	pair, _ := signer.GenerateTokens(userID, "user@example.com", "User", nil)
+	pair, _ := signer.GenerateTokens(userID, "user@example.com", "User", "user", nil)
 
 	interceptor := NewAuthInterceptor(signer)
 	dummyHandler := func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"connectrpc.com/connect"
+)
+
+// PermissionForceCancelItem lets moderators cancel any seller's auction. Services check it
+// against the permissions the auth service grants, so both read it from here.
+const PermissionForceCancelItem = "force_cancel:items"
+
+// ErrPermissionDenied is returned, as CodePermissionDenied, to callers whose token lacks a required permission
+var ErrPermissionDenied = errors.New("permission denied")
+
+// GetPermissions retrieves the permissions granted by the caller's token from the context.
+func GetPermissions(ctx context.Context) ([]string, bool) {
+	permissions, ok := ctx.Value(PermissionsKey).([]string)
+	return permissions, ok
+}
+
+// HasPermission reports whether the caller's token grants permission.
+func HasPermission(ctx context.Context, permission string) bool {
+	permissions, _ := GetPermissions(ctx)
+	return slices.Contains(permissions, permission)
+}
+
+// RequirePermission checks the caller's token grants permission, for handlers that decide
+// per request. Callers that were never authenticated get CodeUnauthenticated.
+func RequirePermission(ctx context.Context, permission string) error {
+	if _, ok := GetUserClaims(ctx); !ok {
+		return connect.NewError(connect.CodeUnauthenticated, errors.New("missing authentication"))
+	}
+	if !HasPermission(ctx, permission) {
+		return connect.NewError(connect.CodePermissionDenied, ErrPermissionDenied)
+	}
+	return nil
+}
+
+// NewPermissionInterceptor creates a ConnectRPC interceptor that only lets callers holding the
+// permission mapped to a procedure through. Procedures without an entry are not checked.
+// It relies on the claims set by the auth interceptor, so it must be installed after it.
+func NewPermissionInterceptor(required map[string]string) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if permission, ok := required[req.Spec().Procedure]; ok {
+				if err := RequirePermission(ctx, permission); err != nil {
+					return nil, err
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+}
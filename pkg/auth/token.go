@@ -3,11 +3,14 @@ package auth
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -16,6 +19,9 @@ import (
 	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
 )
 
+// ErrTokenExpired is matched, with errors.Is, by ValidateToken errors for tokens past their expiry
+var ErrTokenExpired = jwt.ErrTokenExpired
+
 // Claims wraps the protobuf TokenClaims to implement jwt.Claims.
 type Claims struct {
 	*authv1.TokenClaims
@@ -52,14 +58,21 @@ func (c *Claims) GetAudience() (jwt.ClaimStrings, error) {
 type TokenPair struct {
 	AccessToken  string
 	RefreshToken string
-	AccessExpiry time.Time
+	AccessExpiry time.Time // The access token's exp
 }
 
 // Signer handles token generation and validation.
 type Signer struct {
 	privateKey *rsa.PrivateKey
 	publicKey  *rsa.PublicKey
+	keyID      string
 	issuer     string
+
+	// verificationKeys holds every public key accepted for validation, keyed by kid.
+	// It always contains the primary key and grows when keys are rotated in.
+	mu               sync.RWMutex
+	verificationKeys map[string]*rsa.PublicKey
+	keyOrder         []string
 }
 
 // NewSigner creates a Signer from PEM-encoded keys (for auth-service that signs tokens).
@@ -83,29 +96,75 @@ func NewSigner(privateKeyPEM, publicKeyPEM []byte, issuer string) (*Signer, erro
 		return nil, errors.New("private key is not RSA")
 	}
 
-	blockPub, _ := pem.Decode(publicKeyPEM)
-	if blockPub == nil {
-		return nil, errors.New("failed to parse public key PEM")
-	}
-	pub, err := x509.ParsePKIXPublicKey(blockPub.Bytes)
+	rsaPub, err := parsePublicKeyPEM(publicKeyPEM)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse public key: %w", err)
-	}
-	rsaPub, ok := pub.(*rsa.PublicKey)
-	if !ok {
-		return nil, errors.New("public key is not RSA")
+		return nil, err
 	}
 
-	return &Signer{
-		privateKey: priv,
-		publicKey:  rsaPub,
-		issuer:     issuer,
-	}, nil
+	return newSigner(priv, rsaPub, issuer), nil
 }
 
 // NewSignerFromPublicKey creates a Signer with only the public key (for services that only validate tokens).
 // This signer cannot generate tokens, only validate them.
 func NewSignerFromPublicKey(publicKeyPEM []byte, issuer string) (*Signer, error) {
+	rsaPub, err := parsePublicKeyPEM(publicKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	// No private key - cannot sign tokens
+	return newSigner(nil, rsaPub, issuer), nil
+}
+
+func newSigner(priv *rsa.PrivateKey, pub *rsa.PublicKey, issuer string) *Signer {
+	kid := KeyID(pub)
+	return &Signer{
+		privateKey:       priv,
+		publicKey:        pub,
+		keyID:            kid,
+		issuer:           issuer,
+		verificationKeys: map[string]*rsa.PublicKey{kid: pub},
+		keyOrder:         []string{kid},
+	}
+}
+
+// KeyID returns the kid for a public key: the RFC 7638 JWK thumbprint (SHA-256, base64url).
+// Deriving it from the key material means every service computes the same kid without coordination.
+func KeyID(pub *rsa.PublicKey) string {
+	// Members must be in lexicographic order with no whitespace (RFC 7638 section 3)
+	canonical := fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+	)
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// KeyID returns the kid of the key used to sign new tokens.
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// AddPublicKey registers an additional PEM-encoded public key for token validation
+// (e.g. the next key during a rotation) and returns its kid.
+func (s *Signer) AddPublicKey(publicKeyPEM []byte) (string, error) {
+	pub, err := parsePublicKeyPEM(publicKeyPEM)
+	if err != nil {
+		return "", err
+	}
+
+	kid := KeyID(pub)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.verificationKeys[kid]; !exists {
+		s.verificationKeys[kid] = pub
+		s.keyOrder = append(s.keyOrder, kid)
+	}
+	return kid, nil
+}
+
+func parsePublicKeyPEM(publicKeyPEM []byte) (*rsa.PublicKey, error) {
 	blockPub, _ := pem.Decode(publicKeyPEM)
 	if blockPub == nil {
 		return nil, errors.New("failed to parse public key PEM")
@@ -118,24 +177,22 @@ func NewSignerFromPublicKey(publicKeyPEM []byte, issuer string) (*Signer, error)
 	if !ok {
 		return nil, errors.New("public key is not RSA")
 	}
-
-	return &Signer{
-		privateKey: nil, // No private key - cannot sign tokens
-		publicKey:  rsaPub,
-		issuer:     issuer,
-	}, nil
+	return rsaPub, nil
 }
 
 // GenerateTokens creates an access token (JWT) and a refresh token (random string).
-func (s *Signer) GenerateTokens(userID uuid.UUID, email, fullName string, permissions []string) (*TokenPair, error) {
+// The role and permissions are embedded in the access token for authorization checks.
+func (s *Signer) GenerateTokens(userID uuid.UUID, email, fullName, role string, permissions []string) (*TokenPair, error) {
 	now := time.Now()
-	accessExpiry := now.Add(15 * time.Minute)
+	// exp has whole seconds, so AccessExpiry is truncated to match it exactly
+	accessExpiry := now.Add(15 * time.Minute).Truncate(time.Second)
 
 	claims := &Claims{
 		TokenClaims: &authv1.TokenClaims{
 			Sub:         userID.String(),
 			Email:       email,
 			FullName:    fullName,
+			Role:        role,
 			Permissions: permissions,
 			Iss:         s.issuer,
 			Exp:         float64(accessExpiry.Unix()),
@@ -144,6 +201,7 @@ func (s *Signer) GenerateTokens(userID uuid.UUID, email, fullName string, permis
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.keyID
 	signedToken, err := token.SignedString(s.privateKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign token: %w", err)
@@ -169,7 +227,7 @@ func (s *Signer) ValidateToken(tokenString string) (*Claims, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return s.publicKey, nil
+		return s.verificationKey(token.Header["kid"])
 	})
 
 	if err != nil {
@@ -183,6 +241,26 @@ func (s *Signer) ValidateToken(tokenString string) (*Claims, error) {
 	return nil, errors.New("invalid token")
 }
 
+// verificationKey selects the public key matching the token's kid header.
+// Tokens without a kid (issued before key IDs were introduced) use the primary key.
+func (s *Signer) verificationKey(kid interface{}) (*rsa.PublicKey, error) {
+	if kid == nil {
+		return s.publicKey, nil
+	}
+	kidStr, ok := kid.(string)
+	if !ok {
+		return nil, errors.New("invalid kid header")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.verificationKeys[kidStr]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %s", kidStr)
+	}
+	return key, nil
+}
+
 // We need a helper for generating a secure random string for refresh tokens and other secrets.
 // This ensures sufficient entropy and URL-safe characters for security.
 func generateRandomString(n int) (string, error) {
@@ -54,7 +54,7 @@ func TestTokenLifecycle(t *testing.T) {
 	permissions := []string{"read:bids"}
 
 	// 1. Generate
-	pair, err := signer.GenerateTokens(userID, email, fullName, permissions)
+	pair, err := signer.GenerateTokens(userID, email, fullName, "user", permissions)
 	if err != nil {
 		t.Fatalf("GenerateTokens failed: %v", err)
 	}
@@ -69,6 +69,9 @@ func TestTokenLifecycle(t *testing.T) {
 	if claims.Sub != userID.String() {
 		t.Errorf("got subject %s, want %s", claims.Sub, userID)
 	}
+	if claims.Role != "user" {
+		t.Errorf("got role %s, want user", claims.Role)
+	}
 	if claims.Permissions[0] != "read:bids" {
 		t.Errorf("got permission %s, want read:bids", claims.Permissions[0])
 	}
@@ -167,3 +170,39 @@ func TestNewSignerValidation(t *testing.T) {
 		}
 	})
 }
+
+func TestKeyRotation(t *testing.T) {
+	privPEM, pubPEM := generateTestKeys(t)
+	signer, err := NewSigner(privPEM, pubPEM, "test-issuer")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+
+	nextPrivPEM, nextPubPEM := generateTestKeys(t)
+	nextSigner, err := NewSigner(nextPrivPEM, nextPubPEM, "test-issuer")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+
+	pair, err := nextSigner.GenerateTokens(uuid.New(), "user@example.com", "User", "user", nil)
+	if err != nil {
+		t.Fatalf("GenerateTokens failed: %v", err)
+	}
+
+	// 1. Unknown kid is rejected
+	if _, err := signer.ValidateToken(pair.AccessToken); err == nil {
+		t.Error("ValidateToken should reject a token signed with an unregistered key")
+	}
+
+	// 2. Accepted once the key is rotated in
+	kid, err := signer.AddPublicKey(nextPubPEM)
+	if err != nil {
+		t.Fatalf("AddPublicKey failed: %v", err)
+	}
+	if kid != nextSigner.KeyID() {
+		t.Errorf("got kid %s, want %s", kid, nextSigner.KeyID())
+	}
+	if _, err := signer.ValidateToken(pair.AccessToken); err != nil {
+		t.Errorf("ValidateToken failed after rotation: %v", err)
+	}
+}
@@ -9,6 +9,14 @@ import (
 // TransactionManager defines the interface for managing database transactions.
 // It abstracts the underlying database driver (pgx) to allow for easier testing and decoupling.
 type TransactionManager interface {
-	// BeginTx starts a new transaction.
+	// BeginTx starts a new transaction with the database's default isolation level.
 	BeginTx(ctx context.Context) (pgx.Tx, error)
+
+	// BeginTxWithOptions starts a new transaction with the given options, e.g. to request
+	// Serializable or RepeatableRead isolation.
+	BeginTxWithOptions(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error)
+
+	// WithTx runs fn in a new transaction. It commits if fn returns nil and rolls back
+	// if fn returns an error or panics; a panic is re-raised after the rollback.
+	WithTx(ctx context.Context, fn func(pgx.Tx) error) error
 }
@@ -0,0 +1,139 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Default pool settings for ParsePoolConfig
+const (
+	DefaultMaxConns         int32 = 10
+	DefaultMinConns         int32 = 1
+	DefaultMaxConnLifetime        = 30 * time.Minute
+	DefaultStatementTimeout       = 30 * time.Second
+)
+
+// QueryExecModes maps the values of <prefix>_DB_QUERY_EXEC_MODE to pgx query exec modes.
+//
+// The default, cache_statement, prepares each statement once per connection and reuses it,
+// which is the fastest but assumes the statement is still there on the next use. Behind a
+// pooler in transaction mode, such as pgbouncer with pool_mode=transaction, consecutive
+// transactions can land on different server connections, so use one of the modes that
+// does not keep prepared statements:
+//   - cache_describe caches only parameter and result descriptions; it costs no extra round
+//     trips, but a schema change under a cached description can break queries until reconnect
+//   - describe_exec describes every statement before running it, an extra round trip each time
+//   - exec sends the query and arguments in one unnamed statement, with text-format parameters
+//     whose types Postgres has to infer
+//   - simple_protocol interpolates arguments client-side into a simple query; the most
+//     compatible mode, but it gives up binary encoding and server-side parameter handling
+var QueryExecModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+// ParsePoolConfig parses a pgxpool config from url and tunes it from the environment.
+// With a prefix of "USER_STATS" it reads USER_STATS_DB_MAX_CONNS, USER_STATS_DB_MIN_CONNS,
+// USER_STATS_DB_MAX_CONN_LIFETIME, USER_STATS_DB_STATEMENT_TIMEOUT and
+// USER_STATS_DB_QUERY_EXEC_MODE, falling back to the defaults for anything unset. Settings url
+// makes itself, with pool_max_conns, pool_min_conns, pool_max_conn_lifetime or statement_timeout,
+// are kept as they are.
+//
+// The statement timeout is set as the statement_timeout session parameter of every connection,
+// so Postgres cancels any statement that runs past it (SQLSTATE 57014), in or out of a
+// transaction. A timeout of 0 leaves statements unbounded. Poolers that reject unknown startup
+// parameters need it allowed, e.g. pgbouncer's ignore_startup_parameters, or a timeout of 0.
+//
+// The query exec mode is one of the QueryExecModes keys. Unset, it is whatever url asks for
+// with default_query_exec_mode, or pgx's cache_statement.
+func ParsePoolConfig(url, prefix string) (*pgxpool.Config, error) {
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database url: %w", err)
+	}
+	// pgxpool drops the pool settings from the runtime params, so look for them in a plain parse
+	connConfig, err := pgx.ParseConfig(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database url: %w", err)
+	}
+	inURL := func(param string) bool {
+		_, ok := connConfig.RuntimeParams[param]
+		return ok
+	}
+
+	if !inURL("pool_max_conns") {
+		config.MaxConns, err = envInt32(prefix+"_DB_MAX_CONNS", DefaultMaxConns)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !inURL("pool_min_conns") {
+		config.MinConns, err = envInt32(prefix+"_DB_MIN_CONNS", DefaultMinConns)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config.MaxConns <= 0 {
+		return nil, fmt.Errorf("%s_DB_MAX_CONNS must be positive", prefix)
+	}
+	if config.MinConns < 0 || config.MinConns > config.MaxConns {
+		return nil, fmt.Errorf("%s_DB_MIN_CONNS must be between 0 and %d", prefix, config.MaxConns)
+	}
+
+	if !inURL("pool_max_conn_lifetime") {
+		config.MaxConnLifetime = DefaultMaxConnLifetime
+		key := prefix + "_DB_MAX_CONN_LIFETIME"
+		if v := os.Getenv(key); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("invalid %s: %q", key, v)
+			}
+			config.MaxConnLifetime = d
+		}
+	}
+
+	if !inURL("statement_timeout") {
+		statementTimeout := DefaultStatementTimeout
+		key := prefix + "_DB_STATEMENT_TIMEOUT"
+		if v := os.Getenv(key); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d < 0 {
+				return nil, fmt.Errorf("invalid %s: %q", key, v)
+			}
+			statementTimeout = d
+		}
+		if statementTimeout > 0 {
+			config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(statementTimeout.Milliseconds(), 10)
+		}
+	}
+
+	key := prefix + "_DB_QUERY_EXEC_MODE"
+	if v := os.Getenv(key); v != "" {
+		mode, ok := QueryExecModes[v]
+		if !ok {
+			return nil, fmt.Errorf("invalid %s: %q", key, v)
+		}
+		config.ConnConfig.DefaultQueryExecMode = mode
+	}
+	return config, nil
+}
+
+func envInt32(key string, fallback int32) (int32, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %q", key, v)
+	}
+	return int32(n), nil
+}
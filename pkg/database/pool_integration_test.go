@@ -0,0 +1,63 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/database"
+)
+
+func TestParsePoolConfig_StatementTimeoutAbortsSlowQueries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	testDB := newItemsDatabase(t)
+	defer testDB.Close()
+	ctx := context.Background()
+
+	t.Setenv("TEST_DB_STATEMENT_TIMEOUT", "200ms")
+	config, err := database.ParsePoolConfig(testDB.ConnStr, "TEST")
+	require.NoError(t, err)
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	assertCanceled := func(t *testing.T, err error, elapsed time.Duration) {
+		t.Helper()
+		var pgErr *pgconn.PgError
+		require.True(t, errors.As(err, &pgErr), "expected a Postgres error, got %v", err)
+		assert.Equal(t, "57014", pgErr.Code) // query_canceled
+		assert.Less(t, elapsed, 2*time.Second, "query should abort at the deadline, not run to completion")
+	}
+
+	t.Run("Read outside a transaction", func(t *testing.T) {
+		start := time.Now()
+		_, err := pool.Exec(ctx, "SELECT pg_sleep(5)")
+		assertCanceled(t, err, time.Since(start))
+	})
+
+	t.Run("Statement inside a transaction", func(t *testing.T) {
+		txManager := database.NewPostgresTransactionManager(pool, 5*time.Second)
+		start := time.Now()
+		err := txManager.WithTx(ctx, func(tx pgx.Tx) error {
+			_, err := tx.Exec(ctx, "SELECT pg_sleep(5)")
+			return err
+		})
+		assertCanceled(t, err, time.Since(start))
+	})
+
+	t.Run("Fast queries are unaffected", func(t *testing.T) {
+		var one int
+		require.NoError(t, pool.QueryRow(ctx, "SELECT 1").Scan(&one))
+		assert.Equal(t, 1, one)
+	})
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE codes for transactions that lost a race and can safely be re-run
+const (
+	SerializationFailure = "40001"
+	DeadlockDetected     = "40P01"
+)
+
+// ErrConflict is returned by a repository whose optimistic concurrency check lost to another
+// transaction. Re-running the transaction, e.g. with RetryOn(ErrConflict), may succeed.
+var ErrConflict = errors.New("row was changed by another transaction")
+
+// Default retry settings for RetryTx
+const (
+	DefaultTxRetries    = 3
+	DefaultTxMinBackoff = 10 * time.Millisecond
+	DefaultTxMaxBackoff = 200 * time.Millisecond
+)
+
+type retryConfig struct {
+	retries    int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	retryOn    []error
+}
+
+// RetryOption configures RetryTx
+type RetryOption func(*retryConfig)
+
+// WithTxRetries sets how many times RetryTx re-runs a transaction after the first attempt
+func WithTxRetries(retries int) RetryOption {
+	return func(c *retryConfig) {
+		c.retries = retries
+	}
+}
+
+// WithTxBackoff overrides the exponential backoff bounds between attempts
+func WithTxBackoff(minBackoff, maxBackoff time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// RetryOn also re-runs the transaction when it fails with one of errs, e.g. an
+// optimistic concurrency check that lost to another transaction
+func RetryOn(errs ...error) RetryOption {
+	return func(c *retryConfig) {
+		c.retryOn = append(c.retryOn, errs...)
+	}
+}
+
+// IsRetryable reports whether err is a serialization failure or deadlock, after which
+// the whole transaction can be re-run
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == SerializationFailure || pgErr.Code == DeadlockDetected
+}
+
+// RetryTx runs fn with m.WithTx, re-running it with exponential backoff while it fails with a
+// retryable error. fn may run several times, so it must not have side effects outside the
+// transaction. The last error is returned once retries run out or ctx is done.
+func RetryTx(ctx context.Context, m TransactionManager, fn func(pgx.Tx) error, opts ...RetryOption) error {
+	config := retryConfig{
+		retries:    DefaultTxRetries,
+		minBackoff: DefaultTxMinBackoff,
+		maxBackoff: DefaultTxMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	backoff := config.minBackoff
+	for attempt := 0; ; attempt++ {
+		err := m.WithTx(ctx, fn)
+		if err == nil || attempt >= config.retries || !config.retryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, config.maxBackoff)
+	}
+}
+
+// retryable reports whether err is retryable, or one of the errors passed to RetryOn
+func (c retryConfig) retryable(err error) bool {
+	if IsRetryable(err) {
+		return true
+	}
+	for _, target := range c.retryOn {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyTxManager fails the first failures transactions with err, then runs fn
+type flakyTxManager struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (m *flakyTxManager) BeginTx(context.Context) (pgx.Tx, error) {
+	return &recordingTx{}, nil
+}
+
+func (m *flakyTxManager) BeginTxWithOptions(context.Context, pgx.TxOptions) (pgx.Tx, error) {
+	return &recordingTx{}, nil
+}
+
+func (m *flakyTxManager) WithTx(ctx context.Context, fn func(pgx.Tx) error) error {
+	m.calls++
+	if m.calls <= m.failures {
+		return m.err
+	}
+	return runInTx(ctx, &recordingTx{}, fn)
+}
+
+var fastRetry = WithTxBackoff(time.Millisecond, time.Millisecond)
+
+func TestRetryTx_RetriesSerializationFailures(t *testing.T) {
+	txManager := &flakyTxManager{failures: 2, err: &pgconn.PgError{Code: SerializationFailure}}
+	runs := 0
+
+	err := RetryTx(context.Background(), txManager, func(pgx.Tx) error {
+		runs++
+		return nil
+	}, fastRetry)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, txManager.calls)
+	assert.Equal(t, 1, runs, "fn runs once the transaction finally succeeds")
+}
+
+func TestRetryTx_RetriesDeadlocks(t *testing.T) {
+	txManager := &flakyTxManager{failures: 1, err: &pgconn.PgError{Code: DeadlockDetected}}
+
+	err := RetryTx(context.Background(), txManager, func(pgx.Tx) error { return nil }, fastRetry)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, txManager.calls)
+}
+
+func TestRetryTx_GivesUpAfterRetries(t *testing.T) {
+	serializationErr := &pgconn.PgError{Code: SerializationFailure}
+	txManager := &flakyTxManager{failures: 10, err: serializationErr}
+
+	err := RetryTx(context.Background(), txManager, func(pgx.Tx) error { return nil }, fastRetry, WithTxRetries(2))
+
+	assert.ErrorIs(t, err, serializationErr)
+	assert.Equal(t, 3, txManager.calls, "first attempt plus two retries")
+}
+
+func TestRetryTx_DoesNotRetryOtherErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "plain error", err: errors.New("boom")},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txManager := &flakyTxManager{failures: 10, err: tt.err}
+
+			err := RetryTx(context.Background(), txManager, func(pgx.Tx) error { return nil }, fastRetry)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, 1, txManager.calls)
+		})
+	}
+}
+
+func TestRetryTx_RetryOn(t *testing.T) {
+	errConflict := errors.New("conflict")
+	txManager := &flakyTxManager{failures: 2, err: fmt.Errorf("failed to update: %w", errConflict)}
+
+	err := RetryTx(context.Background(), txManager, func(pgx.Tx) error { return nil }, fastRetry, RetryOn(errConflict))
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, txManager.calls)
+
+	// Without RetryOn the same error is final
+	txManager = &flakyTxManager{failures: 2, err: errConflict}
+	err = RetryTx(context.Background(), txManager, func(pgx.Tx) error { return nil }, fastRetry)
+
+	assert.ErrorIs(t, err, errConflict)
+	assert.Equal(t, 1, txManager.calls)
+}
+
+func TestRetryTx_StopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	txManager := &flakyTxManager{failures: 10, err: &pgconn.PgError{Code: SerializationFailure}}
+
+	err := RetryTx(ctx, txManager, func(pgx.Tx) error { return nil }, WithTxBackoff(time.Hour, time.Hour))
+
+	assert.True(t, IsRetryable(err))
+	assert.Equal(t, 1, txManager.calls)
+}
+
+func TestIsRetryable_WrappedError(t *testing.T) {
+	err := errors.Join(errors.New("failed to save bid"), &pgconn.PgError{Code: SerializationFailure})
+	assert.True(t, IsRetryable(err))
+	assert.False(t, IsRetryable(nil))
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Router is a DBTX that sends writes to a primary pool and spreads reads across replicas.
+// Exec and SendBatch go to the primary; Query and QueryRow go to the next replica in turn.
+//
+// Replicas lag behind the primary, so only pure reads that tolerate slightly stale data
+// should go through Query and QueryRow. Use Primary for reads that must see a caller's own
+// writes, and for statements like INSERT ... RETURNING.
+type Router struct {
+	primary  *pgxpool.Pool
+	replicas []*pgxpool.Pool
+	next     atomic.Uint64
+}
+
+// NewRouter creates a router over primary and replicas. Without replicas every
+// statement goes to the primary.
+func NewRouter(primary *pgxpool.Pool, replicas ...*pgxpool.Pool) *Router {
+	return &Router{
+		primary:  primary,
+		replicas: replicas,
+	}
+}
+
+// Primary returns the primary pool
+func (r *Router) Primary() *pgxpool.Pool {
+	return r.primary
+}
+
+// Replica returns the replica the next read should use, round-robin, or the primary
+// when there are no replicas
+func (r *Router) Replica() *pgxpool.Pool {
+	if len(r.replicas) == 0 {
+		return r.primary
+	}
+	n := r.next.Add(1) - 1
+	return r.replicas[n%uint64(len(r.replicas))]
+}
+
+// Exec runs a statement on the primary
+func (r *Router) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	return r.primary.Exec(ctx, sql, arguments...)
+}
+
+// Query runs a read-only query on a replica
+func (r *Router) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return r.Replica().Query(ctx, sql, args...)
+}
+
+// QueryRow runs a read-only single-row query on a replica
+func (r *Router) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return r.Replica().QueryRow(ctx, sql, args...)
+}
+
+// SendBatch sends a batch to the primary, since batches usually write
+func (r *Router) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return r.primary.SendBatch(ctx, b)
+}
+
+var _ DBTX = (*Router)(nil)
+
+// ConnectReplicas opens and pings a pool for each replica URL, configured by ParsePoolConfig
+// with the same env prefix as the primary. On failure every pool opened so far is closed.
+func ConnectReplicas(ctx context.Context, urls []string, prefix string) ([]*pgxpool.Pool, error) {
+	replicas := make([]*pgxpool.Pool, 0, len(urls))
+	closeAll := func() {
+		for _, replica := range replicas {
+			replica.Close()
+		}
+	}
+
+	for i, url := range urls {
+		config, err := ParsePoolConfig(url, prefix)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("failed to configure replica %d: %w", i, err)
+		}
+		pool, err := pgxpool.NewWithConfig(ctx, config)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("failed to create pool for replica %d: %w", i, err)
+		}
+		replicas = append(replicas, pool)
+		if err := pool.Ping(ctx); err != nil {
+			closeAll()
+			return nil, fmt.Errorf("failed to ping replica %d: %w", i, err)
+		}
+	}
+	return replicas, nil
+}
@@ -0,0 +1,68 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/database"
+)
+
+func TestRouter_ReadsFromReplicaWritesToPrimary(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// Two independent databases stand in for a primary and its replica, so it is
+	// visible which one each statement reached
+	primaryDB := newItemsDatabase(t)
+	defer primaryDB.Close()
+	replicaDB := newItemsDatabase(t)
+	defer replicaDB.Close()
+	ctx := context.Background()
+
+	router := database.NewRouter(primaryDB.Pool, replicaDB.Pool)
+
+	count := func(t *testing.T, db database.DBTX) int {
+		t.Helper()
+		var n int
+		require.NoError(t, db.QueryRow(ctx, "SELECT COUNT(*) FROM items").Scan(&n))
+		return n
+	}
+
+	t.Run("Exec writes to the primary", func(t *testing.T) {
+		_, err := router.Exec(ctx, "INSERT INTO items (id, current_highest_bid) VALUES ($1, 100)", uuid.New())
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, count(t, primaryDB.Pool))
+		assert.Equal(t, 0, count(t, replicaDB.Pool))
+	})
+
+	t.Run("Query and QueryRow read from the replica", func(t *testing.T) {
+		replicaOnly := uuid.New()
+		_, err := replicaDB.Pool.Exec(ctx, "INSERT INTO items (id, current_highest_bid) VALUES ($1, 700)", replicaOnly)
+		require.NoError(t, err)
+
+		var bid int64
+		require.NoError(t, router.QueryRow(ctx, "SELECT current_highest_bid FROM items WHERE id = $1", replicaOnly).Scan(&bid))
+		assert.Equal(t, int64(700), bid)
+
+		rows, err := router.Query(ctx, "SELECT id FROM items")
+		require.NoError(t, err)
+		var ids []uuid.UUID
+		for rows.Next() {
+			var id uuid.UUID
+			require.NoError(t, rows.Scan(&id))
+			ids = append(ids, id)
+		}
+		require.NoError(t, rows.Err())
+		assert.Equal(t, []uuid.UUID{replicaOnly}, ids)
+	})
+
+	t.Run("Primary bypasses the replicas", func(t *testing.T) {
+		assert.Equal(t, 1, count(t, router.Primary()))
+	})
+}
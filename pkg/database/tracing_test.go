@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestQueryTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := NewQueryTracer(tp)
+
+	parentCtx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+
+	ctx := tracer.TraceQueryStart(parentCtx, nil, pgx.TraceQueryStartData{SQL: "UPDATE items SET title = $1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("UPDATE 2")})
+
+	ctx = tracer.TraceQueryStart(parentCtx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	update, failed := spans[0], spans[1]
+	assert.Equal(t, "db.query", update.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), update.Parent().SpanID())
+	assert.Contains(t, update.Attributes(), attribute.String("db.statement", "UPDATE items SET title = $1"))
+	assert.Contains(t, update.Attributes(), attribute.Int64("db.rows_affected", 2))
+	assert.Equal(t, codes.Unset, update.Status().Code)
+
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Equal(t, "boom", failed.Status().Description)
+}
@@ -26,7 +26,14 @@ func NewPostgresTransactionManager(pool *pgxpool.Pool, lockTimeout time.Duration
 
 // BeginTx starts a new transaction with configured lock timeout
 func (m *PostgresTransactionManager) BeginTx(ctx context.Context) (pgx.Tx, error) {
-	tx, err := m.pool.Begin(ctx)
+	return m.BeginTxWithOptions(ctx, pgx.TxOptions{})
+}
+
+// BeginTxWithOptions starts a new transaction with the given options and configured lock timeout.
+// Under Serializable or RepeatableRead isolation, callers must be ready to retry when a
+// statement or Commit fails with a serialization failure (SQLSTATE 40001).
+func (m *PostgresTransactionManager) BeginTxWithOptions(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	tx, err := m.pool.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -43,3 +50,33 @@ func (m *PostgresTransactionManager) BeginTx(ctx context.Context) (pgx.Tx, error
 
 	return tx, nil
 }
+
+// WithTx runs fn in a new transaction, committing on success and rolling back on error or panic.
+// Errors from fn are returned unwrapped so callers can match them.
+func (m *PostgresTransactionManager) WithTx(ctx context.Context, fn func(pgx.Tx) error) error {
+	tx, err := m.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return runInTx(ctx, tx, fn)
+}
+
+// runInTx runs fn in tx and finishes tx according to the outcome
+func runInTx(ctx context.Context, tx pgx.Tx, fn func(pgx.Tx) error) error {
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
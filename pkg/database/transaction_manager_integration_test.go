@@ -0,0 +1,119 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/database"
+	"github.com/floroz/gavel/pkg/testhelpers"
+)
+
+// itemsMigration creates a minimal items table, standing in for a service's schema
+const itemsMigration = `-- +goose Up
+CREATE TABLE items (
+    id UUID PRIMARY KEY,
+    current_highest_bid BIGINT NOT NULL DEFAULT 0
+);
+
+-- +goose Down
+DROP TABLE items;
+`
+
+// newItemsDatabase starts a Postgres container with just the items table
+func newItemsDatabase(t *testing.T) *testhelpers.TestDatabase {
+	t.Helper()
+	migrations := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(migrations, "00001_items.sql"), []byte(itemsMigration), 0o600))
+	return testhelpers.NewTestDatabase(t, migrations)
+}
+
+func TestBeginTxWithOptions_SerializableConflict(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	testDB := newItemsDatabase(t)
+	defer testDB.Close()
+	ctx := context.Background()
+
+	itemID := uuid.New()
+	_, err := testDB.Pool.Exec(ctx, "INSERT INTO items (id, current_highest_bid) VALUES ($1, 100)", itemID)
+	require.NoError(t, err)
+
+	txManager := database.NewPostgresTransactionManager(testDB.Pool, 5*time.Second)
+	serializable := pgx.TxOptions{IsoLevel: pgx.Serializable}
+
+	first, err := txManager.BeginTxWithOptions(ctx, serializable)
+	require.NoError(t, err)
+	defer func() { _ = first.Rollback(ctx) }()
+	second, err := txManager.BeginTxWithOptions(ctx, serializable)
+	require.NoError(t, err)
+	defer func() { _ = second.Rollback(ctx) }()
+
+	// Both read the current bid before either writes, like two concurrent bidders
+	var seenByFirst, seenBySecond int64
+	require.NoError(t, first.QueryRow(ctx, "SELECT current_highest_bid FROM items WHERE id = $1", itemID).Scan(&seenByFirst))
+	require.NoError(t, second.QueryRow(ctx, "SELECT current_highest_bid FROM items WHERE id = $1", itemID).Scan(&seenBySecond))
+
+	_, err = first.Exec(ctx, "UPDATE items SET current_highest_bid = $1 WHERE id = $2", seenByFirst+50, itemID)
+	require.NoError(t, err)
+	require.NoError(t, first.Commit(ctx))
+
+	// The second writer's snapshot is stale, so its update must fail instead of overwriting
+	_, err = second.Exec(ctx, "UPDATE items SET current_highest_bid = $1 WHERE id = $2", seenBySecond+20, itemID)
+	if err == nil {
+		err = second.Commit(ctx)
+	}
+	var pgErr *pgconn.PgError
+	require.True(t, errors.As(err, &pgErr), "expected a postgres error, got %v", err)
+	assert.Equal(t, database.SerializationFailure, pgErr.Code)
+	assert.True(t, database.IsRetryable(err))
+
+	var stored int64
+	require.NoError(t, testDB.Pool.QueryRow(ctx, "SELECT current_highest_bid FROM items WHERE id = $1", itemID).Scan(&stored))
+	assert.Equal(t, int64(150), stored, "the first update must survive")
+}
+
+func TestBeginTxWithOptions_AppliesIsolationLevel(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	testDB := newItemsDatabase(t)
+	defer testDB.Close()
+	ctx := context.Background()
+
+	txManager := database.NewPostgresTransactionManager(testDB.Pool, 5*time.Second)
+
+	tests := []struct {
+		name string
+		opts pgx.TxOptions
+		want string
+	}{
+		{name: "default", opts: pgx.TxOptions{}, want: "read committed"},
+		{name: "repeatable read", opts: pgx.TxOptions{IsoLevel: pgx.RepeatableRead}, want: "repeatable read"},
+		{name: "serializable", opts: pgx.TxOptions{IsoLevel: pgx.Serializable}, want: "serializable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := txManager.BeginTxWithOptions(ctx, tt.opts)
+			require.NoError(t, err)
+			defer func() { _ = tx.Rollback(ctx) }()
+
+			var level string
+			require.NoError(t, tx.QueryRow(ctx, "SHOW transaction_isolation").Scan(&level))
+			assert.Equal(t, tt.want, level)
+		})
+	}
+}
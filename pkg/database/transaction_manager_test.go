@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTx tracks how a transaction was finished
+type recordingTx struct {
+	pgx.Tx
+	commitErr  error
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *recordingTx) Commit(context.Context) error {
+	tx.committed = true
+	return tx.commitErr
+}
+
+func (tx *recordingTx) Rollback(context.Context) error {
+	tx.rolledBack = true
+	return nil
+}
+
+func TestRunInTx_CommitsOnSuccess(t *testing.T) {
+	tx := &recordingTx{}
+	var got pgx.Tx
+
+	err := runInTx(context.Background(), tx, func(inner pgx.Tx) error {
+		got = inner
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Same(t, tx, got, "fn must receive the transaction")
+	assert.True(t, tx.committed)
+	assert.False(t, tx.rolledBack)
+}
+
+func TestRunInTx_RollsBackOnError(t *testing.T) {
+	tx := &recordingTx{}
+	fnErr := errors.New("boom")
+
+	err := runInTx(context.Background(), tx, func(pgx.Tx) error {
+		return fnErr
+	})
+
+	assert.Same(t, fnErr, err, "errors from fn are returned as-is")
+	assert.False(t, tx.committed)
+	assert.True(t, tx.rolledBack)
+}
+
+func TestRunInTx_RollsBackAndRepanics(t *testing.T) {
+	tx := &recordingTx{}
+
+	assert.PanicsWithValue(t, "kaboom", func() {
+		_ = runInTx(context.Background(), tx, func(pgx.Tx) error {
+			panic("kaboom")
+		})
+	})
+
+	assert.False(t, tx.committed)
+	assert.True(t, tx.rolledBack)
+}
+
+func TestRunInTx_WrapsCommitError(t *testing.T) {
+	commitErr := errors.New("connection lost")
+	tx := &recordingTx{commitErr: commitErr}
+
+	err := runInTx(context.Background(), tx, func(pgx.Tx) error {
+		return nil
+	})
+
+	assert.ErrorIs(t, err, commitErr)
+	assert.ErrorContains(t, err, "failed to commit transaction")
+}
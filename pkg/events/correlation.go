@@ -0,0 +1,74 @@
+package events
+
+import (
+	"context"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/floroz/gavel/pkg/logging"
+)
+
+// Correlation headers let a user action be followed through every event it leads to, e.g. in logs
+const (
+	CorrelationIDHeader = "x-correlation-id"
+	CausationIDHeader   = "x-causation-id"
+)
+
+// Correlation ties an event to the user action it stems from. CorrelationID is shared by
+// everything the action leads to; CausationID names the request or message that directly caused the event.
+type Correlation struct {
+	CorrelationID string
+	CausationID   string
+}
+
+type correlationKey struct{}
+
+// ContextWithCorrelation returns a copy of ctx carrying c, for events published while handling it
+func ContextWithCorrelation(ctx context.Context, c Correlation) context.Context {
+	return context.WithValue(ctx, correlationKey{}, c)
+}
+
+// CorrelationFromContext returns the correlation set by ContextWithCorrelation. A request handled
+// behind the logging interceptor otherwise starts a correlation, with its request ID as both IDs.
+func CorrelationFromContext(ctx context.Context) Correlation {
+	if c, ok := ctx.Value(correlationKey{}).(Correlation); ok {
+		return c
+	}
+	if requestID, ok := logging.RequestID(ctx); ok {
+		return Correlation{CorrelationID: requestID, CausationID: requestID}
+	}
+	return Correlation{}
+}
+
+// CorrelationFromHeaders returns the correlation carried by a received message
+func CorrelationFromHeaders(headers amqp.Table) Correlation {
+	carrier := HeaderCarrier(headers)
+	return Correlation{
+		CorrelationID: carrier.Get(CorrelationIDHeader),
+		CausationID:   carrier.Get(CausationIDHeader),
+	}
+}
+
+// setHeaders adds the non-empty IDs of c to headers
+func (c Correlation) setHeaders(headers map[string]string) {
+	if c.CorrelationID != "" {
+		headers[CorrelationIDHeader] = c.CorrelationID
+	}
+	if c.CausationID != "" {
+		headers[CausationIDHeader] = c.CausationID
+	}
+}
+
+// EventHeaders returns the trace context and correlation of ctx as message headers, for events
+// that are published later, such as outbox events. It returns nil when ctx carries neither.
+func EventHeaders(ctx context.Context) map[string]string {
+	headers := TraceHeaders(ctx)
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	CorrelationFromContext(ctx).setHeaders(headers)
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
@@ -0,0 +1,33 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/floroz/gavel/pkg/events"
+)
+
+func TestEventHeaders_Correlation(t *testing.T) {
+	assert.Nil(t, events.EventHeaders(context.Background()), "an uncorrelated, untraced context has no headers")
+
+	ctx := events.ContextWithCorrelation(context.Background(), events.Correlation{
+		CorrelationID: "req-1",
+		CausationID:   "msg-7",
+	})
+	assert.Equal(t, map[string]string{
+		events.CorrelationIDHeader: "req-1",
+		events.CausationIDHeader:   "msg-7",
+	}, events.EventHeaders(ctx))
+}
+
+func TestCorrelationFromHeaders(t *testing.T) {
+	headers := amqp.Table{
+		events.CorrelationIDHeader: "req-1",
+		events.CausationIDHeader:   "msg-7",
+	}
+	assert.Equal(t, events.Correlation{CorrelationID: "req-1", CausationID: "msg-7"}, events.CorrelationFromHeaders(headers))
+	assert.Zero(t, events.CorrelationFromHeaders(nil))
+}
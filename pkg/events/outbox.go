@@ -25,24 +25,97 @@ const (
 // OutboxEvent represents a generic event to be stored in the database
 // Service-specific models can embed this or map to it
 type OutboxEvent struct {
-	ID          uuid.UUID    `db:"id"`
-	EventType   string       `db:"event_type"`
-	Payload     []byte       `db:"payload"`
-	Status      OutboxStatus `db:"status"`
-	CreatedAt   time.Time    `db:"created_at"`
-	ProcessedAt *time.Time   `db:"processed_at"`
+	ID uuid.UUID `db:"id"`
+	// AggregateID groups events that must be published in the order they were written,
+	// even across concurrent relays. uuid.Nil leaves the event unordered.
+	AggregateID uuid.UUID `db:"aggregate_id"`
+	EventType   string    `db:"event_type"`
+	Payload     []byte    `db:"payload"`
+	// Headers are published alongside the payload. They carry the trace context and correlation
+	// the event was written in, so consumers continue that trace rather than the relay's.
+	Headers     map[string]string `db:"headers"`
+	Status      OutboxStatus      `db:"status"`
+	CreatedAt   time.Time         `db:"created_at"`
+	ProcessedAt *time.Time        `db:"processed_at"`
+	// Priority is published as the message priority, e.g. PriorityHigh
+	Priority uint8 `db:"priority"`
 }
 
 // OutboxRepository defines the interface for interacting with the outbox table
-// Services must implement this interface or use a generic implementation
+// Services must implement this interface or use a generic implementation.
+//
+// GetPendingEvents claims events for the lifetime of tx: concurrent relays must get disjoint
+// batches, and an aggregate's events may only be claimed while none of its earlier ones are
+// claimed elsewhere, returned in write order.
 type OutboxRepository interface {
 	GetPendingEvents(ctx context.Context, tx pgx.Tx, limit int) ([]*OutboxEvent, error)
 	UpdateEventStatus(ctx context.Context, tx pgx.Tx, id uuid.UUID, status OutboxStatus) error
 }
 
+// Defaults for RunOutboxPruner
+const (
+	DefaultOutboxRetention     = 7 * 24 * time.Hour
+	DefaultOutboxPruneInterval = time.Hour
+)
+
+// OutboxPruner deletes published events that are only kept for troubleshooting
+type OutboxPruner interface {
+	DeletePublishedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
 // EventPublisher defines the interface for publishing events to a broker
 type EventPublisher interface {
-	Publish(ctx context.Context, exchange, routingKey string, body []byte) error
+	Publish(ctx context.Context, exchange, routingKey string, body []byte, opts ...PublishOption) error
+}
+
+// PublishOptions holds the optional message metadata set by PublishOption
+type PublishOptions struct {
+	// Headers are sent with the message. A W3C traceparent among them is continued when
+	// ctx carries no span of its own.
+	Headers map[string]string
+	// ContentType describes the body. Publishers default to ContentTypeProtobuf.
+	ContentType string
+	// Priority orders the message ahead of lower priority ones waiting in a priority queue
+	Priority uint8
+}
+
+// PublishOption configures a single Publish call
+type PublishOption func(*PublishOptions)
+
+// WithHeaders adds headers to the published message
+func WithHeaders(headers map[string]string) PublishOption {
+	return func(o *PublishOptions) {
+		if o.Headers == nil {
+			o.Headers = make(map[string]string, len(headers))
+		}
+		for key, value := range headers {
+			o.Headers[key] = value
+		}
+	}
+}
+
+// WithContentType sets the content type of the published message
+func WithContentType(contentType string) PublishOption {
+	return func(o *PublishOptions) {
+		o.ContentType = contentType
+	}
+}
+
+// WithPriority sets the message priority. Queues declared with a QueueSpec.MaxPriority deliver
+// higher priorities first, capped at their maximum; other queues ignore it.
+func WithPriority(priority uint8) PublishOption {
+	return func(o *PublishOptions) {
+		o.Priority = priority
+	}
+}
+
+// NewPublishOptions applies opts to an empty PublishOptions
+func NewPublishOptions(opts ...PublishOption) PublishOptions {
+	var o PublishOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
 }
 
 // OutboxRelay is a generic relay that polls the database for pending events and publishes them
@@ -123,7 +196,7 @@ func (r *OutboxRelay) processBatch(ctx context.Context) error {
 	for _, event := range events {
 		// Publish to RabbitMQ
 		// Exchange is configurable, Routing Key is the event type
-		err := r.publisher.Publish(ctx, r.exchange, event.EventType, event.Payload)
+		err := r.publisher.Publish(ctx, r.exchange, event.EventType, event.Payload, WithHeaders(event.Headers), WithPriority(event.Priority))
 		if err != nil {
 			// If publishing fails, we return error and the transaction rolls back.
 			// The event remains 'pending' and will be retried.
@@ -139,3 +212,25 @@ func (r *OutboxRelay) processBatch(ctx context.Context) error {
 
 	return tx.Commit(ctx)
 }
+
+// RunOutboxPruner deletes events published more than retention ago every interval until ctx is cancelled
+func RunOutboxPruner(ctx context.Context, pruner OutboxPruner, retention, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := pruner.DeletePublishedBefore(ctx, time.Now().Add(-retention))
+			if err != nil {
+				logger.Error("Failed to prune outbox", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				logger.Info("Pruned published outbox events", "count", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ContentTypeProtobuf is the content type of messages whose body is a marshaled protobuf event
+const ContentTypeProtobuf = "application/x-protobuf"
+
+// ProtoEventPublisher publishes protobuf events, keeping raw Publish for payloads
+// that are already marshaled, such as those relayed from the outbox
+type ProtoEventPublisher interface {
+	EventPublisher
+	// PublishEvent marshals event and publishes it under routingKey as ContentTypeProtobuf
+	PublishEvent(ctx context.Context, event proto.Message, routingKey string, opts ...PublishOption) error
+}
+
+var _ ProtoEventPublisher = (*ProtoPublisher)(nil)
+
+// ProtoPublisher publishes protobuf events to a single exchange through an EventPublisher
+type ProtoPublisher struct {
+	EventPublisher
+	exchange string
+}
+
+// NewProtoPublisher creates a ProtoPublisher that publishes to exchange through publisher
+func NewProtoPublisher(publisher EventPublisher, exchange string) *ProtoPublisher {
+	return &ProtoPublisher{EventPublisher: publisher, exchange: exchange}
+}
+
+// PublishEvent marshals event and publishes it under routingKey
+func (p *ProtoPublisher) PublishEvent(ctx context.Context, event proto.Message, routingKey string, opts ...PublishOption) error {
+	body, err := MarshalEvent(event)
+	if err != nil {
+		return err
+	}
+	opts = append(opts, WithContentType(ContentTypeProtobuf))
+	return p.Publish(ctx, p.exchange, routingKey, body, opts...)
+}
+
+// MarshalEvent serializes event for publishing or saving to the outbox
+func MarshalEvent(event proto.Message) ([]byte, error) {
+	body, err := proto.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,81 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/floroz/gavel/pkg/events"
+	pb "github.com/floroz/gavel/pkg/proto"
+)
+
+// fakePublisher records the last message passed to Publish
+type fakePublisher struct {
+	exchange   string
+	routingKey string
+	body       []byte
+	options    events.PublishOptions
+}
+
+func (p *fakePublisher) Publish(_ context.Context, exchange, routingKey string, body []byte, opts ...events.PublishOption) error {
+	p.exchange = exchange
+	p.routingKey = routingKey
+	p.body = body
+	p.options = events.NewPublishOptions(opts...)
+	return nil
+}
+
+func TestProtoPublisher(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("PublishEvent marshals the event", func(t *testing.T) {
+		fake := &fakePublisher{}
+		publisher := events.NewProtoPublisher(fake, "auction.events")
+
+		event := &pb.BidPlaced{
+			BidId:     "bid-1",
+			ItemId:    "item-1",
+			UserId:    "user-1",
+			Amount:    1500,
+			Timestamp: timestamppb.Now(),
+		}
+		err := publisher.PublishEvent(ctx, event, "bid.placed", events.WithHeaders(map[string]string{"k": "v"}))
+		require.NoError(t, err)
+
+		assert.Equal(t, "auction.events", fake.exchange)
+		assert.Equal(t, "bid.placed", fake.routingKey)
+		assert.Equal(t, events.ContentTypeProtobuf, fake.options.ContentType)
+		assert.Equal(t, map[string]string{"k": "v"}, fake.options.Headers)
+
+		var got pb.BidPlaced
+		require.NoError(t, proto.Unmarshal(fake.body, &got))
+		assert.True(t, proto.Equal(event, &got), "published body should round-trip to the event")
+	})
+
+	t.Run("Publish passes raw bodies through", func(t *testing.T) {
+		fake := &fakePublisher{}
+		publisher := events.NewProtoPublisher(fake, "auction.events")
+
+		err := publisher.Publish(ctx, "other.exchange", "raw", []byte("payload"), events.WithContentType("text/plain"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "other.exchange", fake.exchange)
+		assert.Equal(t, []byte("payload"), fake.body)
+		assert.Equal(t, "text/plain", fake.options.ContentType)
+	})
+}
+
+func TestMarshalEvent(t *testing.T) {
+	event := &pb.UserCreated{UserId: "user-1", Email: "a@example.com"}
+
+	body, err := events.MarshalEvent(event)
+	require.NoError(t, err)
+
+	var got pb.UserCreated
+	require.NoError(t, proto.Unmarshal(body, &got))
+	assert.True(t, proto.Equal(event, &got))
+}
@@ -2,60 +2,459 @@ package events
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/floroz/gavel/pkg/tracing"
+)
+
+// DefaultConfirmTimeout bounds how long Publish waits for a broker confirm
+// when the caller's context has no earlier deadline
+const DefaultConfirmTimeout = 5 * time.Second
+
+// DefaultCloseTimeout bounds how long Close waits for publishes under way to be confirmed
+const DefaultCloseTimeout = 10 * time.Second
+
+// Default reconnect backoff bounds
+const (
+	DefaultReconnectMinBackoff = 100 * time.Millisecond
+	DefaultReconnectMaxBackoff = 10 * time.Second
+)
+
+var (
+	// ErrPublishNacked is returned when the broker refuses to take responsibility for a message
+	ErrPublishNacked = errors.New("publish was not acknowledged by the broker")
+	// ErrPublishReturned is returned for mandatory messages that could not be routed to any queue
+	ErrPublishReturned = errors.New("publish was returned as unroutable")
+	// ErrPublisherClosed is returned by Publish after Close
+	ErrPublisherClosed = errors.New("publisher is closed")
+	// ErrUnconfirmedPublishes is returned by Close when publishes were still awaiting their
+	// confirms at the close timeout; the broker may or may not have those messages
+	ErrUnconfirmedPublishes = errors.New("publisher closed with publishes awaiting confirms")
 )
 
-// RabbitMQPublisher implements auction.EventPublisher
+// DialFunc opens a new broker connection
+type DialFunc func() (*amqp.Connection, error)
+
+// RabbitMQPublisher implements auction.EventPublisher.
+// If the channel or connection is lost it recovers in the background; Publish waits for recovery.
 type RabbitMQPublisher struct {
-	conn    *amqp.Connection
-	channel *amqp.Channel
+	// publishMu serializes Publish, since confirms are matched to publishes in order
+	publishMu sync.Mutex
+	// inflight counts Publish calls under way, which Close lets finish
+	inflight sync.WaitGroup
+
+	// mu guards the connection state below
+	mu       sync.Mutex
+	conn     *amqp.Connection
+	ownsConn bool // conn was dialed by the publisher and must be closed by it
+	channel  *amqp.Channel
+	returns  chan amqp.Return
+	ready    chan struct{} // closed while channel is usable
+	closing  bool          // Close was called; no new publishes are taken
+	closed   bool
+	done     chan struct{}
+
+	dial           DialFunc
+	mandatory      bool
+	confirmTimeout time.Duration
+	closeTimeout   time.Duration
+	minBackoff     time.Duration
+	maxBackoff     time.Duration
+	tracer         trace.Tracer
+}
+
+// PublisherOption configures optional RabbitMQPublisher behaviour
+type PublisherOption func(*RabbitMQPublisher)
+
+// WithMandatory publishes messages with the mandatory flag, so Publish fails
+// with ErrPublishReturned when no queue is bound for the routing key
+func WithMandatory() PublisherOption {
+	return func(p *RabbitMQPublisher) {
+		p.mandatory = true
+	}
 }
 
-// NewRabbitMQPublisher creates a new RabbitMQ publisher
-func NewRabbitMQPublisher(conn *amqp.Connection) (*RabbitMQPublisher, error) {
+// WithConfirmTimeout overrides DefaultConfirmTimeout. It also bounds how long
+// Publish waits for a lost channel to be recovered.
+func WithConfirmTimeout(timeout time.Duration) PublisherOption {
+	return func(p *RabbitMQPublisher) {
+		p.confirmTimeout = timeout
+	}
+}
+
+// WithCloseTimeout overrides DefaultCloseTimeout
+func WithCloseTimeout(timeout time.Duration) PublisherOption {
+	return func(p *RabbitMQPublisher) {
+		p.closeTimeout = timeout
+	}
+}
+
+// WithDialer lets the publisher re-dial the broker when the connection is lost.
+// Without it only the channel can be recovered.
+func WithDialer(dial DialFunc) PublisherOption {
+	return func(p *RabbitMQPublisher) {
+		p.dial = dial
+	}
+}
+
+// WithReconnectBackoff overrides the exponential backoff bounds used while recovering
+func WithReconnectBackoff(minBackoff, maxBackoff time.Duration) PublisherOption {
+	return func(p *RabbitMQPublisher) {
+		p.minBackoff = minBackoff
+		p.maxBackoff = maxBackoff
+	}
+}
+
+// WithPublisherTracerProvider records publish spans with tp instead of the global tracer provider
+func WithPublisherTracerProvider(tp trace.TracerProvider) PublisherOption {
+	return func(p *RabbitMQPublisher) {
+		p.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// NewRabbitMQPublisher creates a new RabbitMQ publisher.
+// The channel is put into confirm mode so Publish only succeeds once the broker has the message.
+func NewRabbitMQPublisher(conn *amqp.Connection, opts ...PublisherOption) (*RabbitMQPublisher, error) {
+	p := &RabbitMQPublisher{
+		conn:           conn,
+		ready:          make(chan struct{}),
+		done:           make(chan struct{}),
+		confirmTimeout: DefaultConfirmTimeout,
+		closeTimeout:   DefaultCloseTimeout,
+		minBackoff:     DefaultReconnectMinBackoff,
+		maxBackoff:     DefaultReconnectMaxBackoff,
+		tracer:         otel.Tracer(tracerName),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	ch, returns, err := openPublishChannel(conn)
+	if err != nil {
+		return nil, err
+	}
+	p.channel = ch
+	p.returns = returns
+	close(p.ready)
+
+	go p.watch(conn, ch)
+	return p, nil
+}
+
+// openPublishChannel opens a confirm-mode channel and makes sure the exchange exists
+func openPublishChannel(conn *amqp.Connection) (*amqp.Channel, chan amqp.Return, error) {
 	ch, err := conn.Channel()
 	if err != nil {
-		return nil, fmt.Errorf("failed to open channel: %w", err)
+		return nil, nil, fmt.Errorf("failed to open channel: %w", err)
 	}
 
 	// Ensure the exchange exists
-	err = ch.ExchangeDeclare(
-		"auction.events", // name
-		"topic",          // type
-		true,             // durable
-		false,            // auto-deleted
-		false,            // internal
-		false,            // no-wait
-		nil,              // arguments
-	)
-	if err != nil {
+	if err := declareExchange(ch, Exchange, amqp.ExchangeTopic); err != nil {
+		ch.Close()
+		return nil, nil, err
+	}
+
+	if err := ch.Confirm(false); err != nil {
 		ch.Close()
-		return nil, fmt.Errorf("failed to declare exchange: %w", err)
+		return nil, nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
 	}
 
-	return &RabbitMQPublisher{
-		conn:    conn,
-		channel: ch,
-	}, nil
+	return ch, ch.NotifyReturn(make(chan amqp.Return, 16)), nil
 }
 
-// Close closes the channel
+// watch waits for the channel or its connection to close and then recovers
+func (p *RabbitMQPublisher) watch(conn *amqp.Connection, ch *amqp.Channel) {
+	connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	chanClosed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	select {
+	case <-p.done:
+		return
+	case <-connClosed:
+	case <-chanClosed:
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.ready = make(chan struct{})
+	p.mu.Unlock()
+
+	p.recover()
+}
+
+// recover re-establishes the channel, re-dialing the connection if needed, with exponential backoff
+func (p *RabbitMQPublisher) recover() {
+	backoff := p.minBackoff
+	for {
+		conn, ch, returns, err := p.reconnect()
+		if err == nil {
+			p.mu.Lock()
+			if p.closed {
+				p.mu.Unlock()
+				ch.Close()
+				return
+			}
+			p.channel = ch
+			p.returns = returns
+			close(p.ready)
+			p.mu.Unlock()
+
+			go p.watch(conn, ch)
+			return
+		}
+
+		select {
+		case <-p.done:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, p.maxBackoff)
+	}
+}
+
+func (p *RabbitMQPublisher) reconnect() (*amqp.Connection, *amqp.Channel, chan amqp.Return, error) {
+	p.mu.Lock()
+	conn := p.conn
+	p.mu.Unlock()
+
+	if conn.IsClosed() {
+		if p.dial == nil {
+			return nil, nil, nil, errors.New("connection closed and no dialer configured")
+		}
+		newConn, err := p.dial()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to dial: %w", err)
+		}
+
+		p.mu.Lock()
+		if p.ownsConn {
+			_ = p.conn.Close()
+		}
+		p.conn = newConn
+		p.ownsConn = true
+		p.mu.Unlock()
+		conn = newConn
+	}
+
+	ch, returns, err := openPublishChannel(conn)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return conn, ch, returns, nil
+}
+
+// awaitChannel returns the current channel, waiting for recovery if it is down
+func (p *RabbitMQPublisher) awaitChannel(ctx context.Context) (*amqp.Channel, chan amqp.Return, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, nil, ErrPublisherClosed
+		}
+		ready := p.ready
+		ch, returns := p.channel, p.returns
+		p.mu.Unlock()
+
+		select {
+		case <-ready:
+			if !ch.IsClosed() {
+				return ch, returns, nil
+			}
+			// Closed but the watcher has not noticed yet; give it a moment
+			select {
+			case <-ctx.Done():
+				return nil, nil, fmt.Errorf("waiting for channel recovery: %w", ctx.Err())
+			case <-time.After(10 * time.Millisecond):
+			}
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("waiting for channel recovery: %w", ctx.Err())
+		}
+	}
+}
+
+// Connected reports whether the publisher has an open channel to publish on. It is false while
+// the channel, or the connection it re-dials, is being recovered, and once the publisher is closed.
+func (p *RabbitMQPublisher) Connected() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+	select {
+	case <-p.ready:
+		return !p.channel.IsClosed()
+	default:
+		return false
+	}
+}
+
+// Close stops taking new publishes and waits, up to the close timeout, for the ones under way to
+// be confirmed, so the last events are not dropped at shutdown. It then closes the channel, and
+// the connection if the publisher dialed it. If some publishes are still unconfirmed at the
+// timeout, Close closes anyway and returns ErrUnconfirmedPublishes.
 func (p *RabbitMQPublisher) Close() error {
-	return p.channel.Close()
+	p.mu.Lock()
+	if p.closing {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closing = true
+	p.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		p.inflight.Wait()
+		close(drained)
+	}()
+	var drainErr error
+	select {
+	case <-drained:
+	case <-time.After(p.closeTimeout):
+		drainErr = ErrUnconfirmedPublishes
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	close(p.done)
+
+	err := p.channel.Close()
+	if p.ownsConn {
+		if connErr := p.conn.Close(); connErr != nil && err == nil {
+			err = connErr
+		}
+	}
+	if errors.Is(err, amqp.ErrClosed) {
+		err = nil
+	}
+	return errors.Join(drainErr, err)
+}
+
+// Publish publishes a message to the broker and waits for it to be confirmed.
+// The trace context of ctx travels in the message headers, so consumers can continue the trace.
+// When ctx is not traced, the trace context in the WithHeaders headers is continued instead.
+// The correlation of ctx is sent too, unless the WithHeaders headers already carry one,
+// and so is SchemaVersion, unless they carry SchemaVersionHeader.
+func (p *RabbitMQPublisher) Publish(ctx context.Context, exchange, routingKey string, body []byte, opts ...PublishOption) error {
+	p.mu.Lock()
+	if p.closing {
+		p.mu.Unlock()
+		return ErrPublisherClosed
+	}
+	p.inflight.Add(1)
+	p.mu.Unlock()
+	defer p.inflight.Done()
+
+	options := NewPublishOptions(opts...)
+	if _, ok := options.Headers[CorrelationIDHeader]; !ok {
+		// Published straight from a request rather than through the outbox
+		correlation := map[string]string{}
+		CorrelationFromContext(ctx).setHeaders(correlation)
+		WithHeaders(correlation)(&options)
+	}
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		// e.g. an outbox event relayed outside the request that wrote it
+		ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(options.Headers))
+	}
+
+	messageID := uuid.NewString()
+	ctx, span := p.tracer.Start(ctx, routingKey+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", exchange),
+			attribute.String("messaging.rabbitmq.destination.routing_key", routingKey),
+			attribute.String("messaging.message.id", messageID),
+		),
+	)
+	defer span.End()
+
+	err := p.publish(ctx, exchange, routingKey, messageID, body, options)
+	tracing.RecordError(span, err)
+	return err
 }
 
-// Publish publishes a message to the broker
-func (p *RabbitMQPublisher) Publish(ctx context.Context, exchange, routingKey string, body []byte) error {
-	return p.channel.PublishWithContext(ctx,
-		exchange,   // exchange
-		routingKey, // routing key
-		false,      // mandatory
-		false,      // immediate
+func (p *RabbitMQPublisher) publish(ctx context.Context, exchange, routingKey, messageID string, body []byte, options PublishOptions) error {
+	headers := amqp.Table{}
+	for key, value := range options.Headers {
+		headers[key] = value
+	}
+	// The publish span replaces any trace context passed in the options
+	otel.GetTextMapPropagator().Inject(ctx, HeaderCarrier(headers))
+	if _, ok := headers[SchemaVersionHeader]; !ok {
+		headers[SchemaVersionHeader] = strconv.Itoa(SchemaVersion)
+	}
+
+	contentType := options.ContentType
+	if contentType == "" {
+		contentType = ContentTypeProtobuf
+	}
+
+	p.publishMu.Lock()
+	defer p.publishMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, p.confirmTimeout)
+	defer cancel()
+
+	ch, returns, err := p.awaitChannel(ctx)
+	if err != nil {
+		return err
+	}
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx,
+		exchange,    // exchange
+		routingKey,  // routing key
+		p.mandatory, // mandatory
+		false,       // immediate
 		amqp.Publishing{
-			ContentType: "application/x-protobuf",
+			ContentType: contentType,
+			Priority:    options.Priority,
+			MessageId:   messageID,
+			Headers:     headers,
 			Body:        body,
 		},
 	)
+	if err != nil {
+		return fmt.Errorf("failed to publish: %w", err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed waiting for publish confirm: %w", err)
+	}
+	if !acked {
+		// Also happens when the broker closes the channel, e.g. for an unknown exchange
+		return ErrPublishNacked
+	}
+
+	// The broker sends basic.return before the ack, so any return for this message is already queued
+	for {
+		select {
+		case ret, ok := <-returns:
+			if !ok {
+				return nil // Channel closed after the ack
+			}
+			if ret.MessageId == messageID {
+				return fmt.Errorf("%w: %s", ErrPublishReturned, ret.ReplyText)
+			}
+			// A return for an earlier publish that timed out; drop it
+		default:
+			return nil
+		}
+	}
 }
@@ -0,0 +1,279 @@
+package events_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/rabbitmq"
+
+	"github.com/floroz/gavel/pkg/events"
+)
+
+// startRabbitMQ runs a broker container and returns its AMQP URL
+func startRabbitMQ(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+
+	rabbitmqContainer, err := rabbitmq.Run(ctx,
+		"rabbitmq:3.12-management-alpine",
+		rabbitmq.WithAdminPassword("password"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if termErr := rabbitmqContainer.Terminate(ctx); termErr != nil {
+			t.Fatalf("failed to terminate container: %s", termErr)
+		}
+	})
+
+	amqpURL, err := rabbitmqContainer.AmqpURL(ctx)
+	require.NoError(t, err)
+	return amqpURL
+}
+
+func TestRabbitMQPublisher_Confirms(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	amqpURL := startRabbitMQ(t)
+
+	conn, err := amqp.Dial(amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Bind a queue so "test.routed" is routable
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+	defer ch.Close()
+
+	t.Run("confirmed publish succeeds", func(t *testing.T) {
+		publisher, err := events.NewRabbitMQPublisher(conn, events.WithMandatory())
+		require.NoError(t, err)
+		defer publisher.Close()
+
+		q, err := ch.QueueDeclare("", false, true, true, false, nil)
+		require.NoError(t, err)
+		require.NoError(t, ch.QueueBind(q.Name, "test.routed", "auction.events", false, nil))
+
+		err = publisher.Publish(ctx, "auction.events", "test.routed", []byte("payload"))
+		require.NoError(t, err)
+
+		msg, ok, err := ch.Get(q.Name, true)
+		require.NoError(t, err)
+		require.True(t, ok, "message should be in the queue")
+		assert.Equal(t, []byte("payload"), msg.Body)
+	})
+
+	t.Run("unknown exchange with mandatory returns error", func(t *testing.T) {
+		publisher, err := events.NewRabbitMQPublisher(conn, events.WithMandatory())
+		require.NoError(t, err)
+		defer publisher.Close()
+
+		err = publisher.Publish(ctx, "does.not.exist", "test.routed", []byte("payload"))
+		require.Error(t, err)
+	})
+
+	t.Run("unroutable with mandatory returns error", func(t *testing.T) {
+		publisher, err := events.NewRabbitMQPublisher(conn, events.WithMandatory())
+		require.NoError(t, err)
+		defer publisher.Close()
+
+		err = publisher.Publish(ctx, "auction.events", "test.nobody.listens", []byte("payload"))
+		assert.ErrorIs(t, err, events.ErrPublishReturned)
+	})
+
+	t.Run("unroutable without mandatory is confirmed", func(t *testing.T) {
+		publisher, err := events.NewRabbitMQPublisher(conn)
+		require.NoError(t, err)
+		defer publisher.Close()
+
+		err = publisher.Publish(ctx, "auction.events", "test.nobody.listens", []byte("payload"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("expired context returns error", func(t *testing.T) {
+		publisher, err := events.NewRabbitMQPublisher(conn)
+		require.NoError(t, err)
+		defer publisher.Close()
+
+		expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+		defer cancel()
+
+		err = publisher.Publish(expired, "auction.events", "test.routed", []byte("payload"))
+		assert.Error(t, err)
+	})
+}
+
+func TestRabbitMQPublisher_Recovery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	amqpURL := startRabbitMQ(t)
+
+	dial := func() (*amqp.Connection, error) { return amqp.Dial(amqpURL) }
+
+	t.Run("recovers after the connection is closed", func(t *testing.T) {
+		conn, err := dial()
+		require.NoError(t, err)
+
+		publisher, err := events.NewRabbitMQPublisher(conn,
+			events.WithDialer(dial),
+			events.WithReconnectBackoff(10*time.Millisecond, 100*time.Millisecond),
+		)
+		require.NoError(t, err)
+		defer publisher.Close()
+
+		require.NoError(t, publisher.Publish(ctx, "auction.events", "test.recovery", []byte("before")))
+
+		// Simulate the broker going away
+		require.NoError(t, conn.Close())
+
+		err = publisher.Publish(ctx, "auction.events", "test.recovery", []byte("after"))
+		require.NoError(t, err, "publish should wait for recovery and succeed")
+		assert.True(t, publisher.Connected(), "the re-dialed connection counts")
+	})
+
+	t.Run("recovers the channel after a channel error", func(t *testing.T) {
+		conn, err := dial()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		publisher, err := events.NewRabbitMQPublisher(conn,
+			events.WithReconnectBackoff(10*time.Millisecond, 100*time.Millisecond),
+		)
+		require.NoError(t, err)
+		defer publisher.Close()
+
+		// Publishing to an unknown exchange makes the broker close the channel
+		require.Error(t, publisher.Publish(ctx, "does.not.exist", "test.recovery", []byte("payload")))
+
+		err = publisher.Publish(ctx, "auction.events", "test.recovery", []byte("after"))
+		require.NoError(t, err)
+	})
+
+	t.Run("publish fails once the wait exceeds the confirm timeout", func(t *testing.T) {
+		conn, err := dial()
+		require.NoError(t, err)
+
+		// No dialer, so the closed connection can never be recovered
+		publisher, err := events.NewRabbitMQPublisher(conn,
+			events.WithConfirmTimeout(200*time.Millisecond),
+			events.WithReconnectBackoff(10*time.Millisecond, 50*time.Millisecond),
+		)
+		require.NoError(t, err)
+		defer publisher.Close()
+
+		require.NoError(t, conn.Close())
+
+		err = publisher.Publish(ctx, "auction.events", "test.recovery", []byte("payload"))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.False(t, publisher.Connected())
+	})
+
+	t.Run("publish after close fails", func(t *testing.T) {
+		conn, err := dial()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		publisher, err := events.NewRabbitMQPublisher(conn)
+		require.NoError(t, err)
+		require.NoError(t, publisher.Close())
+
+		err = publisher.Publish(ctx, "auction.events", "test.recovery", []byte("payload"))
+		assert.ErrorIs(t, err, events.ErrPublisherClosed)
+	})
+}
+
+func TestRabbitMQPublisher_CloseDrainsInflight(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	amqpURL := startRabbitMQ(t)
+
+	t.Run("confirmed publishes survive close", func(t *testing.T) {
+		conn, err := amqp.Dial(amqpURL)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		ch, err := conn.Channel()
+		require.NoError(t, err)
+		defer ch.Close()
+		q, err := ch.QueueDeclare("", false, true, true, false, nil)
+		require.NoError(t, err)
+		require.NoError(t, ch.QueueBind(q.Name, "test.drain", "auction.events", false, nil))
+
+		publisher, err := events.NewRabbitMQPublisher(conn)
+		require.NoError(t, err)
+
+		// Close while publishes are still queued up behind each other
+		const publishes = 50
+		errs := make([]error, publishes)
+		var wg sync.WaitGroup
+		for i := range publishes {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = publisher.Publish(ctx, "auction.events", "test.drain", fmt.Appendf(nil, "msg-%d", i))
+			}()
+		}
+		time.Sleep(5 * time.Millisecond)
+		require.NoError(t, publisher.Close())
+		wg.Wait()
+
+		confirmed := map[string]bool{}
+		for i, err := range errs {
+			if err == nil {
+				confirmed[fmt.Sprintf("msg-%d", i)] = true
+			} else {
+				assert.ErrorIs(t, err, events.ErrPublisherClosed, "only publishes started after Close may fail")
+			}
+		}
+		require.NotEmpty(t, confirmed)
+
+		received := map[string]bool{}
+		for {
+			msg, ok, err := ch.Get(q.Name, true)
+			require.NoError(t, err)
+			if !ok {
+				break
+			}
+			received[string(msg.Body)] = true
+		}
+		for body := range confirmed {
+			assert.True(t, received[body], "confirmed %s was lost", body)
+		}
+	})
+
+	t.Run("close gives up on publishes that cannot be confirmed", func(t *testing.T) {
+		conn, err := amqp.Dial(amqpURL)
+		require.NoError(t, err)
+
+		// No dialer, so the publish waits for a recovery that never comes
+		publisher, err := events.NewRabbitMQPublisher(conn,
+			events.WithConfirmTimeout(2*time.Second),
+			events.WithCloseTimeout(100*time.Millisecond),
+		)
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+
+		published := make(chan error, 1)
+		go func() {
+			published <- publisher.Publish(ctx, "auction.events", "test.drain", []byte("payload"))
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		assert.ErrorIs(t, publisher.Close(), events.ErrUnconfirmedPublishes)
+		assert.Error(t, <-published)
+	})
+}
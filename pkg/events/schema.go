@@ -0,0 +1,67 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// SchemaVersionHeader carries the schema version of a message's payload
+const SchemaVersionHeader = "x-schema-version"
+
+// SchemaVersion is the version of the event schemas in pkg/proto. Protobuf tolerates added and
+// removed fields, so bump it only for a change existing consumers would misread, such as a field
+// whose meaning or unit changes; consumers still on the old version then dead-letter the new
+// events instead of applying them wrongly.
+const SchemaVersion = 1
+
+// ErrUnsupportedSchemaVersion is returned for a message published with a schema version
+// this build does not understand
+var ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+
+// CheckSchemaVersion reports whether a message with headers can be read with SchemaVersion.
+// Messages without the header were published before it was introduced, at version 1.
+// The version may be a string, as this package publishes it, or any integer type, as other
+// AMQP clients encode numeric headers.
+func CheckSchemaVersion(headers amqp.Table) error {
+	value, ok := headers[SchemaVersionHeader]
+	if !ok {
+		return nil
+	}
+	version, ok := schemaVersion(value)
+	if !ok || version != SchemaVersion {
+		return fmt.Errorf("%w: %v, expected %d", ErrUnsupportedSchemaVersion, value, SchemaVersion)
+	}
+	return nil
+}
+
+// schemaVersion reads a schema version header value
+func schemaVersion(value any) (int64, bool) {
+	switch v := value.(type) {
+	case string:
+		version, err := strconv.ParseInt(v, 10, 64)
+		return version, err == nil
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(min(v, math.MaxInt64)), true
+	}
+	return 0, false
+}
@@ -0,0 +1,37 @@
+package events_test
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/floroz/gavel/pkg/events"
+)
+
+func TestCheckSchemaVersion(t *testing.T) {
+	tests := map[string]struct {
+		headers amqp.Table
+		wantErr bool
+	}{
+		"current version":             {headers: amqp.Table{events.SchemaVersionHeader: "1"}},
+		"no header predates versions": {headers: nil},
+		"newer version":               {headers: amqp.Table{events.SchemaVersionHeader: "2"}, wantErr: true},
+		"not a number":                {headers: amqp.Table{events.SchemaVersionHeader: "v1"}, wantErr: true},
+		"int32 version":               {headers: amqp.Table{events.SchemaVersionHeader: int32(1)}},
+		"int64 version":               {headers: amqp.Table{events.SchemaVersionHeader: int64(1)}},
+		"uint8 version":               {headers: amqp.Table{events.SchemaVersionHeader: uint8(1)}},
+		"newer integer version":       {headers: amqp.Table{events.SchemaVersionHeader: int16(2)}, wantErr: true},
+		"not a version":               {headers: amqp.Table{events.SchemaVersionHeader: 1.0}, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := events.CheckSchemaVersion(tt.headers)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, events.ErrUnsupportedSchemaVersion)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Exchange is the topic exchange every domain event is published to
+const Exchange = "auction.events"
+
+// Routing keys events are published under on Exchange. The outbox relay uses an event's
+// type as its routing key, so these double as the outbox event types.
+const (
+	RoutingKeyUserCreated   = "user.created"
+	RoutingKeyUserLoggedIn  = "user.logged_in"
+	RoutingKeyBidPlaced     = "bid.placed"
+	RoutingKeyItemPurchased = "item.purchased"
+	RoutingKeyUserOutbid    = "user.outbid"
+	RoutingKeyItemCancelled = "item.cancelled"
+	RoutingKeyAuctionEnded  = "auction.ended"
+)
+
+// DeadLetterExchange receives deliveries that were rejected or ran out of retries.
+// Each queue gets a "<queue>.dlq" bound to it under the queue's own name.
+const DeadLetterExchange = "auction.events.dlx"
+
+// Message priorities. PriorityHigh is for time-sensitive events, such as bids on an auction
+// about to close, that should overtake a backlog in queues declared with MaxPriority.
+const (
+	PriorityNormal uint8 = 0
+	PriorityHigh   uint8 = 5
+)
+
+// ErrTopologyMismatch is returned when an exchange or queue already exists on the broker
+// with settings other than the ones declared, e.g. a different durability or queue type
+var ErrTopologyMismatch = errors.New("broker topology mismatch")
+
+// TopologyChannel is the part of *amqp.Channel that declares exchanges, queues and bindings
+type TopologyChannel interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+}
+
+// QueueSpec describes a durable queue bound to Exchange
+type QueueSpec struct {
+	Name        string
+	RoutingKeys []string
+	// Exchange overrides the topic exchange the routing keys are bound on, e.g. to keep
+	// the events of an isolated environment apart on a shared broker
+	Exchange string
+	// MaxRetries is how many times a failing delivery is redelivered before it is routed
+	// to the queue's dead-letter queue
+	MaxRetries int
+	// MaxPriority makes this a priority queue, delivering messages with a higher priority, up to
+	// MaxPriority, ahead of those already waiting. RabbitMQ 3 only supports priorities on classic
+	// queues, which do not count redeliveries, so the broker does not enforce MaxRetries for these:
+	// their consumers must retry through DelayedRetry, which counts attempts in a header, or
+	// reject deliveries that keep failing. An existing queue cannot be switched to or from a
+	// priority queue; a new one has to replace it, see Topology.Retired.
+	MaxPriority uint8
+	// DelayedRetry also declares RetryQueue(Name), which routes each message back to this queue
+	// once the message's own TTL expires. Consumers retry a failed delivery after a delay by
+	// republishing it there with an expiration, instead of requeueing it straight away.
+	DelayedRetry bool
+}
+
+// RetryQueue is the queue deliveries of queue wait in before they are retried, see QueueSpec.DelayedRetry
+func RetryQueue(queue string) string {
+	return queue + ".retry"
+}
+
+// Topology is everything a service needs on the broker: Exchange, and the queues it
+// consumes with their dead-letter queues and bindings
+type Topology struct {
+	Queues []QueueSpec
+	// Retired are queues replaced by Queues, e.g. classic queues superseded by quorum queues
+	// under new names, since an existing queue cannot change type. DeclareTopology unbinds them
+	// so they take no new messages, and deletes each once the previous deployment has drained
+	// it and stopped consuming. Until then the events published before the switch stay in it.
+	Retired []QueueSpec
+}
+
+// Declare declares the whole topology. Declaring what already exists with the same settings
+// is a no-op, while the broker refuses anything declared differently; that is reported as
+// ErrTopologyMismatch naming the exchange or queue, instead of surfacing later as lost messages.
+// A passive declare would only check that a queue exists, not how it is set up.
+//
+// The broker closes the channel on a mismatch, so Declare is best run on a channel of its own,
+// as DeclareTopology does.
+func (t Topology) Declare(ch TopologyChannel) error {
+	if err := declareExchange(ch, Exchange, amqp.ExchangeTopic); err != nil {
+		return err
+	}
+	if len(t.Queues) > 0 {
+		if err := declareExchange(ch, DeadLetterExchange, amqp.ExchangeDirect); err != nil {
+			return err
+		}
+	}
+	for _, q := range t.Queues {
+		if err := declareQueue(ch, q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeclareTopology declares t on a channel of its own, so services can check the broker
+// on startup and fail fast before consuming or publishing
+func DeclareTopology(conn *amqp.Connection, t Topology) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	err = t.Declare(ch)
+	if closeErr := ch.Close(); closeErr != nil && !errors.Is(closeErr, amqp.ErrClosed) && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	for _, q := range t.Retired {
+		if err := retireQueue(conn, q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retireQueue unbinds q and deletes it if it is empty and unused. A queue that is already gone,
+// or still has messages or consumers, is not an error: it is deleted on a later startup.
+// Each broker refusal closes the channel, so q gets a channel of its own.
+func retireQueue(conn *amqp.Connection, q QueueSpec) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+
+	// Unbinding a queue that does not exist would fail, so check it still does
+	if _, err := ch.QueueDeclarePassive(q.Name, true, false, false, false, nil); err != nil {
+		return retireError(q.Name, err)
+	}
+	exchange := Exchange
+	if q.Exchange != "" {
+		exchange = q.Exchange
+	}
+	for _, key := range q.RoutingKeys {
+		if err := ch.QueueUnbind(q.Name, key, exchange, nil); err != nil {
+			return retireError(q.Name, err)
+		}
+	}
+	if _, err := ch.QueueDelete(q.Name, true, true, false); err != nil {
+		return retireError(q.Name, err)
+	}
+	return nil
+}
+
+// retireError is the error retiring queue failed with, or nil when the broker only refused
+// because the queue is gone or still in use
+func retireError(queue string, err error) error {
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && (amqpErr.Code == amqp.NotFound || amqpErr.Code == amqp.PreconditionFailed) {
+		return nil
+	}
+	return fmt.Errorf("failed to retire queue %q: %w", queue, err)
+}
+
+func declareExchange(ch TopologyChannel, name, kind string) error {
+	err := ch.ExchangeDeclare(
+		name,  // name
+		kind,  // type
+		true,  // durable
+		false, // auto-deleted
+		false, // internal
+		false, // no-wait
+		nil,   // args
+	)
+	if err != nil {
+		return topologyError("exchange", name, err)
+	}
+	return nil
+}
+
+// declareQueue declares q as a quorum queue whose rejected deliveries, and deliveries
+// requeued more than q.MaxRetries times, are routed to "<queue>.dlq". A priority queue
+// is a classic queue instead, dead-lettering rejected deliveries only.
+func declareQueue(ch TopologyChannel, q QueueSpec) error {
+	dlq := q.Name + ".dlq"
+	_, err := ch.QueueDeclare(
+		dlq,   // name
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		nil,   // args
+	)
+	if err != nil {
+		return topologyError("queue", dlq, err)
+	}
+	if err := ch.QueueBind(dlq, q.Name, DeadLetterExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind queue %q: %w", dlq, err)
+	}
+
+	args := amqp.Table{
+		"x-dead-letter-exchange":    DeadLetterExchange,
+		"x-dead-letter-routing-key": q.Name,
+	}
+	if q.MaxPriority > 0 {
+		args[amqp.QueueTypeArg] = amqp.QueueTypeClassic
+		args["x-max-priority"] = q.MaxPriority
+	} else {
+		// Quorum queues track redeliveries themselves, so the retry budget survives consumer restarts
+		args[amqp.QueueTypeArg] = amqp.QueueTypeQuorum
+		args["x-delivery-limit"] = q.MaxRetries
+	}
+	_, err = ch.QueueDeclare(
+		q.Name, // name
+		true,   // durable
+		false,  // delete when unused
+		false,  // exclusive
+		false,  // no-wait
+		args,
+	)
+	if err != nil {
+		return topologyError("queue", q.Name, err)
+	}
+	if q.DelayedRetry {
+		if err := declareRetryQueue(ch, q.Name); err != nil {
+			return err
+		}
+	}
+	exchange := Exchange
+	if q.Exchange != "" && q.Exchange != Exchange {
+		exchange = q.Exchange
+		if err := declareExchange(ch, exchange, amqp.ExchangeTopic); err != nil {
+			return err
+		}
+	}
+	for _, key := range q.RoutingKeys {
+		if err := ch.QueueBind(q.Name, key, exchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue %q to %q: %w", q.Name, key, err)
+		}
+	}
+	return nil
+}
+
+// declareRetryQueue declares the retry queue of queue. It has no consumers: expired messages
+// are dead-lettered through the default exchange, which routes them to queue by its name.
+// Messages only expire at the head of the queue, so one with a long delay holds back those
+// behind it; retries may come later than asked for, but never sooner.
+func declareRetryQueue(ch TopologyChannel, queue string) error {
+	retry := RetryQueue(queue)
+	_, err := ch.QueueDeclare(
+		retry, // name
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		amqp.Table{
+			amqp.QueueTypeArg:           amqp.QueueTypeClassic,
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": queue,
+		},
+	)
+	if err != nil {
+		return topologyError("queue", retry, err)
+	}
+	return nil
+}
+
+// topologyError reports a declaration the broker refused because of existing settings as ErrTopologyMismatch
+func topologyError(kind, name string, err error) error {
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.PreconditionFailed {
+		return fmt.Errorf("%w: %s %q exists with different settings: %s", ErrTopologyMismatch, kind, name, amqpErr.Reason)
+	}
+	return fmt.Errorf("failed to declare %s %q: %w", kind, name, err)
+}
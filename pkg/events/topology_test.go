@@ -0,0 +1,160 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/events"
+)
+
+func TestTopology_Declare(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	amqpURL := startRabbitMQ(t)
+	conn, err := amqp.Dial(amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	topology := events.Topology{Queues: []events.QueueSpec{{
+		Name:        "test_topology",
+		RoutingKeys: []string{"test.topology"},
+		MaxRetries:  3,
+	}}}
+
+	t.Run("declares queues and bindings", func(t *testing.T) {
+		require.NoError(t, events.DeclareTopology(conn, topology))
+		require.NoError(t, events.DeclareTopology(conn, topology), "declaring again is a no-op")
+
+		publisher, err := events.NewRabbitMQPublisher(conn, events.WithMandatory())
+		require.NoError(t, err)
+		defer publisher.Close()
+		require.NoError(t, publisher.Publish(context.Background(), events.Exchange, "test.topology", []byte("payload")),
+			"the routing key is bound")
+	})
+
+	t.Run("priority queue delivers higher priorities first", func(t *testing.T) {
+		prioritized := events.Topology{Queues: []events.QueueSpec{{
+			Name:        "test_topology_priority",
+			RoutingKeys: []string{"test.priority"},
+			MaxPriority: events.PriorityHigh,
+		}}}
+		require.NoError(t, events.DeclareTopology(conn, prioritized))
+
+		publisher, err := events.NewRabbitMQPublisher(conn)
+		require.NoError(t, err)
+		defer publisher.Close()
+
+		// A backlog of normal messages, then an urgent one published after it
+		ctx := context.Background()
+		for _, body := range []string{"normal-1", "normal-2"} {
+			require.NoError(t, publisher.Publish(ctx, events.Exchange, "test.priority", []byte(body)))
+		}
+		require.NoError(t, publisher.Publish(ctx, events.Exchange, "test.priority", []byte("urgent"),
+			events.WithPriority(events.PriorityHigh)))
+
+		ch, err := conn.Channel()
+		require.NoError(t, err)
+		defer ch.Close()
+
+		var got []string
+		for range 3 {
+			d, ok, err := ch.Get("test_topology_priority", true)
+			require.NoError(t, err)
+			require.True(t, ok)
+			got = append(got, string(d.Body))
+		}
+		assert.Equal(t, []string{"urgent", "normal-1", "normal-2"}, got)
+	})
+
+	t.Run("retry queue routes messages back once they expire", func(t *testing.T) {
+		delayed := events.Topology{Queues: []events.QueueSpec{{
+			Name:         "test_topology_retry",
+			RoutingKeys:  []string{"test.retry"},
+			MaxRetries:   3,
+			DelayedRetry: true,
+		}}}
+		require.NoError(t, events.DeclareTopology(conn, delayed))
+
+		ch, err := conn.Channel()
+		require.NoError(t, err)
+		defer ch.Close()
+
+		ctx := context.Background()
+		require.NoError(t, ch.PublishWithContext(ctx, "", events.RetryQueue("test_topology_retry"), false, false, amqp.Publishing{
+			Expiration: "200",
+			Body:       []byte("retried"),
+		}))
+
+		_, ok, err := ch.Get("test_topology_retry", true)
+		require.NoError(t, err)
+		assert.False(t, ok, "the message waits out its delay")
+
+		require.Eventually(t, func() bool {
+			d, ok, err := ch.Get("test_topology_retry", true)
+			return err == nil && ok && string(d.Body) == "retried"
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("mismatched existing queue fails with a clear error", func(t *testing.T) {
+		// Left over from an older deployment as a classic queue without dead-lettering
+		ch, err := conn.Channel()
+		require.NoError(t, err)
+		_, err = ch.QueueDeclare("test_topology_stale", true, false, false, false, nil)
+		require.NoError(t, err)
+		require.NoError(t, ch.Close())
+
+		stale := events.Topology{Queues: []events.QueueSpec{{Name: "test_topology_stale", MaxRetries: 3}}}
+		err = events.DeclareTopology(conn, stale)
+		require.ErrorIs(t, err, events.ErrTopologyMismatch)
+		assert.Contains(t, err.Error(), `queue "test_topology_stale"`)
+	})
+
+	t.Run("retired queue is unbound and deleted once drained", func(t *testing.T) {
+		// A classic queue of an older deployment, with an event it has not consumed yet
+		ch, err := conn.Channel()
+		require.NoError(t, err)
+		defer ch.Close()
+		_, err = ch.QueueDeclare("test_topology_legacy", true, false, false, false, nil)
+		require.NoError(t, err)
+		require.NoError(t, ch.QueueBind("test_topology_legacy", "test.legacy", events.Exchange, false, nil))
+		require.NoError(t, ch.PublishWithContext(context.Background(), events.Exchange, "test.legacy", false, false, amqp.Publishing{Body: []byte("pending")}))
+
+		migrated := events.Topology{
+			Queues:  []events.QueueSpec{{Name: "test_topology_legacy_v2", RoutingKeys: []string{"test.legacy"}, MaxRetries: 3}},
+			Retired: []events.QueueSpec{{Name: "test_topology_legacy", RoutingKeys: []string{"test.legacy"}}},
+		}
+		require.NoError(t, events.DeclareTopology(conn, migrated))
+
+		// Kept until drained, but new events only reach the queue replacing it
+		require.NoError(t, ch.PublishWithContext(context.Background(), events.Exchange, "test.legacy", false, false, amqp.Publishing{Body: []byte("new")}))
+		require.Eventually(t, func() bool {
+			q, err := ch.QueueDeclarePassive("test_topology_legacy_v2", true, false, false, false, nil)
+			return err == nil && q.Messages == 1
+		}, 5*time.Second, 50*time.Millisecond)
+		d, ok, err := ch.Get("test_topology_legacy", true)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, "pending", string(d.Body))
+		_, ok, err = ch.Get("test_topology_legacy", true)
+		require.NoError(t, err)
+		assert.False(t, ok, "the retired queue takes no new events")
+
+		require.NoError(t, events.DeclareTopology(conn, migrated))
+		require.NoError(t, events.DeclareTopology(conn, migrated), "retiring a deleted queue is a no-op")
+
+		check, err := conn.Channel()
+		require.NoError(t, err)
+		defer check.Close()
+		_, err = check.QueueDeclarePassive("test_topology_legacy", true, false, false, false, nil)
+		var amqpErr *amqp.Error
+		require.ErrorAs(t, err, &amqpErr)
+		assert.Equal(t, amqp.NotFound, amqpErr.Code, "the drained queue is deleted")
+	})
+}
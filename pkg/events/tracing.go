@@ -0,0 +1,47 @@
+package events
+
+import (
+	"context"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const tracerName = "github.com/floroz/gavel/pkg/events"
+
+// HeaderCarrier lets OpenTelemetry propagators read and write trace context in AMQP headers
+type HeaderCarrier amqp.Table
+
+var _ propagation.TextMapCarrier = HeaderCarrier(nil)
+
+// Get returns the string header stored under key
+func (c HeaderCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+// Set stores value under key
+func (c HeaderCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys lists the header names
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// TraceHeaders returns the trace context of ctx as message headers, for events that are
+// published later, such as outbox events. It returns nil when ctx is not traced.
+func TraceHeaders(ctx context.Context) map[string]string {
+	headers := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, headers)
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
@@ -0,0 +1,103 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/floroz/gavel/pkg/events"
+)
+
+const (
+	testTraceID    = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentSpan = "00f067aa0ba902b7"
+)
+
+// useTraceContext installs W3C trace context propagation for the duration of the test
+func useTraceContext(t *testing.T) {
+	t.Helper()
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+}
+
+func TestTraceHeaders(t *testing.T) {
+	useTraceContext(t)
+
+	assert.Nil(t, events.TraceHeaders(context.Background()), "an untraced context has no headers")
+
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+
+	headers := events.TraceHeaders(ctx)
+	require.Contains(t, headers, "traceparent")
+	assert.Contains(t, headers["traceparent"], span.SpanContext().TraceID().String())
+}
+
+func TestWithHeaders(t *testing.T) {
+	opts := events.NewPublishOptions(
+		events.WithHeaders(map[string]string{"a": "1"}),
+		events.WithHeaders(map[string]string{"b": "2"}),
+		events.WithHeaders(nil),
+	)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, opts.Headers)
+}
+
+func TestRabbitMQPublisher_TraceHeaders(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	useTraceContext(t)
+
+	ctx := context.Background()
+	conn, err := amqp.Dial(startRabbitMQ(t))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+	defer ch.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	publisher, err := events.NewRabbitMQPublisher(conn, events.WithPublisherTracerProvider(tp))
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	q, err := ch.QueueDeclare("", false, true, true, false, nil)
+	require.NoError(t, err)
+	require.NoError(t, ch.QueueBind(q.Name, "test.traced", "auction.events", false, nil))
+
+	// An untraced caller, like the outbox relay, passes on the trace the event was written in
+	err = publisher.Publish(ctx, "auction.events", "test.traced", []byte("payload"), events.WithHeaders(map[string]string{
+		"traceparent": "00-" + testTraceID + "-" + testParentSpan + "-01",
+		"x-source":    "test",
+	}))
+	require.NoError(t, err)
+
+	msg, ok, err := ch.Get(q.Name, true)
+	require.NoError(t, err)
+	require.True(t, ok, "message should be in the queue")
+	assert.Equal(t, "test", msg.Headers["x-source"])
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	publish := spans[0]
+	assert.Equal(t, testTraceID, publish.SpanContext().TraceID().String())
+	assert.Equal(t, testParentSpan, publish.Parent().SpanID().String())
+
+	// The message carries the publish span, so consumers hang off it within the same trace
+	consumerCtx := otel.GetTextMapPropagator().Extract(ctx, events.HeaderCarrier(msg.Headers))
+	_, consumerSpan := tp.Tracer("test").Start(consumerCtx, "process")
+	consumerSpan.End()
+	assert.Equal(t, testTraceID, consumerSpan.SpanContext().TraceID().String())
+	assert.Equal(t, publish.SpanContext().SpanID(), recorder.Ended()[1].Parent().SpanID())
+}
@@ -0,0 +1,145 @@
+// Package health reports whether a service's dependencies are reachable.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultTimeout bounds how long a health check waits for every dependency
+const DefaultTimeout = 2 * time.Second
+
+// Status values reported for each dependency and overall
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// ErrConnectionClosed is reported for a broker connection that has been closed
+var ErrConnectionClosed = errors.New("connection is closed")
+
+// Check reports whether one named dependency is reachable
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+	// Optional dependencies are reported on, but being down does not take the service down
+	Optional bool
+}
+
+// Optional marks c as a dependency the service can run without
+func Optional(c Check) Check {
+	c.Optional = true
+	return c
+}
+
+// Broker is a broker connection that is re-dialed when it drops, e.g. an events.RabbitMQPublisher
+type Broker interface {
+	Connected() bool
+}
+
+// Postgres checks the pool can reach the database
+func Postgres(pool *pgxpool.Pool) Check {
+	return Check{Name: "postgres", Check: pool.Ping}
+}
+
+// RabbitMQ checks the broker connection is open. The broker's current connection is checked,
+// so a re-dialed one counts once it is up.
+func RabbitMQ(broker Broker) Check {
+	return Check{Name: "rabbitmq", Check: func(context.Context) error {
+		if !broker.Connected() {
+			return ErrConnectionClosed
+		}
+		return nil
+	}}
+}
+
+// Redis checks the server answers PING
+func Redis(client *redis.Client) Check {
+	return Check{Name: "redis", Check: func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}}
+}
+
+// DependencyStatus is the outcome of one check
+type DependencyStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the outcome of all checks; Status is down if any required dependency is
+type Report struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies,omitempty"`
+}
+
+// Checker runs a set of checks concurrently under a shared timeout
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+}
+
+// NewChecker creates a checker for checks, each given at most timeout to answer
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	return &Checker{checks: checks, timeout: timeout}
+}
+
+// Run checks every dependency
+func (c *Checker) Run(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := Report{Status: StatusUp, Dependencies: make(map[string]DependencyStatus, len(c.checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := DependencyStatus{Status: StatusUp}
+			if err := check.Check(ctx); err != nil {
+				status = DependencyStatus{Status: StatusDown, Error: err.Error()}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Dependencies[check.Name] = status
+			if status.Status == StatusDown && !check.Optional {
+				report.Status = StatusDown
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// Live answers 200 whenever the process can serve requests. Unlike a Checker it ignores
+// dependencies, so a brief outage takes a pod out of rotation without restarting it.
+func Live() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(Report{Status: StatusUp})
+	})
+}
+
+// ServeHTTP writes the report as JSON, with 503 if any required dependency is down
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+
+	code := http.StatusOK
+	if report.Status != StatusUp {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(report)
+}
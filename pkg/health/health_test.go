@@ -0,0 +1,127 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/health"
+)
+
+func up(name string) health.Check {
+	return health.Check{Name: name, Check: func(context.Context) error { return nil }}
+}
+
+func down(name string, err error) health.Check {
+	return health.Check{Name: name, Check: func(context.Context) error { return err }}
+}
+
+func serve(t *testing.T, checker *health.Checker) (int, health.Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	checker.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var report health.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	return rec.Code, report
+}
+
+func TestChecker_ServeHTTP(t *testing.T) {
+	t.Run("all dependencies up", func(t *testing.T) {
+		code, report := serve(t, health.NewChecker(time.Second, up("postgres"), up("rabbitmq"), up("redis")))
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, health.StatusUp, report.Status)
+		assert.Len(t, report.Dependencies, 3)
+		assert.Equal(t, health.DependencyStatus{Status: health.StatusUp}, report.Dependencies["redis"])
+	})
+
+	t.Run("one dependency down", func(t *testing.T) {
+		code, report := serve(t, health.NewChecker(time.Second,
+			up("postgres"),
+			down("rabbitmq", health.ErrConnectionClosed),
+			up("redis"),
+		))
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, health.StatusDown, report.Status)
+		assert.Equal(t, health.StatusUp, report.Dependencies["postgres"].Status)
+		assert.Equal(t, health.DependencyStatus{Status: health.StatusDown, Error: "connection is closed"}, report.Dependencies["rabbitmq"])
+	})
+
+	t.Run("optional dependency down", func(t *testing.T) {
+		code, report := serve(t, health.NewChecker(time.Second,
+			up("postgres"),
+			health.Optional(down("redis", errors.New("connection refused"))),
+		))
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, health.StatusUp, report.Status)
+		assert.Equal(t, health.DependencyStatus{Status: health.StatusDown, Error: "connection refused"}, report.Dependencies["redis"])
+	})
+
+	t.Run("slow dependency times out", func(t *testing.T) {
+		hanging := health.Check{Name: "postgres", Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}}
+
+		start := time.Now()
+		code, report := serve(t, health.NewChecker(20*time.Millisecond, hanging, up("redis")))
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Dependencies["postgres"].Error)
+		assert.Equal(t, health.StatusUp, report.Dependencies["redis"].Status)
+	})
+}
+
+func TestChecker_Run(t *testing.T) {
+	report := health.NewChecker(time.Second, down("redis", errors.New("connection refused"))).Run(context.Background())
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, "connection refused", report.Dependencies["redis"].Error)
+}
+
+// fakeBroker is a broker connection that drops and comes back
+type fakeBroker struct {
+	connected bool
+}
+
+func (b *fakeBroker) Connected() bool { return b.connected }
+
+func TestRabbitMQ(t *testing.T) {
+	broker := &fakeBroker{connected: true}
+	check := health.RabbitMQ(broker)
+	require.NoError(t, check.Check(context.Background()))
+
+	broker.connected = false
+	assert.ErrorIs(t, check.Check(context.Background()), health.ErrConnectionClosed)
+
+	// Ready again once the connection is re-dialed
+	broker.connected = true
+	assert.NoError(t, check.Check(context.Background()))
+}
+
+func TestLivenessVersusReadiness(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/livez", health.Live())
+	mux.Handle("/readyz", health.NewChecker(time.Second, up("postgres"), down("rabbitmq", health.ErrConnectionClosed)))
+
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	// A dependency outage takes the pod out of rotation without failing liveness
+	assert.Equal(t, http.StatusOK, get("/livez"))
+	assert.Equal(t, http.StatusServiceUnavailable, get("/readyz"))
+}
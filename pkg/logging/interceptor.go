@@ -0,0 +1,152 @@
+// Package logging provides structured request logging for Connect handlers.
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RequestIDHeader carries the request id in both directions. A caller-supplied id is kept,
+// so a request can be followed across services; otherwise one is generated.
+const RequestIDHeader = "X-Request-Id"
+
+// Redacted replaces the value of sensitive fields in logged requests
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedFields are the proto field names whose values are never logged
+var DefaultRedactedFields = []string{"password", "access_token", "refresh_token"}
+
+type contextKey struct{}
+
+// Option configures optional interceptor behaviour
+type Option func(*interceptor)
+
+// WithRedactedFields redacts the named proto fields in addition to DefaultRedactedFields
+func WithRedactedFields(names ...string) Option {
+	return func(i *interceptor) {
+		for _, name := range names {
+			i.redacted[protoreflect.Name(name)] = true
+		}
+	}
+}
+
+type interceptor struct {
+	logger   *slog.Logger
+	redacted map[protoreflect.Name]bool
+}
+
+// NewInterceptor creates a ConnectRPC interceptor that logs every unary call with its procedure,
+// duration, code and request id, along with the request message minus its sensitive fields.
+// The request id is available to handlers through RequestID and returned in the response trailers.
+func NewInterceptor(logger *slog.Logger, opts ...Option) connect.UnaryInterceptorFunc {
+	i := &interceptor{logger: logger, redacted: make(map[protoreflect.Name]bool)}
+	WithRedactedFields(DefaultRedactedFields...)(i)
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			requestID := req.Header().Get(RequestIDHeader)
+			if requestID == "" {
+				requestID = uuid.NewString()
+			}
+			ctx = context.WithValue(ctx, contextKey{}, requestID)
+
+			start := time.Now()
+			resp, err := next(ctx, req)
+			duration := time.Since(start)
+
+			code := "ok"
+			level := slog.LevelInfo
+			attrs := []slog.Attr{
+				slog.String("procedure", req.Spec().Procedure),
+				slog.String("request_id", requestID),
+				slog.Duration("duration", duration),
+			}
+			if err != nil {
+				var connectErr *connect.Error
+				if !errors.As(err, &connectErr) {
+					// Connect reports plain errors as CodeUnknown; wrap now so the id can be attached
+					connectErr = connect.NewError(connect.CodeUnknown, err)
+					err = connectErr
+				}
+				connectErr.Meta().Set(RequestIDHeader, requestID)
+				code = connectErr.Code().String()
+				level = levelFor(connectErr.Code())
+				attrs = append(attrs, slog.String("error", connectErr.Message()))
+			} else if resp != nil {
+				resp.Trailer().Set(RequestIDHeader, requestID)
+			}
+			attrs = append(attrs, slog.String("code", code))
+			if msg, ok := req.Any().(proto.Message); ok {
+				attrs = append(attrs, slog.String("request", i.format(msg)))
+			}
+
+			i.logger.LogAttrs(ctx, level, "Handled request", attrs...)
+			return resp, err
+		}
+	}
+}
+
+// RequestID returns the id of the request being handled, set by the logging interceptor
+func RequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok
+}
+
+// levelFor logs failures the server is responsible for as errors, and caller mistakes as warnings
+func levelFor(code connect.Code) slog.Level {
+	switch code {
+	case connect.CodeUnknown, connect.CodeInternal, connect.CodeDataLoss, connect.CodeUnavailable, connect.CodeUnimplemented:
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
+	}
+}
+
+// format renders msg as JSON with its sensitive fields redacted
+func (i *interceptor) format(msg proto.Message) string {
+	clone := proto.Clone(msg)
+	i.redact(clone.ProtoReflect())
+	out, err := protojson.Marshal(clone)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// redact overwrites sensitive fields of m in place, descending into nested messages
+func (i *interceptor) redact(m protoreflect.Message) {
+	var sensitive []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case i.redacted[fd.Name()]:
+			sensitive = append(sensitive, fd)
+		case fd.Kind() == protoreflect.MessageKind && fd.IsList():
+			list := v.List()
+			for j := 0; j < list.Len(); j++ {
+				i.redact(list.Get(j).Message())
+			}
+		case fd.Kind() == protoreflect.MessageKind && !fd.IsMap():
+			i.redact(v.Message())
+		}
+		return true
+	})
+
+	for _, fd := range sensitive {
+		if fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+			m.Set(fd, protoreflect.ValueOfString(Redacted))
+		} else {
+			m.Clear(fd)
+		}
+	}
+}
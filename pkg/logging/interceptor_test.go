@@ -0,0 +1,108 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/logging"
+	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
+	"github.com/floroz/gavel/pkg/proto/auth/v1/authv1connect"
+)
+
+// stubAuthService records the request id it was called with
+type stubAuthService struct {
+	authv1connect.UnimplementedAuthServiceHandler
+	requestID string
+}
+
+func (s *stubAuthService) Login(ctx context.Context, _ *connect.Request[authv1.LoginRequest]) (*connect.Response[authv1.LoginResponse], error) {
+	s.requestID, _ = logging.RequestID(ctx)
+	return connect.NewResponse(&authv1.LoginResponse{AccessToken: "access-secret", RefreshToken: "refresh-secret"}), nil
+}
+
+func (s *stubAuthService) Register(ctx context.Context, _ *connect.Request[authv1.RegisterRequest]) (*connect.Response[authv1.RegisterResponse], error) {
+	s.requestID, _ = logging.RequestID(ctx)
+	return nil, connect.NewError(connect.CodeAlreadyExists, errors.New("email already registered"))
+}
+
+func newTestClient(t *testing.T, logs *bytes.Buffer) (authv1connect.AuthServiceClient, *stubAuthService) {
+	t.Helper()
+	logger := slog.New(slog.NewJSONHandler(logs, nil))
+	stub := &stubAuthService{}
+
+	mux := http.NewServeMux()
+	mux.Handle(authv1connect.NewAuthServiceHandler(stub, connect.WithInterceptors(logging.NewInterceptor(logger))))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return authv1connect.NewAuthServiceClient(server.Client(), server.URL), stub
+}
+
+func decodeLog(t *testing.T, logs *bytes.Buffer) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	return entry
+}
+
+func TestInterceptor_LogsRequest(t *testing.T) {
+	var logs bytes.Buffer
+	client, stub := newTestClient(t, &logs)
+
+	resp, err := client.Login(context.Background(), connect.NewRequest(&authv1.LoginRequest{
+		Email:    "user@example.com",
+		Password: "hunter2",
+	}))
+	require.NoError(t, err)
+
+	entry := decodeLog(t, &logs)
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, authv1connect.AuthServiceLoginProcedure, entry["procedure"])
+	assert.Equal(t, "ok", entry["code"])
+	assert.Contains(t, entry, "duration")
+	assert.Contains(t, entry["request"], "user@example.com")
+	assert.Contains(t, entry["request"], logging.Redacted)
+
+	// The generated id reaches the handler and the caller
+	requestID, _ := entry["request_id"].(string)
+	assert.NotEmpty(t, requestID)
+	assert.Equal(t, requestID, stub.requestID)
+	assert.Equal(t, requestID, resp.Trailer().Get(logging.RequestIDHeader))
+
+	assert.NotContains(t, logs.String(), "hunter2")
+	assert.NotContains(t, logs.String(), "secret")
+}
+
+func TestInterceptor_LogsErrors(t *testing.T) {
+	var logs bytes.Buffer
+	client, stub := newTestClient(t, &logs)
+
+	req := connect.NewRequest(&authv1.RegisterRequest{Email: "user@example.com", Password: "hunter2"})
+	req.Header().Set(logging.RequestIDHeader, "caller-id")
+	_, err := client.Register(context.Background(), req)
+	require.Error(t, err)
+
+	entry := decodeLog(t, &logs)
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, connect.CodeAlreadyExists.String(), entry["code"])
+	assert.Equal(t, "email already registered", entry["error"])
+
+	// A caller-supplied id is kept, and returned with the error
+	assert.Equal(t, "caller-id", entry["request_id"])
+	assert.Equal(t, "caller-id", stub.requestID)
+	var connectErr *connect.Error
+	require.ErrorAs(t, err, &connectErr)
+	assert.Equal(t, "caller-id", connectErr.Meta().Get(logging.RequestIDHeader))
+
+	assert.NotContains(t, logs.String(), "hunter2")
+}
@@ -0,0 +1,77 @@
+// Package money represents amounts of a currency in its minor unit, such as cents.
+package money
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultCurrency is the ISO 4217 code amounts are in when nothing says otherwise
+const DefaultCurrency = "USD"
+
+// ErrCurrencyMismatch is returned when combining or comparing amounts in different currencies
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money is an amount in the minor unit of an ISO 4217 currency, e.g. 1250 USD is $12.50
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// New creates an amount of currency
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// IsZero returns true if the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsPositive returns true if the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return New(m.Amount+other.Amount, m.Currency), nil
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return New(m.Amount-other.Amount, m.Currency), nil
+}
+
+// Cmp returns -1, 0 or +1 as m is less than, equal to or greater than other
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// String formats m as its minor-unit amount and currency, e.g. "1250 USD"
+func (m Money) String() string {
+	return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return nil
+}
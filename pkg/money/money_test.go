@@ -0,0 +1,60 @@
+package money_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/money"
+)
+
+func TestMoney_SameCurrency(t *testing.T) {
+	a := money.New(1250, "USD")
+	b := money.New(500, "USD")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(1750, "USD"), sum)
+
+	diff, err := a.Sub(b)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(750, "USD"), diff)
+
+	tests := []struct {
+		name  string
+		left  money.Money
+		right money.Money
+		want  int
+	}{
+		{name: "less", left: b, right: a, want: -1},
+		{name: "equal", left: a, right: money.New(1250, "USD"), want: 0},
+		{name: "greater", left: a, right: b, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.left.Cmp(tt.right)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, "1250 USD", a.String())
+	assert.True(t, a.IsPositive())
+	assert.True(t, money.New(0, "USD").IsZero())
+}
+
+func TestMoney_CurrencyMismatch(t *testing.T) {
+	usd := money.New(1000, "USD")
+	eur := money.New(1000, "EUR")
+
+	_, err := usd.Cmp(eur)
+	assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+	assert.Contains(t, err.Error(), "USD and EUR")
+
+	_, err = usd.Add(eur)
+	assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+
+	_, err = usd.Sub(eur)
+	assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+}
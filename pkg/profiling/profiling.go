@@ -0,0 +1,53 @@
+// Package profiling serves the net/http/pprof endpoints on an admin port of their own.
+package profiling
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultAddr is where profiles are served unless <prefix>_PPROF_ADDR says otherwise.
+// It is bound to localhost, so profiles are reached through e.g. kubectl port-forward.
+const DefaultAddr = "localhost:6060"
+
+// Handler serves the pprof index, heap, goroutine and other profiles under /debug/pprof/
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// NewServer returns the admin server serving Handler, or nil when profiling is off.
+// With a prefix of "USER_STATS" it is switched on by USER_STATS_PPROF_ENABLED=true and
+// listens on USER_STATS_PPROF_ADDR, or DefaultAddr.
+func NewServer(prefix string) (*http.Server, error) {
+	enabled := false
+	if v := os.Getenv(prefix + "_PPROF_ENABLED"); v != "" {
+		var err error
+		if enabled, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid %s_PPROF_ENABLED: %w", prefix, err)
+		}
+	}
+	if !enabled {
+		return nil, nil
+	}
+
+	addr := os.Getenv(prefix + "_PPROF_ADDR")
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	// No write timeout: CPU profiles and traces stream for as long as the caller asks
+	return &http.Server{
+		Addr:              addr,
+		Handler:           Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}, nil
+}
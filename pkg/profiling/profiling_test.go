@@ -0,0 +1,56 @@
+package profiling_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/profiling"
+)
+
+func TestNewServer(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		srv, err := profiling.NewServer("TEST")
+		require.NoError(t, err)
+		assert.Nil(t, srv)
+	})
+
+	t.Run("explicitly disabled", func(t *testing.T) {
+		t.Setenv("TEST_PPROF_ENABLED", "false")
+		srv, err := profiling.NewServer("TEST")
+		require.NoError(t, err)
+		assert.Nil(t, srv)
+	})
+
+	t.Run("enabled serves the pprof handlers", func(t *testing.T) {
+		t.Setenv("TEST_PPROF_ENABLED", "true")
+		srv, err := profiling.NewServer("TEST")
+		require.NoError(t, err)
+		require.NotNil(t, srv)
+		assert.Equal(t, profiling.DefaultAddr, srv.Addr)
+
+		for _, path := range []string{"/debug/pprof/", "/debug/pprof/heap", "/debug/pprof/cmdline"} {
+			rec := httptest.NewRecorder()
+			srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusOK, rec.Code, path)
+		}
+	})
+
+	t.Run("custom address", func(t *testing.T) {
+		t.Setenv("TEST_PPROF_ENABLED", "1")
+		t.Setenv("TEST_PPROF_ADDR", ":7070")
+		srv, err := profiling.NewServer("TEST")
+		require.NoError(t, err)
+		require.NotNil(t, srv)
+		assert.Equal(t, ":7070", srv.Addr)
+	})
+
+	t.Run("invalid flag", func(t *testing.T) {
+		t.Setenv("TEST_PPROF_ENABLED", "sometimes")
+		_, err := profiling.NewServer("TEST")
+		assert.Error(t, err)
+	})
+}
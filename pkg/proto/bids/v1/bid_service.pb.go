@@ -26,10 +26,12 @@ const (
 type ItemStatus int32
 
 const (
-	ItemStatus_ITEM_STATUS_UNSPECIFIED ItemStatus = 0
-	ItemStatus_ITEM_STATUS_ACTIVE      ItemStatus = 1
-	ItemStatus_ITEM_STATUS_ENDED       ItemStatus = 2
-	ItemStatus_ITEM_STATUS_CANCELLED   ItemStatus = 3
+	ItemStatus_ITEM_STATUS_UNSPECIFIED  ItemStatus = 0
+	ItemStatus_ITEM_STATUS_ACTIVE       ItemStatus = 1
+	ItemStatus_ITEM_STATUS_ENDED        ItemStatus = 2
+	ItemStatus_ITEM_STATUS_CANCELLED    ItemStatus = 3
+	ItemStatus_ITEM_STATUS_ENDED_UNSOLD ItemStatus = 4 // Ended without meeting the reserve price
+	ItemStatus_ITEM_STATUS_SCHEDULED    ItemStatus = 5 // Waiting for start_at before accepting bids
 )
 
 // Enum value maps for ItemStatus.
@@ -39,12 +41,16 @@ var (
 		1: "ITEM_STATUS_ACTIVE",
 		2: "ITEM_STATUS_ENDED",
 		3: "ITEM_STATUS_CANCELLED",
+		4: "ITEM_STATUS_ENDED_UNSOLD",
+		5: "ITEM_STATUS_SCHEDULED",
 	}
 	ItemStatus_value = map[string]int32{
-		"ITEM_STATUS_UNSPECIFIED": 0,
-		"ITEM_STATUS_ACTIVE":      1,
-		"ITEM_STATUS_ENDED":       2,
-		"ITEM_STATUS_CANCELLED":   3,
+		"ITEM_STATUS_UNSPECIFIED":  0,
+		"ITEM_STATUS_ACTIVE":       1,
+		"ITEM_STATUS_ENDED":        2,
+		"ITEM_STATUS_CANCELLED":    3,
+		"ITEM_STATUS_ENDED_UNSOLD": 4,
+		"ITEM_STATUS_SCHEDULED":    5,
 	}
 )
 
@@ -171,6 +177,95 @@ func (x *PlaceBidResponse) GetBid() *Bid {
 	return nil
 }
 
+// PurchaseNow buys an item outright at its buy-now price, ending the auction
+type PurchaseNowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurchaseNowRequest) Reset() {
+	*x = PurchaseNowRequest{}
+	mi := &file_bids_v1_bid_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurchaseNowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurchaseNowRequest) ProtoMessage() {}
+
+func (x *PurchaseNowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bids_v1_bid_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurchaseNowRequest.ProtoReflect.Descriptor instead.
+func (*PurchaseNowRequest) Descriptor() ([]byte, []int) {
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PurchaseNowRequest) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+type PurchaseNowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bid           *Bid                   `protobuf:"bytes,1,opt,name=bid,proto3" json:"bid,omitempty"` // The winning bid, recorded at the buy-now price
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurchaseNowResponse) Reset() {
+	*x = PurchaseNowResponse{}
+	mi := &file_bids_v1_bid_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurchaseNowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurchaseNowResponse) ProtoMessage() {}
+
+func (x *PurchaseNowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bids_v1_bid_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurchaseNowResponse.ProtoReflect.Descriptor instead.
+func (*PurchaseNowResponse) Descriptor() ([]byte, []int) {
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PurchaseNowResponse) GetBid() *Bid {
+	if x != nil {
+		return x.Bid
+	}
+	return nil
+}
+
 type Bid struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -184,7 +279,7 @@ type Bid struct {
 
 func (x *Bid) Reset() {
 	*x = Bid{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[2]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -196,7 +291,7 @@ func (x *Bid) String() string {
 func (*Bid) ProtoMessage() {}
 
 func (x *Bid) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[2]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -209,7 +304,7 @@ func (x *Bid) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Bid.ProtoReflect.Descriptor instead.
 func (*Bid) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{2}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Bid) GetId() string {
@@ -249,26 +344,29 @@ func (x *Bid) GetCreatedAt() string {
 
 // Item message
 type Item struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title             string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description       string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	StartPrice        int64                  `protobuf:"varint,4,opt,name=start_price,json=startPrice,proto3" json:"start_price,omitempty"`
-	CurrentHighestBid int64                  `protobuf:"varint,5,opt,name=current_highest_bid,json=currentHighestBid,proto3" json:"current_highest_bid,omitempty"`
-	EndAt             string                 `protobuf:"bytes,6,opt,name=end_at,json=endAt,proto3" json:"end_at,omitempty"`             // ISO 8601 string
-	CreatedAt         string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // ISO 8601 string
-	UpdatedAt         string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // ISO 8601 string
-	Images            []string               `protobuf:"bytes,9,rep,name=images,proto3" json:"images,omitempty"`
-	Category          string                 `protobuf:"bytes,10,opt,name=category,proto3" json:"category,omitempty"`
-	SellerId          string                 `protobuf:"bytes,11,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
-	Status            ItemStatus             `protobuf:"varint,12,opt,name=status,proto3,enum=bids.v1.ItemStatus" json:"status,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Id                     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title                  string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description            string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	StartPrice             int64                  `protobuf:"varint,4,opt,name=start_price,json=startPrice,proto3" json:"start_price,omitempty"`
+	CurrentHighestBid      int64                  `protobuf:"varint,5,opt,name=current_highest_bid,json=currentHighestBid,proto3" json:"current_highest_bid,omitempty"`
+	EndAt                  string                 `protobuf:"bytes,6,opt,name=end_at,json=endAt,proto3" json:"end_at,omitempty"`             // ISO 8601 string
+	CreatedAt              string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // ISO 8601 string
+	UpdatedAt              string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // ISO 8601 string
+	Images                 []string               `protobuf:"bytes,9,rep,name=images,proto3" json:"images,omitempty"`
+	Category               string                 `protobuf:"bytes,10,opt,name=category,proto3" json:"category,omitempty"`
+	SellerId               string                 `protobuf:"bytes,11,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	Status                 ItemStatus             `protobuf:"varint,12,opt,name=status,proto3,enum=bids.v1.ItemStatus" json:"status,omitempty"`
+	BuyNowPrice            int64                  `protobuf:"varint,13,opt,name=buy_now_price,json=buyNowPrice,proto3" json:"buy_now_price,omitempty"`                                   // 0 when the item has no buy-now option
+	StartAt                string                 `protobuf:"bytes,14,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`                                                  // ISO 8601 string
+	CurrentHighestBidderId string                 `protobuf:"bytes,15,opt,name=current_highest_bidder_id,json=currentHighestBidderId,proto3" json:"current_highest_bidder_id,omitempty"` // empty when the item has no bids
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
 func (x *Item) Reset() {
 	*x = Item{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[3]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -280,7 +378,7 @@ func (x *Item) String() string {
 func (*Item) ProtoMessage() {}
 
 func (x *Item) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[3]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -293,7 +391,7 @@ func (x *Item) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Item.ProtoReflect.Descriptor instead.
 func (*Item) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{3}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Item) GetId() string {
@@ -380,6 +478,27 @@ func (x *Item) GetStatus() ItemStatus {
 	return ItemStatus_ITEM_STATUS_UNSPECIFIED
 }
 
+func (x *Item) GetBuyNowPrice() int64 {
+	if x != nil {
+		return x.BuyNowPrice
+	}
+	return 0
+}
+
+func (x *Item) GetStartAt() string {
+	if x != nil {
+		return x.StartAt
+	}
+	return ""
+}
+
+func (x *Item) GetCurrentHighestBidderId() string {
+	if x != nil {
+		return x.CurrentHighestBidderId
+	}
+	return ""
+}
+
 // CreateItem
 type CreateItemRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -389,13 +508,16 @@ type CreateItemRequest struct {
 	EndAt         string                 `protobuf:"bytes,4,opt,name=end_at,json=endAt,proto3" json:"end_at,omitempty"` // ISO 8601 string
 	Images        []string               `protobuf:"bytes,5,rep,name=images,proto3" json:"images,omitempty"`
 	Category      string                 `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
+	ReservePrice  int64                  `protobuf:"varint,7,opt,name=reserve_price,json=reservePrice,proto3" json:"reserve_price,omitempty"` // Hidden minimum sale price, never returned in Item; 0 for none
+	BuyNowPrice   int64                  `protobuf:"varint,8,opt,name=buy_now_price,json=buyNowPrice,proto3" json:"buy_now_price,omitempty"`  // Price to end the auction immediately; 0 for none
+	StartAt       string                 `protobuf:"bytes,9,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`                 // ISO 8601 string; empty starts the auction immediately
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateItemRequest) Reset() {
 	*x = CreateItemRequest{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[4]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -407,7 +529,7 @@ func (x *CreateItemRequest) String() string {
 func (*CreateItemRequest) ProtoMessage() {}
 
 func (x *CreateItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[4]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -420,7 +542,7 @@ func (x *CreateItemRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateItemRequest.ProtoReflect.Descriptor instead.
 func (*CreateItemRequest) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{4}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CreateItemRequest) GetTitle() string {
@@ -465,6 +587,27 @@ func (x *CreateItemRequest) GetCategory() string {
 	return ""
 }
 
+func (x *CreateItemRequest) GetReservePrice() int64 {
+	if x != nil {
+		return x.ReservePrice
+	}
+	return 0
+}
+
+func (x *CreateItemRequest) GetBuyNowPrice() int64 {
+	if x != nil {
+		return x.BuyNowPrice
+	}
+	return 0
+}
+
+func (x *CreateItemRequest) GetStartAt() string {
+	if x != nil {
+		return x.StartAt
+	}
+	return ""
+}
+
 type CreateItemResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Item          *Item                  `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
@@ -474,7 +617,7 @@ type CreateItemResponse struct {
 
 func (x *CreateItemResponse) Reset() {
 	*x = CreateItemResponse{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[5]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -486,7 +629,7 @@ func (x *CreateItemResponse) String() string {
 func (*CreateItemResponse) ProtoMessage() {}
 
 func (x *CreateItemResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[5]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -499,7 +642,7 @@ func (x *CreateItemResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateItemResponse.ProtoReflect.Descriptor instead.
 func (*CreateItemResponse) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{5}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CreateItemResponse) GetItem() *Item {
@@ -519,7 +662,7 @@ type GetItemRequest struct {
 
 func (x *GetItemRequest) Reset() {
 	*x = GetItemRequest{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[6]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -531,7 +674,7 @@ func (x *GetItemRequest) String() string {
 func (*GetItemRequest) ProtoMessage() {}
 
 func (x *GetItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[6]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -544,7 +687,7 @@ func (x *GetItemRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetItemRequest.ProtoReflect.Descriptor instead.
 func (*GetItemRequest) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{6}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetItemRequest) GetId() string {
@@ -563,7 +706,7 @@ type GetItemResponse struct {
 
 func (x *GetItemResponse) Reset() {
 	*x = GetItemResponse{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[7]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -575,7 +718,7 @@ func (x *GetItemResponse) String() string {
 func (*GetItemResponse) ProtoMessage() {}
 
 func (x *GetItemResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[7]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -588,7 +731,7 @@ func (x *GetItemResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetItemResponse.ProtoReflect.Descriptor instead.
 func (*GetItemResponse) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{7}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetItemResponse) GetItem() *Item {
@@ -610,7 +753,7 @@ type ListItemsRequest struct {
 
 func (x *ListItemsRequest) Reset() {
 	*x = ListItemsRequest{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[8]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -622,7 +765,7 @@ func (x *ListItemsRequest) String() string {
 func (*ListItemsRequest) ProtoMessage() {}
 
 func (x *ListItemsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[8]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -635,7 +778,7 @@ func (x *ListItemsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListItemsRequest.ProtoReflect.Descriptor instead.
 func (*ListItemsRequest) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{8}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ListItemsRequest) GetPageSize() int32 {
@@ -669,7 +812,7 @@ type ListItemsResponse struct {
 
 func (x *ListItemsResponse) Reset() {
 	*x = ListItemsResponse{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[9]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -681,7 +824,7 @@ func (x *ListItemsResponse) String() string {
 func (*ListItemsResponse) ProtoMessage() {}
 
 func (x *ListItemsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[9]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -694,7 +837,7 @@ func (x *ListItemsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListItemsResponse.ProtoReflect.Descriptor instead.
 func (*ListItemsResponse) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{9}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ListItemsResponse) GetItems() []*Item {
@@ -722,7 +865,7 @@ type ListSellerItemsRequest struct {
 
 func (x *ListSellerItemsRequest) Reset() {
 	*x = ListSellerItemsRequest{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[10]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -734,7 +877,7 @@ func (x *ListSellerItemsRequest) String() string {
 func (*ListSellerItemsRequest) ProtoMessage() {}
 
 func (x *ListSellerItemsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[10]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -747,7 +890,7 @@ func (x *ListSellerItemsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSellerItemsRequest.ProtoReflect.Descriptor instead.
 func (*ListSellerItemsRequest) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{10}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ListSellerItemsRequest) GetPageSize() int32 {
@@ -774,7 +917,7 @@ type ListSellerItemsResponse struct {
 
 func (x *ListSellerItemsResponse) Reset() {
 	*x = ListSellerItemsResponse{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[11]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -786,7 +929,7 @@ func (x *ListSellerItemsResponse) String() string {
 func (*ListSellerItemsResponse) ProtoMessage() {}
 
 func (x *ListSellerItemsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[11]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -799,7 +942,7 @@ func (x *ListSellerItemsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSellerItemsResponse.ProtoReflect.Descriptor instead.
 func (*ListSellerItemsResponse) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{11}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ListSellerItemsResponse) GetItems() []*Item {
@@ -830,7 +973,7 @@ type UpdateItemRequest struct {
 
 func (x *UpdateItemRequest) Reset() {
 	*x = UpdateItemRequest{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[12]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -842,7 +985,7 @@ func (x *UpdateItemRequest) String() string {
 func (*UpdateItemRequest) ProtoMessage() {}
 
 func (x *UpdateItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[12]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -855,7 +998,7 @@ func (x *UpdateItemRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateItemRequest.ProtoReflect.Descriptor instead.
 func (*UpdateItemRequest) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{12}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *UpdateItemRequest) GetId() string {
@@ -902,7 +1045,7 @@ type UpdateItemResponse struct {
 
 func (x *UpdateItemResponse) Reset() {
 	*x = UpdateItemResponse{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[13]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -914,7 +1057,7 @@ func (x *UpdateItemResponse) String() string {
 func (*UpdateItemResponse) ProtoMessage() {}
 
 func (x *UpdateItemResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[13]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -927,7 +1070,7 @@ func (x *UpdateItemResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateItemResponse.ProtoReflect.Descriptor instead.
 func (*UpdateItemResponse) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{13}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *UpdateItemResponse) GetItem() *Item {
@@ -947,7 +1090,7 @@ type CancelItemRequest struct {
 
 func (x *CancelItemRequest) Reset() {
 	*x = CancelItemRequest{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[14]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -959,7 +1102,7 @@ func (x *CancelItemRequest) String() string {
 func (*CancelItemRequest) ProtoMessage() {}
 
 func (x *CancelItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[14]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -972,7 +1115,7 @@ func (x *CancelItemRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelItemRequest.ProtoReflect.Descriptor instead.
 func (*CancelItemRequest) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{14}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *CancelItemRequest) GetId() string {
@@ -991,7 +1134,7 @@ type CancelItemResponse struct {
 
 func (x *CancelItemResponse) Reset() {
 	*x = CancelItemResponse{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[15]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1003,7 +1146,7 @@ func (x *CancelItemResponse) String() string {
 func (*CancelItemResponse) ProtoMessage() {}
 
 func (x *CancelItemResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[15]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1016,7 +1159,7 @@ func (x *CancelItemResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelItemResponse.ProtoReflect.Descriptor instead.
 func (*CancelItemResponse) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{15}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *CancelItemResponse) GetItem() *Item {
@@ -1026,6 +1169,95 @@ func (x *CancelItemResponse) GetItem() *Item {
 	return nil
 }
 
+// ForceCancelItem cancels any seller's auction, even one that has bids
+type ForceCancelItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceCancelItemRequest) Reset() {
+	*x = ForceCancelItemRequest{}
+	mi := &file_bids_v1_bid_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceCancelItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceCancelItemRequest) ProtoMessage() {}
+
+func (x *ForceCancelItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bids_v1_bid_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceCancelItemRequest.ProtoReflect.Descriptor instead.
+func (*ForceCancelItemRequest) Descriptor() ([]byte, []int) {
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ForceCancelItemRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ForceCancelItemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Item          *Item                  `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceCancelItemResponse) Reset() {
+	*x = ForceCancelItemResponse{}
+	mi := &file_bids_v1_bid_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceCancelItemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceCancelItemResponse) ProtoMessage() {}
+
+func (x *ForceCancelItemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bids_v1_bid_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceCancelItemResponse.ProtoReflect.Descriptor instead.
+func (*ForceCancelItemResponse) Descriptor() ([]byte, []int) {
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ForceCancelItemResponse) GetItem() *Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
 // GetItemBids
 type GetItemBidsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1038,7 +1270,7 @@ type GetItemBidsRequest struct {
 
 func (x *GetItemBidsRequest) Reset() {
 	*x = GetItemBidsRequest{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[16]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1050,7 +1282,7 @@ func (x *GetItemBidsRequest) String() string {
 func (*GetItemBidsRequest) ProtoMessage() {}
 
 func (x *GetItemBidsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[16]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1063,7 +1295,7 @@ func (x *GetItemBidsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetItemBidsRequest.ProtoReflect.Descriptor instead.
 func (*GetItemBidsRequest) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{16}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *GetItemBidsRequest) GetItemId() string {
@@ -1097,7 +1329,7 @@ type GetItemBidsResponse struct {
 
 func (x *GetItemBidsResponse) Reset() {
 	*x = GetItemBidsResponse{}
-	mi := &file_bids_v1_bid_service_proto_msgTypes[17]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1109,7 +1341,7 @@ func (x *GetItemBidsResponse) String() string {
 func (*GetItemBidsResponse) ProtoMessage() {}
 
 func (x *GetItemBidsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_bids_v1_bid_service_proto_msgTypes[17]
+	mi := &file_bids_v1_bid_service_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1122,7 +1354,7 @@ func (x *GetItemBidsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetItemBidsResponse.ProtoReflect.Descriptor instead.
 func (*GetItemBidsResponse) Descriptor() ([]byte, []int) {
-	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{17}
+	return file_bids_v1_bid_service_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *GetItemBidsResponse) GetBids() []*Bid {
@@ -1148,6 +1380,10 @@ const file_bids_v1_bid_service_proto_rawDesc = "" +
 	"\aitem_id\x18\x01 \x01(\tR\x06itemId\x12\x16\n" +
 	"\x06amount\x18\x02 \x01(\x03R\x06amount\"2\n" +
 	"\x10PlaceBidResponse\x12\x1e\n" +
+	"\x03bid\x18\x01 \x01(\v2\f.bids.v1.BidR\x03bid\"-\n" +
+	"\x12PurchaseNowRequest\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\tR\x06itemId\"5\n" +
+	"\x13PurchaseNowResponse\x12\x1e\n" +
 	"\x03bid\x18\x01 \x01(\v2\f.bids.v1.BidR\x03bid\"~\n" +
 	"\x03Bid\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
@@ -1155,7 +1391,7 @@ const file_bids_v1_bid_service_proto_rawDesc = "" +
 	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x16\n" +
 	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\tR\tcreatedAt\"\xf2\x02\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\"\xec\x03\n" +
 	"\x04Item\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
@@ -1172,7 +1408,10 @@ const file_bids_v1_bid_service_proto_rawDesc = "" +
 	"\bcategory\x18\n" +
 	" \x01(\tR\bcategory\x12\x1b\n" +
 	"\tseller_id\x18\v \x01(\tR\bsellerId\x12+\n" +
-	"\x06status\x18\f \x01(\x0e2\x13.bids.v1.ItemStatusR\x06status\"\xb7\x01\n" +
+	"\x06status\x18\f \x01(\x0e2\x13.bids.v1.ItemStatusR\x06status\x12\"\n" +
+	"\rbuy_now_price\x18\r \x01(\x03R\vbuyNowPrice\x12\x19\n" +
+	"\bstart_at\x18\x0e \x01(\tR\astartAt\x129\n" +
+	"\x19current_highest_bidder_id\x18\x0f \x01(\tR\x16currentHighestBidderId\"\x9b\x02\n" +
 	"\x11CreateItemRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1f\n" +
@@ -1180,7 +1419,10 @@ const file_bids_v1_bid_service_proto_rawDesc = "" +
 	"startPrice\x12\x15\n" +
 	"\x06end_at\x18\x04 \x01(\tR\x05endAt\x12\x16\n" +
 	"\x06images\x18\x05 \x03(\tR\x06images\x12\x1a\n" +
-	"\bcategory\x18\x06 \x01(\tR\bcategory\"7\n" +
+	"\bcategory\x18\x06 \x01(\tR\bcategory\x12#\n" +
+	"\rreserve_price\x18\a \x01(\x03R\freservePrice\x12\"\n" +
+	"\rbuy_now_price\x18\b \x01(\x03R\vbuyNowPrice\x12\x19\n" +
+	"\bstart_at\x18\t \x01(\tR\astartAt\"7\n" +
 	"\x12CreateItemResponse\x12!\n" +
 	"\x04item\x18\x01 \x01(\v2\r.bids.v1.ItemR\x04item\" \n" +
 	"\x0eGetItemRequest\x12\x0e\n" +
@@ -1216,6 +1458,10 @@ const file_bids_v1_bid_service_proto_rawDesc = "" +
 	"\x11CancelItemRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"7\n" +
 	"\x12CancelItemResponse\x12!\n" +
+	"\x04item\x18\x01 \x01(\v2\r.bids.v1.ItemR\x04item\"(\n" +
+	"\x16ForceCancelItemRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"<\n" +
+	"\x17ForceCancelItemResponse\x12!\n" +
 	"\x04item\x18\x01 \x01(\v2\r.bids.v1.ItemR\x04item\"i\n" +
 	"\x12GetItemBidsRequest\x12\x17\n" +
 	"\aitem_id\x18\x01 \x01(\tR\x06itemId\x12\x1b\n" +
@@ -1224,16 +1470,19 @@ const file_bids_v1_bid_service_proto_rawDesc = "" +
 	"page_token\x18\x03 \x01(\tR\tpageToken\"_\n" +
 	"\x13GetItemBidsResponse\x12 \n" +
 	"\x04bids\x18\x01 \x03(\v2\f.bids.v1.BidR\x04bids\x12&\n" +
-	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken*s\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken*\xac\x01\n" +
 	"\n" +
 	"ItemStatus\x12\x1b\n" +
 	"\x17ITEM_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
 	"\x12ITEM_STATUS_ACTIVE\x10\x01\x12\x15\n" +
 	"\x11ITEM_STATUS_ENDED\x10\x02\x12\x19\n" +
-	"\x15ITEM_STATUS_CANCELLED\x10\x032\xc4\x04\n" +
+	"\x15ITEM_STATUS_CANCELLED\x10\x03\x12\x1c\n" +
+	"\x18ITEM_STATUS_ENDED_UNSOLD\x10\x04\x12\x19\n" +
+	"\x15ITEM_STATUS_SCHEDULED\x10\x052\xe4\x05\n" +
 	"\n" +
 	"BidService\x12?\n" +
-	"\bPlaceBid\x12\x18.bids.v1.PlaceBidRequest\x1a\x19.bids.v1.PlaceBidResponse\x12E\n" +
+	"\bPlaceBid\x12\x18.bids.v1.PlaceBidRequest\x1a\x19.bids.v1.PlaceBidResponse\x12H\n" +
+	"\vPurchaseNow\x12\x1b.bids.v1.PurchaseNowRequest\x1a\x1c.bids.v1.PurchaseNowResponse\x12E\n" +
 	"\n" +
 	"CreateItem\x12\x1a.bids.v1.CreateItemRequest\x1a\x1b.bids.v1.CreateItemResponse\x12<\n" +
 	"\aGetItem\x12\x17.bids.v1.GetItemRequest\x1a\x18.bids.v1.GetItemResponse\x12B\n" +
@@ -1243,7 +1492,8 @@ const file_bids_v1_bid_service_proto_rawDesc = "" +
 	"UpdateItem\x12\x1a.bids.v1.UpdateItemRequest\x1a\x1b.bids.v1.UpdateItemResponse\x12E\n" +
 	"\n" +
 	"CancelItem\x12\x1a.bids.v1.CancelItemRequest\x1a\x1b.bids.v1.CancelItemResponse\x12H\n" +
-	"\vGetItemBids\x12\x1b.bids.v1.GetItemBidsRequest\x1a\x1c.bids.v1.GetItemBidsResponseB2Z0github.com/floroz/gavel/pkg/proto/bids/v1;bidsv1b\x06proto3"
+	"\vGetItemBids\x12\x1b.bids.v1.GetItemBidsRequest\x1a\x1c.bids.v1.GetItemBidsResponse\x12T\n" +
+	"\x0fForceCancelItem\x12\x1f.bids.v1.ForceCancelItemRequest\x1a .bids.v1.ForceCancelItemResponseB2Z0github.com/floroz/gavel/pkg/proto/bids/v1;bidsv1b\x06proto3"
 
 var (
 	file_bids_v1_bid_service_proto_rawDescOnce sync.Once
@@ -1258,59 +1508,69 @@ func file_bids_v1_bid_service_proto_rawDescGZIP() []byte {
 }
 
 var file_bids_v1_bid_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_bids_v1_bid_service_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_bids_v1_bid_service_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_bids_v1_bid_service_proto_goTypes = []any{
 	(ItemStatus)(0),                 // 0: bids.v1.ItemStatus
 	(*PlaceBidRequest)(nil),         // 1: bids.v1.PlaceBidRequest
 	(*PlaceBidResponse)(nil),        // 2: bids.v1.PlaceBidResponse
-	(*Bid)(nil),                     // 3: bids.v1.Bid
-	(*Item)(nil),                    // 4: bids.v1.Item
-	(*CreateItemRequest)(nil),       // 5: bids.v1.CreateItemRequest
-	(*CreateItemResponse)(nil),      // 6: bids.v1.CreateItemResponse
-	(*GetItemRequest)(nil),          // 7: bids.v1.GetItemRequest
-	(*GetItemResponse)(nil),         // 8: bids.v1.GetItemResponse
-	(*ListItemsRequest)(nil),        // 9: bids.v1.ListItemsRequest
-	(*ListItemsResponse)(nil),       // 10: bids.v1.ListItemsResponse
-	(*ListSellerItemsRequest)(nil),  // 11: bids.v1.ListSellerItemsRequest
-	(*ListSellerItemsResponse)(nil), // 12: bids.v1.ListSellerItemsResponse
-	(*UpdateItemRequest)(nil),       // 13: bids.v1.UpdateItemRequest
-	(*UpdateItemResponse)(nil),      // 14: bids.v1.UpdateItemResponse
-	(*CancelItemRequest)(nil),       // 15: bids.v1.CancelItemRequest
-	(*CancelItemResponse)(nil),      // 16: bids.v1.CancelItemResponse
-	(*GetItemBidsRequest)(nil),      // 17: bids.v1.GetItemBidsRequest
-	(*GetItemBidsResponse)(nil),     // 18: bids.v1.GetItemBidsResponse
+	(*PurchaseNowRequest)(nil),      // 3: bids.v1.PurchaseNowRequest
+	(*PurchaseNowResponse)(nil),     // 4: bids.v1.PurchaseNowResponse
+	(*Bid)(nil),                     // 5: bids.v1.Bid
+	(*Item)(nil),                    // 6: bids.v1.Item
+	(*CreateItemRequest)(nil),       // 7: bids.v1.CreateItemRequest
+	(*CreateItemResponse)(nil),      // 8: bids.v1.CreateItemResponse
+	(*GetItemRequest)(nil),          // 9: bids.v1.GetItemRequest
+	(*GetItemResponse)(nil),         // 10: bids.v1.GetItemResponse
+	(*ListItemsRequest)(nil),        // 11: bids.v1.ListItemsRequest
+	(*ListItemsResponse)(nil),       // 12: bids.v1.ListItemsResponse
+	(*ListSellerItemsRequest)(nil),  // 13: bids.v1.ListSellerItemsRequest
+	(*ListSellerItemsResponse)(nil), // 14: bids.v1.ListSellerItemsResponse
+	(*UpdateItemRequest)(nil),       // 15: bids.v1.UpdateItemRequest
+	(*UpdateItemResponse)(nil),      // 16: bids.v1.UpdateItemResponse
+	(*CancelItemRequest)(nil),       // 17: bids.v1.CancelItemRequest
+	(*CancelItemResponse)(nil),      // 18: bids.v1.CancelItemResponse
+	(*ForceCancelItemRequest)(nil),  // 19: bids.v1.ForceCancelItemRequest
+	(*ForceCancelItemResponse)(nil), // 20: bids.v1.ForceCancelItemResponse
+	(*GetItemBidsRequest)(nil),      // 21: bids.v1.GetItemBidsRequest
+	(*GetItemBidsResponse)(nil),     // 22: bids.v1.GetItemBidsResponse
 }
 var file_bids_v1_bid_service_proto_depIdxs = []int32{
-	3,  // 0: bids.v1.PlaceBidResponse.bid:type_name -> bids.v1.Bid
-	0,  // 1: bids.v1.Item.status:type_name -> bids.v1.ItemStatus
-	4,  // 2: bids.v1.CreateItemResponse.item:type_name -> bids.v1.Item
-	4,  // 3: bids.v1.GetItemResponse.item:type_name -> bids.v1.Item
-	4,  // 4: bids.v1.ListItemsResponse.items:type_name -> bids.v1.Item
-	4,  // 5: bids.v1.ListSellerItemsResponse.items:type_name -> bids.v1.Item
-	4,  // 6: bids.v1.UpdateItemResponse.item:type_name -> bids.v1.Item
-	4,  // 7: bids.v1.CancelItemResponse.item:type_name -> bids.v1.Item
-	3,  // 8: bids.v1.GetItemBidsResponse.bids:type_name -> bids.v1.Bid
-	1,  // 9: bids.v1.BidService.PlaceBid:input_type -> bids.v1.PlaceBidRequest
-	5,  // 10: bids.v1.BidService.CreateItem:input_type -> bids.v1.CreateItemRequest
-	7,  // 11: bids.v1.BidService.GetItem:input_type -> bids.v1.GetItemRequest
-	9,  // 12: bids.v1.BidService.ListItems:input_type -> bids.v1.ListItemsRequest
-	11, // 13: bids.v1.BidService.ListSellerItems:input_type -> bids.v1.ListSellerItemsRequest
-	13, // 14: bids.v1.BidService.UpdateItem:input_type -> bids.v1.UpdateItemRequest
-	15, // 15: bids.v1.BidService.CancelItem:input_type -> bids.v1.CancelItemRequest
-	17, // 16: bids.v1.BidService.GetItemBids:input_type -> bids.v1.GetItemBidsRequest
-	2,  // 17: bids.v1.BidService.PlaceBid:output_type -> bids.v1.PlaceBidResponse
-	6,  // 18: bids.v1.BidService.CreateItem:output_type -> bids.v1.CreateItemResponse
-	8,  // 19: bids.v1.BidService.GetItem:output_type -> bids.v1.GetItemResponse
-	10, // 20: bids.v1.BidService.ListItems:output_type -> bids.v1.ListItemsResponse
-	12, // 21: bids.v1.BidService.ListSellerItems:output_type -> bids.v1.ListSellerItemsResponse
-	14, // 22: bids.v1.BidService.UpdateItem:output_type -> bids.v1.UpdateItemResponse
-	16, // 23: bids.v1.BidService.CancelItem:output_type -> bids.v1.CancelItemResponse
-	18, // 24: bids.v1.BidService.GetItemBids:output_type -> bids.v1.GetItemBidsResponse
-	17, // [17:25] is the sub-list for method output_type
-	9,  // [9:17] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	5,  // 0: bids.v1.PlaceBidResponse.bid:type_name -> bids.v1.Bid
+	5,  // 1: bids.v1.PurchaseNowResponse.bid:type_name -> bids.v1.Bid
+	0,  // 2: bids.v1.Item.status:type_name -> bids.v1.ItemStatus
+	6,  // 3: bids.v1.CreateItemResponse.item:type_name -> bids.v1.Item
+	6,  // 4: bids.v1.GetItemResponse.item:type_name -> bids.v1.Item
+	6,  // 5: bids.v1.ListItemsResponse.items:type_name -> bids.v1.Item
+	6,  // 6: bids.v1.ListSellerItemsResponse.items:type_name -> bids.v1.Item
+	6,  // 7: bids.v1.UpdateItemResponse.item:type_name -> bids.v1.Item
+	6,  // 8: bids.v1.CancelItemResponse.item:type_name -> bids.v1.Item
+	6,  // 9: bids.v1.ForceCancelItemResponse.item:type_name -> bids.v1.Item
+	5,  // 10: bids.v1.GetItemBidsResponse.bids:type_name -> bids.v1.Bid
+	1,  // 11: bids.v1.BidService.PlaceBid:input_type -> bids.v1.PlaceBidRequest
+	3,  // 12: bids.v1.BidService.PurchaseNow:input_type -> bids.v1.PurchaseNowRequest
+	7,  // 13: bids.v1.BidService.CreateItem:input_type -> bids.v1.CreateItemRequest
+	9,  // 14: bids.v1.BidService.GetItem:input_type -> bids.v1.GetItemRequest
+	11, // 15: bids.v1.BidService.ListItems:input_type -> bids.v1.ListItemsRequest
+	13, // 16: bids.v1.BidService.ListSellerItems:input_type -> bids.v1.ListSellerItemsRequest
+	15, // 17: bids.v1.BidService.UpdateItem:input_type -> bids.v1.UpdateItemRequest
+	17, // 18: bids.v1.BidService.CancelItem:input_type -> bids.v1.CancelItemRequest
+	21, // 19: bids.v1.BidService.GetItemBids:input_type -> bids.v1.GetItemBidsRequest
+	19, // 20: bids.v1.BidService.ForceCancelItem:input_type -> bids.v1.ForceCancelItemRequest
+	2,  // 21: bids.v1.BidService.PlaceBid:output_type -> bids.v1.PlaceBidResponse
+	4,  // 22: bids.v1.BidService.PurchaseNow:output_type -> bids.v1.PurchaseNowResponse
+	8,  // 23: bids.v1.BidService.CreateItem:output_type -> bids.v1.CreateItemResponse
+	10, // 24: bids.v1.BidService.GetItem:output_type -> bids.v1.GetItemResponse
+	12, // 25: bids.v1.BidService.ListItems:output_type -> bids.v1.ListItemsResponse
+	14, // 26: bids.v1.BidService.ListSellerItems:output_type -> bids.v1.ListSellerItemsResponse
+	16, // 27: bids.v1.BidService.UpdateItem:output_type -> bids.v1.UpdateItemResponse
+	18, // 28: bids.v1.BidService.CancelItem:output_type -> bids.v1.CancelItemResponse
+	22, // 29: bids.v1.BidService.GetItemBids:output_type -> bids.v1.GetItemBidsResponse
+	20, // 30: bids.v1.BidService.ForceCancelItem:output_type -> bids.v1.ForceCancelItemResponse
+	21, // [21:31] is the sub-list for method output_type
+	11, // [11:21] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_bids_v1_bid_service_proto_init() }
@@ -1318,14 +1578,14 @@ func file_bids_v1_bid_service_proto_init() {
 	if File_bids_v1_bid_service_proto != nil {
 		return
 	}
-	file_bids_v1_bid_service_proto_msgTypes[12].OneofWrappers = []any{}
+	file_bids_v1_bid_service_proto_msgTypes[14].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bids_v1_bid_service_proto_rawDesc), len(file_bids_v1_bid_service_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   18,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
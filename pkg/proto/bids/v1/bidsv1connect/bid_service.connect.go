@@ -37,6 +37,8 @@ const (
 const (
 	// BidServicePlaceBidProcedure is the fully-qualified name of the BidService's PlaceBid RPC.
 	BidServicePlaceBidProcedure = "/bids.v1.BidService/PlaceBid"
+	// BidServicePurchaseNowProcedure is the fully-qualified name of the BidService's PurchaseNow RPC.
+	BidServicePurchaseNowProcedure = "/bids.v1.BidService/PurchaseNow"
 	// BidServiceCreateItemProcedure is the fully-qualified name of the BidService's CreateItem RPC.
 	BidServiceCreateItemProcedure = "/bids.v1.BidService/CreateItem"
 	// BidServiceGetItemProcedure is the fully-qualified name of the BidService's GetItem RPC.
@@ -52,11 +54,15 @@ const (
 	BidServiceCancelItemProcedure = "/bids.v1.BidService/CancelItem"
 	// BidServiceGetItemBidsProcedure is the fully-qualified name of the BidService's GetItemBids RPC.
 	BidServiceGetItemBidsProcedure = "/bids.v1.BidService/GetItemBids"
+	// BidServiceForceCancelItemProcedure is the fully-qualified name of the BidService's
+	// ForceCancelItem RPC.
+	BidServiceForceCancelItemProcedure = "/bids.v1.BidService/ForceCancelItem"
 )
 
 // BidServiceClient is a client for the bids.v1.BidService service.
 type BidServiceClient interface {
 	PlaceBid(context.Context, *connect.Request[v1.PlaceBidRequest]) (*connect.Response[v1.PlaceBidResponse], error)
+	PurchaseNow(context.Context, *connect.Request[v1.PurchaseNowRequest]) (*connect.Response[v1.PurchaseNowResponse], error)
 	// Item management
 	CreateItem(context.Context, *connect.Request[v1.CreateItemRequest]) (*connect.Response[v1.CreateItemResponse], error)
 	GetItem(context.Context, *connect.Request[v1.GetItemRequest]) (*connect.Response[v1.GetItemResponse], error)
@@ -65,6 +71,8 @@ type BidServiceClient interface {
 	UpdateItem(context.Context, *connect.Request[v1.UpdateItemRequest]) (*connect.Response[v1.UpdateItemResponse], error)
 	CancelItem(context.Context, *connect.Request[v1.CancelItemRequest]) (*connect.Response[v1.CancelItemResponse], error)
 	GetItemBids(context.Context, *connect.Request[v1.GetItemBidsRequest]) (*connect.Response[v1.GetItemBidsResponse], error)
+	// Moderation, limited to callers holding the force_cancel:items permission
+	ForceCancelItem(context.Context, *connect.Request[v1.ForceCancelItemRequest]) (*connect.Response[v1.ForceCancelItemResponse], error)
 }
 
 // NewBidServiceClient constructs a client for the bids.v1.BidService service. By default, it uses
@@ -84,6 +92,12 @@ func NewBidServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...
 			connect.WithSchema(bidServiceMethods.ByName("PlaceBid")),
 			connect.WithClientOptions(opts...),
 		),
+		purchaseNow: connect.NewClient[v1.PurchaseNowRequest, v1.PurchaseNowResponse](
+			httpClient,
+			baseURL+BidServicePurchaseNowProcedure,
+			connect.WithSchema(bidServiceMethods.ByName("PurchaseNow")),
+			connect.WithClientOptions(opts...),
+		),
 		createItem: connect.NewClient[v1.CreateItemRequest, v1.CreateItemResponse](
 			httpClient,
 			baseURL+BidServiceCreateItemProcedure,
@@ -126,12 +140,19 @@ func NewBidServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...
 			connect.WithSchema(bidServiceMethods.ByName("GetItemBids")),
 			connect.WithClientOptions(opts...),
 		),
+		forceCancelItem: connect.NewClient[v1.ForceCancelItemRequest, v1.ForceCancelItemResponse](
+			httpClient,
+			baseURL+BidServiceForceCancelItemProcedure,
+			connect.WithSchema(bidServiceMethods.ByName("ForceCancelItem")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // bidServiceClient implements BidServiceClient.
 type bidServiceClient struct {
 	placeBid        *connect.Client[v1.PlaceBidRequest, v1.PlaceBidResponse]
+	purchaseNow     *connect.Client[v1.PurchaseNowRequest, v1.PurchaseNowResponse]
 	createItem      *connect.Client[v1.CreateItemRequest, v1.CreateItemResponse]
 	getItem         *connect.Client[v1.GetItemRequest, v1.GetItemResponse]
 	listItems       *connect.Client[v1.ListItemsRequest, v1.ListItemsResponse]
@@ -139,6 +160,7 @@ type bidServiceClient struct {
 	updateItem      *connect.Client[v1.UpdateItemRequest, v1.UpdateItemResponse]
 	cancelItem      *connect.Client[v1.CancelItemRequest, v1.CancelItemResponse]
 	getItemBids     *connect.Client[v1.GetItemBidsRequest, v1.GetItemBidsResponse]
+	forceCancelItem *connect.Client[v1.ForceCancelItemRequest, v1.ForceCancelItemResponse]
 }
 
 // PlaceBid calls bids.v1.BidService.PlaceBid.
@@ -146,6 +168,11 @@ func (c *bidServiceClient) PlaceBid(ctx context.Context, req *connect.Request[v1
 	return c.placeBid.CallUnary(ctx, req)
 }
 
+// PurchaseNow calls bids.v1.BidService.PurchaseNow.
+func (c *bidServiceClient) PurchaseNow(ctx context.Context, req *connect.Request[v1.PurchaseNowRequest]) (*connect.Response[v1.PurchaseNowResponse], error) {
+	return c.purchaseNow.CallUnary(ctx, req)
+}
+
 // CreateItem calls bids.v1.BidService.CreateItem.
 func (c *bidServiceClient) CreateItem(ctx context.Context, req *connect.Request[v1.CreateItemRequest]) (*connect.Response[v1.CreateItemResponse], error) {
 	return c.createItem.CallUnary(ctx, req)
@@ -181,9 +208,15 @@ func (c *bidServiceClient) GetItemBids(ctx context.Context, req *connect.Request
 	return c.getItemBids.CallUnary(ctx, req)
 }
 
+// ForceCancelItem calls bids.v1.BidService.ForceCancelItem.
+func (c *bidServiceClient) ForceCancelItem(ctx context.Context, req *connect.Request[v1.ForceCancelItemRequest]) (*connect.Response[v1.ForceCancelItemResponse], error) {
+	return c.forceCancelItem.CallUnary(ctx, req)
+}
+
 // BidServiceHandler is an implementation of the bids.v1.BidService service.
 type BidServiceHandler interface {
 	PlaceBid(context.Context, *connect.Request[v1.PlaceBidRequest]) (*connect.Response[v1.PlaceBidResponse], error)
+	PurchaseNow(context.Context, *connect.Request[v1.PurchaseNowRequest]) (*connect.Response[v1.PurchaseNowResponse], error)
 	// Item management
 	CreateItem(context.Context, *connect.Request[v1.CreateItemRequest]) (*connect.Response[v1.CreateItemResponse], error)
 	GetItem(context.Context, *connect.Request[v1.GetItemRequest]) (*connect.Response[v1.GetItemResponse], error)
@@ -192,6 +225,8 @@ type BidServiceHandler interface {
 	UpdateItem(context.Context, *connect.Request[v1.UpdateItemRequest]) (*connect.Response[v1.UpdateItemResponse], error)
 	CancelItem(context.Context, *connect.Request[v1.CancelItemRequest]) (*connect.Response[v1.CancelItemResponse], error)
 	GetItemBids(context.Context, *connect.Request[v1.GetItemBidsRequest]) (*connect.Response[v1.GetItemBidsResponse], error)
+	// Moderation, limited to callers holding the force_cancel:items permission
+	ForceCancelItem(context.Context, *connect.Request[v1.ForceCancelItemRequest]) (*connect.Response[v1.ForceCancelItemResponse], error)
 }
 
 // NewBidServiceHandler builds an HTTP handler from the service implementation. It returns the path
@@ -207,6 +242,12 @@ func NewBidServiceHandler(svc BidServiceHandler, opts ...connect.HandlerOption)
 		connect.WithSchema(bidServiceMethods.ByName("PlaceBid")),
 		connect.WithHandlerOptions(opts...),
 	)
+	bidServicePurchaseNowHandler := connect.NewUnaryHandler(
+		BidServicePurchaseNowProcedure,
+		svc.PurchaseNow,
+		connect.WithSchema(bidServiceMethods.ByName("PurchaseNow")),
+		connect.WithHandlerOptions(opts...),
+	)
 	bidServiceCreateItemHandler := connect.NewUnaryHandler(
 		BidServiceCreateItemProcedure,
 		svc.CreateItem,
@@ -249,10 +290,18 @@ func NewBidServiceHandler(svc BidServiceHandler, opts ...connect.HandlerOption)
 		connect.WithSchema(bidServiceMethods.ByName("GetItemBids")),
 		connect.WithHandlerOptions(opts...),
 	)
+	bidServiceForceCancelItemHandler := connect.NewUnaryHandler(
+		BidServiceForceCancelItemProcedure,
+		svc.ForceCancelItem,
+		connect.WithSchema(bidServiceMethods.ByName("ForceCancelItem")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/bids.v1.BidService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case BidServicePlaceBidProcedure:
 			bidServicePlaceBidHandler.ServeHTTP(w, r)
+		case BidServicePurchaseNowProcedure:
+			bidServicePurchaseNowHandler.ServeHTTP(w, r)
 		case BidServiceCreateItemProcedure:
 			bidServiceCreateItemHandler.ServeHTTP(w, r)
 		case BidServiceGetItemProcedure:
@@ -267,6 +316,8 @@ func NewBidServiceHandler(svc BidServiceHandler, opts ...connect.HandlerOption)
 			bidServiceCancelItemHandler.ServeHTTP(w, r)
 		case BidServiceGetItemBidsProcedure:
 			bidServiceGetItemBidsHandler.ServeHTTP(w, r)
+		case BidServiceForceCancelItemProcedure:
+			bidServiceForceCancelItemHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -280,6 +331,10 @@ func (UnimplementedBidServiceHandler) PlaceBid(context.Context, *connect.Request
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bids.v1.BidService.PlaceBid is not implemented"))
 }
 
+func (UnimplementedBidServiceHandler) PurchaseNow(context.Context, *connect.Request[v1.PurchaseNowRequest]) (*connect.Response[v1.PurchaseNowResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bids.v1.BidService.PurchaseNow is not implemented"))
+}
+
 func (UnimplementedBidServiceHandler) CreateItem(context.Context, *connect.Request[v1.CreateItemRequest]) (*connect.Response[v1.CreateItemResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bids.v1.BidService.CreateItem is not implemented"))
 }
@@ -307,3 +362,7 @@ func (UnimplementedBidServiceHandler) CancelItem(context.Context, *connect.Reque
 func (UnimplementedBidServiceHandler) GetItemBids(context.Context, *connect.Request[v1.GetItemBidsRequest]) (*connect.Response[v1.GetItemBidsResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bids.v1.BidService.GetItemBids is not implemented"))
 }
+
+func (UnimplementedBidServiceHandler) ForceCancelItem(context.Context, *connect.Request[v1.ForceCancelItemRequest]) (*connect.Response[v1.ForceCancelItemResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bids.v1.BidService.ForceCancelItem is not implemented"))
+}
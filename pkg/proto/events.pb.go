@@ -177,6 +177,375 @@ func (x *UserCreated) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+// UserLoggedIn event is published when a user successfully logs in
+type UserLoggedIn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`               // UUID of the user
+	IpAddress     string                 `protobuf:"bytes,2,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`      // Client IP address, if known
+	UserAgent     string                 `protobuf:"bytes,3,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`      // Client user agent, if known
+	LoggedInAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=logged_in_at,json=loggedInAt,proto3" json:"logged_in_at,omitempty"` // When the login happened
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserLoggedIn) Reset() {
+	*x = UserLoggedIn{}
+	mi := &file_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserLoggedIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserLoggedIn) ProtoMessage() {}
+
+func (x *UserLoggedIn) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserLoggedIn.ProtoReflect.Descriptor instead.
+func (*UserLoggedIn) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UserLoggedIn) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserLoggedIn) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *UserLoggedIn) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *UserLoggedIn) GetLoggedInAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LoggedInAt
+	}
+	return nil
+}
+
+// ItemPurchased event is published when a buyer ends an auction at its buy-now price
+type ItemPurchased struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`                // UUID of the item
+	BuyerId       string                 `protobuf:"bytes,2,opt,name=buyer_id,json=buyerId,proto3" json:"buyer_id,omitempty"`             // UUID of the buyer
+	BidId         string                 `protobuf:"bytes,3,opt,name=bid_id,json=bidId,proto3" json:"bid_id,omitempty"`                   // UUID of the winning bid
+	Amount        int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`                             // Purchase price in cents/micros
+	PurchasedAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=purchased_at,json=purchasedAt,proto3" json:"purchased_at,omitempty"` // When the purchase happened
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ItemPurchased) Reset() {
+	*x = ItemPurchased{}
+	mi := &file_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemPurchased) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemPurchased) ProtoMessage() {}
+
+func (x *ItemPurchased) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemPurchased.ProtoReflect.Descriptor instead.
+func (*ItemPurchased) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ItemPurchased) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *ItemPurchased) GetBuyerId() string {
+	if x != nil {
+		return x.BuyerId
+	}
+	return ""
+}
+
+func (x *ItemPurchased) GetBidId() string {
+	if x != nil {
+		return x.BidId
+	}
+	return ""
+}
+
+func (x *ItemPurchased) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ItemPurchased) GetPurchasedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PurchasedAt
+	}
+	return nil
+}
+
+// UserOutbid event is published when a new bid takes the lead from another user
+type UserOutbid struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`       // UUID of the item
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`       // UUID of the user who lost the lead
+	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`                    // New highest bid in cents/micros
+	OutbidAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=outbid_at,json=outbidAt,proto3" json:"outbid_at,omitempty"` // When the lead changed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserOutbid) Reset() {
+	*x = UserOutbid{}
+	mi := &file_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserOutbid) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserOutbid) ProtoMessage() {}
+
+func (x *UserOutbid) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserOutbid.ProtoReflect.Descriptor instead.
+func (*UserOutbid) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UserOutbid) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *UserOutbid) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserOutbid) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *UserOutbid) GetOutbidAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OutbidAt
+	}
+	return nil
+}
+
+// ItemCancelled event is published when a seller cancels their auction
+type ItemCancelled struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`                // UUID of the item
+	SellerId      string                 `protobuf:"bytes,2,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`          // UUID of the item's seller, whether they or a moderator cancelled it
+	CancelledAt   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"` // When the item was cancelled
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ItemCancelled) Reset() {
+	*x = ItemCancelled{}
+	mi := &file_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemCancelled) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemCancelled) ProtoMessage() {}
+
+func (x *ItemCancelled) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemCancelled.ProtoReflect.Descriptor instead.
+func (*ItemCancelled) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ItemCancelled) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *ItemCancelled) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *ItemCancelled) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
+// AuctionEnded event is published when an auction passes its end time and is closed
+type AuctionEnded struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`                     // UUID of the item
+	SellerId      string                 `protobuf:"bytes,2,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`               // UUID of the seller
+	Sold          bool                   `protobuf:"varint,3,opt,name=sold,proto3" json:"sold,omitempty"`                                      // Whether the highest bid met the reserve price
+	WinnerId      string                 `protobuf:"bytes,4,opt,name=winner_id,json=winnerId,proto3" json:"winner_id,omitempty"`               // UUID of the winning bidder, empty if unsold
+	WinningBidId  string                 `protobuf:"bytes,5,opt,name=winning_bid_id,json=winningBidId,proto3" json:"winning_bid_id,omitempty"` // UUID of the winning bid, empty if unsold
+	Amount        int64                  `protobuf:"varint,6,opt,name=amount,proto3" json:"amount,omitempty"`                                  // Winning amount in cents/micros, 0 if unsold
+	EndedAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"`                  // When the auction was closed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuctionEnded) Reset() {
+	*x = AuctionEnded{}
+	mi := &file_events_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuctionEnded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuctionEnded) ProtoMessage() {}
+
+func (x *AuctionEnded) ProtoReflect() protoreflect.Message {
+	mi := &file_events_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuctionEnded.ProtoReflect.Descriptor instead.
+func (*AuctionEnded) Descriptor() ([]byte, []int) {
+	return file_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AuctionEnded) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *AuctionEnded) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *AuctionEnded) GetSold() bool {
+	if x != nil {
+		return x.Sold
+	}
+	return false
+}
+
+func (x *AuctionEnded) GetWinnerId() string {
+	if x != nil {
+		return x.WinnerId
+	}
+	return ""
+}
+
+func (x *AuctionEnded) GetWinningBidId() string {
+	if x != nil {
+		return x.WinningBidId
+	}
+	return ""
+}
+
+func (x *AuctionEnded) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *AuctionEnded) GetEndedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndedAt
+	}
+	return nil
+}
+
 var File_events_proto protoreflect.FileDescriptor
 
 const file_events_proto_rawDesc = "" +
@@ -187,14 +556,46 @@ const file_events_proto_rawDesc = "" +
 	"\aitem_id\x18\x02 \x01(\tR\x06itemId\x12\x17\n" +
 	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x16\n" +
 	"\x06amount\x18\x04 \x01(\x03R\x06amount\x128\n" +
-	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xb7\x01\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xd7\x01\n" +
 	"\vUserCreated\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1b\n" +
 	"\tfull_name\x18\x03 \x01(\tR\bfullName\x12!\n" +
 	"\fcountry_code\x18\x04 \x01(\tR\vcountryCode\x129\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB&Z$github.com/floroz/gavel/pkg/proto;pbb\x06proto3"
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtJ\x04\b\x06\x10\aR\x18email_verification_token\"\xa3\x01\n" +
+	"\fUserLoggedIn\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x02 \x01(\tR\tipAddress\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x03 \x01(\tR\tuserAgent\x12<\n" +
+	"\flogged_in_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"loggedInAt\"\xb1\x01\n" +
+	"\rItemPurchased\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\tR\x06itemId\x12\x19\n" +
+	"\bbuyer_id\x18\x02 \x01(\tR\abuyerId\x12\x15\n" +
+	"\x06bid_id\x18\x03 \x01(\tR\x05bidId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12=\n" +
+	"\fpurchased_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vpurchasedAt\"\x8f\x01\n" +
+	"\n" +
+	"UserOutbid\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\tR\x06itemId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x127\n" +
+	"\toutbid_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\boutbidAt\"\x84\x01\n" +
+	"\rItemCancelled\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\tR\x06itemId\x12\x1b\n" +
+	"\tseller_id\x18\x02 \x01(\tR\bsellerId\x12=\n" +
+	"\fcancelled_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\"\xea\x01\n" +
+	"\fAuctionEnded\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\tR\x06itemId\x12\x1b\n" +
+	"\tseller_id\x18\x02 \x01(\tR\bsellerId\x12\x12\n" +
+	"\x04sold\x18\x03 \x01(\bR\x04sold\x12\x1b\n" +
+	"\twinner_id\x18\x04 \x01(\tR\bwinnerId\x12$\n" +
+	"\x0ewinning_bid_id\x18\x05 \x01(\tR\fwinningBidId\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x03R\x06amount\x125\n" +
+	"\bended_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\aendedAtB&Z$github.com/floroz/gavel/pkg/proto;pbb\x06proto3"
 
 var (
 	file_events_proto_rawDescOnce sync.Once
@@ -208,20 +609,30 @@ func file_events_proto_rawDescGZIP() []byte {
 	return file_events_proto_rawDescData
 }
 
-var file_events_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_events_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_events_proto_goTypes = []any{
 	(*BidPlaced)(nil),             // 0: events.BidPlaced
 	(*UserCreated)(nil),           // 1: events.UserCreated
-	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	(*UserLoggedIn)(nil),          // 2: events.UserLoggedIn
+	(*ItemPurchased)(nil),         // 3: events.ItemPurchased
+	(*UserOutbid)(nil),            // 4: events.UserOutbid
+	(*ItemCancelled)(nil),         // 5: events.ItemCancelled
+	(*AuctionEnded)(nil),          // 6: events.AuctionEnded
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
 }
 var file_events_proto_depIdxs = []int32{
-	2, // 0: events.BidPlaced.timestamp:type_name -> google.protobuf.Timestamp
-	2, // 1: events.UserCreated.created_at:type_name -> google.protobuf.Timestamp
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	7, // 0: events.BidPlaced.timestamp:type_name -> google.protobuf.Timestamp
+	7, // 1: events.UserCreated.created_at:type_name -> google.protobuf.Timestamp
+	7, // 2: events.UserLoggedIn.logged_in_at:type_name -> google.protobuf.Timestamp
+	7, // 3: events.ItemPurchased.purchased_at:type_name -> google.protobuf.Timestamp
+	7, // 4: events.UserOutbid.outbid_at:type_name -> google.protobuf.Timestamp
+	7, // 5: events.ItemCancelled.cancelled_at:type_name -> google.protobuf.Timestamp
+	7, // 6: events.AuctionEnded.ended_at:type_name -> google.protobuf.Timestamp
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_events_proto_init() }
@@ -235,7 +646,7 @@ func file_events_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_proto_rawDesc), len(file_events_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
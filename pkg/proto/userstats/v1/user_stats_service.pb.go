@@ -24,6 +24,7 @@ const (
 
 type GetUserStatsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Whose stats to fetch; empty means the caller's own
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -58,6 +59,13 @@ func (*GetUserStatsRequest) Descriptor() ([]byte, []int) {
 	return file_userstats_v1_user_stats_service_proto_rawDescGZIP(), []int{0}
 }
 
+func (x *GetUserStatsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
 type UserStatsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Stats         *UserStats             `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
@@ -103,13 +111,15 @@ func (x *UserStatsResponse) GetStats() *UserStats {
 }
 
 type UserStats struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	TotalBids     int64                  `protobuf:"varint,2,opt,name=total_bids,json=totalBids,proto3" json:"total_bids,omitempty"`
-	TotalAmount   int64                  `protobuf:"varint,3,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`        // Total value of bids placed
-	LastUpdatedAt string                 `protobuf:"bytes,4,opt,name=last_updated_at,json=lastUpdatedAt,proto3" json:"last_updated_at,omitempty"` // ISO 8601 string
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	UserId           string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TotalBids        int64                  `protobuf:"varint,2,opt,name=total_bids,json=totalBids,proto3" json:"total_bids,omitempty"`
+	TotalAmount      int64                  `protobuf:"varint,3,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`        // Total value of bids placed
+	LastUpdatedAt    string                 `protobuf:"bytes,4,opt,name=last_updated_at,json=lastUpdatedAt,proto3" json:"last_updated_at,omitempty"` // ISO 8601 string, time of the last bid; empty if the user has not bid
+	TotalAuctionsWon int64                  `protobuf:"varint,5,opt,name=total_auctions_won,json=totalAuctionsWon,proto3" json:"total_auctions_won,omitempty"`
+	TotalSpent       int64                  `protobuf:"varint,6,opt,name=total_spent,json=totalSpent,proto3" json:"total_spent,omitempty"` // Total value of winning bids in auctions won
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *UserStats) Reset() {
@@ -170,20 +180,38 @@ func (x *UserStats) GetLastUpdatedAt() string {
 	return ""
 }
 
+func (x *UserStats) GetTotalAuctionsWon() int64 {
+	if x != nil {
+		return x.TotalAuctionsWon
+	}
+	return 0
+}
+
+func (x *UserStats) GetTotalSpent() int64 {
+	if x != nil {
+		return x.TotalSpent
+	}
+	return 0
+}
+
 var File_userstats_v1_user_stats_service_proto protoreflect.FileDescriptor
 
 const file_userstats_v1_user_stats_service_proto_rawDesc = "" +
 	"\n" +
-	"%userstats/v1/user_stats_service.proto\x12\fuserstats.v1\"\x15\n" +
-	"\x13GetUserStatsRequest\"B\n" +
+	"%userstats/v1/user_stats_service.proto\x12\fuserstats.v1\".\n" +
+	"\x13GetUserStatsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"B\n" +
 	"\x11UserStatsResponse\x12-\n" +
-	"\x05stats\x18\x01 \x01(\v2\x17.userstats.v1.UserStatsR\x05stats\"\x8e\x01\n" +
+	"\x05stats\x18\x01 \x01(\v2\x17.userstats.v1.UserStatsR\x05stats\"\xdd\x01\n" +
 	"\tUserStats\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
 	"\n" +
 	"total_bids\x18\x02 \x01(\x03R\ttotalBids\x12!\n" +
 	"\ftotal_amount\x18\x03 \x01(\x03R\vtotalAmount\x12&\n" +
-	"\x0flast_updated_at\x18\x04 \x01(\tR\rlastUpdatedAt2f\n" +
+	"\x0flast_updated_at\x18\x04 \x01(\tR\rlastUpdatedAt\x12,\n" +
+	"\x12total_auctions_won\x18\x05 \x01(\x03R\x10totalAuctionsWon\x12\x1f\n" +
+	"\vtotal_spent\x18\x06 \x01(\x03R\n" +
+	"totalSpent2f\n" +
 	"\x10UserStatsService\x12R\n" +
 	"\fGetUserStats\x12!.userstats.v1.GetUserStatsRequest\x1a\x1f.userstats.v1.UserStatsResponseB<Z:github.com/floroz/gavel/pkg/proto/userstats/v1;userstatsv1b\x06proto3"
 
@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnavailable is returned, as CodeUnavailable, when a check fails closed
+var ErrUnavailable = errors.New("service temporarily unavailable, try again later")
+
+// FailurePolicy decides what happens to a call when the Redis behind a check cannot be reached
+type FailurePolicy int
+
+const (
+	// FailOpen lets the call through and logs a warning, so an outage does not lock everyone out.
+	// It suits checks that only add protection, such as rate limiting.
+	FailOpen FailurePolicy = iota
+	// FailClosed rejects the call with CodeUnavailable. It suits deployments that would rather
+	// refuse logins than take them unthrottled while Redis is down.
+	FailClosed
+)
+
+// DefaultRateLimitFailurePolicy is the policy of the Limiter unless WithFailurePolicy says otherwise
+const DefaultRateLimitFailurePolicy = FailOpen
+
+// ParseFailurePolicy parses "open" or "closed", as set in configuration
+func ParseFailurePolicy(s string) (FailurePolicy, error) {
+	switch s {
+	case "open":
+		return FailOpen, nil
+	case "closed":
+		return FailClosed, nil
+	}
+	return 0, fmt.Errorf("invalid failure policy %q, want open or closed", s)
+}
+
+// String returns the policy as ParseFailurePolicy reads it
+func (p FailurePolicy) String() string {
+	if p == FailClosed {
+		return "closed"
+	}
+	return "open"
+}
@@ -0,0 +1,212 @@
+// Package ratelimit limits how often a client may call selected Connect procedures.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// RetryAfterHeader tells a limited caller how many seconds to wait before trying again
+const RetryAfterHeader = "Retry-After"
+
+// ForwardedForHeader lists the client and proxies a request passed through
+const ForwardedForHeader = "X-Forwarded-For"
+
+// ErrRateLimited is returned, as CodeResourceExhausted, to callers over their limit
+var ErrRateLimited = errors.New("too many requests, try again later")
+
+// slidingWindow keeps the timestamps of a key's recent calls in a sorted set. It drops those
+// older than the window and records the new call if there is room, returning 0; otherwise it
+// returns how many milliseconds remain until the oldest call leaves the window.
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+if redis.call('ZCARD', key) < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	return 0
+end
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+return tonumber(oldest[2]) + window - now
+`)
+
+// Limiter allows up to limit calls per key in any sliding window, shared through Redis
+// so every instance of a service enforces the same budget
+type Limiter struct {
+	client        *redis.Client
+	limit         int
+	window        time.Duration
+	prefix        string
+	failurePolicy FailurePolicy
+	proxies       TrustedProxies
+}
+
+// Option configures optional Limiter behaviour
+type Option func(*Limiter)
+
+// WithFailurePolicy overrides DefaultRateLimitFailurePolicy, deciding what the interceptor
+// does with calls while Redis is unavailable
+func WithFailurePolicy(policy FailurePolicy) Option {
+	return func(l *Limiter) {
+		l.failurePolicy = policy
+	}
+}
+
+// WithTrustedProxies lets the interceptor identify clients by X-Forwarded-For when calls come
+// through one of proxies, see ClientIP. Without it clients are identified by the peer address.
+func WithTrustedProxies(proxies TrustedProxies) Option {
+	return func(l *Limiter) {
+		l.proxies = proxies
+	}
+}
+
+// NewLimiter creates a limiter allowing limit calls per key every window
+func NewLimiter(client *redis.Client, limit int, window time.Duration, opts ...Option) *Limiter {
+	l := &Limiter{
+		client:        client,
+		limit:         limit,
+		window:        window,
+		prefix:        "ratelimit:",
+		failurePolicy: DefaultRateLimitFailurePolicy,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Allow records a call for key if it is within the limit. Otherwise it reports how long
+// until the next call would be allowed.
+func (l *Limiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	now := time.Now().UnixMilli()
+	wait, err := slidingWindow.Run(ctx, l.client, []string{l.prefix + key},
+		now, l.window.Milliseconds(), l.limit, strconv.FormatInt(now, 10)+"-"+uuid.NewString(),
+	).Int64()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if wait > 0 {
+		return false, time.Duration(wait) * time.Millisecond, nil
+	}
+	return true, 0, nil
+}
+
+// NewInterceptor creates a ConnectRPC interceptor that limits calls to procedures per client IP.
+// Calls over the limit fail with CodeResourceExhausted and a Retry-After hint. If Redis is
+// unavailable the limiter's FailurePolicy applies: by default calls are let through, so an
+// outage does not lock everyone out.
+func NewInterceptor(limiter *Limiter, logger *slog.Logger, procedures ...string) connect.UnaryInterceptorFunc {
+	limited := make(map[string]bool, len(procedures))
+	for _, procedure := range procedures {
+		limited[procedure] = true
+	}
+
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			procedure := req.Spec().Procedure
+			if !limited[procedure] {
+				return next(ctx, req)
+			}
+
+			allowed, retryAfter, err := limiter.Allow(ctx, procedure+":"+ClientIP(req, limiter.proxies))
+			if err != nil {
+				if limiter.failurePolicy == FailClosed {
+					logger.Error("Rate limit check failed, rejecting request", "procedure", procedure, "error", err)
+					return nil, connect.NewError(connect.CodeUnavailable, ErrUnavailable)
+				}
+				logger.Warn("Rate limit check failed, allowing request", "procedure", procedure, "error", err)
+				return next(ctx, req)
+			}
+			if !allowed {
+				connectErr := connect.NewError(connect.CodeResourceExhausted, ErrRateLimited)
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				connectErr.Meta().Set(RetryAfterHeader, strconv.Itoa(seconds))
+				return nil, connectErr
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// TrustedProxies are the addresses of reverse proxies whose X-Forwarded-For entries are believed
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses a comma-separated list of IP addresses and CIDR ranges
+func ParseTrustedProxies(s string) (TrustedProxies, error) {
+	var proxies TrustedProxies
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(field); err == nil {
+			proxies = append(proxies, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", field, err)
+		}
+		proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return proxies, nil
+}
+
+// trusts reports whether ip, as written in a header or peer address, is one of the proxies
+func (p TrustedProxies) trusts(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range p {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP identifies the caller of req by the address the request came from. Only when that is
+// one of proxies is X-Forwarded-For consulted, taking the right-most entry that is not itself a
+// trusted proxy: entries to its left were written by the client and could be anything. Request
+// fields such as IpAddress are never used, as the client sets them too.
+func ClientIP(req connect.AnyRequest, proxies TrustedProxies) string {
+	ip := req.Peer().Addr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !proxies.trusts(ip) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(req.Header().Values(ForwardedForHeader), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		ip = hop
+		if !proxies.trusts(hop) {
+			break
+		}
+	}
+	return ip
+}
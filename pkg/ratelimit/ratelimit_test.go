@@ -0,0 +1,202 @@
+package ratelimit_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
+	"github.com/floroz/gavel/pkg/proto/auth/v1/authv1connect"
+	"github.com/floroz/gavel/pkg/ratelimit"
+	"github.com/floroz/gavel/pkg/testhelpers"
+)
+
+type stubAuthService struct {
+	authv1connect.UnimplementedAuthServiceHandler
+}
+
+func (stubAuthService) Login(context.Context, *connect.Request[authv1.LoginRequest]) (*connect.Response[authv1.LoginResponse], error) {
+	return connect.NewResponse(&authv1.LoginResponse{}), nil
+}
+
+func (stubAuthService) Register(context.Context, *connect.Request[authv1.RegisterRequest]) (*connect.Response[authv1.RegisterResponse], error) {
+	return connect.NewResponse(&authv1.RegisterResponse{}), nil
+}
+
+// ipRecorder answers Login with nothing, recording who ClientIP says called it
+type ipRecorder struct {
+	authv1connect.UnimplementedAuthServiceHandler
+	proxies ratelimit.TrustedProxies
+	ip      string
+}
+
+func (r *ipRecorder) Login(_ context.Context, req *connect.Request[authv1.LoginRequest]) (*connect.Response[authv1.LoginResponse], error) {
+	r.ip = ratelimit.ClientIP(req, r.proxies)
+	return connect.NewResponse(&authv1.LoginResponse{}), nil
+}
+
+func TestClientIP(t *testing.T) {
+	// clientIP calls Login from the loopback address with the given X-Forwarded-For
+	clientIP := func(t *testing.T, proxies string, forwardedFor ...string) string {
+		t.Helper()
+		trusted, err := ratelimit.ParseTrustedProxies(proxies)
+		require.NoError(t, err)
+		recorder := &ipRecorder{proxies: trusted}
+
+		mux := http.NewServeMux()
+		mux.Handle(authv1connect.NewAuthServiceHandler(recorder))
+		server := httptest.NewServer(mux)
+		t.Cleanup(server.Close)
+		client := authv1connect.NewAuthServiceClient(server.Client(), server.URL)
+
+		req := connect.NewRequest(&authv1.LoginRequest{IpAddress: "203.0.113.1"})
+		for _, value := range forwardedFor {
+			req.Header().Add(ratelimit.ForwardedForHeader, value)
+		}
+		_, err = client.Login(context.Background(), req)
+		require.NoError(t, err)
+		return recorder.ip
+	}
+
+	t.Run("peer address without trusted proxies", func(t *testing.T) {
+		assert.Equal(t, "127.0.0.1", clientIP(t, "", "198.51.100.7"), "the request field and header are ignored")
+	})
+
+	t.Run("right-most untrusted hop behind a trusted proxy", func(t *testing.T) {
+		assert.Equal(t, "198.51.100.7", clientIP(t, "127.0.0.1", "203.0.113.99, 198.51.100.7"),
+			"entries the client prepended are ignored")
+	})
+
+	t.Run("skips every trusted hop", func(t *testing.T) {
+		assert.Equal(t, "198.51.100.7", clientIP(t, "127.0.0.0/8, 10.0.0.0/8", "203.0.113.99", "198.51.100.7, 10.0.0.5"))
+	})
+
+	t.Run("trusted proxy without the header", func(t *testing.T) {
+		assert.Equal(t, "127.0.0.1", clientIP(t, "127.0.0.1"))
+	})
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ratelimit.ParseTrustedProxies(" 10.0.0.0/8, 192.168.1.1,,::1 ")
+	require.NoError(t, err)
+	assert.Len(t, proxies, 3)
+
+	_, err = ratelimit.ParseTrustedProxies("10.0.0.0/8, proxy.internal")
+	assert.Error(t, err)
+}
+
+func TestInterceptor_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	testRedis := testhelpers.NewTestRedis(t)
+	window := time.Second
+	// The test server sees every call from loopback, so it stands in for the proxy
+	proxies, err := ratelimit.ParseTrustedProxies("127.0.0.1")
+	require.NoError(t, err)
+	limiter := ratelimit.NewLimiter(testRedis.Client, 2, window, ratelimit.WithTrustedProxies(proxies))
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	mux := http.NewServeMux()
+	mux.Handle(authv1connect.NewAuthServiceHandler(stubAuthService{}, connect.WithInterceptors(
+		ratelimit.NewInterceptor(limiter, logger, authv1connect.AuthServiceLoginProcedure, authv1connect.AuthServiceRegisterProcedure),
+	)))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client := authv1connect.NewAuthServiceClient(server.Client(), server.URL)
+	ctx := context.Background()
+
+	login := func(ip string) error {
+		req := connect.NewRequest(&authv1.LoginRequest{Email: "user@example.com"})
+		req.Header().Set(ratelimit.ForwardedForHeader, ip)
+		_, err := client.Login(ctx, req)
+		return err
+	}
+
+	require.NoError(t, login("203.0.113.1"))
+	require.NoError(t, login("203.0.113.1"))
+
+	// The third call in the window is refused with a hint
+	err = login("203.0.113.1")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+	var connectErr *connect.Error
+	require.ErrorAs(t, err, &connectErr)
+	assert.Equal(t, "1", connectErr.Meta().Get(ratelimit.RetryAfterHeader))
+
+	// Other clients and procedures have their own budget
+	require.NoError(t, login("203.0.113.2"))
+	req := connect.NewRequest(&authv1.RegisterRequest{Email: "user@example.com"})
+	req.Header().Set(ratelimit.ForwardedForHeader, "203.0.113.1")
+	_, err = client.Register(ctx, req)
+	require.NoError(t, err)
+
+	// Once the window has passed the client may call again
+	time.Sleep(window)
+	assert.NoError(t, login("203.0.113.1"))
+}
+
+func TestInterceptor_RedisUnavailable(t *testing.T) {
+	// Nothing listens on port 1, so every check fails straight away
+	broken := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { broken.Close() })
+
+	newClient := func(t *testing.T, opts ...ratelimit.Option) authv1connect.AuthServiceClient {
+		t.Helper()
+		limiter := ratelimit.NewLimiter(broken, 2, time.Minute, opts...)
+		mux := http.NewServeMux()
+		mux.Handle(authv1connect.NewAuthServiceHandler(stubAuthService{}, connect.WithInterceptors(
+			ratelimit.NewInterceptor(limiter, slog.New(slog.DiscardHandler), authv1connect.AuthServiceLoginProcedure),
+		)))
+		server := httptest.NewServer(mux)
+		t.Cleanup(server.Close)
+		return authv1connect.NewAuthServiceClient(server.Client(), server.URL)
+	}
+	login := func(client authv1connect.AuthServiceClient) error {
+		_, err := client.Login(context.Background(), connect.NewRequest(&authv1.LoginRequest{Email: "user@example.com"}))
+		return err
+	}
+
+	t.Run("fails open by default", func(t *testing.T) {
+		client := newClient(t)
+		for range 3 {
+			assert.NoError(t, login(client), "calls are let through while Redis is down")
+		}
+	})
+
+	t.Run("fails closed", func(t *testing.T) {
+		client := newClient(t, ratelimit.WithFailurePolicy(ratelimit.FailClosed))
+		err := login(client)
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeUnavailable, connect.CodeOf(err))
+	})
+
+	t.Run("unlimited procedures are unaffected", func(t *testing.T) {
+		client := newClient(t, ratelimit.WithFailurePolicy(ratelimit.FailClosed))
+		_, err := client.Register(context.Background(), connect.NewRequest(&authv1.RegisterRequest{}))
+		assert.NoError(t, err)
+	})
+}
+
+func TestParseFailurePolicy(t *testing.T) {
+	for _, policy := range []ratelimit.FailurePolicy{ratelimit.FailOpen, ratelimit.FailClosed} {
+		parsed, err := ratelimit.ParseFailurePolicy(policy.String())
+		require.NoError(t, err)
+		assert.Equal(t, policy, parsed)
+	}
+
+	_, err := ratelimit.ParseFailurePolicy("sometimes")
+	assert.Error(t, err)
+
+	assert.Equal(t, ratelimit.FailOpen, ratelimit.DefaultRateLimitFailurePolicy)
+}
@@ -0,0 +1,222 @@
+package testhelpers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Seeder builds canonical rows for a test database. Rows are inserted in the order they are
+// declared, all in one transaction, and refer to earlier rows by name, so a seller is always
+// inserted before their item and an item before its bids.
+//
+// Users are inserted into the users table when the database has one (the auth service's).
+// Elsewhere, such as the bid service's database, they are only given IDs for items and bids to use.
+type Seeder struct {
+	pool  *pgxpool.Pool
+	steps []seedStep
+}
+
+type seedStep func(ctx context.Context, tx pgx.Tx, seeded *Seeded) error
+
+// Seeded holds the IDs generated for seeded rows
+type Seeded struct {
+	Users map[string]uuid.UUID
+	Items map[string]uuid.UUID
+	// Bids are in the order they were declared
+	Bids []uuid.UUID
+
+	hasUsersTable bool
+}
+
+// Seed starts a seed of pool. Nothing is written until Apply.
+func Seed(pool *pgxpool.Pool) *Seeder {
+	return &Seeder{pool: pool}
+}
+
+// UserOption configures a seeded user
+type UserOption func(*seedUser)
+
+type seedUser struct {
+	email    string
+	fullName string
+}
+
+// WithEmail overrides the generated email address
+func WithEmail(email string) UserOption {
+	return func(u *seedUser) {
+		u.email = email
+	}
+}
+
+// User seeds a user called name
+func (s *Seeder) User(name string, opts ...UserOption) *Seeder {
+	s.steps = append(s.steps, func(ctx context.Context, tx pgx.Tx, seeded *Seeded) error {
+		if _, exists := seeded.Users[name]; exists {
+			return fmt.Errorf("user %q seeded twice", name)
+		}
+		id := uuid.New()
+		user := seedUser{
+			email:    fmt.Sprintf("%s-%s@example.com", name, id.String()[:8]),
+			fullName: name,
+		}
+		for _, opt := range opts {
+			opt(&user)
+		}
+
+		if seeded.hasUsersTable {
+			query := `
+				INSERT INTO users (id, email, password_hash, full_name)
+				VALUES ($1, $2, 'seeded-password-hash', $3)
+			`
+			if _, err := tx.Exec(ctx, query, id, user.email, user.fullName); err != nil {
+				return fmt.Errorf("failed to seed user %q: %w", name, err)
+			}
+		}
+		seeded.Users[name] = id
+		return nil
+	})
+	return s
+}
+
+// ItemOption configures a seeded item
+type ItemOption func(*seedItem)
+
+type seedItem struct {
+	startPrice int64
+	endAt      time.Time
+	status     string
+}
+
+// WithStartPrice overrides the default start price of 1000 cents
+func WithStartPrice(cents int64) ItemOption {
+	return func(i *seedItem) {
+		i.startPrice = cents
+	}
+}
+
+// WithEndAt overrides the default end, a day from now
+func WithEndAt(endAt time.Time) ItemOption {
+	return func(i *seedItem) {
+		i.endAt = endAt
+	}
+}
+
+// WithItemStatus overrides the default "active" status
+func WithItemStatus(status string) ItemOption {
+	return func(i *seedItem) {
+		i.status = status
+	}
+}
+
+// Item seeds an item called name, sold by the user seeded as seller
+func (s *Seeder) Item(name, seller string, opts ...ItemOption) *Seeder {
+	s.steps = append(s.steps, func(ctx context.Context, tx pgx.Tx, seeded *Seeded) error {
+		if _, exists := seeded.Items[name]; exists {
+			return fmt.Errorf("item %q seeded twice", name)
+		}
+		sellerID, ok := seeded.Users[seller]
+		if !ok {
+			return fmt.Errorf("item %q: seller %q must be seeded first", name, seller)
+		}
+		item := seedItem{
+			startPrice: 1000,
+			endAt:      time.Now().Add(24 * time.Hour),
+			status:     "active",
+		}
+		for _, opt := range opts {
+			opt(&item)
+		}
+
+		id := uuid.New()
+		query := `
+			INSERT INTO items (id, title, description, start_price, end_at, category, seller_id, status)
+			VALUES ($1, $2, 'Seeded item', $3, $4, 'other', $5, $6::item_status)
+		`
+		if _, err := tx.Exec(ctx, query, id, name, item.startPrice, item.endAt, sellerID, item.status); err != nil {
+			return fmt.Errorf("failed to seed item %q: %w", name, err)
+		}
+		seeded.Items[name] = id
+		return nil
+	})
+	return s
+}
+
+// Bid seeds a bid of amount cents by bidder on item, raising the item's highest bid if it beats it
+func (s *Seeder) Bid(item, bidder string, amount int64) *Seeder {
+	s.steps = append(s.steps, func(ctx context.Context, tx pgx.Tx, seeded *Seeded) error {
+		itemID, ok := seeded.Items[item]
+		if !ok {
+			return fmt.Errorf("bid on %q: item must be seeded first", item)
+		}
+		bidderID, ok := seeded.Users[bidder]
+		if !ok {
+			return fmt.Errorf("bid on %q: bidder %q must be seeded first", item, bidder)
+		}
+
+		id := uuid.New()
+		// Space bids out so they keep their declared order in history
+		createdAt := time.Now().Add(time.Duration(len(seeded.Bids)) * time.Millisecond)
+		if _, err := tx.Exec(ctx,
+			"INSERT INTO bids (id, item_id, user_id, amount, created_at) VALUES ($1, $2, $3, $4, $5)",
+			id, itemID, bidderID, amount, createdAt,
+		); err != nil {
+			return fmt.Errorf("failed to seed bid on %q: %w", item, err)
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE items SET current_highest_bid = $1, current_highest_bidder_id = $2
+			WHERE id = $3 AND current_highest_bid < $1
+		`, amount, bidderID, itemID); err != nil {
+			return fmt.Errorf("failed to update highest bid of %q: %w", item, err)
+		}
+		seeded.Bids = append(seeded.Bids, id)
+		return nil
+	})
+	return s
+}
+
+// Apply inserts the seeded rows in one transaction, failing the test if any of them cannot be
+func (s *Seeder) Apply(t testing.TB) *Seeded {
+	t.Helper()
+
+	seeded, err := s.apply(context.Background())
+	if err != nil {
+		t.Fatalf("failed to seed database: %s", err)
+	}
+	return seeded
+}
+
+func (s *Seeder) apply(ctx context.Context) (*Seeded, error) {
+	seeded := &Seeded{
+		Users: make(map[string]uuid.UUID),
+		Items: make(map[string]uuid.UUID),
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := tx.QueryRow(ctx, "SELECT to_regclass('public.users') IS NOT NULL").Scan(&seeded.hasUsersTable); err != nil {
+		return nil, fmt.Errorf("failed to look up users table: %w", err)
+	}
+
+	for _, step := range s.steps {
+		if err := step(ctx, tx, seeded); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit seed: %w", err)
+	}
+	return seeded, nil
+}
@@ -0,0 +1,90 @@
+package testhelpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeed_BidDatabase(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	testDB := NewTestDatabase(t, "../../services/bid-service/migrations")
+	defer testDB.Close()
+	ctx := context.Background()
+
+	t.Run("seeds an item owned by a user and bids on it", func(t *testing.T) {
+		seeded := Seed(testDB.Pool).
+			User("alice").
+			User("bob").
+			User("carol").
+			Item("lamp", "alice", WithStartPrice(500)).
+			Bid("lamp", "bob", 600).
+			Bid("lamp", "carol", 900).
+			Apply(t)
+
+		var sellerID, bidderID uuid.UUID
+		var startPrice, highestBid int64
+		err := testDB.Pool.QueryRow(ctx,
+			"SELECT seller_id, start_price, current_highest_bid, current_highest_bidder_id FROM items WHERE id = $1",
+			seeded.Items["lamp"],
+		).Scan(&sellerID, &startPrice, &highestBid, &bidderID)
+		require.NoError(t, err)
+		assert.Equal(t, seeded.Users["alice"], sellerID)
+		assert.Equal(t, int64(500), startPrice)
+		assert.Equal(t, int64(900), highestBid)
+		assert.Equal(t, seeded.Users["carol"], bidderID)
+
+		rows, err := testDB.Pool.Query(ctx, "SELECT id FROM bids WHERE item_id = $1 ORDER BY created_at", seeded.Items["lamp"])
+		require.NoError(t, err)
+		var bidIDs []uuid.UUID
+		for rows.Next() {
+			var id uuid.UUID
+			require.NoError(t, rows.Scan(&id))
+			bidIDs = append(bidIDs, id)
+		}
+		require.NoError(t, rows.Err())
+		assert.Equal(t, seeded.Bids, bidIDs)
+	})
+
+	t.Run("a failed seed applies nothing", func(t *testing.T) {
+		_, err := Seed(testDB.Pool).
+			User("erin").
+			Item("clock", "erin").
+			Bid("clock", "nobody", 1500).
+			apply(ctx)
+		require.ErrorContains(t, err, `bidder "nobody" must be seeded first`)
+
+		var count int
+		require.NoError(t, testDB.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM items WHERE title = 'clock'").Scan(&count))
+		assert.Zero(t, count)
+	})
+
+	t.Run("sellers must be seeded before their items", func(t *testing.T) {
+		_, err := Seed(testDB.Pool).Item("vase", "frank").User("frank").apply(ctx)
+		require.ErrorContains(t, err, `seller "frank" must be seeded first`)
+	})
+}
+
+func TestSeed_AuthDatabase(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	testDB := NewTestDatabase(t, "../../services/auth-service/migrations")
+	defer testDB.Close()
+
+	seeded := Seed(testDB.Pool).
+		User("alice", WithEmail("alice@example.com")).
+		Apply(t)
+
+	var email string
+	err := testDB.Pool.QueryRow(context.Background(), "SELECT email FROM users WHERE id = $1", seeded.Users["alice"]).Scan(&email)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", email)
+}
@@ -0,0 +1,188 @@
+package testhelpers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pressly/goose/v3"
+)
+
+// templateDatabase is migrated once and copied by Clone
+const templateDatabase = "migrated_template"
+
+// SharedTestDatabase is one Postgres container shared by every test in a package, for suites where
+// starting a container per test dominates the run time. Create it in a package variable, hand it
+// to tests with Database or Clone and stop it from TestMain once m.Run returns.
+//
+// Database resets the data before each test: every table except goose's version table is truncated
+// with identities restarted, so rows seeded by migrations are gone as well. Schema changes made by a
+// test are not undone, and tests sharing the database must not call t.Parallel.
+//
+// Clone gives a test a database of its own, copied from a template migrated once per package, so
+// it starts exactly as migrations left it and may run in parallel. Use NewTestDatabase for tests
+// that need a server of their own.
+type SharedTestDatabase struct {
+	migrationsPath string
+
+	once sync.Once
+	db   *TestDatabase
+	err  error
+
+	templateOnce sync.Once
+	templateErr  error
+	cloneMu      sync.Mutex // serializes CREATE DATABASE, which locks the template
+	clones       atomic.Int64
+}
+
+// NewSharedTestDatabase prepares a shared database. The container starts on first use.
+func NewSharedTestDatabase(migrationsPath string) *SharedTestDatabase {
+	return &SharedTestDatabase{migrationsPath: migrationsPath}
+}
+
+// Database returns the shared database with all data removed, starting it if needed.
+// Its Close does nothing, so tests can treat it like one from NewTestDatabase.
+func (s *SharedTestDatabase) Database(t testing.TB) *TestDatabase {
+	t.Helper()
+
+	s.start(t)
+	if err := s.reset(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return s.db
+}
+
+// Clone returns a freshly migrated database in the shared container without re-running
+// migrations. Its Close drops it. If the template cannot be created or copied, Clone falls
+// back to NewTestDatabase.
+func (s *SharedTestDatabase) Clone(t testing.TB) *TestDatabase {
+	t.Helper()
+
+	s.start(t)
+	ctx := context.Background()
+
+	s.templateOnce.Do(func() {
+		s.templateErr = s.createTemplate(ctx)
+	})
+	if s.templateErr != nil {
+		t.Logf("template database unavailable, migrating a new container instead: %v", s.templateErr)
+		return NewTestDatabase(t, s.migrationsPath)
+	}
+
+	clone, err := s.clone(ctx, fmt.Sprintf("clone_%d", s.clones.Add(1)))
+	if err != nil {
+		t.Logf("failed to clone template database, migrating a new container instead: %v", err)
+		return NewTestDatabase(t, s.migrationsPath)
+	}
+	return clone
+}
+
+// start starts the container on first use
+func (s *SharedTestDatabase) start(t testing.TB) {
+	t.Helper()
+
+	s.once.Do(func() {
+		s.db, s.err = startTestDatabase(context.Background(), s.migrationsPath)
+		if s.err == nil {
+			s.db.release = func() {}
+		}
+	})
+	if s.err != nil {
+		t.Fatal(s.err)
+	}
+}
+
+// reset truncates every table in the public schema apart from goose's version table
+func (s *SharedTestDatabase) reset(ctx context.Context) error {
+	query := `
+		SELECT COALESCE(string_agg(format('%I.%I', schemaname, tablename), ', '), '')
+		FROM pg_tables
+		WHERE schemaname = 'public' AND tablename <> $1
+	`
+	var tables string
+	if err := s.db.Pool.QueryRow(ctx, query, goose.TableName()).Scan(&tables); err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+	if tables == "" {
+		return nil
+	}
+
+	if _, err := s.db.Pool.Exec(ctx, "TRUNCATE "+tables+" RESTART IDENTITY CASCADE"); err != nil {
+		return fmt.Errorf("failed to truncate tables: %w", err)
+	}
+	return nil
+}
+
+// createTemplate creates and migrates the template database. No connection to it is kept
+// open, as Postgres refuses to copy a database others are connected to.
+func (s *SharedTestDatabase) createTemplate(ctx context.Context) error {
+	if _, err := s.db.Pool.Exec(ctx, "CREATE DATABASE "+templateDatabase); err != nil {
+		return fmt.Errorf("failed to create template database: %w", err)
+	}
+
+	connStr, err := withDatabase(s.db.ConnStr, templateDatabase)
+	if err != nil {
+		return err
+	}
+	return migrateUp(connStr, s.migrationsPath)
+}
+
+// clone copies the template into a new database called name
+func (s *SharedTestDatabase) clone(ctx context.Context, name string) (*TestDatabase, error) {
+	s.cloneMu.Lock()
+	_, err := s.db.Pool.Exec(ctx, fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s",
+		pgx.Identifier{name}.Sanitize(), pgx.Identifier{templateDatabase}.Sanitize()))
+	s.cloneMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database from template: %w", err)
+	}
+
+	drop := func() {
+		_, _ = s.db.Pool.Exec(context.Background(), "DROP DATABASE IF EXISTS "+pgx.Identifier{name}.Sanitize()+" WITH (FORCE)")
+	}
+
+	connStr, err := withDatabase(s.db.ConnStr, name)
+	if err != nil {
+		drop()
+		return nil, err
+	}
+	pool, err := pgxpool.New(ctx, connStr)
+	if err != nil {
+		drop()
+		return nil, fmt.Errorf("failed to connect to cloned database: %w", err)
+	}
+
+	return &TestDatabase{
+		Container: s.db.Container,
+		Pool:      pool,
+		ConnStr:   connStr,
+		release: func() {
+			pool.Close()
+			drop()
+		},
+	}, nil
+}
+
+// withDatabase points a connection string at another database on the same server
+func withDatabase(connStr, name string) (string, error) {
+	u, err := url.Parse(connStr)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse connection string: %w", err)
+	}
+	u.Path = "/" + name
+	return u.String(), nil
+}
+
+// Close stops the container if it was started
+func (s *SharedTestDatabase) Close() {
+	if s.db == nil {
+		return
+	}
+	s.db.release = nil
+	s.db.Close()
+}
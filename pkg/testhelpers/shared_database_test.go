@@ -0,0 +1,117 @@
+package testhelpers_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/testhelpers"
+)
+
+const notesMigration = `-- +goose Up
+CREATE TABLE notes (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    body TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE notes;
+`
+
+var sharedDB *testhelpers.SharedTestDatabase
+
+// TestMain starts at most one container for the package and stops it once every test has run
+func TestMain(m *testing.M) {
+	migrations, err := os.MkdirTemp("", "testhelpers-migrations")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(migrations, "00001_notes.sql"), []byte(notesMigration), 0o600); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	sharedDB = testhelpers.NewSharedTestDatabase(migrations)
+	code := m.Run()
+	sharedDB.Close()
+	_ = os.RemoveAll(migrations)
+	os.Exit(code)
+}
+
+// insertNote checks the table starts empty with a fresh identity, whichever test runs first
+func insertNote(t *testing.T, body string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	testDB := sharedDB.Database(t)
+	defer testDB.Close() // no-op for the shared database
+	ctx := context.Background()
+
+	var count int
+	require.NoError(t, testDB.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM notes").Scan(&count))
+	assert.Zero(t, count, "rows from earlier tests should be truncated")
+
+	var id int64
+	require.NoError(t, testDB.Pool.QueryRow(ctx, "INSERT INTO notes (body) VALUES ($1) RETURNING id", body).Scan(&id))
+	assert.Equal(t, int64(1), id, "identities should restart")
+}
+
+func TestSharedTestDatabase_First(t *testing.T) {
+	insertNote(t, "first")
+}
+
+func TestSharedTestDatabase_Second(t *testing.T) {
+	insertNote(t, "second")
+}
+
+func TestSharedTestDatabase_Clone(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	ctx := context.Background()
+
+	first := sharedDB.Clone(t)
+	defer first.Close()
+	second := sharedDB.Clone(t)
+	defer second.Close()
+
+	// Clones come migrated and are independent of each other
+	_, err := first.Pool.Exec(ctx, "INSERT INTO notes (body) VALUES ('only in first')")
+	require.NoError(t, err)
+
+	var count int
+	require.NoError(t, second.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM notes").Scan(&count))
+	assert.Zero(t, count)
+	require.NoError(t, first.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM notes").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+// BenchmarkFreshDatabase compares migrating a new container per test with cloning the template,
+// using the bid service's migrations
+func BenchmarkFreshDatabase(b *testing.B) {
+	const migrations = "../../services/bid-service/migrations"
+
+	b.Run("NewTestDatabase", func(b *testing.B) {
+		for b.Loop() {
+			testhelpers.NewTestDatabase(b, migrations).Close()
+		}
+	})
+
+	b.Run("Clone", func(b *testing.B) {
+		shared := testhelpers.NewSharedTestDatabase(migrations)
+		defer shared.Close()
+		shared.Clone(b).Close() // start the container and build the template outside the timer
+
+		for b.Loop() {
+			shared.Clone(b).Close()
+		}
+	})
+}
@@ -20,12 +20,23 @@ type TestDatabase struct {
 	Container *postgres.PostgresContainer
 	Pool      *pgxpool.Pool
 	ConnStr   string
+
+	// release replaces the default Close for databases owned by a SharedTestDatabase
+	release func()
 }
 
-func NewTestDatabase(t *testing.T, migrationsPath string) *TestDatabase {
+func NewTestDatabase(t testing.TB, migrationsPath string) *TestDatabase {
 	t.Helper()
-	ctx := context.Background()
 
+	td, err := startTestDatabase(context.Background(), migrationsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return td
+}
+
+// startTestDatabase starts a Postgres container and migrates it up
+func startTestDatabase(ctx context.Context, migrationsPath string) (*TestDatabase, error) {
 	// Start Postgres container
 	pgContainer, err := postgres.Run(ctx,
 		"postgres:16-alpine",
@@ -38,52 +49,71 @@ func NewTestDatabase(t *testing.T, migrationsPath string) *TestDatabase {
 				WithStartupTimeout(5*time.Second)),
 	)
 	if err != nil {
-		t.Fatalf("failed to start postgres container: %s", err)
+		return nil, fmt.Errorf("failed to start postgres container: %w", err)
 	}
 
 	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
 	if err != nil {
-		t.Fatalf("failed to get connection string: %s", err)
+		_ = pgContainer.Terminate(ctx)
+		return nil, fmt.Errorf("failed to get connection string: %w", err)
 	}
 
 	// Connect to database with pgxpool
 	pool, err := pgxpool.New(ctx, connStr)
 	if err != nil {
-		t.Fatalf("failed to connect to database: %s", err)
+		_ = pgContainer.Terminate(ctx)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	td := &TestDatabase{
+		Container: pgContainer,
+		Pool:      pool,
+		ConnStr:   connStr,
 	}
 
 	if pingErr := pool.Ping(ctx); pingErr != nil {
-		t.Fatalf("failed to ping database: %s", pingErr)
+		td.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", pingErr)
 	}
 
-	// Run migrations using standard sql driver
-	db, openErr := sql.Open("pgx", connStr)
-	if openErr != nil {
-		t.Fatalf("failed to open sql db for migrations: %s", openErr)
+	if migrateErr := migrateUp(connStr, migrationsPath); migrateErr != nil {
+		td.Close()
+		return nil, migrateErr
+	}
+
+	return td, nil
+}
+
+// migrateUp runs migrations using standard sql driver
+func migrateUp(connStr, migrationsPath string) error {
+	db, err := sql.Open("pgx", connStr)
+	if err != nil {
+		return fmt.Errorf("failed to open sql db for migrations: %w", err)
 	}
 	defer db.Close()
 
 	if dialectErr := goose.SetDialect("postgres"); dialectErr != nil {
-		t.Fatalf("failed to set goose dialect: %s", dialectErr)
+		return fmt.Errorf("failed to set goose dialect: %w", dialectErr)
 	}
 
 	absPath, err := filepath.Abs(migrationsPath)
 	if err != nil {
-		t.Fatalf("failed to get absolute path for migrations: %s", err)
+		return fmt.Errorf("failed to get absolute path for migrations: %w", err)
 	}
 
 	if err := goose.Up(db, absPath); err != nil {
-		t.Fatalf("failed to run migrations: %s", err)
-	}
-
-	return &TestDatabase{
-		Container: pgContainer,
-		Pool:      pool,
-		ConnStr:   connStr,
+		return fmt.Errorf("failed to run migrations: %w", err)
 	}
+	return nil
 }
 
+// Close stops the container. For databases handed out by a SharedTestDatabase it leaves the
+// container running: Database's is kept as is and a Clone is dropped.
 func (td *TestDatabase) Close() {
+	if td.release != nil {
+		td.release()
+		return
+	}
 	ctx := context.Background()
 	td.Pool.Close()
 	if termErr := td.Container.Terminate(ctx); termErr != nil {
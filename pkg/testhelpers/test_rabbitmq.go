@@ -0,0 +1,77 @@
+package testhelpers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/testcontainers/testcontainers-go/modules/rabbitmq"
+)
+
+// rabbitMQReadyTimeout bounds how long NewTestRabbitMQ waits for the broker to accept connections
+const rabbitMQReadyTimeout = 30 * time.Second
+
+type TestRabbitMQ struct {
+	Container *rabbitmq.RabbitMQContainer
+	URL       string
+	Conn      *amqp.Connection
+}
+
+// NewTestRabbitMQ starts a RabbitMQ container and returns once a connection to it is open
+func NewTestRabbitMQ(t testing.TB) *TestRabbitMQ {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := rabbitmq.Run(ctx,
+		"rabbitmq:3.12-management-alpine",
+		rabbitmq.WithAdminPassword("password"),
+	)
+	if err != nil {
+		t.Fatalf("failed to start rabbitmq container: %s", err)
+	}
+
+	amqpURL, err := container.AmqpURL(ctx)
+	if err != nil {
+		_ = container.Terminate(ctx)
+		t.Fatalf("failed to get amqp url: %s", err)
+	}
+
+	conn, err := dialWhenReady(amqpURL, rabbitMQReadyTimeout)
+	if err != nil {
+		_ = container.Terminate(ctx)
+		t.Fatal(err)
+	}
+
+	return &TestRabbitMQ{
+		Container: container,
+		URL:       amqpURL,
+		Conn:      conn,
+	}
+}
+
+// dialWhenReady retries until the broker accepts connections, since the container
+// can report ready before the AMQP listener does
+func dialWhenReady(amqpURL string, timeout time.Duration) (*amqp.Connection, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := amqp.Dial(amqpURL)
+		if err == nil {
+			return conn, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("rabbitmq did not accept connections within %s: %w", timeout, err)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+func (tr *TestRabbitMQ) Close() {
+	ctx := context.Background()
+	_ = tr.Conn.Close()
+	if termErr := tr.Container.Terminate(ctx); termErr != nil {
+		// Just log error, don't fail test cleanup explicitly if container fails to stop
+		fmt.Printf("failed to terminate container: %v\n", termErr)
+	}
+}
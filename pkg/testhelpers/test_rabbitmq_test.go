@@ -0,0 +1,50 @@
+package testhelpers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/testhelpers"
+)
+
+func TestNewTestRabbitMQ(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	broker := testhelpers.NewTestRabbitMQ(t)
+	defer broker.Close()
+
+	ch, err := broker.Conn.Channel()
+	require.NoError(t, err)
+	defer ch.Close()
+
+	q, err := ch.QueueDeclare("", false, true, true, false, nil)
+	require.NoError(t, err)
+	msgs, err := ch.Consume(q.Name, "", true, false, false, false, nil)
+	require.NoError(t, err)
+
+	// Publish through the default exchange, which routes by queue name
+	err = ch.PublishWithContext(context.Background(), "", q.Name, false, false, amqp.Publishing{
+		ContentType: "text/plain",
+		Body:        []byte("hello"),
+	})
+	require.NoError(t, err)
+
+	select {
+	case msg := <-msgs:
+		assert.Equal(t, []byte("hello"), msg.Body)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for message from RabbitMQ")
+	}
+
+	// The URL works for connections of the test's own
+	conn, err := amqp.Dial(broker.URL)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
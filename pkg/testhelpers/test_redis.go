@@ -0,0 +1,69 @@
+package testhelpers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+type TestRedis struct {
+	Container *testcontainers.DockerContainer
+	Client    *redis.Client
+	Addr      string
+}
+
+// NewTestRedis starts a Redis container and returns once it answers PING
+func NewTestRedis(t testing.TB) *TestRedis {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := testcontainers.Run(ctx,
+		"redis:7-alpine",
+		testcontainers.WithExposedPorts("6379/tcp"),
+		testcontainers.WithWaitStrategy(
+			wait.ForExec([]string{"redis-cli", "ping"}).
+				WithResponseMatcher(func(body io.Reader) bool {
+					reply, readErr := io.ReadAll(body)
+					return readErr == nil && strings.Contains(string(reply), "PONG")
+				}).
+				WithStartupTimeout(10*time.Second)),
+	)
+	if err != nil {
+		t.Fatalf("failed to start redis container: %s", err)
+	}
+
+	addr, err := container.PortEndpoint(ctx, "6379/tcp", "")
+	if err != nil {
+		_ = container.Terminate(ctx)
+		t.Fatalf("failed to get redis address: %s", err)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if pingErr := client.Ping(ctx).Err(); pingErr != nil {
+		_ = client.Close()
+		_ = container.Terminate(ctx)
+		t.Fatalf("failed to ping redis: %s", pingErr)
+	}
+
+	return &TestRedis{
+		Container: container,
+		Client:    client,
+		Addr:      addr,
+	}
+}
+
+func (tr *TestRedis) Close() {
+	ctx := context.Background()
+	_ = tr.Client.Close()
+	if termErr := tr.Container.Terminate(ctx); termErr != nil {
+		// Just log error, don't fail test cleanup explicitly if container fails to stop
+		fmt.Printf("failed to terminate container: %v\n", termErr)
+	}
+}
@@ -0,0 +1,34 @@
+package testhelpers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/testhelpers"
+)
+
+func TestNewTestRedis(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	tr := testhelpers.NewTestRedis(t)
+	defer tr.Close()
+	ctx := context.Background()
+
+	require.NoError(t, tr.Client.Set(ctx, "greeting", "hello", time.Minute).Err())
+
+	value, err := tr.Client.Get(ctx, "greeting").Result()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", value)
+
+	// Addr works for clients of the test's own, like the one cmd/api builds from REDIS_URL
+	other := redis.NewClient(&redis.Options{Addr: tr.Addr})
+	defer other.Close()
+	assert.Equal(t, "hello", other.Get(ctx, "greeting").Val())
+}
@@ -3,23 +3,29 @@ package main
 import (
 	"context"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"connectrpc.com/connect"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
-	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/redis/go-redis/v9"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
 	"github.com/floroz/gavel/pkg/auth"
 	pkgdb "github.com/floroz/gavel/pkg/database"
-	pkgevents "github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/pkg/logging"
 	"github.com/floroz/gavel/pkg/proto/auth/v1/authv1connect"
+	"github.com/floroz/gavel/pkg/ratelimit"
 	"github.com/floroz/gavel/services/auth-service/internal/adapters/api"
 	"github.com/floroz/gavel/services/auth-service/internal/adapters/database"
-
+	"github.com/floroz/gavel/services/auth-service/internal/adapters/mailer"
+	"github.com/floroz/gavel/services/auth-service/internal/config"
 	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
 )
 
@@ -32,49 +38,33 @@ func main() {
 	_ = godotenv.Load(".env.local")
 	_ = godotenv.Load()
 
-	ctx := context.Background()
-
-	// 1. Load Keys
-	privateKeyPath := os.Getenv("JWT_PRIVATE_KEY_PATH")
-	publicKeyPath := os.Getenv("JWT_PUBLIC_KEY_PATH")
-
-	if privateKeyPath == "" || publicKeyPath == "" {
-		logger.Error("JWT_PRIVATE_KEY_PATH and JWT_PUBLIC_KEY_PATH must be set")
-		os.Exit(1)
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	privateKeyPEM, err := os.ReadFile(privateKeyPath)
-	if err != nil {
-		logger.Error("Failed to read private key", "path", privateKeyPath, "error", err)
-		os.Exit(1)
-	}
+	// Handle graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		logger.Info("Shutting down Auth Service API...")
+		cancel()
+	}()
 
-	publicKeyPEM, err := os.ReadFile(publicKeyPath)
+	cfg, err := config.LoadAPI()
 	if err != nil {
-		logger.Error("Failed to read public key", "path", publicKeyPath, "error", err)
+		logger.Error("Invalid configuration", "error", err)
 		os.Exit(1)
 	}
 
-	issuer := os.Getenv("JWT_ISSUER")
-	if issuer == "" {
-		logger.Error("JWT_ISSUER is not set")
-		os.Exit(1)
-	}
-
-	signer, err := auth.NewSigner(privateKeyPEM, publicKeyPEM, issuer)
+	// 1. Load Keys
+	signer, err := auth.LoadSigner(cfg.JWTPrivateKey, cfg.JWTPublicKey, cfg.JWTIssuer)
 	if err != nil {
 		logger.Error("Failed to create signer", "error", err)
 		os.Exit(1)
 	}
 
 	// 2. Initialize Postgres Connection Pool
-	dbURL := os.Getenv("AUTH_DB_URL")
-	if dbURL == "" {
-		logger.Error("AUTH_DB_URL is not set")
-		os.Exit(1)
-	}
-
-	dbConfig, err := pgxpool.ParseConfig(dbURL)
+	dbConfig, err := pkgdb.ParsePoolConfig(cfg.DatabaseURL, "AUTH")
 	if err != nil {
 		logger.Error("Unable to parse database config", "error", err)
 		os.Exit(1)
@@ -93,58 +83,67 @@ func main() {
 	}
 	logger.Info("Postgres Connected")
 
-	// 3. Initialize RabbitMQ
-	rabbitURL := os.Getenv("RABBITMQ_URL")
-	if rabbitURL == "" {
-		logger.Error("RABBITMQ_URL is not set")
-		os.Exit(1)
-	}
-	amqpConn, err := amqp.Dial(rabbitURL)
+	// 3. Initialize Repositories
+	txManager := pkgdb.NewPostgresTransactionManager(pool, 5*time.Second)
+	userRepo := database.NewPostgresUserRepository(pool)
+	tokenRepo := database.NewPostgresTokenRepository(pool)
+	outboxRepo := database.NewPostgresOutboxRepository(pool)
+
+	// 4. Initialize Service
+	hashing := cfg.PasswordHashing
+	passwordHasher, err := users.NewPasswordHasher(hashing.Algorithm, hashing.ArgonParams, hashing.BcryptCost)
 	if err != nil {
-		logger.Error("Failed to connect to RabbitMQ", "error", err)
+		logger.Error("Invalid password hashing configuration", "error", err)
 		os.Exit(1)
 	}
-	defer amqpConn.Close()
-	logger.Info("RabbitMQ Connected")
-
-	rabbitPublisher, err := pkgevents.NewRabbitMQPublisher(amqpConn)
+	var verificationMailer users.VerificationMailer
+	if cfg.SMTP.Addr != "" {
+		verificationMailer, err = mailer.NewSMTPMailer(cfg.SMTP, cfg.VerifyEmailURL)
+	} else {
+		logger.Warn("AUTH_SMTP_ADDR is not set, verification links will be logged instead of emailed")
+		verificationMailer, err = mailer.NewLogMailer(logger, cfg.VerifyEmailURL)
+	}
 	if err != nil {
-		logger.Error("Failed to create RabbitMQ publisher", "error", err)
+		logger.Error("Invalid mail configuration", "error", err)
 		os.Exit(1)
 	}
-	defer rabbitPublisher.Close()
-
-	// 4. Initialize Repositories
-	txManager := pkgdb.NewPostgresTransactionManager(pool, 5*time.Second)
-	userRepo := database.NewPostgresUserRepository(pool)
-	tokenRepo := database.NewPostgresTokenRepository(pool)
-	outboxRepo := database.NewPostgresOutboxRepository(pool)
-
-	// 5. Initialize Service
-	authService := users.NewService(userRepo, tokenRepo, outboxRepo, signer, txManager)
-
-	// 6. Start Outbox Relay
-	outboxRelay := pkgevents.NewOutboxRelay(
-		outboxRepo,
-		rabbitPublisher,
-		txManager,
-		10,               // batch size
-		5*time.Second,    // interval
-		"auction.events", // exchange
-		logger,
+	authService := users.NewService(userRepo, tokenRepo, outboxRepo, signer, txManager,
+		users.WithPasswordHasher(passwordHasher),
+		users.WithMaxSessions(cfg.MaxSessions),
+		users.WithLockoutPolicy(cfg.LockoutMaxAttempts, cfg.LockoutDuration),
+		users.WithVerificationMailer(verificationMailer),
+		users.WithLogger(logger),
 	)
 
-	// Run relay in background
-	go func() {
-		logger.Info("Starting Outbox Relay...")
-		if err := outboxRelay.Run(ctx); err != nil {
-			logger.Error("Outbox Relay stopped", "error", err)
-		}
-	}()
+	// Events written to the outbox are published by the auth worker (cmd/worker)
 
-	// 7. Initialize API Handler (ConnectRPC)
+	// 5. Initialize API Handler (ConnectRPC) with auth interceptor
 	authHandler := api.NewAuthServiceHandler(authService)
-	path, connectHandler := authv1connect.NewAuthServiceHandler(authHandler)
+
+	authInterceptor := auth.NewAuthInterceptorWithPublicRoutes(signer, api.PublicRoutes())
+	// Log first, so calls the other interceptors reject are logged too
+	interceptors := []connect.Interceptor{logging.NewInterceptor(logger, logging.WithRedactedFields("token"))}
+
+	// Limit credential guessing per client IP when Redis is available
+	if cfg.RedisURL != "" {
+		rdb := redis.NewClient(&redis.Options{Addr: cfg.RedisURL})
+		defer rdb.Close()
+		limiter := ratelimit.NewLimiter(rdb, cfg.RateLimit, cfg.RateLimitWindow,
+			ratelimit.WithFailurePolicy(cfg.RateLimitFailurePolicy),
+			ratelimit.WithTrustedProxies(cfg.TrustedProxies),
+		)
+		interceptors = append(interceptors, ratelimit.NewInterceptor(limiter, logger,
+			authv1connect.AuthServiceLoginProcedure,
+			authv1connect.AuthServiceRegisterProcedure,
+		))
+	} else {
+		logger.Warn("REDIS_URL is not set, Login and Register are not rate limited")
+	}
+
+	path, connectHandler := authv1connect.NewAuthServiceHandler(
+		authHandler,
+		connect.WithInterceptors(append(interceptors, authInterceptor)...),
+	)
 
 	mux := http.NewServeMux()
 	mux.Handle(path, connectHandler)
@@ -155,9 +154,11 @@ func main() {
 		_, _ = w.Write([]byte("OK"))
 	})
 
-	// Expose Public Key (JWKS)
-	// For simplicity, we just serve the PEM file content for now on a specific endpoint
-	// In a real system, we'd serve a standard JWKS JSON.
+	// Expose Public Keys as a standard JWKS document (RFC 7517)
+	mux.Handle(api.JWKSPath, api.NewJWKSHandler(signer, 5*time.Minute))
+
+	// Legacy PEM endpoint, kept for consumers that have not moved to JWKS yet
+	publicKeyPEM := signer.PublicKeyPEM()
 	mux.HandleFunc("/.well-known/public-key", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
 		_, _ = w.Write(publicKeyPEM)
@@ -165,15 +166,20 @@ func main() {
 
 	// 6. Start Server
 	addr := ":8080"
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		logger.Error("Failed to listen", "addr", addr, "error", err)
+		os.Exit(1)
+	}
 	logger.Info("Starting Auth Service API", "addr", addr)
 
 	srv := &http.Server{
-		Addr:    addr,
 		Handler: h2c.NewHandler(mux, &http2.Server{}),
 	}
 
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if err := serve(ctx, srv, lis, shutdownTimeout); err != nil {
 		logger.Error("Server failed", "error", err)
 		os.Exit(1)
 	}
+	logger.Info("Auth Service API stopped")
 }
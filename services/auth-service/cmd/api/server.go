@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// shutdownTimeout bounds how long in-flight requests get to finish once shutdown starts
+const shutdownTimeout = 10 * time.Second
+
+// serve runs srv on lis until ctx is cancelled, then stops accepting connections and waits
+// up to gracePeriod for in-flight requests to complete
+func serve(ctx context.Context, srv *http.Server, lis net.Listener, gracePeriod time.Duration) error {
+	g, gCtx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		if err := srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		<-gCtx.Done()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), gracePeriod)
+		defer shutdownCancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("server shutdown: %w", err)
+		}
+		return nil
+	})
+
+	return g.Wait()
+}
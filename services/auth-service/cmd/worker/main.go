@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	pkgdb "github.com/floroz/gavel/pkg/database"
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/services/auth-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/auth-service/internal/adapters/events"
+	"github.com/floroz/gavel/services/auth-service/internal/config"
+	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
+)
+
+func main() {
+	// Initialize structured logger
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	// Load environment variables (local overrides .env)
+	_ = godotenv.Load(".env.local")
+	_ = godotenv.Load()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		logger.Info("Shutting down worker...")
+		cancel()
+	}()
+
+	cfg, err := config.LoadWorker()
+	if err != nil {
+		logger.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// 1. Initialize Postgres Connection Pool
+	dbConfig, err := pkgdb.ParsePoolConfig(cfg.DatabaseURL, "AUTH")
+	if err != nil {
+		logger.Error("Unable to parse database config", "error", err)
+		os.Exit(1)
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, dbConfig)
+	if err != nil {
+		logger.Error("Unable to create connection pool", "error", err)
+		os.Exit(1)
+	}
+	defer pool.Close()
+
+	if pingErr := pool.Ping(ctx); pingErr != nil {
+		logger.Error("Unable to ping database", "error", pingErr)
+		os.Exit(1)
+	}
+	logger.Info("Postgres Connected")
+
+	// 2. Connect to RabbitMQ
+	amqpConn, err := amqp.Dial(cfg.RabbitMQURL)
+	if err != nil {
+		logger.Error("Failed to connect to RabbitMQ", "error", err)
+		os.Exit(1)
+	}
+	defer amqpConn.Close()
+	logger.Info("RabbitMQ Connected")
+
+	// Fail fast if the broker has the exchange with other settings, rather than losing messages
+	if err := pkgevents.DeclareTopology(amqpConn, pkgevents.Topology{}); err != nil {
+		logger.Error("Invalid RabbitMQ topology", "error", err)
+		os.Exit(1)
+	}
+
+	// 3. Initialize Producer
+	producer, err := events.NewUserEventsProducer(pool, amqpConn, logger,
+		// Re-dial if the broker restarts so the relay keeps publishing
+		pkgevents.WithDialer(func() (*amqp.Connection, error) { return amqp.Dial(cfg.RabbitMQURL) }),
+	)
+	if err != nil {
+		logger.Error("Failed to create producer", "error", err)
+		os.Exit(1)
+	}
+	defer producer.Close()
+
+	// 4. Prune published outbox events once they are past retention,
+	// and refresh tokens once their whole session has expired
+	go users.RunTokenPruner(ctx, database.NewPostgresTokenRepository(pool), cfg.TokenPruneInterval, logger)
+	go pkgevents.RunOutboxPruner(ctx, database.NewPostgresOutboxRepository(pool), cfg.OutboxRetention, pkgevents.DefaultOutboxPruneInterval, logger)
+
+	logger.Info("Starting User Events Producer...")
+	if runErr := producer.Run(ctx); runErr != nil {
+		logger.Error("Producer failed", "error", runErr)
+		// Run returns nil on context cancel.
+		if ctx.Err() == nil {
+			os.Exit(1)
+		}
+	}
+
+	logger.Info("Worker stopped")
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/floroz/gavel/pkg/apperrors"
+	"github.com/floroz/gavel/pkg/auth"
 	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
 	"github.com/floroz/gavel/pkg/proto/auth/v1/authv1connect"
 	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
@@ -25,6 +27,21 @@ func NewAuthServiceHandler(service users.AuthService) *AuthServiceHandler {
 	}
 }
 
+// PublicRoutes returns the procedures the auth interceptor lets through without an access token
+func PublicRoutes() map[string]bool {
+	return map[string]bool{
+		"/auth.v1.AuthService/Register":   true,
+		"/auth.v1.AuthService/Login":      true,
+		"/auth.v1.AuthService/Refresh":    true,
+		"/auth.v1.AuthService/Logout":     true,
+		"/auth.v1.AuthService/GetProfile": true,
+		// Presents the token in the request body rather than the Authorization header
+		"/auth.v1.AuthService/VerifyToken": true,
+		// Reached from the emailed link, possibly before the user ever logs in
+		"/auth.v1.AuthService/VerifyEmail": true,
+	}
+}
+
 func (h *AuthServiceHandler) Register(
 	ctx context.Context,
 	req *connect.Request[authv1.RegisterRequest],
@@ -36,15 +53,10 @@ func (h *AuthServiceHandler) Register(
 		req.Msg.FullName,
 		req.Msg.PhoneNumber,
 		req.Msg.CountryCode,
+		req.Msg.IdempotencyKey,
 	)
 	if err != nil {
-		if errors.Is(err, users.ErrUserAlreadyExists) {
-			return nil, connect.NewError(connect.CodeAlreadyExists, err)
-		}
-		if errors.Is(err, users.ErrInvalidInput) {
-			return nil, connect.NewError(connect.CodeInvalidArgument, err)
-		}
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, apperrors.ToConnectError(err)
 	}
 
 	return connect.NewResponse(&authv1.RegisterResponse{
@@ -61,22 +73,15 @@ func (h *AuthServiceHandler) Login(
 	ip := req.Msg.IpAddress
 	ua := req.Msg.UserAgent
 
-	accessToken, refreshToken, err := h.service.Login(ctx, req.Msg.Email, req.Msg.Password, ua, ip)
+	tokens, err := h.service.Login(ctx, req.Msg.Email, req.Msg.Password, ua, ip)
 	if err != nil {
-		if errors.Is(err, users.ErrInvalidCredentials) {
-			return nil, connect.NewError(connect.CodeUnauthenticated, err)
-		}
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, apperrors.ToConnectError(err)
 	}
 
 	return connect.NewResponse(&authv1.LoginResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		// Expiry is 15m from now, but the service doesn't return the time.
-		// We could update service to return it, or just calculate it here roughly or decode the token.
-		// For now, let's leave it empty or update service.
-		// Service returns strings. Let's update service later if needed.
-		ExpiresAt: timestamppb.New(timestamppb.Now().AsTime().Add(15 * time.Minute)), // Approximation
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    timestamppb.New(tokens.AccessExpiry),
 	}), nil
 }
 
@@ -84,18 +89,19 @@ func (h *AuthServiceHandler) Refresh(
 	ctx context.Context,
 	req *connect.Request[authv1.RefreshRequest],
 ) (*connect.Response[authv1.RefreshResponse], error) {
-	accessToken, refreshToken, err := h.service.Refresh(ctx, req.Msg.RefreshToken, req.Msg.UserAgent, req.Msg.IpAddress)
+	tokens, err := h.service.Refresh(ctx, req.Msg.RefreshToken, req.Msg.UserAgent, req.Msg.IpAddress)
 	if err != nil {
-		if errors.Is(err, users.ErrInvalidToken) || errors.Is(err, users.ErrUserNotFound) {
+		// The token's user is gone, so the token no longer authenticates anyone
+		if errors.Is(err, users.ErrUserNotFound) {
 			return nil, connect.NewError(connect.CodeUnauthenticated, err)
 		}
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, apperrors.ToConnectError(err)
 	}
 
 	return connect.NewResponse(&authv1.RefreshResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresAt:    timestamppb.New(timestamppb.Now().AsTime().Add(15 * time.Minute)),
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    timestamppb.New(tokens.AccessExpiry),
 	}), nil
 }
 
@@ -107,7 +113,7 @@ func (h *AuthServiceHandler) Logout(
 	if err != nil {
 		// Even if error (e.g. not found), we usually return OK for logout to not leak info
 		// But logging it is good.
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, apperrors.ToConnectError(err)
 	}
 	return connect.NewResponse(&authv1.LogoutResponse{}), nil
 }
@@ -131,13 +137,36 @@ func (h *AuthServiceHandler) GetProfile(
 
 	user, err := h.service.GetProfile(ctx, userID)
 	if err != nil {
-		if errors.Is(err, users.ErrUserNotFound) {
-			return nil, connect.NewError(connect.CodeNotFound, err)
-		}
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, apperrors.ToConnectError(err)
 	}
 
 	return connect.NewResponse(&authv1.GetProfileResponse{
+		Id:            user.ID.String(),
+		Email:         user.Email,
+		FullName:      user.FullName,
+		AvatarUrl:     user.AvatarURL,
+		CountryCode:   user.CountryCode,
+		CreatedAt:     timestamppb.New(user.CreatedAt),
+		EmailVerified: user.EmailVerified,
+	}), nil
+}
+
+func (h *AuthServiceHandler) UpdateProfile(
+	ctx context.Context,
+	req *connect.Request[authv1.UpdateProfileRequest],
+) (*connect.Response[authv1.UpdateProfileResponse], error) {
+	// User ID is guaranteed by the auth interceptor at router level
+	userID, err := uuid.Parse(auth.MustGetUserID(ctx))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, errors.New("invalid user_id in token"))
+	}
+
+	user, err := h.service.UpdateProfile(ctx, userID, req.Msg.FullName, req.Msg.AvatarUrl, req.Msg.CountryCode)
+	if err != nil {
+		return nil, apperrors.ToConnectError(err)
+	}
+
+	return connect.NewResponse(&authv1.UpdateProfileResponse{
 		Id:          user.ID.String(),
 		Email:       user.Email,
 		FullName:    user.FullName,
@@ -146,3 +175,106 @@ func (h *AuthServiceHandler) GetProfile(
 		CreatedAt:   timestamppb.New(user.CreatedAt),
 	}), nil
 }
+
+func (h *AuthServiceHandler) ListSessions(
+	ctx context.Context,
+	req *connect.Request[authv1.ListSessionsRequest],
+) (*connect.Response[authv1.ListSessionsResponse], error) {
+	// User ID is guaranteed by the auth interceptor at router level
+	userID, err := uuid.Parse(auth.MustGetUserID(ctx))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, errors.New("invalid user_id in token"))
+	}
+
+	sessions, err := h.service.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, apperrors.ToConnectError(err)
+	}
+
+	pbSessions := make([]*authv1.Session, len(sessions))
+	for i, session := range sessions {
+		pbSessions[i] = &authv1.Session{
+			Id:         session.ID.String(),
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IPAddress,
+			CreatedAt:  timestamppb.New(session.CreatedAt),
+			LastUsedAt: timestamppb.New(session.LastUsedAt),
+			ExpiresAt:  timestamppb.New(session.ExpiresAt),
+		}
+	}
+
+	return connect.NewResponse(&authv1.ListSessionsResponse{
+		Sessions: pbSessions,
+	}), nil
+}
+
+func (h *AuthServiceHandler) RevokeSession(
+	ctx context.Context,
+	req *connect.Request[authv1.RevokeSessionRequest],
+) (*connect.Response[authv1.RevokeSessionResponse], error) {
+	// User ID is guaranteed by the auth interceptor at router level
+	userID, err := uuid.Parse(auth.MustGetUserID(ctx))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, errors.New("invalid user_id in token"))
+	}
+
+	sessionID, err := uuid.Parse(req.Msg.SessionId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid session_id"))
+	}
+
+	if err := h.service.RevokeSession(ctx, userID, sessionID); err != nil {
+		return nil, apperrors.ToConnectError(err)
+	}
+
+	return connect.NewResponse(&authv1.RevokeSessionResponse{}), nil
+}
+
+func (h *AuthServiceHandler) VerifyToken(
+	ctx context.Context,
+	req *connect.Request[authv1.VerifyTokenRequest],
+) (*connect.Response[authv1.VerifyTokenResponse], error) {
+	claims, err := h.service.VerifyToken(ctx, req.Msg.AccessToken)
+	if err != nil {
+		return nil, apperrors.ToConnectError(err)
+	}
+
+	return connect.NewResponse(&authv1.VerifyTokenResponse{
+		UserId:      claims.Sub,
+		Email:       claims.Email,
+		Role:        claims.Role,
+		Permissions: claims.Permissions,
+		ExpiresAt:   timestamppb.New(time.Unix(int64(claims.Exp), 0)),
+	}), nil
+}
+
+func (h *AuthServiceHandler) VerifyEmail(
+	ctx context.Context,
+	req *connect.Request[authv1.VerifyEmailRequest],
+) (*connect.Response[authv1.VerifyEmailResponse], error) {
+	user, err := h.service.VerifyEmail(ctx, req.Msg.Token)
+	if err != nil {
+		return nil, apperrors.ToConnectError(err)
+	}
+
+	return connect.NewResponse(&authv1.VerifyEmailResponse{
+		UserId: user.ID.String(),
+	}), nil
+}
+
+func (h *AuthServiceHandler) DeleteAccount(
+	ctx context.Context,
+	req *connect.Request[authv1.DeleteAccountRequest],
+) (*connect.Response[authv1.DeleteAccountResponse], error) {
+	// User ID is guaranteed by the auth interceptor at router level
+	userID, err := uuid.Parse(auth.MustGetUserID(ctx))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, errors.New("invalid user_id in token"))
+	}
+
+	if err := h.service.DeleteAccount(ctx, userID); err != nil {
+		return nil, apperrors.ToConnectError(err)
+	}
+
+	return connect.NewResponse(&authv1.DeleteAccountResponse{}), nil
+}
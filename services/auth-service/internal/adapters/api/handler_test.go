@@ -0,0 +1,66 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/auth"
+	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
+	"github.com/floroz/gavel/services/auth-service/internal/adapters/api"
+	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
+)
+
+// tokenService issues real tokens from Login and Refresh; the rest of users.AuthService is unused
+type tokenService struct {
+	users.AuthService
+	signer *auth.Signer
+}
+
+func (s *tokenService) Login(ctx context.Context, email, password, userAgent, ip string) (*auth.TokenPair, error) {
+	return s.signer.GenerateTokens(uuid.New(), email, "Test User", users.RoleUser, nil)
+}
+
+func (s *tokenService) Refresh(ctx context.Context, refreshToken, userAgent, ip string) (*auth.TokenPair, error) {
+	return s.signer.GenerateTokens(uuid.New(), "test@example.com", "Test User", users.RoleUser, nil)
+}
+
+func TestAuthServiceHandler_ExpiresAtMatchesToken(t *testing.T) {
+	signer, err := auth.NewDevSigner()
+	require.NoError(t, err)
+	handler := api.NewAuthServiceHandler(&tokenService{signer: signer})
+
+	// tokenExp returns the exp claim of accessToken, in Unix seconds
+	tokenExp := func(t *testing.T, accessToken string) int64 {
+		t.Helper()
+		claims, err := signer.ValidateToken(accessToken)
+		require.NoError(t, err)
+		return int64(claims.Exp)
+	}
+
+	t.Run("Login", func(t *testing.T) {
+		resp, err := handler.Login(context.Background(), connect.NewRequest(&authv1.LoginRequest{
+			Email: "test@example.com", Password: "password",
+		}))
+		require.NoError(t, err)
+
+		expiresAt := resp.Msg.ExpiresAt.AsTime()
+		assert.Equal(t, tokenExp(t, resp.Msg.AccessToken), expiresAt.Unix())
+		assert.Zero(t, expiresAt.Nanosecond(), "expiry is exactly the token's exp")
+	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		resp, err := handler.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{
+			RefreshToken: "refresh-token",
+		}))
+		require.NoError(t, err)
+
+		expiresAt := resp.Msg.ExpiresAt.AsTime()
+		assert.Equal(t, tokenExp(t, resp.Msg.AccessToken), expiresAt.Unix())
+		assert.Zero(t, expiresAt.Nanosecond(), "expiry is exactly the token's exp")
+	})
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/floroz/gavel/pkg/auth"
+)
+
+// JWKSPath is the well-known location of the key set (RFC 8414 convention)
+const JWKSPath = "/.well-known/jwks.json"
+
+// NewJWKSHandler serves the Signer's public keys as a JWKS document so downstream
+// services can validate tokens without sharing PEM files out-of-band.
+// Clients may cache the response for maxAge; keep it shorter than the rotation overlap.
+func NewJWKSHandler(signer *auth.Signer, maxAge time.Duration) http.Handler {
+	cacheControl := fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Built per request so keys added via AddPublicKey are reflected immediately
+		body, err := json.Marshal(signer.JWKS())
+		if err != nil {
+			http.Error(w, "failed to encode key set", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", cacheControl)
+		_, _ = w.Write(body)
+	})
+}
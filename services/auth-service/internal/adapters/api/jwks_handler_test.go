@@ -0,0 +1,119 @@
+package api_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/auth"
+	"github.com/floroz/gavel/services/auth-service/internal/adapters/api"
+)
+
+// generateTestKeys creates RSA key pairs for testing
+func generateTestKeys(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Failed to generate RSA key")
+
+	privPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+
+	pubBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err, "Failed to marshal public key")
+	pubPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: pubBytes,
+	})
+
+	return privPEM, pubPEM
+}
+
+func fetchJWKS(t *testing.T, url string) (auth.JWKS, *http.Response) {
+	t.Helper()
+	res, err := http.Get(url)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var jwks auth.JWKS
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&jwks))
+	return jwks, res
+}
+
+// publicKeyFromJWK rebuilds an RSA public key from its JWK modulus and exponent
+func publicKeyFromJWK(t *testing.T, jwk auth.JWK) *rsa.PublicKey {
+	t.Helper()
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	require.NoError(t, err)
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	require.NoError(t, err)
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+}
+
+func TestJWKSHandler(t *testing.T) {
+	privPEM, pubPEM := generateTestKeys(t)
+	signer, err := auth.NewSigner(privPEM, pubPEM, "test-issuer")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(api.NewJWKSHandler(signer, 5*time.Minute))
+	t.Cleanup(server.Close)
+
+	t.Run("Verifies freshly minted token with published key", func(t *testing.T) {
+		jwks, res := fetchJWKS(t, server.URL)
+		assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+		assert.Equal(t, "public, max-age=300", res.Header.Get("Cache-Control"))
+		require.Len(t, jwks.Keys, 1)
+
+		jwk := jwks.Keys[0]
+		assert.Equal(t, "RSA", jwk.Kty)
+		assert.Equal(t, "RS256", jwk.Alg)
+		assert.Equal(t, signer.KeyID(), jwk.Kid)
+
+		pair, err := signer.GenerateTokens(uuid.New(), "user@example.com", "User", "user", nil)
+		require.NoError(t, err)
+
+		pub := publicKeyFromJWK(t, jwk)
+		token, err := jwt.Parse(pair.AccessToken, func(token *jwt.Token) (interface{}, error) {
+			assert.Equal(t, jwk.Kid, token.Header["kid"])
+			return pub, nil
+		}, jwt.WithValidMethods([]string{"RS256"}))
+		require.NoError(t, err)
+		assert.True(t, token.Valid)
+	})
+
+	t.Run("Reflects rotated keys", func(t *testing.T) {
+		_, nextPubPEM := generateTestKeys(t)
+		kid, err := signer.AddPublicKey(nextPubPEM)
+		require.NoError(t, err)
+
+		jwks, _ := fetchJWKS(t, server.URL)
+		require.Len(t, jwks.Keys, 2)
+		assert.Equal(t, signer.KeyID(), jwks.Keys[0].Kid)
+		assert.Equal(t, kid, jwks.Keys[1].Kid)
+	})
+
+	t.Run("Rejects non-GET methods", func(t *testing.T) {
+		res, err := http.Post(server.URL, "application/json", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+	})
+}
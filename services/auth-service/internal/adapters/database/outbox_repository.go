@@ -24,13 +24,19 @@ func NewPostgresOutboxRepository(pool *pgxpool.Pool) *PostgresOutboxRepository {
 // CreateEvent persists an event to the outbox table in the same transaction as the business logic
 func (r *PostgresOutboxRepository) CreateEvent(ctx context.Context, tx pgx.Tx, event *pkgevents.OutboxEvent) error {
 	query := `
-		INSERT INTO outbox_events (id, event_type, payload, status, created_at)
-		VALUES ($1, $2, $3, $4::outbox_status, $5)
+		INSERT INTO outbox_events (id, aggregate_id, event_type, payload, headers, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6::outbox_status, $7)
 	`
+	var aggregateID *uuid.UUID
+	if event.AggregateID != uuid.Nil {
+		aggregateID = &event.AggregateID
+	}
 	_, err := tx.Exec(ctx, query,
 		event.ID,
+		aggregateID,
 		event.EventType,
 		event.Payload,
+		event.Headers,
 		event.Status,
 		event.CreatedAt,
 	)
@@ -40,16 +46,38 @@ func (r *PostgresOutboxRepository) CreateEvent(ctx context.Context, tx pgx.Tx, e
 	return nil
 }
 
+// GetPendingEvents claims up to limit pending events for the lifetime of tx, in write order.
+// Uses SELECT FOR UPDATE SKIP LOCKED to prevent multiple workers from processing the same event:
+// a worker claims the oldest pending event of up to limit aggregates, along with the events queued
+// behind each, so an aggregate is only ever being published by one worker at a time. Of those, the
+// limit oldest are returned, which leaves each aggregate's events in order; the aggregates whose
+// events all fall beyond the limit stay locked until tx ends, and are claimed in a later batch.
 func (r *PostgresOutboxRepository) GetPendingEvents(ctx context.Context, tx pgx.Tx, limit int) ([]*pkgevents.OutboxEvent, error) {
 	query := `
-		SELECT id, event_type, payload, status, created_at, processed_at
+		WITH heads AS (
+			SELECT id, aggregate_id
+			FROM outbox_events e
+			WHERE status = $1::outbox_status
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_events earlier
+				WHERE earlier.aggregate_id = e.aggregate_id
+				AND earlier.status = $1::outbox_status
+				AND earlier.seq < e.seq
+			)
+			ORDER BY seq
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		SELECT id, aggregate_id, event_type, payload, headers, status, created_at, processed_at
 		FROM outbox_events
-		WHERE status = 'pending'
-		ORDER BY created_at ASC
-		LIMIT $1
-		FOR UPDATE SKIP LOCKED
+		WHERE id IN (SELECT id FROM heads)
+		OR (status = $1::outbox_status AND aggregate_id IN (SELECT aggregate_id FROM heads))
+		ORDER BY seq
+		LIMIT $2
+		FOR UPDATE
 	`
-	rows, err := tx.Query(ctx, query, limit)
+
+	rows, err := tx.Query(ctx, query, pkgevents.OutboxStatusPending, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query pending events: %w", err)
 	}
@@ -58,19 +86,25 @@ func (r *PostgresOutboxRepository) GetPendingEvents(ctx context.Context, tx pgx.
 	var events []*pkgevents.OutboxEvent
 	for rows.Next() {
 		var event pkgevents.OutboxEvent
+		var aggregateID *uuid.UUID
 		if err := rows.Scan(
 			&event.ID,
+			&aggregateID,
 			&event.EventType,
 			&event.Payload,
+			&event.Headers,
 			&event.Status,
 			&event.CreatedAt,
 			&event.ProcessedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
+		if aggregateID != nil {
+			event.AggregateID = *aggregateID
+		}
 		events = append(events, &event)
 	}
-	return events, nil
+	return events, rows.Err()
 }
 
 func (r *PostgresOutboxRepository) UpdateEventStatus(ctx context.Context, tx pgx.Tx, id uuid.UUID, status pkgevents.OutboxStatus) error {
@@ -86,3 +120,16 @@ func (r *PostgresOutboxRepository) UpdateEventStatus(ctx context.Context, tx pgx
 	}
 	return nil
 }
+
+// DeletePublishedBefore deletes events published before cutoff, returning how many were removed
+func (r *PostgresOutboxRepository) DeletePublishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		DELETE FROM outbox_events
+		WHERE status = $1::outbox_status AND processed_at < $2
+	`
+	result, err := r.pool.Exec(ctx, query, pkgevents.OutboxStatusPublished, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete published events: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -91,4 +91,57 @@ func TestOutboxRepository_Integration(t *testing.T) {
 		assert.Equal(t, string(events.OutboxStatusPublished), status)
 		assert.NotNil(t, processedAt)
 	})
+
+	t.Run("GetPendingEvents_LimitsTotalEvents", func(t *testing.T) {
+		_, err := td.Pool.Exec(ctx, "DELETE FROM outbox_events")
+		require.NoError(t, err)
+
+		newEvent := func(userID uuid.UUID) *events.OutboxEvent {
+			return &events.OutboxEvent{
+				ID:          uuid.New(),
+				AggregateID: userID,
+				EventType:   events.RoutingKeyUserLoggedIn,
+				Payload:     []byte(`{}`),
+				Status:      events.OutboxStatusPending,
+				CreatedAt:   time.Now().UTC(),
+			}
+		}
+		ids := func(evts []*events.OutboxEvent) []uuid.UUID {
+			var result []uuid.UUID
+			for _, event := range evts {
+				result = append(result, event.ID)
+			}
+			return result
+		}
+
+		userA, userB := uuid.New(), uuid.New()
+		a1, a2, b1, a3, b2 := newEvent(userA), newEvent(userA), newEvent(userB), newEvent(userA), newEvent(userB)
+		tx, err := td.Pool.Begin(ctx)
+		require.NoError(t, err)
+		for _, event := range []*events.OutboxEvent{a1, a2, b1, a3, b2} {
+			require.NoError(t, repo.CreateEvent(ctx, tx, event))
+		}
+		require.NoError(t, tx.Commit(ctx))
+
+		tx, err = td.Pool.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+		claimed, err := repo.GetPendingEvents(ctx, tx, 2)
+		require.NoError(t, err)
+		// Both users are claimed, but only the oldest two events are handed out
+		assert.Equal(t, []uuid.UUID{a1.ID, a2.ID}, ids(claimed))
+		require.NoError(t, tx.Commit(ctx))
+
+		// With those published, the next batch carries on where each user left off
+		for _, event := range claimed {
+			_, err := td.Pool.Exec(ctx, "UPDATE outbox_events SET status = 'published' WHERE id = $1", event.ID)
+			require.NoError(t, err)
+		}
+		tx, err = td.Pool.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+		claimed, err = repo.GetPendingEvents(ctx, tx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{b1.ID, a3.ID}, ids(claimed))
+	})
 }
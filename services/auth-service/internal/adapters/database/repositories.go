@@ -4,14 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
 )
 
+// uniqueViolation is the SQLSTATE of an insert that hit a unique index
+const uniqueViolation = "23505"
+
 // PostgresUserRepository implements users.UserRepository
 type PostgresUserRepository struct {
 	pool *pgxpool.Pool
@@ -23,8 +28,9 @@ func NewPostgresUserRepository(pool *pgxpool.Pool) *PostgresUserRepository {
 
 func (r *PostgresUserRepository) CreateUser(ctx context.Context, tx pgx.Tx, user *users.User) error {
 	query := `
-		INSERT INTO users (id, email, password_hash, full_name, avatar_url, phone_number, country_code, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO users (id, email, password_hash, full_name, avatar_url, phone_number, country_code, role, created_at, updated_at,
+			idempotency_key, registration_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12)
 	`
 	_, err := tx.Exec(ctx, query,
 		user.ID,
@@ -34,10 +40,22 @@ func (r *PostgresUserRepository) CreateUser(ctx context.Context, tx pgx.Tx, user
 		user.AvatarURL,
 		user.PhoneNumber,
 		user.CountryCode,
+		user.Role,
 		user.CreatedAt,
 		user.UpdatedAt,
+		user.IdempotencyKey,
+		user.RegistrationHash,
 	)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			switch pgErr.ConstraintName {
+			case "users_email_key":
+				return users.ErrUserAlreadyExists
+			case "idx_users_idempotency_key":
+				return users.ErrIdempotencyKeyTaken
+			}
+		}
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 	return nil
@@ -45,7 +63,8 @@ func (r *PostgresUserRepository) CreateUser(ctx context.Context, tx pgx.Tx, user
 
 func (r *PostgresUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*users.User, error) {
 	query := `
-		SELECT id, email, password_hash, full_name, avatar_url, phone_number, country_code, created_at, updated_at
+		SELECT id, email, password_hash, full_name, avatar_url, phone_number, country_code, role, created_at, updated_at,
+			failed_login_attempts, locked_until, email_verified, deleted_at
 		FROM users
 		WHERE id = $1
 	`
@@ -58,12 +77,17 @@ func (r *PostgresUserRepository) GetUserByID(ctx context.Context, id uuid.UUID)
 		&user.AvatarURL,
 		&user.PhoneNumber,
 		&user.CountryCode,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.FailedLoginAttempts,
+		&user.LockedUntil,
+		&user.EmailVerified,
+		&user.DeletedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil // Return nil if not found, let service handle it
+			return nil, users.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user by id: %w", err)
 	}
@@ -72,7 +96,8 @@ func (r *PostgresUserRepository) GetUserByID(ctx context.Context, id uuid.UUID)
 
 func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email string) (*users.User, error) {
 	query := `
-		SELECT id, email, password_hash, full_name, avatar_url, phone_number, country_code, created_at, updated_at
+		SELECT id, email, password_hash, full_name, avatar_url, phone_number, country_code, role, created_at, updated_at,
+			failed_login_attempts, locked_until, email_verified, deleted_at
 		FROM users
 		WHERE email = $1
 	`
@@ -85,8 +110,13 @@ func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email strin
 		&user.AvatarURL,
 		&user.PhoneNumber,
 		&user.CountryCode,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.FailedLoginAttempts,
+		&user.LockedUntil,
+		&user.EmailVerified,
+		&user.DeletedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -97,6 +127,173 @@ func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email strin
 	return &user, nil
 }
 
+func (r *PostgresUserRepository) GetUserByIdempotencyKey(ctx context.Context, key string) (*users.User, error) {
+	query := `
+		SELECT id, email, password_hash, full_name, avatar_url, phone_number, country_code, role, created_at, updated_at,
+			failed_login_attempts, locked_until, email_verified, deleted_at, idempotency_key, registration_hash
+		FROM users
+		WHERE idempotency_key = $1
+	`
+	var user users.User
+	err := r.pool.QueryRow(ctx, query, key).Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.FullName,
+		&user.AvatarURL,
+		&user.PhoneNumber,
+		&user.CountryCode,
+		&user.Role,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.FailedLoginAttempts,
+		&user.LockedUntil,
+		&user.EmailVerified,
+		&user.DeletedAt,
+		&user.IdempotencyKey,
+		&user.RegistrationHash,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user by idempotency key: %w", err)
+	}
+	return &user, nil
+}
+
+// ReleaseIdempotencyKey clears the user's idempotency key and registration fingerprint
+func (r *PostgresUserRepository) ReleaseIdempotencyKey(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET idempotency_key = NULL, registration_hash = NULL
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// UpdateProfile saves the user's full name, avatar URL and country code
+func (r *PostgresUserRepository) UpdateProfile(ctx context.Context, user *users.User) error {
+	query := `
+		UPDATE users
+		SET full_name = $1, avatar_url = $2, country_code = $3, updated_at = $4
+		WHERE id = $5
+	`
+	_, err := r.pool.Exec(ctx, query, user.FullName, user.AvatarURL, user.CountryCode, user.UpdatedAt, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+	return nil
+}
+
+// SoftDeleteUser marks an active account as deleted. It returns false if there is no such account.
+func (r *PostgresUserRepository) SoftDeleteUser(ctx context.Context, tx pgx.Tx, userID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE users
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	tag, err := tx.Exec(ctx, query, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete user: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *PostgresUserRepository) CreateEmailVerification(ctx context.Context, tx pgx.Tx, verification *users.EmailVerification) error {
+	query := `
+		INSERT INTO email_verification_tokens (token_hash, user_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := tx.Exec(ctx, query, verification.TokenHash, verification.UserID, verification.ExpiresAt, verification.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create email verification: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) GetEmailVerification(ctx context.Context, tokenHash []byte) (*users.EmailVerification, error) {
+	query := `
+		SELECT token_hash, user_id, expires_at, created_at
+		FROM email_verification_tokens
+		WHERE token_hash = $1
+	`
+	var verification users.EmailVerification
+	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(
+		&verification.TokenHash,
+		&verification.UserID,
+		&verification.ExpiresAt,
+		&verification.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get email verification: %w", err)
+	}
+	return &verification, nil
+}
+
+func (r *PostgresUserRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET email_verified = TRUE, updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+	return nil
+}
+
+// IncrementFailedLoginAttempts atomically bumps the failed login counter and returns the new value
+func (r *PostgresUserRepository) IncrementFailedLoginAttempts(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = failed_login_attempts + 1, updated_at = NOW()
+		WHERE id = $1
+		RETURNING failed_login_attempts
+	`
+	var attempts int
+	if err := r.pool.QueryRow(ctx, query, userID).Scan(&attempts); err != nil {
+		return 0, fmt.Errorf("failed to increment failed login attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// LockUser locks the account until the given time and clears the failed login counter
+func (r *PostgresUserRepository) LockUser(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	query := `
+		UPDATE users
+		SET locked_until = $1, failed_login_attempts = 0, updated_at = NOW()
+		WHERE id = $2
+	`
+	_, err := r.pool.Exec(ctx, query, until, userID)
+	if err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+	return nil
+}
+
+// ResetFailedLoginAttempts clears the failed login counter and any lock
+func (r *PostgresUserRepository) ResetFailedLoginAttempts(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = 0, locked_until = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to reset failed login attempts: %w", err)
+	}
+	return nil
+}
+
 // PostgresTokenRepository implements users.TokenRepository
 type PostgresTokenRepository struct {
 	pool *pgxpool.Pool
@@ -108,8 +305,8 @@ func NewPostgresTokenRepository(pool *pgxpool.Pool) *PostgresTokenRepository {
 
 func (r *PostgresTokenRepository) CreateRefreshToken(ctx context.Context, tx pgx.Tx, token *users.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (token_hash, user_id, expires_at, revoked, created_at, user_agent, ip_address)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO refresh_tokens (token_hash, user_id, expires_at, revoked, created_at, user_agent, ip_address, family_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 	_, err := tx.Exec(ctx, query,
 		token.TokenHash,
@@ -119,6 +316,7 @@ func (r *PostgresTokenRepository) CreateRefreshToken(ctx context.Context, tx pgx
 		token.CreatedAt,
 		token.UserAgent,
 		token.IPAddress,
+		token.FamilyID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create refresh token: %w", err)
@@ -128,7 +326,8 @@ func (r *PostgresTokenRepository) CreateRefreshToken(ctx context.Context, tx pgx
 
 func (r *PostgresTokenRepository) GetRefreshToken(ctx context.Context, tokenHash []byte) (*users.RefreshToken, error) {
 	query := `
-		SELECT token_hash, user_id, expires_at, revoked, created_at, user_agent, ip_address
+		SELECT token_hash, user_id, expires_at, revoked, created_at, user_agent, ip_address,
+			family_id, consumed_at
 		FROM refresh_tokens
 		WHERE token_hash = $1
 	`
@@ -141,6 +340,8 @@ func (r *PostgresTokenRepository) GetRefreshToken(ctx context.Context, tokenHash
 		&token.CreatedAt,
 		&token.UserAgent,
 		&token.IPAddress,
+		&token.FamilyID,
+		&token.ConsumedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -160,6 +361,125 @@ func (r *PostgresTokenRepository) RevokeRefreshToken(ctx context.Context, tx pgx
 	return nil
 }
 
+// ConsumeRefreshToken marks an active token as exchanged. The conditional update makes
+// concurrent refreshes with the same token race safely: only one of them wins.
+func (r *PostgresTokenRepository) ConsumeRefreshToken(ctx context.Context, tx pgx.Tx, tokenHash []byte) (bool, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET consumed_at = NOW()
+		WHERE token_hash = $1 AND consumed_at IS NULL AND revoked = false
+	`
+	tag, err := tx.Exec(ctx, query, tokenHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume refresh token: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *PostgresTokenRepository) RevokeTokenFamily(ctx context.Context, tx pgx.Tx, familyID uuid.UUID) error {
+	query := `UPDATE refresh_tokens SET revoked = true WHERE family_id = $1`
+	_, err := tx.Exec(ctx, query, familyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+	return nil
+}
+
+// ListActiveSessions returns one row per token family that still has a usable refresh token.
+// The session starts with the family's first token; it was last used when the current token was issued.
+func (r *PostgresTokenRepository) ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]*users.Session, error) {
+	query := `
+		SELECT t.family_id, t.user_agent, t.ip_address,
+			(SELECT MIN(f.created_at) FROM refresh_tokens f WHERE f.family_id = t.family_id),
+			t.created_at, t.expires_at
+		FROM refresh_tokens t
+		WHERE t.user_id = $1 AND t.revoked = false AND t.consumed_at IS NULL AND t.expires_at > NOW()
+		ORDER BY t.created_at DESC
+	`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*users.Session
+	for rows.Next() {
+		var session users.Session
+		if err := rows.Scan(
+			&session.ID,
+			&session.UserAgent,
+			&session.IPAddress,
+			&session.CreatedAt,
+			&session.LastUsedAt,
+			&session.ExpiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, &session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes the active tokens of a session, scoped to its owner
+func (r *PostgresTokenRepository) RevokeSession(ctx context.Context, tx pgx.Tx, userID, sessionID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked = true
+		WHERE family_id = $1 AND user_id = $2 AND revoked = false
+	`
+	tag, err := tx.Exec(ctx, query, sessionID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RevokeOldestSessions revokes every active refresh token of the user but the newest keep.
+// Each active token is the head of one session, so this ends all but the newest keep sessions.
+func (r *PostgresTokenRepository) RevokeOldestSessions(ctx context.Context, tx pgx.Tx, userID uuid.UUID, keep int) (int, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked = true
+		WHERE token_hash IN (
+			SELECT token_hash FROM refresh_tokens
+			WHERE user_id = $1 AND revoked = false AND consumed_at IS NULL AND expires_at > NOW()
+			ORDER BY created_at DESC
+			OFFSET $2
+		)
+	`
+	tag, err := tx.Exec(ctx, query, userID, keep)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke oldest sessions: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// DeleteExpiredRefreshTokens deletes a batch of tokens from families with no token left
+// valid at cutoff. Rows locked by a concurrent refresh or logout are skipped for the next run.
+func (r *PostgresTokenRepository) DeleteExpiredRefreshTokens(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM refresh_tokens
+		WHERE token_hash IN (
+			SELECT t.token_hash FROM refresh_tokens t
+			WHERE t.expires_at < $1
+				AND NOT EXISTS (
+					SELECT 1 FROM refresh_tokens f
+					WHERE f.family_id = t.family_id AND f.expires_at >= $1
+				)
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+	tag, err := r.pool.Exec(ctx, query, cutoff, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
 func (r *PostgresTokenRepository) RevokeAllUserTokens(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
 	query := `UPDATE refresh_tokens SET revoked = true WHERE user_id = $1`
 	_, err := tx.Exec(ctx, query, userID)
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	pkgdb "github.com/floroz/gavel/pkg/database"
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/services/auth-service/internal/adapters/database"
+)
+
+// UserEventsProducer orchestrates the process of relaying user events from the outbox to RabbitMQ
+type UserEventsProducer struct {
+	relay     *pkgevents.OutboxRelay
+	publisher *pkgevents.RabbitMQPublisher
+}
+
+// NewUserEventsProducer creates a new producer
+func NewUserEventsProducer(
+	pool *pgxpool.Pool,
+	conn *amqp.Connection,
+	logger *slog.Logger,
+	opts ...pkgevents.PublisherOption,
+) (*UserEventsProducer, error) {
+	publisher, err := pkgevents.NewRabbitMQPublisher(conn, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create publisher: %w", err)
+	}
+
+	txManager := pkgdb.NewPostgresTransactionManager(pool, 3*time.Second)
+	outboxRepo := database.NewPostgresOutboxRepository(pool)
+
+	relay := pkgevents.NewOutboxRelay(
+		outboxRepo,
+		publisher,
+		txManager,
+		10,                   // Batch size
+		500*time.Millisecond, // Polling interval
+		pkgevents.Exchange,   // exchange
+		logger,
+	)
+
+	return &UserEventsProducer{
+		relay:     relay,
+		publisher: publisher,
+	}, nil
+}
+
+// Run starts the relay loop
+func (p *UserEventsProducer) Run(ctx context.Context) error {
+	return p.relay.Run(ctx)
+}
+
+// Close closes the publisher channel
+func (p *UserEventsProducer) Close() error {
+	return p.publisher.Close()
+}
@@ -0,0 +1,116 @@
+package events_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/rabbitmq"
+
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/pkg/testhelpers"
+	"github.com/floroz/gavel/services/auth-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/auth-service/internal/adapters/events"
+)
+
+// TestUserEventsProducerIntegration relays an outbox row to a real RabbitMQ container
+func TestUserEventsProducerIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+
+	// 1. Start RabbitMQ Container
+	rabbitmqContainer, err := rabbitmq.Run(ctx,
+		"rabbitmq:3.12-management-alpine",
+		rabbitmq.WithAdminPassword("password"),
+	)
+	require.NoError(t, err)
+	defer func() {
+		if termErr := rabbitmqContainer.Terminate(ctx); termErr != nil {
+			t.Fatalf("failed to terminate container: %s", termErr)
+		}
+	}()
+
+	amqpURL, err := rabbitmqContainer.AmqpURL(ctx)
+	require.NoError(t, err)
+
+	// 2. Setup Postgres
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+	dbPool := testDB.Pool
+
+	// 3. Setup Producer
+	pubConn, err := amqp091.Dial(amqpURL)
+	require.NoError(t, err)
+	defer pubConn.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	producer, err := events.NewUserEventsProducer(dbPool, pubConn, logger)
+	require.NoError(t, err)
+	defer producer.Close()
+
+	// 4. Bind a queue to verify message delivery
+	conn, err := amqp091.Dial(amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+	defer ch.Close()
+
+	err = ch.ExchangeDeclare("auction.events", "topic", true, false, false, false, nil)
+	require.NoError(t, err)
+
+	q, err := ch.QueueDeclare("", false, false, true, false, nil)
+	require.NoError(t, err)
+
+	err = ch.QueueBind(q.Name, "user.created", "auction.events", false, nil)
+	require.NoError(t, err)
+
+	msgs, err := ch.Consume(q.Name, "", true, false, false, false, nil)
+	require.NoError(t, err)
+
+	// 5. Seed the outbox the same way the users service does
+	event := &pkgevents.OutboxEvent{
+		ID:        uuid.New(),
+		EventType: "user.created",
+		Payload:   []byte(`{"test":"integration"}`),
+		Status:    pkgevents.OutboxStatusPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	tx, err := dbPool.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, database.NewPostgresOutboxRepository(dbPool).CreateEvent(ctx, tx, event))
+	require.NoError(t, tx.Commit(ctx))
+
+	// 6. Run Producer
+	ctxRelay, cancelRelay := context.WithCancel(ctx)
+	defer cancelRelay()
+	go func() {
+		_ = producer.Run(ctxRelay)
+	}()
+
+	// 7. Verify Message Receipt in RabbitMQ
+	select {
+	case msg := <-msgs:
+		assert.Equal(t, event.Payload, msg.Body)
+		assert.Equal(t, "user.created", msg.RoutingKey)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timeout waiting for message from RabbitMQ")
+	}
+
+	// 8. Verify the row is marked published
+	require.Eventually(t, func() bool {
+		var status string
+		scanErr := dbPool.QueryRow(ctx, "SELECT status FROM outbox_events WHERE id = $1", event.ID).Scan(&status)
+		return scanErr == nil && status == string(pkgevents.OutboxStatusPublished)
+	}, 2*time.Second, 100*time.Millisecond, "Event status should be updated to 'published'")
+}
@@ -0,0 +1,179 @@
+// Package mailer delivers the emails the auth service sends its users
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"time"
+
+	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
+)
+
+// DefaultSendTimeout bounds a delivery whose context has no deadline
+const DefaultSendTimeout = 30 * time.Second
+
+// SMTPConfig is the server emails are sent through
+type SMTPConfig struct {
+	Addr string // host:port
+	// Username and Password authenticate with PLAIN auth, which net/smtp only sends over TLS
+	// or to localhost. Without a username mail is sent unauthenticated.
+	Username string
+	Password string
+	From     string // sender, e.g. "Gavel <no-reply@gavel.example>"
+}
+
+// SMTPMailer emails new users their verification link
+type SMTPMailer struct {
+	addr      string
+	host      string
+	auth      smtp.Auth
+	from      *mail.Address
+	verifyURL *url.URL
+}
+
+// NewSMTPMailer sends through the server in cfg. The emailed links open verifyEmailURL with
+// the token in its token query parameter.
+func NewSMTPMailer(cfg SMTPConfig, verifyEmailURL string) (*SMTPMailer, error) {
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %q: %w", cfg.Addr, err)
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", cfg.From, err)
+	}
+	verifyURL, err := parseVerifyEmailURL(verifyEmailURL)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &SMTPMailer{addr: cfg.Addr, host: host, from: from, verifyURL: verifyURL}
+	if cfg.Username != "" {
+		m.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, host)
+	}
+	return m, nil
+}
+
+func (m *SMTPMailer) SendVerificationEmail(ctx context.Context, user *users.User, token string) error {
+	to := &mail.Address{Name: user.FullName, Address: user.Email}
+	msg := m.message(to, "Verify your email address", fmt.Sprintf(
+		"Hi %s,\r\n\r\nConfirm your email address by opening the link below:\r\n\r\n%s\r\n\r\nThe link expires in %s.\r\n",
+		user.FullName, VerificationLink(m.verifyURL, token), users.EmailVerificationTTL,
+	))
+	if err := m.send(ctx, to.Address, msg); err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+	return nil
+}
+
+// message renders a plain text email; mail.Address encodes names, so they can't inject headers
+func (m *SMTPMailer) message(to *mail.Address, subject, body string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(body)
+	return b.Bytes()
+}
+
+// send delivers msg like smtp.SendMail, but gives up once ctx is done
+func (m *SMTPMailer) send(ctx context.Context, to string, msg []byte) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(DefaultSendTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if err := c.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(m.from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// LogMailer logs verification links instead of emailing them, for development without an
+// SMTP server. Anyone who can read the logs can verify any new account.
+type LogMailer struct {
+	logger    *slog.Logger
+	verifyURL *url.URL
+}
+
+// NewLogMailer logs links to verifyEmailURL through logger
+func NewLogMailer(logger *slog.Logger, verifyEmailURL string) (*LogMailer, error) {
+	verifyURL, err := parseVerifyEmailURL(verifyEmailURL)
+	if err != nil {
+		return nil, err
+	}
+	return &LogMailer{logger: logger, verifyURL: verifyURL}, nil
+}
+
+func (m *LogMailer) SendVerificationEmail(ctx context.Context, user *users.User, token string) error {
+	m.logger.InfoContext(ctx, "Verification email not sent, no SMTP server configured",
+		"user_id", user.ID, "link", VerificationLink(m.verifyURL, token))
+	return nil
+}
+
+// VerificationLink is base with token added as its token query parameter
+func VerificationLink(base *url.URL, token string) string {
+	link := *base
+	q := link.Query()
+	q.Set("token", token)
+	link.RawQuery = q.Encode()
+	return link.String()
+}
+
+func parseVerifyEmailURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid verify email URL %q: must be an absolute http(s) URL", raw)
+	}
+	return u, nil
+}
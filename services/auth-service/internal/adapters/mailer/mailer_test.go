@@ -0,0 +1,144 @@
+package mailer_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"log/slog"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/services/auth-service/internal/adapters/mailer"
+	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
+)
+
+// received is one email accepted by fakeSMTPServer
+type received struct {
+	from, to string
+	data     string
+}
+
+// fakeSMTPServer accepts a single unauthenticated delivery and sends it on the returned channel
+func fakeSMTPServer(t *testing.T) (string, <-chan received) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	out := make(chan received, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		var msg received
+		_ = tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+			case "EHLO", "HELO":
+				_ = tp.PrintfLine("250 localhost")
+			case "MAIL":
+				msg.from = line
+				_ = tp.PrintfLine("250 OK")
+			case "RCPT":
+				msg.to = line
+				_ = tp.PrintfLine("250 OK")
+			case "DATA":
+				_ = tp.PrintfLine("354 Go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				msg.data = string(data)
+				_ = tp.PrintfLine("250 OK")
+				out <- msg
+			case "QUIT":
+				_ = tp.PrintfLine("221 Bye")
+				return
+			default:
+				_ = tp.PrintfLine("502 Unsupported")
+			}
+		}
+	}()
+	return ln.Addr().String(), out
+}
+
+func TestSMTPMailer_SendVerificationEmail(t *testing.T) {
+	addr, deliveries := fakeSMTPServer(t)
+	m, err := mailer.NewSMTPMailer(mailer.SMTPConfig{Addr: addr, From: "Gavel <no-reply@gavel.test>"}, "https://gavel.test/verify-email")
+	require.NoError(t, err)
+	user := &users.User{ID: uuid.New(), Email: "new@example.com", FullName: "New User\r\nBcc: victim@example.com"}
+
+	require.NoError(t, m.SendVerificationEmail(context.Background(), user, "secret-token"))
+
+	msg := <-deliveries
+	assert.Equal(t, "MAIL FROM:<no-reply@gavel.test>", msg.from)
+	assert.Equal(t, "RCPT TO:<new@example.com>", msg.to)
+	assert.Contains(t, msg.data, "https://gavel.test/verify-email?token=secret-token")
+
+	headers, err := textproto.NewReader(bufio.NewReader(strings.NewReader(msg.data))).ReadMIMEHeader()
+	require.NoError(t, err)
+	assert.Empty(t, headers.Get("Bcc"), "the name must not inject headers")
+	assert.Equal(t, "Verify your email address", headers.Get("Subject"))
+}
+
+func TestSMTPMailer_UnreachableServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+	m, err := mailer.NewSMTPMailer(mailer.SMTPConfig{Addr: addr, From: "no-reply@gavel.test"}, "https://gavel.test/verify-email")
+	require.NoError(t, err)
+
+	err = m.SendVerificationEmail(context.Background(), &users.User{Email: "new@example.com"}, "secret-token")
+
+	assert.Error(t, err)
+}
+
+func TestNewSMTPMailer_InvalidConfig(t *testing.T) {
+	tests := map[string]struct {
+		cfg       mailer.SMTPConfig
+		verifyURL string
+	}{
+		"address without port": {mailer.SMTPConfig{Addr: "smtp.gavel.test", From: "no-reply@gavel.test"}, "https://gavel.test/verify-email"},
+		"invalid sender":       {mailer.SMTPConfig{Addr: "smtp.gavel.test:587", From: "not an address"}, "https://gavel.test/verify-email"},
+		"relative link":        {mailer.SMTPConfig{Addr: "smtp.gavel.test:587", From: "no-reply@gavel.test"}, "/verify-email"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := mailer.NewSMTPMailer(tc.cfg, tc.verifyURL)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLogMailer_SendVerificationEmail(t *testing.T) {
+	var logs bytes.Buffer
+	m, err := mailer.NewLogMailer(slog.New(slog.NewTextHandler(&logs, nil)), "http://localhost:3000/verify-email")
+	require.NoError(t, err)
+
+	require.NoError(t, m.SendVerificationEmail(context.Background(), &users.User{ID: uuid.New()}, "secret-token"))
+
+	assert.Contains(t, logs.String(), "http://localhost:3000/verify-email?token=secret-token")
+}
+
+func TestVerificationLink(t *testing.T) {
+	base, err := url.Parse("https://gavel.test/verify-email?lang=en")
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://gavel.test/verify-email?lang=en&token=a%2Bb%2F", mailer.VerificationLink(base, "a+b/"))
+	assert.Equal(t, "https://gavel.test/verify-email?lang=en", base.String(), "base must not be modified")
+}
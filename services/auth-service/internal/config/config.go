@@ -0,0 +1,288 @@
+// Package config loads the auth service binaries' settings from the environment. Every
+// setting is read and checked up front, so a misconfigured deployment reports all of its
+// problems at once instead of failing on the first.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/floroz/gavel/pkg/auth"
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/pkg/ratelimit"
+	"github.com/floroz/gavel/services/auth-service/internal/adapters/mailer"
+	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
+)
+
+// Default per-IP budget for Login and Register calls
+const (
+	DefaultRateLimit       = 10
+	DefaultRateLimitWindow = time.Minute
+)
+
+// DefaultVerifyEmailURL is the page verification links open in development, when they are
+// logged rather than emailed
+const DefaultVerifyEmailURL = "http://localhost:3000/verify-email"
+
+// ErrMissing is returned, listing the variables, when required settings are not set
+var ErrMissing = errors.New("missing required settings")
+
+// PasswordHashing selects the algorithm and cost of new password hashes
+type PasswordHashing struct {
+	Algorithm   string // PASSWORD_HASH_ALGORITHM, argon2id or bcrypt
+	ArgonParams auth.HashParams
+	BcryptCost  int
+}
+
+// API is the configuration of the auth API (cmd/api)
+type API struct {
+	DatabaseURL   string         // AUTH_DB_URL
+	JWTPrivateKey auth.KeySource // JWT_PRIVATE_KEY_PATH or JWT_PRIVATE_KEY (base64 PEM)
+	JWTPublicKey  auth.KeySource // JWT_PUBLIC_KEY_PATH or JWT_PUBLIC_KEY (base64 PEM)
+	JWTIssuer     string         // JWT_ISSUER
+	// RedisURL is the Redis address Login and Register are rate limited with; without it
+	// they are not rate limited
+	RedisURL        string
+	RateLimit       int           // AUTH_RATE_LIMIT
+	RateLimitWindow time.Duration // AUTH_RATE_LIMIT_WINDOW
+	// RateLimitFailurePolicy decides whether Login and Register are let through or refused
+	// while Redis is unavailable
+	RateLimitFailurePolicy ratelimit.FailurePolicy // AUTH_RATE_LIMIT_FAILURE_POLICY, open or closed
+	// TrustedProxies are the reverse proxies whose X-Forwarded-For identifies the client being
+	// rate limited; calls from anywhere else are limited by their own address
+	TrustedProxies ratelimit.TrustedProxies // AUTH_TRUSTED_PROXIES, comma-separated IPs and CIDRs
+	MaxSessions    int                      // AUTH_MAX_SESSIONS
+	// LockoutMaxAttempts is how many consecutive failed logins lock an account; 0 disables lockout
+	LockoutMaxAttempts int           // AUTH_LOCKOUT_MAX_ATTEMPTS
+	LockoutDuration    time.Duration // AUTH_LOCKOUT_DURATION
+	PasswordHashing    PasswordHashing
+	// SMTP is the server verification links are emailed through: AUTH_SMTP_ADDR,
+	// AUTH_SMTP_USERNAME, AUTH_SMTP_PASSWORD and AUTH_MAIL_FROM. Without an address the links
+	// are logged instead, which is only fit for development.
+	SMTP mailer.SMTPConfig
+	// VerifyEmailURL is the page the verification link opens, with the token in its query
+	VerifyEmailURL string // AUTH_VERIFY_EMAIL_URL, required along with AUTH_SMTP_ADDR
+}
+
+// Worker is the configuration of the auth outbox worker (cmd/worker)
+type Worker struct {
+	DatabaseURL     string        // AUTH_DB_URL
+	RabbitMQURL     string        // RABBITMQ_URL
+	OutboxRetention time.Duration // AUTH_OUTBOX_RETENTION
+	// TokenPruneInterval is how often expired refresh tokens are deleted
+	TokenPruneInterval time.Duration // AUTH_TOKEN_PRUNE_INTERVAL
+}
+
+// LoadAPI reads the API's settings, falling back to defaults for optional ones
+func LoadAPI() (*API, error) {
+	var l loader
+	cfg := &API{
+		DatabaseURL:            l.required("AUTH_DB_URL"),
+		JWTPrivateKey:          l.key("JWT_PRIVATE_KEY"),
+		JWTPublicKey:           l.key("JWT_PUBLIC_KEY"),
+		JWTIssuer:              l.required("JWT_ISSUER"),
+		RedisURL:               os.Getenv("REDIS_URL"),
+		RateLimit:              l.positiveInt("AUTH_RATE_LIMIT", DefaultRateLimit),
+		RateLimitWindow:        l.positiveDuration("AUTH_RATE_LIMIT_WINDOW", DefaultRateLimitWindow),
+		RateLimitFailurePolicy: l.failurePolicy("AUTH_RATE_LIMIT_FAILURE_POLICY", ratelimit.DefaultRateLimitFailurePolicy),
+		TrustedProxies:         l.trustedProxies("AUTH_TRUSTED_PROXIES"),
+		MaxSessions:            l.positiveInt("AUTH_MAX_SESSIONS", users.DefaultMaxSessions),
+		LockoutMaxAttempts:     l.nonNegativeInt("AUTH_LOCKOUT_MAX_ATTEMPTS", users.DefaultMaxFailedLogins),
+		LockoutDuration:        l.positiveDuration("AUTH_LOCKOUT_DURATION", users.DefaultLockoutDuration),
+		PasswordHashing:        l.passwordHashing(),
+	}
+	cfg.SMTP, cfg.VerifyEmailURL = l.mail()
+	if err := l.err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadWorker reads the worker's settings, falling back to defaults for optional ones
+func LoadWorker() (*Worker, error) {
+	var l loader
+	cfg := &Worker{
+		DatabaseURL:        l.required("AUTH_DB_URL"),
+		RabbitMQURL:        l.required("RABBITMQ_URL"),
+		OutboxRetention:    l.positiveDuration("AUTH_OUTBOX_RETENTION", pkgevents.DefaultOutboxRetention),
+		TokenPruneInterval: l.positiveDuration("AUTH_TOKEN_PRUNE_INTERVAL", users.DefaultTokenPruneInterval),
+	}
+	if err := l.err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loader collects every missing and invalid setting it is asked for
+type loader struct {
+	missing []string
+	invalid []error
+}
+
+func (l *loader) required(key string) string {
+	v := os.Getenv(key)
+	if v == "" {
+		l.missing = append(l.missing, key)
+	}
+	return v
+}
+
+// key reads a required key from <name>_PATH or <name>, see auth.KeySourceFromEnv
+func (l *loader) key(name string) auth.KeySource {
+	source := auth.KeySourceFromEnv(name)
+	if !source.IsSet() {
+		l.missing = append(l.missing, name+"_PATH or "+name)
+	}
+	return source
+}
+
+func (l *loader) positiveInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid %s: %q", key, v))
+		return def
+	}
+	return n
+}
+
+func (l *loader) nonNegativeInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid %s: %q", key, v))
+		return def
+	}
+	return n
+}
+
+func (l *loader) positiveDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid %s: %q", key, v))
+		return def
+	}
+	return d
+}
+
+func (l *loader) failurePolicy(key string, def ratelimit.FailurePolicy) ratelimit.FailurePolicy {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	policy, err := ratelimit.ParseFailurePolicy(v)
+	if err != nil {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid %s: %w", key, err))
+		return def
+	}
+	return policy
+}
+
+func (l *loader) trustedProxies(key string) ratelimit.TrustedProxies {
+	proxies, err := ratelimit.ParseTrustedProxies(os.Getenv(key))
+	if err != nil {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid %s: %w", key, err))
+	}
+	return proxies
+}
+
+func (l *loader) uint32(key string, def uint32) uint32 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid %s: %w", key, err))
+		return def
+	}
+	return uint32(n)
+}
+
+// passwordHashing reads PASSWORD_HASH_ALGORITHM, ARGON2_TIME, ARGON2_MEMORY_KB and BCRYPT_COST.
+// Hashes from either algorithm keep verifying regardless of the algorithm chosen for new ones.
+func (l *loader) passwordHashing() PasswordHashing {
+	h := PasswordHashing{
+		Algorithm:   os.Getenv("PASSWORD_HASH_ALGORITHM"),
+		ArgonParams: auth.DefaultHashParams,
+		BcryptCost:  bcrypt.DefaultCost,
+	}
+	if h.Algorithm == "" {
+		h.Algorithm = users.AlgorithmArgon2id
+	}
+	if h.Algorithm != users.AlgorithmArgon2id && h.Algorithm != users.AlgorithmBcrypt {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid PASSWORD_HASH_ALGORITHM: %q", h.Algorithm))
+	}
+
+	h.ArgonParams.Time = l.uint32("ARGON2_TIME", h.ArgonParams.Time)
+	h.ArgonParams.Memory = l.uint32("ARGON2_MEMORY_KB", h.ArgonParams.Memory)
+	if err := h.ArgonParams.Validate(); err != nil {
+		l.invalid = append(l.invalid, err)
+	}
+
+	if v := os.Getenv("BCRYPT_COST"); v != "" {
+		c, err := strconv.Atoi(v)
+		switch {
+		case err != nil:
+			l.invalid = append(l.invalid, fmt.Errorf("invalid BCRYPT_COST: %w", err))
+		case c < bcrypt.MinCost || c > bcrypt.MaxCost:
+			l.invalid = append(l.invalid, fmt.Errorf("BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost))
+		default:
+			h.BcryptCost = c
+		}
+	}
+	return h
+}
+
+// mail reads the SMTP server and the verification page. The sender and page are only required
+// with a server; without one links are logged and open DefaultVerifyEmailURL by default.
+func (l *loader) mail() (mailer.SMTPConfig, string) {
+	smtp := mailer.SMTPConfig{
+		Addr:     os.Getenv("AUTH_SMTP_ADDR"),
+		Username: os.Getenv("AUTH_SMTP_USERNAME"),
+		Password: os.Getenv("AUTH_SMTP_PASSWORD"),
+		From:     os.Getenv("AUTH_MAIL_FROM"),
+	}
+	verifyURL := os.Getenv("AUTH_VERIFY_EMAIL_URL")
+	if smtp.Addr != "" {
+		if smtp.From == "" {
+			l.missing = append(l.missing, "AUTH_MAIL_FROM")
+		}
+		if verifyURL == "" {
+			l.missing = append(l.missing, "AUTH_VERIFY_EMAIL_URL")
+		}
+	}
+	if verifyURL == "" {
+		return smtp, DefaultVerifyEmailURL
+	}
+	if u, err := url.Parse(verifyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid AUTH_VERIFY_EMAIL_URL: %q", verifyURL))
+	}
+	return smtp, verifyURL
+}
+
+// err reports everything missing as one ErrMissing, along with every invalid value
+func (l *loader) err() error {
+	var errs []error
+	if len(l.missing) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrMissing, strings.Join(l.missing, ", ")))
+	}
+	return errors.Join(append(errs, l.invalid...)...)
+}
@@ -0,0 +1,174 @@
+package config_test
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/auth"
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/pkg/ratelimit"
+	"github.com/floroz/gavel/services/auth-service/internal/adapters/mailer"
+	"github.com/floroz/gavel/services/auth-service/internal/config"
+	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
+)
+
+// setEnv replaces every setting the loaders read with env, leaving the rest unset
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range []string{
+		"AUTH_DB_URL", "RABBITMQ_URL", "REDIS_URL", "JWT_PRIVATE_KEY_PATH", "JWT_PUBLIC_KEY_PATH", "JWT_ISSUER",
+		"JWT_PRIVATE_KEY", "JWT_PUBLIC_KEY",
+		"AUTH_RATE_LIMIT", "AUTH_RATE_LIMIT_WINDOW", "AUTH_RATE_LIMIT_FAILURE_POLICY", "AUTH_TRUSTED_PROXIES", "AUTH_MAX_SESSIONS", "AUTH_OUTBOX_RETENTION",
+		"AUTH_LOCKOUT_MAX_ATTEMPTS", "AUTH_LOCKOUT_DURATION", "AUTH_TOKEN_PRUNE_INTERVAL",
+		"PASSWORD_HASH_ALGORITHM", "ARGON2_TIME", "ARGON2_MEMORY_KB", "BCRYPT_COST",
+		"AUTH_SMTP_ADDR", "AUTH_SMTP_USERNAME", "AUTH_SMTP_PASSWORD", "AUTH_MAIL_FROM", "AUTH_VERIFY_EMAIL_URL",
+	} {
+		t.Setenv(key, env[key])
+	}
+}
+
+func TestLoadAPI(t *testing.T) {
+	t.Run("complete environment", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"AUTH_DB_URL":                    "postgres://localhost/auth_db",
+			"JWT_PRIVATE_KEY_PATH":           "/keys/private.pem",
+			"JWT_PUBLIC_KEY":                 "cHVibGljIGtleQ==",
+			"JWT_ISSUER":                     "gavel-auth",
+			"REDIS_URL":                      "localhost:6379",
+			"AUTH_RATE_LIMIT":                "5",
+			"AUTH_RATE_LIMIT_WINDOW":         "30s",
+			"AUTH_RATE_LIMIT_FAILURE_POLICY": "closed",
+			"AUTH_TRUSTED_PROXIES":           "10.0.0.0/8",
+			"AUTH_MAX_SESSIONS":              "3",
+			"AUTH_LOCKOUT_MAX_ATTEMPTS":      "0",
+			"AUTH_LOCKOUT_DURATION":          "1h",
+			"PASSWORD_HASH_ALGORITHM":        users.AlgorithmBcrypt,
+			"BCRYPT_COST":                    "12",
+			"AUTH_SMTP_ADDR":                 "smtp.gavel.test:587",
+			"AUTH_SMTP_USERNAME":             "gavel",
+			"AUTH_SMTP_PASSWORD":             "secret",
+			"AUTH_MAIL_FROM":                 "Gavel <no-reply@gavel.test>",
+			"AUTH_VERIFY_EMAIL_URL":          "https://gavel.test/verify-email",
+		})
+
+		cfg, err := config.LoadAPI()
+		require.NoError(t, err)
+		assert.Equal(t, &config.API{
+			DatabaseURL:            "postgres://localhost/auth_db",
+			JWTPrivateKey:          auth.KeySource{Path: "/keys/private.pem"},
+			JWTPublicKey:           auth.KeySource{Base64: "cHVibGljIGtleQ=="},
+			JWTIssuer:              "gavel-auth",
+			RedisURL:               "localhost:6379",
+			RateLimit:              5,
+			RateLimitWindow:        30 * time.Second,
+			RateLimitFailurePolicy: ratelimit.FailClosed,
+			TrustedProxies:         ratelimit.TrustedProxies{netip.MustParsePrefix("10.0.0.0/8")},
+			MaxSessions:            3,
+			LockoutMaxAttempts:     0,
+			LockoutDuration:        time.Hour,
+			PasswordHashing: config.PasswordHashing{
+				Algorithm:   users.AlgorithmBcrypt,
+				ArgonParams: auth.DefaultHashParams,
+				BcryptCost:  12,
+			},
+			SMTP: mailer.SMTPConfig{
+				Addr:     "smtp.gavel.test:587",
+				Username: "gavel",
+				Password: "secret",
+				From:     "Gavel <no-reply@gavel.test>",
+			},
+			VerifyEmailURL: "https://gavel.test/verify-email",
+		}, cfg)
+	})
+
+	t.Run("optional settings default", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"AUTH_DB_URL":          "postgres://localhost/auth_db",
+			"JWT_PRIVATE_KEY_PATH": "/keys/private.pem",
+			"JWT_PUBLIC_KEY_PATH":  "/keys/public.pem",
+			"JWT_ISSUER":           "gavel-auth",
+		})
+
+		cfg, err := config.LoadAPI()
+		require.NoError(t, err)
+		assert.Empty(t, cfg.RedisURL)
+		assert.Equal(t, config.DefaultRateLimit, cfg.RateLimit)
+		assert.Equal(t, config.DefaultRateLimitWindow, cfg.RateLimitWindow)
+		assert.Equal(t, ratelimit.FailOpen, cfg.RateLimitFailurePolicy)
+		assert.Empty(t, cfg.TrustedProxies)
+		assert.Equal(t, users.DefaultMaxSessions, cfg.MaxSessions)
+		assert.Equal(t, users.DefaultMaxFailedLogins, cfg.LockoutMaxAttempts)
+		assert.Equal(t, users.DefaultLockoutDuration, cfg.LockoutDuration)
+		assert.Equal(t, users.AlgorithmArgon2id, cfg.PasswordHashing.Algorithm)
+		assert.Empty(t, cfg.SMTP.Addr)
+		assert.Equal(t, config.DefaultVerifyEmailURL, cfg.VerifyEmailURL)
+	})
+
+	t.Run("smtp needs a sender and verification page", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"AUTH_DB_URL":          "postgres://localhost/auth_db",
+			"JWT_PRIVATE_KEY_PATH": "/keys/private.pem",
+			"JWT_PUBLIC_KEY_PATH":  "/keys/public.pem",
+			"JWT_ISSUER":           "gavel-auth",
+			"AUTH_SMTP_ADDR":       "smtp.gavel.test:587",
+		})
+
+		_, err := config.LoadAPI()
+		require.ErrorIs(t, err, config.ErrMissing)
+		assert.Contains(t, err.Error(), "missing required settings: AUTH_MAIL_FROM, AUTH_VERIFY_EMAIL_URL")
+	})
+
+	t.Run("reports every problem at once", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"JWT_PUBLIC_KEY_PATH":            "/keys/public.pem",
+			"AUTH_RATE_LIMIT":                "-1",
+			"BCRYPT_COST":                    "99",
+			"AUTH_RATE_LIMIT_FAILURE_POLICY": "maybe",
+			"AUTH_VERIFY_EMAIL_URL":          "/verify-email",
+			"AUTH_LOCKOUT_MAX_ATTEMPTS":      "-3",
+			"AUTH_LOCKOUT_DURATION":          "0s",
+		})
+
+		_, err := config.LoadAPI()
+		require.ErrorIs(t, err, config.ErrMissing)
+		assert.Contains(t, err.Error(), "AUTH_DB_URL, JWT_PRIVATE_KEY_PATH or JWT_PRIVATE_KEY, JWT_ISSUER")
+		assert.NotContains(t, err.Error(), "JWT_PUBLIC_KEY")
+		assert.Contains(t, err.Error(), `invalid AUTH_RATE_LIMIT: "-1"`)
+		assert.Contains(t, err.Error(), "BCRYPT_COST must be between")
+		assert.Contains(t, err.Error(), "invalid AUTH_RATE_LIMIT_FAILURE_POLICY")
+		assert.Contains(t, err.Error(), `invalid AUTH_VERIFY_EMAIL_URL: "/verify-email"`)
+		assert.Contains(t, err.Error(), `invalid AUTH_LOCKOUT_MAX_ATTEMPTS: "-3"`)
+		assert.Contains(t, err.Error(), `invalid AUTH_LOCKOUT_DURATION: "0s"`)
+	})
+}
+
+func TestLoadWorker(t *testing.T) {
+	t.Run("complete environment", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"AUTH_DB_URL":  "postgres://localhost/auth_db",
+			"RABBITMQ_URL": "amqp://localhost:5672/",
+		})
+
+		cfg, err := config.LoadWorker()
+		require.NoError(t, err)
+		assert.Equal(t, &config.Worker{
+			DatabaseURL:        "postgres://localhost/auth_db",
+			RabbitMQURL:        "amqp://localhost:5672/",
+			OutboxRetention:    pkgevents.DefaultOutboxRetention,
+			TokenPruneInterval: users.DefaultTokenPruneInterval,
+		}, cfg)
+	})
+
+	t.Run("reports every missing key at once", func(t *testing.T) {
+		setEnv(t, map[string]string{"AUTH_OUTBOX_RETENTION": "soon"})
+
+		_, err := config.LoadWorker()
+		require.ErrorIs(t, err, config.ErrMissing)
+		assert.Contains(t, err.Error(), "missing required settings: AUTH_DB_URL, RABBITMQ_URL")
+		assert.Contains(t, err.Error(), `invalid AUTH_OUTBOX_RETENTION: "soon"`)
+	})
+}
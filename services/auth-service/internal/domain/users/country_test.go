@@ -0,0 +1,35 @@
+package users
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountryCodes(t *testing.T) {
+	assert.Len(t, countryCodes, 250, "249 ISO 3166-1 codes plus XK")
+	for code := range callingCodes {
+		assert.True(t, countryCodes[code], "%s has a calling code but is not a country", code)
+	}
+}
+
+func TestValidateCountryCode(t *testing.T) {
+	tests := map[string]struct {
+		countryCode string
+		valid       bool
+	}{
+		"assigned code":          {countryCode: "US", valid: true},
+		"well-formed unassigned": {countryCode: "ZZ"},
+		"alpha-3 code":           {countryCode: "usa"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateCountryCode(tt.countryCode)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/floroz/gavel/pkg/auth"
+)
+
+// Supported password hashing algorithms
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var ErrUnsupportedHash = errors.New("unsupported password hash algorithm")
+
+// PasswordHasher hashes and verifies user passwords
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Compare reports whether password matches encodedHash
+	Compare(encodedHash, password string) (bool, error)
+}
+
+// Argon2idHasher produces $argon2id$... hashes
+type Argon2idHasher struct {
+	Params auth.HashParams
+}
+
+func (h Argon2idHasher) Hash(password string) (string, error) {
+	return auth.HashPasswordWithParams(password, h.Params)
+}
+
+func (h Argon2idHasher) Compare(encodedHash, password string) (bool, error) {
+	return auth.VerifyPassword(encodedHash, password)
+}
+
+// BcryptHasher produces $2a$... hashes
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h BcryptHasher) Compare(encodedHash, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// migratingHasher hashes new passwords with the preferred algorithm but verifies
+// hashes from any supported algorithm, so users can be moved over gradually.
+type migratingHasher struct {
+	preferred string
+	hashers   map[string]PasswordHasher
+}
+
+// NewPasswordHasher returns a PasswordHasher that hashes with the given algorithm
+// and verifies existing hashes based on their algorithm prefix.
+func NewPasswordHasher(algorithm string, argonParams auth.HashParams, bcryptCost int) (PasswordHasher, error) {
+	h := newMigratingHasher(algorithm, argonParams, bcryptCost)
+	if _, ok := h.hashers[algorithm]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedHash, algorithm)
+	}
+	return h, nil
+}
+
+// defaultPasswordHasher hashes with Argon2id and still accepts bcrypt hashes
+func defaultPasswordHasher() PasswordHasher {
+	return newMigratingHasher(AlgorithmArgon2id, auth.DefaultHashParams, bcrypt.DefaultCost)
+}
+
+func newMigratingHasher(algorithm string, argonParams auth.HashParams, bcryptCost int) *migratingHasher {
+	return &migratingHasher{
+		preferred: algorithm,
+		hashers: map[string]PasswordHasher{
+			AlgorithmArgon2id: Argon2idHasher{Params: argonParams},
+			AlgorithmBcrypt:   BcryptHasher{Cost: bcryptCost},
+		},
+	}
+}
+
+func (h *migratingHasher) Hash(password string) (string, error) {
+	return h.hashers[h.preferred].Hash(password)
+}
+
+func (h *migratingHasher) Compare(encodedHash, password string) (bool, error) {
+	algorithm, err := hashAlgorithm(encodedHash)
+	if err != nil {
+		return false, err
+	}
+	return h.hashers[algorithm].Compare(encodedHash, password)
+}
+
+// hashAlgorithm identifies the algorithm from the hash's modular crypt format prefix
+func hashAlgorithm(encodedHash string) (string, error) {
+	switch {
+	case strings.HasPrefix(encodedHash, "$argon2id$"):
+		return AlgorithmArgon2id, nil
+	case strings.HasPrefix(encodedHash, "$2a$"),
+		strings.HasPrefix(encodedHash, "$2b$"),
+		strings.HasPrefix(encodedHash, "$2y$"):
+		return AlgorithmBcrypt, nil
+	default:
+		return "", ErrUnsupportedHash
+	}
+}
@@ -0,0 +1,80 @@
+package users
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/floroz/gavel/pkg/auth"
+)
+
+var testArgonParams = auth.HashParams{Memory: 8 * 1024, Time: 1, Threads: 1}
+
+func TestNewPasswordHasher(t *testing.T) {
+	const password = "correct-horse-battery-staple"
+
+	tests := []struct {
+		algorithm string
+		prefix    string
+	}{
+		{algorithm: AlgorithmArgon2id, prefix: "$argon2id$"},
+		{algorithm: AlgorithmBcrypt, prefix: "$2a$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			hasher, err := NewPasswordHasher(tt.algorithm, testArgonParams, bcrypt.MinCost)
+			require.NoError(t, err)
+
+			hash, err := hasher.Hash(password)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, tt.prefix), "hash %q should start with %q", hash, tt.prefix)
+
+			match, err := hasher.Compare(hash, password)
+			require.NoError(t, err)
+			assert.True(t, match)
+
+			match, err = hasher.Compare(hash, "wrong-password")
+			require.NoError(t, err)
+			assert.False(t, match)
+		})
+	}
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		_, err := NewPasswordHasher("md5", testArgonParams, bcrypt.MinCost)
+		assert.ErrorIs(t, err, ErrUnsupportedHash)
+	})
+}
+
+func TestPasswordHasher_DispatchesOnPrefix(t *testing.T) {
+	const password = "correct-horse-battery-staple"
+
+	bcryptHash, err := BcryptHasher{Cost: bcrypt.MinCost}.Hash(password)
+	require.NoError(t, err)
+	argonHash, err := Argon2idHasher{Params: testArgonParams}.Hash(password)
+	require.NoError(t, err)
+
+	for _, algorithm := range []string{AlgorithmArgon2id, AlgorithmBcrypt} {
+		t.Run("preferred "+algorithm, func(t *testing.T) {
+			hasher, err := NewPasswordHasher(algorithm, testArgonParams, bcrypt.MinCost)
+			require.NoError(t, err)
+
+			for _, hash := range []string{bcryptHash, argonHash} {
+				match, err := hasher.Compare(hash, password)
+				require.NoError(t, err)
+				assert.True(t, match, "hash %q should verify", hash)
+			}
+		})
+	}
+
+	t.Run("unknown prefix", func(t *testing.T) {
+		hasher, err := NewPasswordHasher(AlgorithmArgon2id, testArgonParams, bcrypt.MinCost)
+		require.NoError(t, err)
+
+		_, err = hasher.Compare("$1$md5crypt$hash", password)
+		assert.ErrorIs(t, err, ErrUnsupportedHash)
+	})
+}
@@ -16,6 +16,28 @@ type User struct {
 	AvatarURL    string    `json:"avatar_url" db:"avatar_url"`
 	PhoneNumber  string    `json:"phone_number" db:"phone_number"`
 	CountryCode  string    `json:"country_code" db:"country_code"`
+	Role         string    `json:"role" db:"role"`
+
+	EmailVerified bool       `json:"email_verified" db:"email_verified"`
+	DeletedAt     *time.Time `json:"-" db:"deleted_at"`
+
+	// IdempotencyKey is the key the account was registered with, if any. RegistrationHash
+	// fingerprints that request so a replay of the key can be checked against it.
+	IdempotencyKey   string `json:"-" db:"idempotency_key"`
+	RegistrationHash []byte `json:"-" db:"registration_hash"`
+
+	FailedLoginAttempts int        `json:"-" db:"failed_login_attempts"`
+	LockedUntil         *time.Time `json:"-" db:"locked_until"`
+}
+
+// IsDeleted returns true if the account has been deleted by its owner
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// IsLocked returns true if the account is locked out at the given time
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
 }
 
 type RefreshToken struct {
@@ -26,4 +48,32 @@ type RefreshToken struct {
 	CreatedAt time.Time `db:"created_at"`
 	UserAgent string    `db:"user_agent"`
 	IPAddress string    `db:"ip_address"`
+
+	// FamilyID links every token rotated from the same login
+	FamilyID   uuid.UUID  `db:"family_id"`
+	ConsumedAt *time.Time `db:"consumed_at"`
+}
+
+// IsConsumed returns true if the token has already been exchanged for a new one
+func (t *RefreshToken) IsConsumed() bool {
+	return t.ConsumedAt != nil
+}
+
+// EmailVerification is an outstanding token a user can confirm their email address with
+type EmailVerification struct {
+	TokenHash []byte    `db:"token_hash"`
+	UserID    uuid.UUID `db:"user_id"`
+	ExpiresAt time.Time `db:"expires_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Session is a login and the chain of refresh tokens rotated from it.
+// Its ID is the refresh token family ID.
+type Session struct {
+	ID         uuid.UUID
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time // When the user logged in
+	LastUsedAt time.Time // When the current refresh token was issued
+	ExpiresAt  time.Time
 }
@@ -0,0 +1,75 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		name        string
+		phoneNumber string
+		countryCode string
+		want        string
+		wantErr     error
+	}{
+		{name: "E.164", phoneNumber: "+15551234567", countryCode: "US", want: "+15551234567"},
+		{name: "E.164 with formatting", phoneNumber: "+44 20-7946.0958", countryCode: "GB", want: "+442079460958"},
+		{name: "E.164 from another country", phoneNumber: "+33 6 12 34 56 78", countryCode: "US", want: "+33612345678"},
+		{name: "00 international prefix", phoneNumber: "0049 30 901820", countryCode: "FR", want: "+4930901820"},
+		{name: "local US number", phoneNumber: "(555) 123-4567", countryCode: "US", want: "+15551234567"},
+		{name: "local US number with trunk prefix", phoneNumber: "1 555 123 4567", countryCode: "US", want: "+15551234567"},
+		{name: "local UK number drops trunk prefix", phoneNumber: "07911 123456", countryCode: "GB", want: "+447911123456"},
+		{name: "local Italian number keeps leading zero", phoneNumber: "06 6982 1234", countryCode: "IT", want: "+390669821234"},
+		{name: "letters", phoneNumber: "call me maybe", countryCode: "US", wantErr: errPhoneFormat},
+		{name: "misplaced plus", phoneNumber: "555+1234567", countryCode: "US", wantErr: errPhoneFormat},
+		{name: "unknown calling code", phoneNumber: "+999 1234 5678", countryCode: "US", wantErr: errPhoneCountryCode},
+		{name: "local number from unknown country", phoneNumber: "5551234567", countryCode: "QQ", wantErr: errPhoneCountryCode},
+		{name: "too long", phoneNumber: "+4412345678901234", countryCode: "GB", wantErr: errPhoneLength},
+		{name: "too short", phoneNumber: "+44 123", countryCode: "GB", wantErr: errPhoneLength},
+		{name: "short North American number", phoneNumber: "+1 555 1234", countryCode: "US", wantErr: errPhoneLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizePhoneNumber(tt.phoneNumber, tt.countryCode)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_Register_PhoneNumber(t *testing.T) {
+	t.Run("stores the normalized number", func(t *testing.T) {
+		svc := newTestService(t, WithPasswordHasher(Argon2idHasher{Params: testArgonParams}))
+		svc.users.On("GetUserByEmail", mock.Anything, "new@example.com").Return(nil, nil)
+		svc.users.On("CreateEmailVerification", mock.Anything, mock.Anything, mock.AnythingOfType("*users.EmailVerification")).Return(nil)
+		svc.users.On("CreateUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *User) bool {
+			return u.PhoneNumber == "+15551234567"
+		})).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		user, err := svc.Register(context.Background(), "new@example.com", "password123", "New User", "(555) 123-4567", "US", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "+15551234567", user.PhoneNumber)
+		svc.users.AssertExpectations(t)
+	})
+
+	t.Run("rejects an invalid number", func(t *testing.T) {
+		svc := newTestService(t)
+
+		_, err := svc.Register(context.Background(), "new@example.com", "password123", "New User", "not a phone", "US", "")
+
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		svc.users.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -2,23 +2,63 @@ package users
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/floroz/gavel/pkg/auth"
 	"github.com/floroz/gavel/pkg/events"
 )
 
 type UserRepository interface {
+	// CreateUser returns ErrUserAlreadyExists if the email is taken, and ErrIdempotencyKeyTaken
+	// if the idempotency key is
 	CreateUser(ctx context.Context, tx pgx.Tx, user *User) error
+	// GetUserByID returns ErrUserNotFound if there is no user with the ID, deleted or not
 	GetUserByID(ctx context.Context, id uuid.UUID) (*User, error)
+	// GetUserByEmail returns nil if no user has the email, as registering checks it is free
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	// GetUserByIdempotencyKey returns the user registered with the key, or nil if there is none
+	GetUserByIdempotencyKey(ctx context.Context, key string) (*User, error)
+	// ReleaseIdempotencyKey clears the user's idempotency key, so it can be used again
+	ReleaseIdempotencyKey(ctx context.Context, userID uuid.UUID) error
+	// UpdateProfile saves the user's full name, avatar URL and country code
+	UpdateProfile(ctx context.Context, user *User) error
+	// SoftDeleteUser marks an active account as deleted. It returns false if there is no such account.
+	SoftDeleteUser(ctx context.Context, tx pgx.Tx, userID uuid.UUID) (bool, error)
+
+	CreateEmailVerification(ctx context.Context, tx pgx.Tx, verification *EmailVerification) error
+	// GetEmailVerification returns nil if no token has the given hash
+	GetEmailVerification(ctx context.Context, tokenHash []byte) (*EmailVerification, error)
+	// MarkEmailVerified sets the user's email verified flag. Tokens are kept, so a reused
+	// link can be told apart from an unknown one.
+	MarkEmailVerified(ctx context.Context, userID uuid.UUID) error
+
+	// IncrementFailedLoginAttempts bumps the failed login counter and returns the new value
+	IncrementFailedLoginAttempts(ctx context.Context, userID uuid.UUID) (int, error)
+	// LockUser locks the account until the given time and clears the failed login counter
+	LockUser(ctx context.Context, userID uuid.UUID, until time.Time) error
+	// ResetFailedLoginAttempts clears the failed login counter and any active lock
+	ResetFailedLoginAttempts(ctx context.Context, userID uuid.UUID) error
 }
 
 type TokenRepository interface {
 	CreateRefreshToken(ctx context.Context, tx pgx.Tx, token *RefreshToken) error
 	GetRefreshToken(ctx context.Context, tokenHash []byte) (*RefreshToken, error)
 	RevokeRefreshToken(ctx context.Context, tx pgx.Tx, tokenHash []byte) error
+	// ConsumeRefreshToken marks an active token as exchanged. It returns false if the
+	// token was already consumed or revoked, e.g. by a concurrent refresh.
+	ConsumeRefreshToken(ctx context.Context, tx pgx.Tx, tokenHash []byte) (bool, error)
+	// RevokeTokenFamily revokes every token rotated from the same login
+	RevokeTokenFamily(ctx context.Context, tx pgx.Tx, familyID uuid.UUID) error
+	// ListActiveSessions returns one entry per token family with a usable refresh token
+	ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]*Session, error)
+	// RevokeSession revokes a session owned by userID. It returns false if no such active session exists.
+	RevokeSession(ctx context.Context, tx pgx.Tx, userID, sessionID uuid.UUID) (bool, error)
+	// RevokeOldestSessions revokes the user's active refresh tokens, oldest first, until at most
+	// keep remain, and returns how many it revoked
+	RevokeOldestSessions(ctx context.Context, tx pgx.Tx, userID uuid.UUID, keep int) (int, error)
 	// RevokeAllUserTokens is useful for "logout from all devices" functionality
 	RevokeAllUserTokens(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error
 }
@@ -28,14 +68,31 @@ type OutboxRepository interface {
 	events.OutboxRepository
 }
 
+// VerificationMailer emails a new user the link to verify their address with, after their
+// registration is committed. The token is the only copy of the secret: it is neither stored
+// nor published, only its hash is kept.
+type VerificationMailer interface {
+	SendVerificationEmail(ctx context.Context, user *User, token string) error
+}
+
 type EventPublisher interface {
-	Publish(ctx context.Context, exchange, routingKey string, body []byte) error
+	Publish(ctx context.Context, exchange, routingKey string, body []byte, opts ...events.PublishOption) error
 }
 
 type AuthService interface {
-	Register(ctx context.Context, email, password, fullName, phoneNumber, countryCode string) (*User, error)
-	Login(ctx context.Context, email, password, userAgent, ip string) (accessToken, refreshToken string, err error)
-	Refresh(ctx context.Context, refreshToken, userAgent, ip string) (newAccess, newRefresh string, err error)
+	// Register creates an account. With a non-empty idempotencyKey, replaying the same request
+	// returns the account it created and reusing the key for a different one fails with
+	// ErrIdempotencyKeyReused.
+	Register(ctx context.Context, email, password, fullName, phoneNumber, countryCode, idempotencyKey string) (*User, error)
+	// Login and Refresh issue a new token pair, whose AccessExpiry is the access token's exp
+	Login(ctx context.Context, email, password, userAgent, ip string) (*auth.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken, userAgent, ip string) (*auth.TokenPair, error)
 	Logout(ctx context.Context, refreshToken string) error
 	GetProfile(ctx context.Context, userID uuid.UUID) (*User, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, fullName, avatarURL, countryCode string) (*User, error)
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]*Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	VerifyToken(ctx context.Context, accessToken string) (*auth.Claims, error)
+	VerifyEmail(ctx context.Context, token string) (*User, error)
+	DeleteAccount(ctx context.Context, userID uuid.UUID) error
 }
@@ -0,0 +1,41 @@
+package users
+
+import (
+	"slices"
+
+	"github.com/floroz/gavel/pkg/auth"
+)
+
+// Roles a user can hold. Every account starts as RoleUser.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Permissions carried in access tokens, named action:resource
+const (
+	PermissionPlaceBids       = "place:bids"
+	PermissionManageOwnItems  = "manage:own_items"
+	PermissionReadStats       = "read:stats"
+	PermissionUpdateProfile   = "update:profile"
+	PermissionForceCancelItem = auth.PermissionForceCancelItem
+	PermissionManageUsers     = "manage:users"
+)
+
+var userPermissions = []string{
+	PermissionPlaceBids,
+	PermissionManageOwnItems,
+	PermissionReadStats,
+	PermissionUpdateProfile,
+}
+
+// rolePermissions maps each role to the permissions granted to its holders
+var rolePermissions = map[string][]string{
+	RoleUser:  userPermissions,
+	RoleAdmin: append(slices.Clone(userPermissions), PermissionForceCancelItem, PermissionManageUsers),
+}
+
+// PermissionsFor returns the permissions granted to role. Unknown roles are granted none.
+func PermissionsFor(role string) []string {
+	return slices.Clone(rolePermissions[role])
+}
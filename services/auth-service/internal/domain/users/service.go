@@ -1,17 +1,22 @@
 package users
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/floroz/gavel/pkg/apperrors"
 	"github.com/floroz/gavel/pkg/auth"
 	"github.com/floroz/gavel/pkg/database"
 	"github.com/floroz/gavel/pkg/events"
@@ -19,19 +24,108 @@ import (
 )
 
 var (
-	ErrUserAlreadyExists  = errors.New("user with this email already exists")
-	ErrInvalidCredentials = errors.New("invalid email or password")
-	ErrInvalidToken       = errors.New("invalid or expired refresh token")
-	ErrUserNotFound       = errors.New("user not found")
-	ErrInvalidInput       = errors.New("invalid input")
+	ErrUserAlreadyExists  = apperrors.New(apperrors.CodeAlreadyExists, "user with this email already exists")
+	ErrInvalidCredentials = apperrors.New(apperrors.CodeUnauthenticated, "invalid email or password")
+	ErrInvalidToken       = apperrors.New(apperrors.CodeUnauthenticated, "invalid or expired refresh token")
+	ErrInvalidAccessToken = apperrors.New(apperrors.CodeUnauthenticated, "invalid access token")
+	ErrAccessTokenExpired = apperrors.New(apperrors.CodeUnauthenticated, "access token has expired")
+	ErrUserNotFound       = apperrors.New(apperrors.CodeNotFound, "user not found")
+	ErrInvalidInput       = apperrors.New(apperrors.CodeInvalidArgument, "invalid input")
+	ErrSessionNotFound    = apperrors.New(apperrors.CodeNotFound, "session not found")
+	ErrAccountLocked      = apperrors.New(apperrors.CodePermissionDenied, "account is temporarily locked due to too many failed login attempts")
+
+	ErrInvalidVerificationToken = apperrors.New(apperrors.CodeInvalidArgument, "invalid email verification token")
+	ErrVerificationTokenExpired = apperrors.New(apperrors.CodeFailedPrecondition, "email verification token has expired")
+	ErrEmailAlreadyVerified     = apperrors.New(apperrors.CodeAlreadyExists, "email is already verified")
+
+	ErrIdempotencyKeyReused = apperrors.New(apperrors.CodeAlreadyExists, "idempotency key was already used for a different registration")
+
+	// ErrIdempotencyKeyTaken is returned by UserRepository.CreateUser when another user already
+	// holds the idempotency key, e.g. one created by a concurrent Register
+	ErrIdempotencyKeyTaken = errors.New("idempotency key is taken")
 )
 
+// DefaultMaxSessions is how many sessions a user may have active at once unless
+// WithMaxSessions says otherwise
+const DefaultMaxSessions = 10
+
+// MaxIdempotencyKeyLength bounds the Register idempotency key
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyKeyTTL is how long after a registration its idempotency key replays it. An older
+// key is released and can be used again.
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// Default brute-force protection settings
+const (
+	DefaultMaxFailedLogins = 5
+	DefaultLockoutDuration = 15 * time.Minute
+)
+
+// EmailVerificationTTL is how long the link emailed on registration stays valid
+const EmailVerificationTTL = 24 * time.Hour
+
+// maxAvatarURLLength matches the longest URL browsers and CDNs reliably handle
+const maxAvatarURLLength = 2048
+
 type Service struct {
 	userRepo   UserRepository
 	tokenRepo  TokenRepository
 	outboxRepo OutboxRepository
 	signer     *auth.Signer
 	txManager  database.TransactionManager
+
+	maxFailedLogins int
+	lockoutDuration time.Duration
+	hasher          PasswordHasher
+	maxSessions     int
+	mailer          VerificationMailer
+	logger          *slog.Logger
+	now             func() time.Time
+}
+
+// Option configures optional Service behaviour
+type Option func(*Service)
+
+// WithLockoutPolicy locks an account for the given duration after maxAttempts
+// consecutive failed logins. A maxAttempts of 0 disables lockout.
+func WithLockoutPolicy(maxAttempts int, duration time.Duration) Option {
+	return func(s *Service) {
+		s.maxFailedLogins = maxAttempts
+		s.lockoutDuration = duration
+	}
+}
+
+// WithPasswordHasher sets the algorithm and cost used for new password hashes.
+// Existing hashes keep verifying since their algorithm and cost are stored alongside them.
+func WithPasswordHasher(hasher PasswordHasher) Option {
+	return func(s *Service) {
+		s.hasher = hasher
+	}
+}
+
+// WithVerificationMailer sends new users their email verification link through mailer once
+// their registration is committed. Without one no link is sent, and addresses can't be verified.
+func WithVerificationMailer(mailer VerificationMailer) Option {
+	return func(s *Service) {
+		s.mailer = mailer
+	}
+}
+
+// WithLogger reports failures that don't fail the call, like a verification link that could not
+// be sent, through logger rather than slog.Default()
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithMaxSessions caps the sessions a user can have active at once. A login beyond the cap
+// ends the user's oldest sessions. A max of 0 leaves sessions uncapped.
+func WithMaxSessions(max int) Option {
+	return func(s *Service) {
+		s.maxSessions = max
+	}
 }
 
 func NewService(
@@ -40,32 +134,64 @@ func NewService(
 	outboxRepo OutboxRepository,
 	signer *auth.Signer,
 	txManager database.TransactionManager,
+	opts ...Option,
 ) *Service {
-	return &Service{
-		userRepo:   userRepo,
-		tokenRepo:  tokenRepo,
-		outboxRepo: outboxRepo,
-		signer:     signer,
-		txManager:  txManager,
-	}
+	s := &Service{
+		userRepo:        userRepo,
+		tokenRepo:       tokenRepo,
+		outboxRepo:      outboxRepo,
+		signer:          signer,
+		txManager:       txManager,
+		maxFailedLogins: DefaultMaxFailedLogins,
+		lockoutDuration: DefaultLockoutDuration,
+		hasher:          defaultPasswordHasher(),
+		maxSessions:     DefaultMaxSessions,
+		logger:          slog.Default(),
+		now:             time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-func (s *Service) Register(ctx context.Context, email, password, fullName, phoneNumber, countryCode string) (*User, error) {
+func (s *Service) Register(ctx context.Context, email, password, fullName, phoneNumber, countryCode, idempotencyKey string) (*User, error) {
+	email = normalizeEmail(email)
 	if err := validateUser(email, password, fullName, phoneNumber, countryCode); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
 	}
+	phoneNumber, err := normalizePhoneNumber(phoneNumber, countryCode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if len(idempotencyKey) > MaxIdempotencyKeyLength {
+		return nil, fmt.Errorf("%w: idempotency key must be at most %d characters", ErrInvalidInput, MaxIdempotencyKeyLength)
+	}
+
+	var requestHash []byte
+	if idempotencyKey != "" {
+		requestHash = registrationHash(email, fullName, phoneNumber, countryCode)
+		replayed, err := s.replayRegistration(ctx, idempotencyKey, requestHash, password)
+		if err != nil {
+			return nil, err
+		}
+		if replayed != nil {
+			return replayed, nil
+		}
+	}
 
 	// Check if user already exists
 	existing, err := s.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing user: %w", err)
 	}
+	// Including deleted accounts: their email is not released for reuse
 	if existing != nil {
 		return nil, ErrUserAlreadyExists
 	}
 
 	// Hash password
-	hash, err := auth.HashPassword(password)
+	hash, err := s.hasher.Hash(password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
@@ -79,8 +205,12 @@ func (s *Service) Register(ctx context.Context, email, password, fullName, phone
 		FullName:     fullName,
 		PhoneNumber:  phoneNumber,
 		CountryCode:  countryCode,
+		Role:         RoleUser,
 		CreatedAt:    now,
 		UpdatedAt:    now,
+
+		IdempotencyKey:   idempotencyKey,
+		RegistrationHash: requestHash,
 	}
 
 	// Transaction: Save User and Outbox Event
@@ -91,9 +221,28 @@ func (s *Service) Register(ctx context.Context, email, password, fullName, phone
 	defer tx.Rollback(ctx)
 
 	if err := s.userRepo.CreateUser(ctx, tx, user); err != nil {
+		// A concurrent Register with the same key may have won the race on either unique column
+		if idempotencyKey != "" && (errors.Is(err, ErrIdempotencyKeyTaken) || errors.Is(err, ErrUserAlreadyExists)) {
+			_ = tx.Rollback(ctx)
+			return s.replayConcurrentRegistration(ctx, idempotencyKey, requestHash, password, err)
+		}
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	// The token is only handed to the mailer; a hash is kept to check it against
+	verificationToken, err := newVerificationToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate verification token: %w", err)
+	}
+	verification := &EmailVerification{
+		TokenHash: hashToken(verificationToken),
+		UserID:    user.ID,
+		ExpiresAt: now.Add(EmailVerificationTTL),
+		CreatedAt: now,
+	}
+	if err := s.userRepo.CreateEmailVerification(ctx, tx, verification); err != nil {
+		return nil, fmt.Errorf("failed to create email verification: %w", err)
+	}
 	// Create Outbox Event
 	event := &pb.UserCreated{
 		UserId:      user.ID.String(),
@@ -102,17 +251,19 @@ func (s *Service) Register(ctx context.Context, email, password, fullName, phone
 		CountryCode: user.CountryCode,
 		CreatedAt:   timestamppb.New(user.CreatedAt),
 	}
-	payload, err := proto.Marshal(event)
+	payload, err := events.MarshalEvent(event)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal event: %w", err)
+		return nil, err
 	}
 
 	outboxEvent := &events.OutboxEvent{
-		ID:        uuid.New(),
-		EventType: "user.created",
-		Payload:   payload,
-		Status:    events.OutboxStatusPending,
-		CreatedAt: now,
+		ID:          uuid.New(),
+		AggregateID: user.ID,
+		EventType:   events.RoutingKeyUserCreated,
+		Payload:     payload,
+		Headers:     events.EventHeaders(ctx),
+		Status:      events.OutboxStatusPending,
+		CreatedAt:   now,
 	}
 
 	if err := s.outboxRepo.CreateEvent(ctx, tx, outboxEvent); err != nil {
@@ -123,80 +274,163 @@ func (s *Service) Register(ctx context.Context, email, password, fullName, phone
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	// Only sent once the account exists, and without holding the transaction open. The account
+	// is kept if the link can't be sent, as failing would only make a retry find the email taken.
+	if s.mailer != nil {
+		if err := s.mailer.SendVerificationEmail(ctx, user, verificationToken); err != nil {
+			s.logger.ErrorContext(ctx, "Failed to send verification email", "user_id", user.ID, "error", err)
+		}
+	}
+
 	return user, nil
 }
 
-func (s *Service) Login(ctx context.Context, email, password, userAgent, ip string) (string, string, error) {
-	user, err := s.userRepo.GetUserByEmail(ctx, email)
+// replayRegistration returns the user an earlier Register with the same key created, or nil if
+// the key is new or has expired. The password is checked against the stored hash, as only a
+// fingerprint of the other fields is kept.
+func (s *Service) replayRegistration(ctx context.Context, key string, requestHash []byte, password string) (*User, error) {
+	user, err := s.userRepo.GetUserByIdempotencyKey(ctx, key)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to check idempotency key: %w", err)
 	}
 	if user == nil {
-		return "", "", ErrInvalidCredentials
+		return nil, nil
+	}
+	if !s.now().Before(user.CreatedAt.Add(IdempotencyKeyTTL)) {
+		if err := s.userRepo.ReleaseIdempotencyKey(ctx, user.ID); err != nil {
+			return nil, fmt.Errorf("failed to release idempotency key: %w", err)
+		}
+		return nil, nil
+	}
+
+	if !bytes.Equal(user.RegistrationHash, requestHash) {
+		return nil, ErrIdempotencyKeyReused
+	}
+	samePassword, err := s.hasher.Compare(user.PasswordHash, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify password: %w", err)
+	}
+	if !samePassword {
+		return nil, ErrIdempotencyKeyReused
+	}
+	// The account is gone, and like any deleted account its email is not released
+	if user.IsDeleted() {
+		return nil, ErrUserAlreadyExists
+	}
+	return user, nil
+}
+
+// replayConcurrentRegistration resolves a CreateUser that failed with createErr because a
+// concurrent Register committed first, replaying that registration if it used the same key
+func (s *Service) replayConcurrentRegistration(ctx context.Context, key string, requestHash []byte, password string, createErr error) (*User, error) {
+	replayed, err := s.replayRegistration(ctx, key, requestHash, password)
+	if err != nil {
+		return nil, err
+	}
+	if replayed != nil {
+		return replayed, nil
+	}
+	if errors.Is(createErr, ErrIdempotencyKeyTaken) {
+		return nil, ErrIdempotencyKeyReused
+	}
+	return nil, ErrUserAlreadyExists
+}
+
+func (s *Service) Login(ctx context.Context, email, password, userAgent, ip string) (*auth.TokenPair, error) {
+	user, err := s.userRepo.GetUserByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	// Deleted accounts fail like unknown ones, without revealing the email was registered
+	if user == nil || user.IsDeleted() {
+		return nil, ErrInvalidCredentials
+	}
+
+	// Reject while locked, even if the password is correct
+	if user.IsLocked(s.now()) {
+		return nil, ErrAccountLocked
 	}
 
 	// Verify password
-	valid, err := auth.VerifyPassword(user.PasswordHash, password)
+	valid, err := s.hasher.Compare(user.PasswordHash, password)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to verify password: %w", err)
+		return nil, fmt.Errorf("failed to verify password: %w", err)
 	}
 	if !valid {
-		return "", "", ErrInvalidCredentials
+		return nil, s.recordFailedLogin(ctx, user)
+	}
+
+	// Successful login resets the brute-force counter
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := s.userRepo.ResetFailedLoginAttempts(ctx, user.ID); err != nil {
+			return nil, fmt.Errorf("failed to reset failed login attempts: %w", err)
+		}
 	}
 
 	return s.generateAndSaveTokens(ctx, user, userAgent, ip)
 }
 
-func (s *Service) Refresh(ctx context.Context, refreshToken, userAgent, ip string) (string, string, error) {
+func (s *Service) Refresh(ctx context.Context, refreshToken, userAgent, ip string) (*auth.TokenPair, error) {
 	// Hash the incoming token to look it up
 	tokenHash := hashToken(refreshToken)
 
 	// Get stored token
 	storedToken, err := s.tokenRepo.GetRefreshToken(ctx, tokenHash)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get refresh token: %w", err)
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
 	}
 	if storedToken == nil {
-		return "", "", ErrInvalidToken
+		return nil, ErrInvalidToken
+	}
+
+	// A consumed token being presented again means it was stolen (or the legitimate
+	// client was). Either way, kill the whole chain so neither party can continue.
+	if storedToken.IsConsumed() {
+		if err := s.revokeTokenFamily(ctx, storedToken.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidToken
 	}
 
 	// Check validity
 	if storedToken.Revoked {
-		// Potential reuse attack!
-		// We should probably revoke all user tokens here for security.
-		// For now, just return error.
-		return "", "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 	if time.Now().After(storedToken.ExpiresAt) {
-		return "", "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
 	// Get User
-	user, err := s.userRepo.GetUserByID(ctx, storedToken.UserID)
+	user, err := s.activeUser(ctx, storedToken.UserID)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get user: %w", err)
-	}
-	if user == nil {
-		return "", "", ErrUserNotFound
+		return nil, err
 	}
 
-	// Rotate tokens: Revoke old one, issue new ones
+	// Rotate tokens: Consume old one, issue new ones in the same family
 	tx, err := s.txManager.BeginTx(ctx)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	// Revoke old token
-	if err := s.tokenRepo.RevokeRefreshToken(ctx, tx, tokenHash); err != nil {
-		return "", "", fmt.Errorf("failed to revoke token: %w", err)
+	consumed, err := s.tokenRepo.ConsumeRefreshToken(ctx, tx, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume token: %w", err)
+	}
+	if !consumed {
+		// Lost the race against another refresh with the same token
+		_ = tx.Rollback(ctx)
+		if err := s.revokeTokenFamily(ctx, storedToken.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidToken
 	}
 
 	// Generate and save new tokens (inside the same transaction)
 	// We duplicate generateAndSaveTokens logic slightly here to use the existing tx
-	tokenPair, err := s.signer.GenerateTokens(user.ID, user.Email, user.FullName, nil) // Permissions empty for now
+	tokenPair, err := s.signer.GenerateTokens(user.ID, user.Email, user.FullName, user.Role, PermissionsFor(user.Role))
 	if err != nil {
-		return "", "", fmt.Errorf("failed to generate tokens: %w", err)
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
 	newTokenHash := hashToken(tokenPair.RefreshToken)
@@ -208,17 +442,18 @@ func (s *Service) Refresh(ctx context.Context, refreshToken, userAgent, ip strin
 		CreatedAt: time.Now(),
 		UserAgent: userAgent,
 		IPAddress: ip,
+		FamilyID:  storedToken.FamilyID,
 	}
 
 	if err := s.tokenRepo.CreateRefreshToken(ctx, tx, newStoredToken); err != nil {
-		return "", "", fmt.Errorf("failed to save refresh token: %w", err)
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return "", "", fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return tokenPair.AccessToken, tokenPair.RefreshToken, nil
+	return tokenPair, nil
 }
 
 func (s *Service) Logout(ctx context.Context, refreshToken string) error {
@@ -238,23 +473,164 @@ func (s *Service) Logout(ctx context.Context, refreshToken string) error {
 }
 
 func (s *Service) GetProfile(ctx context.Context, userID uuid.UUID) (*User, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// activeUser returns the user with the given ID, or ErrUserNotFound if there is none or the
+// account has been deleted
+func (s *Service) activeUser(ctx context.Context, userID uuid.UUID) (*User, error) {
 	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, ErrUserNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
-	if user == nil {
+	if user.IsDeleted() {
 		return nil, ErrUserNotFound
 	}
 	return user, nil
 }
 
+// UpdateProfile replaces the user's name, avatar and country, validated as at registration
+func (s *Service) UpdateProfile(ctx context.Context, userID uuid.UUID, fullName, avatarURL, countryCode string) (*User, error) {
+	fullName = strings.TrimSpace(fullName)
+	avatarURL = strings.TrimSpace(avatarURL)
+	if err := validateProfile(fullName, avatarURL, countryCode); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.FullName = fullName
+	user.AvatarURL = avatarURL
+	user.CountryCode = countryCode
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.UpdateProfile(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+	return user, nil
+}
+
+// DeleteAccount deactivates the user's account and signs out all their sessions.
+// The row is kept for the bids and items that reference it, and the email stays taken.
+func (s *Service) DeleteAccount(ctx context.Context, userID uuid.UUID) error {
+	tx, err := s.txManager.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	deleted, err := s.userRepo.SoftDeleteUser(ctx, tx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if !deleted {
+		return ErrUserNotFound
+	}
+	if err := s.tokenRepo.RevokeAllUserTokens(ctx, tx, userID); err != nil {
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListSessions returns the user's active sessions, most recently used first
+func (s *Service) ListSessions(ctx context.Context, userID uuid.UUID) ([]*Session, error) {
+	sessions, err := s.tokenRepo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes every refresh token in one of the user's sessions.
+// Sessions belonging to other users are reported as not found.
+func (s *Service) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	tx, err := s.txManager.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	revoked, err := s.tokenRepo.RevokeSession(ctx, tx, userID, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+// VerifyToken validates an access token issued by this service and returns its claims
+func (s *Service) VerifyToken(ctx context.Context, accessToken string) (*auth.Claims, error) {
+	if strings.TrimSpace(accessToken) == "" {
+		return nil, fmt.Errorf("%w: access token cannot be empty", ErrInvalidInput)
+	}
+	claims, err := s.signer.ValidateToken(accessToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrTokenExpired) {
+			return nil, ErrAccessTokenExpired
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAccessToken, err)
+	}
+	return claims, nil
+}
+
+// VerifyEmail marks the email of the user the token was issued to as verified
+func (s *Service) VerifyEmail(ctx context.Context, token string) (*User, error) {
+	if strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("%w: verification token cannot be empty", ErrInvalidInput)
+	}
+
+	verification, err := s.userRepo.GetEmailVerification(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email verification: %w", err)
+	}
+	if verification == nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user, err := s.activeUser(ctx, verification.UserID)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Checked before expiry, so following an old link after verifying says so
+	if user.EmailVerified {
+		return nil, ErrEmailAlreadyVerified
+	}
+	if !s.now().Before(verification.ExpiresAt) {
+		return nil, ErrVerificationTokenExpired
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("failed to mark email verified: %w", err)
+	}
+	user.EmailVerified = true
+	return user, nil
+}
+
 // Helpers
 
-func (s *Service) generateAndSaveTokens(ctx context.Context, user *User, userAgent, ip string) (string, string, error) {
+// generateAndSaveTokens starts a new session for a freshly authenticated user and
+// records the login in the outbox within the same transaction
+func (s *Service) generateAndSaveTokens(ctx context.Context, user *User, userAgent, ip string) (*auth.TokenPair, error) {
 	// Generate Tokens
-	tokenPair, err := s.signer.GenerateTokens(user.ID, user.Email, user.FullName, nil)
+	tokenPair, err := s.signer.GenerateTokens(user.ID, user.Email, user.FullName, user.Role, PermissionsFor(user.Role))
 	if err != nil {
-		return "", "", fmt.Errorf("failed to generate tokens: %w", err)
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
 	// Save Refresh Token
@@ -267,30 +643,126 @@ func (s *Service) generateAndSaveTokens(ctx context.Context, user *User, userAge
 		CreatedAt: time.Now(),
 		UserAgent: userAgent,
 		IPAddress: ip,
+		FamilyID:  uuid.New(), // Each login starts a new token family
 	}
 
 	tx, err := s.txManager.BeginTx(ctx)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
 	if err := s.tokenRepo.CreateRefreshToken(ctx, tx, refreshToken); err != nil {
-		return "", "", fmt.Errorf("failed to save refresh token: %w", err)
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	// Make room for the new session by ending the oldest ones
+	if s.maxSessions > 0 {
+		if _, err := s.tokenRepo.RevokeOldestSessions(ctx, tx, user.ID, s.maxSessions); err != nil {
+			return nil, fmt.Errorf("failed to evict old sessions: %w", err)
+		}
+	}
+
+	// Create Outbox Event
+	event := &pb.UserLoggedIn{
+		UserId:     user.ID.String(),
+		IpAddress:  ip,
+		UserAgent:  userAgent,
+		LoggedInAt: timestamppb.New(refreshToken.CreatedAt),
+	}
+	payload, err := events.MarshalEvent(event)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxEvent := &events.OutboxEvent{
+		ID:          uuid.New(),
+		AggregateID: user.ID,
+		EventType:   events.RoutingKeyUserLoggedIn,
+		Payload:     payload,
+		Headers:     events.EventHeaders(ctx),
+		Status:      events.OutboxStatusPending,
+		CreatedAt:   refreshToken.CreatedAt,
+	}
+
+	if err := s.outboxRepo.CreateEvent(ctx, tx, outboxEvent); err != nil {
+		return nil, fmt.Errorf("failed to create outbox event: %w", err)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return "", "", fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return tokenPair, nil
+}
+
+// recordFailedLogin bumps the user's failed login counter and locks the account
+// once the configured threshold is reached. It returns the error Login should surface.
+func (s *Service) recordFailedLogin(ctx context.Context, user *User) error {
+	if s.maxFailedLogins <= 0 {
+		return ErrInvalidCredentials
+	}
+
+	attempts, err := s.userRepo.IncrementFailedLoginAttempts(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record failed login: %w", err)
+	}
+	if attempts < s.maxFailedLogins {
+		return ErrInvalidCredentials
+	}
+
+	if err := s.userRepo.LockUser(ctx, user.ID, s.now().Add(s.lockoutDuration)); err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
 	}
+	return ErrAccountLocked
+}
+
+// revokeTokenFamily revokes every refresh token descended from the same login
+func (s *Service) revokeTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	tx, err := s.txManager.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-	return tokenPair.AccessToken, tokenPair.RefreshToken, nil
+	if err := s.tokenRepo.RevokeTokenFamily(ctx, tx, familyID); err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
 
+// newVerificationToken returns a random, URL-safe token for an email verification link
+func newVerificationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is the SHA-256 of a refresh or verification token, the only form stored.
+// The tokens carry 256 bits of entropy, so an unsalted fast hash cannot be brute forced.
 func hashToken(token string) []byte {
 	hash := sha256.Sum256([]byte(token))
 	return hash[:]
 }
 
+// registrationHash fingerprints the normalized, non-secret fields of a Register request
+func registrationHash(email, fullName, phoneNumber, countryCode string) []byte {
+	hash := sha256.Sum256([]byte(strings.Join([]string{email, fullName, phoneNumber, countryCode}, "\x00")))
+	return hash[:]
+}
+
+// normalizeEmail returns the form emails are stored and looked up in, so that addresses
+// differing only in case or surrounding whitespace belong to the same account
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 func validateUser(email, password, fullName, phoneNumber, countryCode string) error {
 	if !strings.Contains(email, "@") || len(email) < 3 {
 		return errors.New("invalid email format")
@@ -298,12 +770,50 @@ func validateUser(email, password, fullName, phoneNumber, countryCode string) er
 	if len(password) < 8 {
 		return errors.New("password must be at least 8 characters")
 	}
-	if strings.TrimSpace(fullName) == "" {
-		return errors.New("full name cannot be empty")
+	if err := validateFullName(fullName); err != nil {
+		return err
 	}
 	if strings.TrimSpace(phoneNumber) == "" {
 		return errors.New("phone number cannot be empty")
 	}
+	return validateCountryCode(countryCode)
+}
+
+// validateProfile checks the fields a user may change after registering
+func validateProfile(fullName, avatarURL, countryCode string) error {
+	if err := validateFullName(fullName); err != nil {
+		return err
+	}
+	if err := validateAvatarURL(avatarURL); err != nil {
+		return err
+	}
+	return validateCountryCode(countryCode)
+}
+
+// validateAvatarURL allows an empty avatar, or an absolute http(s) URL short enough to store and
+// render safely. Other schemes, such as javascript: or data:, are rejected.
+func validateAvatarURL(avatarURL string) error {
+	if avatarURL == "" {
+		return nil
+	}
+	if len(avatarURL) > maxAvatarURLLength {
+		return fmt.Errorf("avatar url must be at most %d characters", maxAvatarURLLength)
+	}
+	u, err := url.Parse(avatarURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("avatar url must be an absolute http(s) URL")
+	}
+	return nil
+}
+
+func validateFullName(fullName string) error {
+	if strings.TrimSpace(fullName) == "" {
+		return errors.New("full name cannot be empty")
+	}
+	return nil
+}
+
+func validateCountryCode(countryCode string) error {
 	if len(countryCode) != 2 || countryCode != strings.ToUpper(countryCode) {
 		return errors.New("country code must be 2 uppercase letters (ISO 3166-1 alpha-2)")
 	}
@@ -312,5 +822,8 @@ func validateUser(email, password, fullName, phoneNumber, countryCode string) er
 			return errors.New("country code must contain only letters")
 		}
 	}
+	if !countryCodes[countryCode] {
+		return fmt.Errorf("country code %q is not an ISO 3166-1 alpha-2 country", countryCode)
+	}
 	return nil
 }
@@ -0,0 +1,1216 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/floroz/gavel/pkg/auth"
+	"github.com/floroz/gavel/pkg/events"
+	pb "github.com/floroz/gavel/pkg/proto"
+	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
+)
+
+// MockUserRepository is a mock implementation of UserRepository for testing
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) CreateUser(ctx context.Context, tx pgx.Tx, user *User) error {
+	args := m.Called(ctx, tx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserByIdempotencyKey(ctx context.Context, key string) (*User, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (m *MockUserRepository) ReleaseIdempotencyKey(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateProfile(ctx context.Context, user *User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SoftDeleteUser(ctx context.Context, tx pgx.Tx, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, tx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) CreateEmailVerification(ctx context.Context, tx pgx.Tx, verification *EmailVerification) error {
+	args := m.Called(ctx, tx, verification)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetEmailVerification(ctx context.Context, tokenHash []byte) (*EmailVerification, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*EmailVerification), args.Error(1)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) IncrementFailedLoginAttempts(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserRepository) LockUser(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	args := m.Called(ctx, userID, until)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ResetFailedLoginAttempts(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockTokenRepository is a mock implementation of TokenRepository for testing
+type MockTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRepository) CreateRefreshToken(ctx context.Context, tx pgx.Tx, token *RefreshToken) error {
+	args := m.Called(ctx, tx, token)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetRefreshToken(ctx context.Context, tokenHash []byte) (*RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*RefreshToken), args.Error(1)
+}
+
+func (m *MockTokenRepository) RevokeRefreshToken(ctx context.Context, tx pgx.Tx, tokenHash []byte) error {
+	args := m.Called(ctx, tx, tokenHash)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) ConsumeRefreshToken(ctx context.Context, tx pgx.Tx, tokenHash []byte) (bool, error) {
+	args := m.Called(ctx, tx, tokenHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) RevokeTokenFamily(ctx context.Context, tx pgx.Tx, familyID uuid.UUID) error {
+	args := m.Called(ctx, tx, familyID)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]*Session, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Session), args.Error(1)
+}
+
+func (m *MockTokenRepository) RevokeSession(ctx context.Context, tx pgx.Tx, userID, sessionID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, tx, userID, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) RevokeOldestSessions(ctx context.Context, tx pgx.Tx, userID uuid.UUID, keep int) (int, error) {
+	args := m.Called(ctx, tx, userID, keep)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) RevokeAllUserTokens(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	args := m.Called(ctx, tx, userID)
+	return args.Error(0)
+}
+
+// MockOutboxRepository is a mock implementation of OutboxRepository for testing
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) CreateEvent(ctx context.Context, tx pgx.Tx, event *events.OutboxEvent) error {
+	args := m.Called(ctx, tx, event)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) GetPendingEvents(ctx context.Context, tx pgx.Tx, limit int) ([]*events.OutboxEvent, error) {
+	args := m.Called(ctx, tx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*events.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxRepository) UpdateEventStatus(ctx context.Context, tx pgx.Tx, id uuid.UUID, status events.OutboxStatus) error {
+	args := m.Called(ctx, tx, id, status)
+	return args.Error(0)
+}
+
+// MockVerificationMailer is a mock implementation of VerificationMailer for testing
+type MockVerificationMailer struct {
+	mock.Mock
+}
+
+func (m *MockVerificationMailer) SendVerificationEmail(ctx context.Context, user *User, token string) error {
+	args := m.Called(ctx, user, token)
+	return args.Error(0)
+}
+
+// fakeTx is a no-op transaction; repositories are mocked so only Commit/Rollback are exercised
+type fakeTx struct {
+	pgx.Tx
+	commitErr error
+}
+
+func (tx fakeTx) Commit(context.Context) error { return tx.commitErr }
+func (fakeTx) Rollback(context.Context) error  { return nil }
+
+// fakeTxManager hands out fakeTx transactions, whose Commit fails with commitErr
+type fakeTxManager struct {
+	commitErr error
+}
+
+func (m fakeTxManager) BeginTx(context.Context) (pgx.Tx, error) {
+	return fakeTx{commitErr: m.commitErr}, nil
+}
+
+func (m fakeTxManager) BeginTxWithOptions(context.Context, pgx.TxOptions) (pgx.Tx, error) {
+	return fakeTx{commitErr: m.commitErr}, nil
+}
+
+func (m fakeTxManager) WithTx(_ context.Context, fn func(pgx.Tx) error) error {
+	if err := fn(fakeTx{}); err != nil {
+		return err
+	}
+	return m.commitErr
+}
+
+// testService bundles a Service with its mocked dependencies
+type testService struct {
+	*Service
+	users  *MockUserRepository
+	tokens *MockTokenRepository
+	outbox *MockOutboxRepository
+
+	// signingKey signs the service's tokens, for crafting tokens the signer would not issue
+	signingKey *rsa.PrivateKey
+}
+
+func newTestService(t *testing.T, opts ...Option) *testService {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+	pubBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes})
+
+	signer, err := auth.NewSigner(privPEM, pubPEM, "test-issuer")
+	require.NoError(t, err)
+
+	ts := &testService{
+		users:  new(MockUserRepository),
+		tokens: new(MockTokenRepository),
+		outbox: new(MockOutboxRepository),
+
+		signingKey: privateKey,
+	}
+	// Nothing to evict unless a test says otherwise
+	ts.tokens.On("RevokeOldestSessions", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, nil).Maybe()
+	ts.Service = NewService(ts.users, ts.tokens, ts.outbox, signer, fakeTxManager{}, opts...)
+	return ts
+}
+
+func newTestUser(t *testing.T, password string) *User {
+	t.Helper()
+	hash, err := auth.HashPassword(password)
+	require.NoError(t, err)
+	return &User{
+		ID:           uuid.New(),
+		Email:        "user@example.com",
+		PasswordHash: hash,
+		FullName:     "Test User",
+		Role:         RoleUser,
+	}
+}
+
+func TestService_Login_Lockout(t *testing.T) {
+	const password = "correct-password"
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("locks the account after max failed attempts", func(t *testing.T) {
+		svc := newTestService(t, WithLockoutPolicy(3, 10*time.Minute))
+		svc.now = func() time.Time { return now }
+		user := newTestUser(t, password)
+		user.FailedLoginAttempts = 2
+
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+		svc.users.On("IncrementFailedLoginAttempts", mock.Anything, user.ID).Return(3, nil)
+		svc.users.On("LockUser", mock.Anything, user.ID, now.Add(10*time.Minute)).Return(nil)
+
+		_, err := svc.Login(context.Background(), user.Email, "wrong-password", "ua", "127.0.0.1")
+
+		assert.ErrorIs(t, err, ErrAccountLocked)
+		svc.users.AssertExpectations(t)
+	})
+
+	t.Run("below threshold returns invalid credentials", func(t *testing.T) {
+		svc := newTestService(t, WithLockoutPolicy(3, 10*time.Minute))
+		user := newTestUser(t, password)
+
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+		svc.users.On("IncrementFailedLoginAttempts", mock.Anything, user.ID).Return(1, nil)
+
+		_, err := svc.Login(context.Background(), user.Email, "wrong-password", "ua", "127.0.0.1")
+
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		svc.users.AssertNotCalled(t, "LockUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("correct password is rejected while locked", func(t *testing.T) {
+		svc := newTestService(t)
+		svc.now = func() time.Time { return now }
+		user := newTestUser(t, password)
+		lockedUntil := now.Add(5 * time.Minute)
+		user.LockedUntil = &lockedUntil
+
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+
+		_, err := svc.Login(context.Background(), user.Email, password, "ua", "127.0.0.1")
+
+		assert.ErrorIs(t, err, ErrAccountLocked)
+		svc.tokens.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("login succeeds once the lock expires and resets the counter", func(t *testing.T) {
+		svc := newTestService(t)
+		svc.now = func() time.Time { return now }
+		user := newTestUser(t, password)
+		lockedUntil := now.Add(-1 * time.Second)
+		user.LockedUntil = &lockedUntil
+
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+		svc.users.On("ResetFailedLoginAttempts", mock.Anything, user.ID).Return(nil)
+		svc.tokens.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.AnythingOfType("*users.RefreshToken")).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.AnythingOfType("*events.OutboxEvent")).Return(nil)
+
+		tokens, err := svc.Login(context.Background(), user.Email, password, "ua", "127.0.0.1")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.NotEmpty(t, tokens.RefreshToken)
+		svc.users.AssertExpectations(t)
+		svc.tokens.AssertExpectations(t)
+	})
+
+	t.Run("successful login without prior failures does not touch the counter", func(t *testing.T) {
+		svc := newTestService(t)
+		user := newTestUser(t, password)
+
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+		svc.tokens.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.AnythingOfType("*users.RefreshToken")).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.AnythingOfType("*events.OutboxEvent")).Return(nil)
+
+		_, err := svc.Login(context.Background(), user.Email, password, "ua", "127.0.0.1")
+
+		require.NoError(t, err)
+		svc.users.AssertNotCalled(t, "ResetFailedLoginAttempts", mock.Anything, mock.Anything)
+	})
+
+	t.Run("lockout disabled never locks", func(t *testing.T) {
+		svc := newTestService(t, WithLockoutPolicy(0, 0))
+		user := newTestUser(t, password)
+
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+
+		_, err := svc.Login(context.Background(), user.Email, "wrong-password", "ua", "127.0.0.1")
+
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		svc.users.AssertNotCalled(t, "IncrementFailedLoginAttempts", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Refresh_Reuse(t *testing.T) {
+	const presented = "refresh-token"
+	familyID := uuid.New()
+	user := &User{ID: uuid.New(), Email: "user@example.com", FullName: "Test User"}
+
+	newStoredToken := func() *RefreshToken {
+		return &RefreshToken{
+			TokenHash: hashToken(presented),
+			UserID:    user.ID,
+			ExpiresAt: time.Now().Add(time.Hour),
+			CreatedAt: time.Now(),
+			FamilyID:  familyID,
+		}
+	}
+
+	t.Run("rotation consumes the old token and keeps the family", func(t *testing.T) {
+		svc := newTestService(t)
+		svc.tokens.On("GetRefreshToken", mock.Anything, hashToken(presented)).Return(newStoredToken(), nil)
+		svc.users.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		svc.tokens.On("ConsumeRefreshToken", mock.Anything, mock.Anything, hashToken(presented)).Return(true, nil)
+		svc.tokens.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.MatchedBy(func(rt *RefreshToken) bool {
+			return rt.FamilyID == familyID
+		})).Return(nil)
+
+		tokens, err := svc.Refresh(context.Background(), presented, "ua", "127.0.0.1")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.NotEqual(t, presented, tokens.RefreshToken)
+		svc.tokens.AssertExpectations(t)
+		svc.tokens.AssertNotCalled(t, "RevokeTokenFamily", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("replaying a consumed token revokes the family", func(t *testing.T) {
+		svc := newTestService(t)
+		stored := newStoredToken()
+		consumedAt := time.Now().Add(-time.Minute)
+		stored.ConsumedAt = &consumedAt
+		svc.tokens.On("GetRefreshToken", mock.Anything, hashToken(presented)).Return(stored, nil)
+		svc.tokens.On("RevokeTokenFamily", mock.Anything, mock.Anything, familyID).Return(nil)
+
+		_, err := svc.Refresh(context.Background(), presented, "ua", "127.0.0.1")
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		svc.tokens.AssertExpectations(t)
+		svc.tokens.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("losing a concurrent refresh revokes the family", func(t *testing.T) {
+		svc := newTestService(t)
+		svc.tokens.On("GetRefreshToken", mock.Anything, hashToken(presented)).Return(newStoredToken(), nil)
+		svc.users.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		svc.tokens.On("ConsumeRefreshToken", mock.Anything, mock.Anything, hashToken(presented)).Return(false, nil)
+		svc.tokens.On("RevokeTokenFamily", mock.Anything, mock.Anything, familyID).Return(nil)
+
+		_, err := svc.Refresh(context.Background(), presented, "ua", "127.0.0.1")
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		svc.tokens.AssertExpectations(t)
+		svc.tokens.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("revoked token is rejected without touching the family", func(t *testing.T) {
+		svc := newTestService(t)
+		stored := newStoredToken()
+		stored.Revoked = true
+		svc.tokens.On("GetRefreshToken", mock.Anything, hashToken(presented)).Return(stored, nil)
+
+		_, err := svc.Refresh(context.Background(), presented, "ua", "127.0.0.1")
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		svc.tokens.AssertNotCalled(t, "RevokeTokenFamily", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_PasswordHashCost(t *testing.T) {
+	const password = "correct-password"
+	cheap := auth.HashParams{Memory: 8 * 1024, Time: 1, Threads: 1}
+	expensive := auth.HashParams{Memory: 16 * 1024, Time: 3, Threads: 2}
+
+	t.Run("new hashes use the configured cost", func(t *testing.T) {
+		svc := newTestService(t, WithPasswordHasher(Argon2idHasher{Params: cheap}))
+		svc.users.On("GetUserByEmail", mock.Anything, "new@example.com").Return(nil, nil)
+		svc.users.On("CreateEmailVerification", mock.Anything, mock.Anything, mock.AnythingOfType("*users.EmailVerification")).Return(nil)
+		svc.users.On("CreateUser", mock.Anything, mock.Anything, mock.AnythingOfType("*users.User")).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		user, err := svc.Register(context.Background(), "new@example.com", password, "New User", "+15550000000", "US", "")
+
+		require.NoError(t, err)
+		assert.Contains(t, user.PasswordHash, "$m=8192,t=1,p=1$")
+	})
+
+	t.Run("lower cost hash verifies under a higher cost configuration", func(t *testing.T) {
+		svc := newTestService(t, WithPasswordHasher(Argon2idHasher{Params: expensive}))
+		user := newTestUser(t, password)
+		hash, err := auth.HashPasswordWithParams(password, cheap)
+		require.NoError(t, err)
+		user.PasswordHash = hash
+
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+		svc.tokens.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.AnythingOfType("*users.RefreshToken")).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.AnythingOfType("*events.OutboxEvent")).Return(nil)
+
+		_, err = svc.Login(context.Background(), user.Email, password, "ua", "127.0.0.1")
+
+		require.NoError(t, err)
+	})
+}
+
+func TestService_Login_BcryptMigration(t *testing.T) {
+	const password = "correct-password"
+
+	bcryptHash, err := BcryptHasher{Cost: bcrypt.MinCost}.Hash(password)
+	require.NoError(t, err)
+
+	argonHasher, err := NewPasswordHasher(AlgorithmArgon2id, auth.DefaultHashParams, bcrypt.MinCost)
+	require.NoError(t, err)
+
+	for name, opts := range map[string][]Option{
+		"default hasher":           nil,
+		"explicit argon2id hasher": {WithPasswordHasher(argonHasher)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc := newTestService(t, opts...)
+			user := newTestUser(t, password)
+			user.PasswordHash = bcryptHash
+
+			svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+			svc.tokens.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.AnythingOfType("*users.RefreshToken")).Return(nil)
+			svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.AnythingOfType("*events.OutboxEvent")).Return(nil)
+
+			_, err := svc.Login(context.Background(), user.Email, password, "ua", "127.0.0.1")
+			require.NoError(t, err)
+
+			svc.users.On("IncrementFailedLoginAttempts", mock.Anything, user.ID).Return(1, nil)
+			_, err = svc.Login(context.Background(), user.Email, "wrong-password", "ua", "127.0.0.1")
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+
+	t.Run("new registrations use argon2id", func(t *testing.T) {
+		svc := newTestService(t, WithPasswordHasher(argonHasher))
+		svc.users.On("GetUserByEmail", mock.Anything, "new@example.com").Return(nil, nil)
+		svc.users.On("CreateEmailVerification", mock.Anything, mock.Anything, mock.AnythingOfType("*users.EmailVerification")).Return(nil)
+		svc.users.On("CreateUser", mock.Anything, mock.Anything, mock.AnythingOfType("*users.User")).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		user, err := svc.Register(context.Background(), "new@example.com", password, "New User", "+15550000000", "US", "")
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(user.PasswordHash, "$argon2id$"))
+	})
+}
+
+func TestService_Login_OutboxEvent(t *testing.T) {
+	const password = "correct-password"
+
+	t.Run("successful login writes user.logged_in", func(t *testing.T) {
+		svc := newTestService(t)
+		user := newTestUser(t, password)
+
+		var savedToken *RefreshToken
+		var saved *events.OutboxEvent
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+		svc.tokens.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.AnythingOfType("*users.RefreshToken")).
+			Run(func(args mock.Arguments) { savedToken = args.Get(2).(*RefreshToken) }).
+			Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.AnythingOfType("*events.OutboxEvent")).
+			Run(func(args mock.Arguments) { saved = args.Get(2).(*events.OutboxEvent) }).
+			Return(nil)
+
+		_, err := svc.Login(context.Background(), user.Email, password, "TestAgent/1.0", "10.0.0.1")
+		require.NoError(t, err)
+
+		require.NotNil(t, saved)
+		assert.Equal(t, "user.logged_in", saved.EventType)
+		assert.Equal(t, events.OutboxStatusPending, saved.Status)
+
+		var event pb.UserLoggedIn
+		require.NoError(t, proto.Unmarshal(saved.Payload, &event))
+		assert.Equal(t, user.ID.String(), event.UserId)
+		assert.Equal(t, "10.0.0.1", event.IpAddress)
+		assert.Equal(t, "TestAgent/1.0", event.UserAgent)
+		assert.Equal(t, savedToken.CreatedAt.UTC(), event.LoggedInAt.AsTime())
+	})
+
+	t.Run("failed login writes nothing", func(t *testing.T) {
+		svc := newTestService(t)
+		user := newTestUser(t, password)
+
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+		svc.users.On("IncrementFailedLoginAttempts", mock.Anything, user.ID).Return(1, nil)
+
+		_, err := svc.Login(context.Background(), user.Email, "wrong-password", "TestAgent/1.0", "10.0.0.1")
+
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		svc.outbox.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Login_MaxSessions(t *testing.T) {
+	const password = "correct-password"
+
+	// newService drops the default RevokeOldestSessions stub, so each case sets its own expectation
+	newService := func(t *testing.T, user *User, opts ...Option) *testService {
+		t.Helper()
+		svc := newTestService(t, opts...)
+		svc.tokens.ExpectedCalls = nil
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+		svc.tokens.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.AnythingOfType("*users.RefreshToken")).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		return svc
+	}
+
+	t.Run("keeps the default number of sessions", func(t *testing.T) {
+		user := newTestUser(t, password)
+		svc := newService(t, user)
+		svc.tokens.On("RevokeOldestSessions", mock.Anything, mock.Anything, user.ID, DefaultMaxSessions).Return(0, nil).Once()
+
+		_, err := svc.Login(context.Background(), user.Email, password, "TestAgent/1.0", "10.0.0.1")
+
+		require.NoError(t, err)
+		svc.tokens.AssertExpectations(t)
+	})
+
+	t.Run("keeps the configured number of sessions", func(t *testing.T) {
+		user := newTestUser(t, password)
+		svc := newService(t, user, WithMaxSessions(3))
+		svc.tokens.On("RevokeOldestSessions", mock.Anything, mock.Anything, user.ID, 3).Return(2, nil).Once()
+
+		_, err := svc.Login(context.Background(), user.Email, password, "TestAgent/1.0", "10.0.0.1")
+
+		require.NoError(t, err)
+		svc.tokens.AssertExpectations(t)
+	})
+
+	t.Run("uncapped sessions are never evicted", func(t *testing.T) {
+		user := newTestUser(t, password)
+		svc := newService(t, user, WithMaxSessions(0))
+
+		_, err := svc.Login(context.Background(), user.Email, password, "TestAgent/1.0", "10.0.0.1")
+
+		require.NoError(t, err)
+		svc.tokens.AssertNotCalled(t, "RevokeOldestSessions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed eviction fails the login", func(t *testing.T) {
+		user := newTestUser(t, password)
+		svc := newService(t, user)
+		svc.tokens.On("RevokeOldestSessions", mock.Anything, mock.Anything, user.ID, DefaultMaxSessions).Return(0, errors.New("db down"))
+
+		_, err := svc.Login(context.Background(), user.Email, password, "TestAgent/1.0", "10.0.0.1")
+
+		assert.ErrorContains(t, err, "failed to evict old sessions")
+		svc.outbox.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Register_IdempotencyKey(t *testing.T) {
+	const (
+		password = "correct-password"
+		key      = "register-7f3c"
+	)
+
+	// stored is what the first Register under key saved
+	stored := func(t *testing.T) *User {
+		t.Helper()
+		user := newTestUser(t, password)
+		user.Email = "new@example.com"
+		user.FullName = "New User"
+		user.PhoneNumber = "+15550000000"
+		user.CountryCode = "US"
+		user.IdempotencyKey = key
+		user.RegistrationHash = registrationHash(user.Email, user.FullName, user.PhoneNumber, user.CountryCode)
+		user.CreatedAt = time.Now()
+		return user
+	}
+
+	t.Run("first request stores the key with the user", func(t *testing.T) {
+		svc := newTestService(t, WithPasswordHasher(Argon2idHasher{Params: testArgonParams}))
+		svc.users.On("GetUserByIdempotencyKey", mock.Anything, key).Return(nil, nil)
+		svc.users.On("GetUserByEmail", mock.Anything, "new@example.com").Return(nil, nil)
+		svc.users.On("CreateEmailVerification", mock.Anything, mock.Anything, mock.AnythingOfType("*users.EmailVerification")).Return(nil)
+		svc.users.On("CreateUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *User) bool {
+			return u.IdempotencyKey == key && len(u.RegistrationHash) > 0
+		})).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		_, err := svc.Register(context.Background(), "new@example.com", password, "New User", "+15550000000", "US", key)
+
+		require.NoError(t, err)
+		svc.users.AssertExpectations(t)
+	})
+
+	t.Run("identical replay returns the original user", func(t *testing.T) {
+		svc := newTestService(t)
+		original := stored(t)
+		svc.users.On("GetUserByIdempotencyKey", mock.Anything, key).Return(original, nil)
+
+		// The same inputs, before normalization
+		user, err := svc.Register(context.Background(), " New@Example.com", password, "New User", "+1 555 000 0000", "US", key)
+
+		require.NoError(t, err)
+		assert.Equal(t, original.ID, user.ID)
+		svc.users.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+		svc.outbox.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("different payload under the same key conflicts", func(t *testing.T) {
+		svc := newTestService(t)
+		svc.users.On("GetUserByIdempotencyKey", mock.Anything, key).Return(stored(t), nil)
+
+		_, err := svc.Register(context.Background(), "other@example.com", password, "New User", "+15550000000", "US", key)
+
+		assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+		svc.users.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("different password under the same key conflicts", func(t *testing.T) {
+		svc := newTestService(t)
+		svc.users.On("GetUserByIdempotencyKey", mock.Anything, key).Return(stored(t), nil)
+
+		_, err := svc.Register(context.Background(), "new@example.com", "another-password", "New User", "+15550000000", "US", key)
+
+		assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+	})
+
+	t.Run("deleted account is not replayed", func(t *testing.T) {
+		svc := newTestService(t)
+		deleted := stored(t)
+		deletedAt := time.Now()
+		deleted.DeletedAt = &deletedAt
+		svc.users.On("GetUserByIdempotencyKey", mock.Anything, key).Return(deleted, nil)
+
+		_, err := svc.Register(context.Background(), "new@example.com", password, "New User", "+15550000000", "US", key)
+
+		assert.ErrorIs(t, err, ErrUserAlreadyExists)
+		svc.users.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("expired key is released and not replayed", func(t *testing.T) {
+		svc := newTestService(t)
+		expired := stored(t)
+		expired.CreatedAt = time.Now().Add(-IdempotencyKeyTTL - time.Minute)
+		svc.users.On("GetUserByIdempotencyKey", mock.Anything, key).Return(expired, nil)
+		svc.users.On("ReleaseIdempotencyKey", mock.Anything, expired.ID).Return(nil)
+		svc.users.On("GetUserByEmail", mock.Anything, "new@example.com").Return(expired, nil)
+
+		_, err := svc.Register(context.Background(), "new@example.com", password, "New User", "+15550000000", "US", key)
+
+		assert.ErrorIs(t, err, ErrUserAlreadyExists)
+		svc.users.AssertExpectations(t)
+	})
+
+	t.Run("concurrent request with the same key replays the winner", func(t *testing.T) {
+		svc := newTestService(t)
+		winner := stored(t)
+		svc.users.On("GetUserByIdempotencyKey", mock.Anything, key).Return(nil, nil).Once()
+		svc.users.On("GetUserByEmail", mock.Anything, "new@example.com").Return(nil, nil)
+		svc.users.On("CreateUser", mock.Anything, mock.Anything, mock.Anything).Return(ErrIdempotencyKeyTaken)
+		svc.users.On("GetUserByIdempotencyKey", mock.Anything, key).Return(winner, nil).Once()
+
+		user, err := svc.Register(context.Background(), "new@example.com", password, "New User", "+15550000000", "US", key)
+
+		require.NoError(t, err)
+		assert.Equal(t, winner.ID, user.ID)
+		svc.outbox.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("concurrent request with another key for the email conflicts", func(t *testing.T) {
+		svc := newTestService(t)
+		svc.users.On("GetUserByIdempotencyKey", mock.Anything, key).Return(nil, nil)
+		svc.users.On("GetUserByEmail", mock.Anything, "new@example.com").Return(nil, nil)
+		svc.users.On("CreateUser", mock.Anything, mock.Anything, mock.Anything).Return(ErrUserAlreadyExists)
+
+		_, err := svc.Register(context.Background(), "new@example.com", password, "New User", "+15550000000", "US", key)
+
+		assert.ErrorIs(t, err, ErrUserAlreadyExists)
+	})
+
+	t.Run("overlong key is rejected", func(t *testing.T) {
+		svc := newTestService(t)
+
+		_, err := svc.Register(context.Background(), "new@example.com", password, "New User", "+15550000000", "US",
+			strings.Repeat("k", MaxIdempotencyKeyLength+1))
+
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+}
+
+func TestService_EmailNormalization(t *testing.T) {
+	const password = "correct-password"
+
+	t.Run("register stores the lowercased, trimmed email", func(t *testing.T) {
+		svc := newTestService(t, WithPasswordHasher(Argon2idHasher{Params: testArgonParams}))
+		svc.users.On("GetUserByEmail", mock.Anything, "mixed.case@example.com").Return(nil, nil)
+		svc.users.On("CreateEmailVerification", mock.Anything, mock.Anything, mock.AnythingOfType("*users.EmailVerification")).Return(nil)
+		svc.users.On("CreateUser", mock.Anything, mock.Anything, mock.MatchedBy(func(u *User) bool {
+			return u.Email == "mixed.case@example.com"
+		})).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		user, err := svc.Register(context.Background(), "  Mixed.Case@Example.COM ", password, "New User", "+15550000000", "US", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "mixed.case@example.com", user.Email)
+		svc.users.AssertExpectations(t)
+	})
+
+	t.Run("register detects duplicates regardless of case", func(t *testing.T) {
+		svc := newTestService(t)
+		svc.users.On("GetUserByEmail", mock.Anything, "user@example.com").Return(newTestUser(t, password), nil)
+
+		_, err := svc.Register(context.Background(), "USER@example.com", password, "New User", "+15550000000", "US", "")
+
+		assert.ErrorIs(t, err, ErrUserAlreadyExists)
+	})
+
+	t.Run("login looks up the normalized email", func(t *testing.T) {
+		svc := newTestService(t)
+		user := newTestUser(t, password)
+		svc.users.On("GetUserByEmail", mock.Anything, "user@example.com").Return(user, nil)
+		svc.users.On("ResetFailedLoginAttempts", mock.Anything, user.ID).Return(nil).Maybe()
+		svc.tokens.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.AnythingOfType("*users.RefreshToken")).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.AnythingOfType("*events.OutboxEvent")).Return(nil)
+
+		_, err := svc.Login(context.Background(), " User@Example.com", password, "ua", "127.0.0.1")
+
+		require.NoError(t, err)
+		svc.users.AssertExpectations(t)
+	})
+}
+
+func TestService_UpdateProfile(t *testing.T) {
+	t.Run("saves the validated fields", func(t *testing.T) {
+		svc := newTestService(t)
+		user := newTestUser(t, "correct-password")
+		svc.users.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		svc.users.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(u *User) bool {
+			return u.FullName == "Renamed User" && u.AvatarURL == "https://cdn.example.com/a.png" && u.CountryCode == "IT"
+		})).Return(nil)
+
+		updated, err := svc.UpdateProfile(context.Background(), user.ID, " Renamed User ", "https://cdn.example.com/a.png", "IT")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed User", updated.FullName)
+		svc.users.AssertExpectations(t)
+	})
+
+	invalid := map[string][3]string{
+		"empty name":           {"  ", "", "US"},
+		"lowercase country":    {"User", "", "us"},
+		"three letter country": {"User", "", "USA"},
+		"non-http avatar":      {"User", "javascript:alert(1)", "US"},
+		"unassigned country":   {"User", "", "ZZ"},
+	}
+	for name, fields := range invalid {
+		t.Run("rejects "+name, func(t *testing.T) {
+			svc := newTestService(t)
+
+			_, err := svc.UpdateProfile(context.Background(), uuid.New(), fields[0], fields[1], fields[2])
+
+			assert.ErrorIs(t, err, ErrInvalidInput)
+			svc.users.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("unknown user is not found", func(t *testing.T) {
+		svc := newTestService(t)
+		id := uuid.New()
+		svc.users.On("GetUserByID", mock.Anything, id).Return(nil, ErrUserNotFound)
+
+		_, err := svc.UpdateProfile(context.Background(), id, "User", "", "US")
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestValidateAvatarURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"https", "https://cdn.example.com/avatars/42.png?size=256", false},
+		{"http", "http://example.com/a.jpg", false},
+		{"javascript scheme", "javascript:alert(document.cookie)", true},
+		{"data scheme", "data:image/png;base64,iVBORw0KGgo=", true},
+		{"relative", "/avatars/42.png", true},
+		{"missing host", "https:///a.png", true},
+		{"over length", "https://cdn.example.com/" + strings.Repeat("a", maxAvatarURLLength), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAvatarURL(tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("UpdateProfile rejects invalid avatars as invalid input", func(t *testing.T) {
+		svc := newTestService(t)
+
+		_, err := svc.UpdateProfile(context.Background(), uuid.New(), "User", "javascript:alert(1)", "US")
+
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		svc.users.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_TokenRoles(t *testing.T) {
+	const password = "correct-password"
+
+	t.Run("login issues the user's role and permissions", func(t *testing.T) {
+		svc := newTestService(t)
+		user := newTestUser(t, password)
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+		svc.tokens.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		tokens, err := svc.Login(context.Background(), user.Email, password, "ua", "127.0.0.1")
+		require.NoError(t, err)
+
+		claims, err := svc.signer.ValidateToken(tokens.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, RoleUser, claims.Role)
+		assert.ElementsMatch(t, PermissionsFor(RoleUser), claims.Permissions)
+		assert.Contains(t, claims.Permissions, PermissionPlaceBids)
+		assert.NotContains(t, claims.Permissions, PermissionForceCancelItem)
+	})
+
+	t.Run("refresh reissues the current role", func(t *testing.T) {
+		svc := newTestService(t)
+		user := newTestUser(t, password)
+		user.Role = RoleAdmin
+		stored := &RefreshToken{
+			TokenHash: hashToken("refresh-token"),
+			UserID:    user.ID,
+			ExpiresAt: time.Now().Add(time.Hour),
+			FamilyID:  uuid.New(),
+		}
+		svc.tokens.On("GetRefreshToken", mock.Anything, stored.TokenHash).Return(stored, nil)
+		svc.users.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		svc.tokens.On("ConsumeRefreshToken", mock.Anything, mock.Anything, stored.TokenHash).Return(true, nil)
+		svc.tokens.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		tokens, err := svc.Refresh(context.Background(), "refresh-token", "ua", "127.0.0.1")
+		require.NoError(t, err)
+
+		claims, err := svc.signer.ValidateToken(tokens.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, RoleAdmin, claims.Role)
+		assert.Contains(t, claims.Permissions, PermissionForceCancelItem)
+	})
+
+	t.Run("unknown roles are granted nothing", func(t *testing.T) {
+		assert.Empty(t, PermissionsFor("superuser"))
+	})
+}
+
+func TestService_VerifyToken(t *testing.T) {
+	t.Run("valid token returns its claims", func(t *testing.T) {
+		svc := newTestService(t)
+		userID := uuid.New()
+		pair, err := svc.signer.GenerateTokens(userID, "user@example.com", "Test User", RoleUser, PermissionsFor(RoleUser))
+		require.NoError(t, err)
+
+		claims, err := svc.VerifyToken(context.Background(), pair.AccessToken)
+
+		require.NoError(t, err)
+		assert.Equal(t, userID.String(), claims.Sub)
+		assert.Equal(t, "user@example.com", claims.Email)
+		assert.Equal(t, RoleUser, claims.Role)
+		assert.Equal(t, PermissionsFor(RoleUser), claims.Permissions)
+		assert.Equal(t, pair.AccessExpiry.Unix(), int64(claims.Exp))
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		svc := newTestService(t)
+		expired := jwt.NewWithClaims(jwt.SigningMethodRS256, &auth.Claims{TokenClaims: &authv1.TokenClaims{
+			Sub: uuid.New().String(),
+			Iss: "test-issuer",
+			Iat: float64(time.Now().Add(-time.Hour).Unix()),
+			Exp: float64(time.Now().Add(-time.Minute).Unix()),
+		}})
+		token, err := expired.SignedString(svc.signingKey)
+		require.NoError(t, err)
+
+		_, err = svc.VerifyToken(context.Background(), token)
+
+		assert.ErrorIs(t, err, ErrAccessTokenExpired)
+	})
+
+	t.Run("garbage input", func(t *testing.T) {
+		svc := newTestService(t)
+
+		_, err := svc.VerifyToken(context.Background(), "not.a.jwt")
+
+		assert.ErrorIs(t, err, ErrInvalidAccessToken)
+	})
+
+	t.Run("token signed by another key", func(t *testing.T) {
+		svc := newTestService(t)
+		other := newTestService(t)
+		pair, err := other.signer.GenerateTokens(uuid.New(), "user@example.com", "Test User", RoleUser, nil)
+		require.NoError(t, err)
+
+		_, err = svc.VerifyToken(context.Background(), pair.AccessToken)
+
+		assert.ErrorIs(t, err, ErrInvalidAccessToken)
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		svc := newTestService(t)
+
+		_, err := svc.VerifyToken(context.Background(), "  ")
+
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+}
+
+func TestService_VerifyEmail(t *testing.T) {
+	const token = "verification-token"
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T, user *User, expiresAt time.Time) *testService {
+		svc := newTestService(t)
+		svc.now = func() time.Time { return now }
+		svc.users.On("GetEmailVerification", mock.Anything, hashToken(token)).Return(&EmailVerification{
+			TokenHash: hashToken(token),
+			UserID:    user.ID,
+			ExpiresAt: expiresAt,
+		}, nil)
+		svc.users.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		return svc
+	}
+
+	t.Run("valid token marks the email verified", func(t *testing.T) {
+		user := newTestUser(t, "correct-password")
+		svc := setup(t, user, now.Add(time.Hour))
+		svc.users.On("MarkEmailVerified", mock.Anything, user.ID).Return(nil)
+
+		verified, err := svc.VerifyEmail(context.Background(), token)
+
+		require.NoError(t, err)
+		assert.True(t, verified.EmailVerified)
+		svc.users.AssertExpectations(t)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		user := newTestUser(t, "correct-password")
+		svc := setup(t, user, now.Add(-time.Minute))
+
+		_, err := svc.VerifyEmail(context.Background(), token)
+
+		assert.ErrorIs(t, err, ErrVerificationTokenExpired)
+		svc.users.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything)
+	})
+
+	t.Run("already verified user", func(t *testing.T) {
+		user := newTestUser(t, "correct-password")
+		user.EmailVerified = true
+		svc := setup(t, user, now.Add(-time.Minute))
+
+		_, err := svc.VerifyEmail(context.Background(), token)
+
+		assert.ErrorIs(t, err, ErrEmailAlreadyVerified)
+		svc.users.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		svc := newTestService(t)
+		svc.users.On("GetEmailVerification", mock.Anything, hashToken("unknown")).Return(nil, nil)
+
+		_, err := svc.VerifyEmail(context.Background(), "unknown")
+
+		assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+	})
+
+	t.Run("register emails a token that verifies the new user", func(t *testing.T) {
+		mailer := &MockVerificationMailer{}
+		svc := newTestService(t, WithPasswordHasher(Argon2idHasher{Params: testArgonParams}), WithVerificationMailer(mailer))
+		var stored *EmailVerification
+		var sent string
+		var payload []byte
+		var published pb.UserCreated
+		svc.users.On("GetUserByEmail", mock.Anything, "new@example.com").Return(nil, nil)
+		svc.users.On("CreateUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		svc.users.On("CreateEmailVerification", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(2).(*EmailVerification)
+		}).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			payload = args.Get(2).(*events.OutboxEvent).Payload
+			require.NoError(t, proto.Unmarshal(payload, &published))
+		}).Return(nil)
+		mailer.On("SendVerificationEmail", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			sent = args.String(2)
+		}).Return(nil)
+
+		user, err := svc.Register(context.Background(), "new@example.com", "correct-password", "New User", "+15550000000", "US", "")
+
+		require.NoError(t, err)
+		assert.False(t, user.EmailVerified)
+		require.NotNil(t, stored)
+		assert.Equal(t, user.ID, stored.UserID)
+		require.NotEmpty(t, sent)
+		assert.Equal(t, hashToken(sent), stored.TokenHash)
+		assert.Equal(t, user.ID.String(), published.UserId)
+		assert.NotContains(t, string(payload), sent, "the token must not leave through the event")
+		assert.WithinDuration(t, time.Now().Add(EmailVerificationTTL), stored.ExpiresAt, time.Minute)
+	})
+
+	t.Run("no link is sent for a registration that fails to commit", func(t *testing.T) {
+		mailer := &MockVerificationMailer{}
+		svc := newTestService(t, WithPasswordHasher(Argon2idHasher{Params: testArgonParams}), WithVerificationMailer(mailer))
+		svc.Service.txManager = fakeTxManager{commitErr: errors.New("connection reset")}
+		svc.users.On("GetUserByEmail", mock.Anything, "new@example.com").Return(nil, nil)
+		svc.users.On("CreateUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		svc.users.On("CreateEmailVerification", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		_, err := svc.Register(context.Background(), "new@example.com", "correct-password", "New User", "+15550000000", "US", "")
+
+		require.Error(t, err)
+		mailer.AssertNotCalled(t, "SendVerificationEmail", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("registration is kept when the link can't be sent", func(t *testing.T) {
+		mailer := &MockVerificationMailer{}
+		svc := newTestService(t, WithPasswordHasher(Argon2idHasher{Params: testArgonParams}), WithVerificationMailer(mailer),
+			WithLogger(slog.New(slog.DiscardHandler)))
+		svc.users.On("GetUserByEmail", mock.Anything, "new@example.com").Return(nil, nil)
+		svc.users.On("CreateUser", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		svc.users.On("CreateEmailVerification", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		svc.outbox.On("CreateEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mailer.On("SendVerificationEmail", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("smtp unavailable"))
+
+		user, err := svc.Register(context.Background(), "new@example.com", "correct-password", "New User", "+15550000000", "US", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", user.Email)
+		mailer.AssertExpectations(t)
+	})
+}
+
+func TestService_DeleteAccount(t *testing.T) {
+	const password = "correct-password"
+
+	t.Run("soft deletes and revokes every session", func(t *testing.T) {
+		svc := newTestService(t)
+		userID := uuid.New()
+		svc.users.On("SoftDeleteUser", mock.Anything, mock.Anything, userID).Return(true, nil)
+		svc.tokens.On("RevokeAllUserTokens", mock.Anything, mock.Anything, userID).Return(nil)
+
+		require.NoError(t, svc.DeleteAccount(context.Background(), userID))
+		svc.users.AssertExpectations(t)
+		svc.tokens.AssertExpectations(t)
+	})
+
+	t.Run("already deleted account is not found", func(t *testing.T) {
+		svc := newTestService(t)
+		userID := uuid.New()
+		svc.users.On("SoftDeleteUser", mock.Anything, mock.Anything, userID).Return(false, nil)
+
+		err := svc.DeleteAccount(context.Background(), userID)
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		svc.tokens.AssertNotCalled(t, "RevokeAllUserTokens", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	deletedUser := func(t *testing.T) *User {
+		user := newTestUser(t, password)
+		deletedAt := time.Now().Add(-time.Hour)
+		user.DeletedAt = &deletedAt
+		return user
+	}
+
+	t.Run("deleted user cannot log in", func(t *testing.T) {
+		svc := newTestService(t)
+		user := deletedUser(t)
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+
+		_, err := svc.Login(context.Background(), user.Email, password, "ua", "127.0.0.1")
+
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		svc.tokens.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("deleted user has no profile", func(t *testing.T) {
+		svc := newTestService(t)
+		user := deletedUser(t)
+		svc.users.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+
+		_, err := svc.GetProfile(context.Background(), user.ID)
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("deleted user's email cannot be registered again", func(t *testing.T) {
+		svc := newTestService(t)
+		user := deletedUser(t)
+		svc.users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+
+		_, err := svc.Register(context.Background(), user.Email, password, "New User", "+15550000000", "US", "")
+
+		assert.ErrorIs(t, err, ErrUserAlreadyExists)
+		svc.users.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_GetProfile(t *testing.T) {
+	t.Run("missing user is not found", func(t *testing.T) {
+		svc := newTestService(t)
+		id := uuid.New()
+		svc.users.On("GetUserByID", mock.Anything, id).Return(nil, ErrUserNotFound)
+
+		_, err := svc.GetProfile(context.Background(), id)
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		assert.NotErrorIs(t, err, pgx.ErrNoRows)
+	})
+
+	t.Run("repository failure is not reported as not found", func(t *testing.T) {
+		svc := newTestService(t)
+		id := uuid.New()
+		svc.users.On("GetUserByID", mock.Anything, id).Return(nil, assert.AnError)
+
+		_, err := svc.GetProfile(context.Background(), id)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NotErrorIs(t, err, ErrUserNotFound)
+	})
+}
@@ -0,0 +1,61 @@
+package users
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Defaults for RunTokenPruner
+const (
+	DefaultTokenPruneInterval  = time.Hour
+	DefaultTokenPruneBatchSize = 1000
+)
+
+// TokenPruner deletes refresh tokens that can no longer be used
+type TokenPruner interface {
+	// DeleteExpiredRefreshTokens deletes up to limit tokens of families whose every token
+	// expired before cutoff, and returns how many it deleted. Tokens of a family that is
+	// still alive are kept, so replaying one of them still revokes the family.
+	DeleteExpiredRefreshTokens(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+}
+
+// PruneExpiredTokens deletes expired refresh tokens in batches of batchSize, each in its
+// own short statement so live logins and refreshes are never blocked for long. It returns
+// how many tokens it deleted.
+func PruneExpiredTokens(ctx context.Context, pruner TokenPruner, batchSize int) (int64, error) {
+	cutoff := time.Now()
+	var total int64
+	for {
+		deleted, err := pruner.DeleteExpiredRefreshTokens(ctx, cutoff, batchSize)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		if deleted < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// RunTokenPruner prunes expired refresh tokens every interval until ctx is cancelled
+func RunTokenPruner(ctx context.Context, pruner TokenPruner, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := PruneExpiredTokens(ctx, pruner, DefaultTokenPruneBatchSize)
+			if err != nil {
+				logger.Error("Failed to prune refresh tokens", "error", err, "deleted", deleted)
+				continue
+			}
+			if deleted > 0 {
+				logger.Info("Pruned expired refresh tokens", "count", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Every refresh token belongs to a family: the chain of tokens rotated from a single login.
+-- consumed_at marks a token that has already been exchanged; presenting it again revokes the family.
+ALTER TABLE refresh_tokens
+    ADD COLUMN family_id UUID,
+    ADD COLUMN consumed_at TIMESTAMPTZ;
+
+-- Existing tokens each start their own family
+UPDATE refresh_tokens SET family_id = gen_random_uuid() WHERE family_id IS NULL;
+
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS consumed_at,
+    DROP COLUMN IF EXISTS family_id;
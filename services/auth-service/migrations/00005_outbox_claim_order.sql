@@ -0,0 +1,18 @@
+-- +goose Up
+-- seq records the order events were written in; relays publish an aggregate's events in seq order
+ALTER TABLE outbox_events
+    ADD COLUMN seq BIGINT GENERATED ALWAYS AS IDENTITY,
+    ADD COLUMN aggregate_id UUID;
+
+CREATE INDEX idx_outbox_events_pending_seq ON outbox_events (seq) WHERE status = 'pending';
+CREATE INDEX idx_outbox_events_pending_aggregate ON outbox_events (aggregate_id, seq) WHERE status = 'pending';
+CREATE INDEX idx_outbox_events_published_processed_at ON outbox_events (processed_at) WHERE status = 'published';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_events_published_processed_at;
+DROP INDEX IF EXISTS idx_outbox_events_pending_aggregate;
+DROP INDEX IF EXISTS idx_outbox_events_pending_seq;
+
+ALTER TABLE outbox_events
+    DROP COLUMN IF EXISTS aggregate_id,
+    DROP COLUMN IF EXISTS seq;
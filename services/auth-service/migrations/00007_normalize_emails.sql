@@ -0,0 +1,14 @@
+-- +goose Up
+-- Emails are now stored lowercased and trimmed. Accounts whose normalized email is already
+-- taken by another account are left as they are, to be merged by hand.
+UPDATE users u
+SET email = lower(trim(u.email))
+WHERE u.email <> lower(trim(u.email))
+AND NOT EXISTS (
+    SELECT 1 FROM users other
+    WHERE other.id <> u.id AND lower(trim(other.email)) = lower(trim(u.email))
+);
+
+-- +goose Down
+-- Original casing is not kept, so there is nothing to restore
+SELECT 1;
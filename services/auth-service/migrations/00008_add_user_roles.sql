@@ -0,0 +1,8 @@
+-- +goose Up
+-- Permissions are derived from the role when tokens are issued, see users.PermissionsFor
+ALTER TABLE users
+    ADD COLUMN role TEXT NOT NULL DEFAULT 'user';
+
+-- +goose Down
+ALTER TABLE users
+    DROP COLUMN IF EXISTS role;
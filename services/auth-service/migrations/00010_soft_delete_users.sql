@@ -0,0 +1,9 @@
+-- +goose Up
+-- Deleted accounts keep their row, so bids and items referencing them stay intact.
+-- Their email stays taken: it cannot be registered again.
+ALTER TABLE users
+    ADD COLUMN deleted_at TIMESTAMPTZ; -- NULL while the account is active
+
+-- +goose Down
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deleted_at;
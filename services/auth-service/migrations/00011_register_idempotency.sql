@@ -0,0 +1,15 @@
+-- +goose Up
+-- A client-chosen key lets a retried Register return the account it already created.
+-- registration_hash fingerprints the request's non-secret fields to detect a key reused for another payload.
+ALTER TABLE users
+    ADD COLUMN idempotency_key TEXT,
+    ADD COLUMN registration_hash BYTEA;
+
+CREATE UNIQUE INDEX idx_users_idempotency_key ON users(idempotency_key) WHERE idempotency_key IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_idempotency_key;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS registration_hash,
+    DROP COLUMN IF EXISTS idempotency_key;
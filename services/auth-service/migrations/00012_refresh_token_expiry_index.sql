@@ -0,0 +1,6 @@
+-- +goose Up
+-- Lets the token pruner find expired refresh tokens without scanning the table
+CREATE INDEX idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_refresh_tokens_expires_at;
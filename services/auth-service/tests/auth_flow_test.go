@@ -5,11 +5,16 @@ import (
 	"testing"
 
 	"connectrpc.com/connect"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 
+	"github.com/floroz/gavel/pkg/auth"
 	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
 	"github.com/floroz/gavel/pkg/testhelpers"
+	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
 )
 
 func TestAuth_Flows(t *testing.T) {
@@ -170,11 +175,75 @@ func TestAuth_Flows(t *testing.T) {
 		assert.NotEmpty(t, res.Msg.AccessToken)
 		assert.NotEmpty(t, res.Msg.RefreshToken)
 
+		// New accounts get the default role and its permissions
+		claims := &auth.Claims{TokenClaims: &authv1.TokenClaims{}}
+		_, _, err = jwt.NewParser().ParseUnverified(res.Msg.AccessToken, claims)
+		require.NoError(t, err)
+		assert.Equal(t, users.RoleUser, claims.Role)
+		assert.ElementsMatch(t, users.PermissionsFor(users.RoleUser), claims.Permissions)
+
 		// Verify Refresh Token in DB
 		user := verifyUserExists(t, pool, email)
 		require.NotNil(t, user)
 		exists := verifyTokenExists(t, pool, user.ID)
 		assert.True(t, exists, "Refresh token should be saved")
+
+		// Verify Outbox Event
+		assert.True(t, verifyLoginEventExists(t, pool, user.ID), "UserLoggedIn event should be in outbox")
+	})
+
+	t.Run("Login_EmailIsCaseInsensitive", func(t *testing.T) {
+		password := "casepassword"
+		_, err := client.Register(context.Background(), connect.NewRequest(&authv1.RegisterRequest{
+			Email:       " Mixed.Case@Example.com ",
+			Password:    password,
+			FullName:    "Mixed Case",
+			PhoneNumber: "+15557777770",
+			CountryCode: "US",
+		}))
+		require.NoError(t, err)
+
+		// Stored normalized
+		user := verifyUserExists(t, pool, "mixed.case@example.com")
+		require.NotNil(t, user)
+
+		// The same address in another case is neither a new account nor a failed login
+		_, err = client.Register(context.Background(), connect.NewRequest(&authv1.RegisterRequest{
+			Email:       "mixed.case@EXAMPLE.com",
+			Password:    password,
+			FullName:    "Mixed Case",
+			PhoneNumber: "+15557777771",
+			CountryCode: "US",
+		}))
+		assert.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+
+		res, err := client.Login(context.Background(), connect.NewRequest(&authv1.LoginRequest{
+			Email:    "MIXED.CASE@example.COM",
+			Password: password,
+		}))
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.Msg.AccessToken)
+	})
+
+	t.Run("Login_LegacyBcryptHash", func(t *testing.T) {
+		// Users migrated from the bcrypt era keep their hash until they next change password
+		email := "legacy@example.com"
+		password := "legacypassword"
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		require.NoError(t, err)
+
+		_, err = pool.Exec(context.Background(), `
+			INSERT INTO users (id, email, password_hash, full_name, phone_number, country_code)
+			VALUES ($1, $2, $3, 'Legacy User', '+15554444444', 'US')
+		`, uuid.New(), email, string(hash))
+		require.NoError(t, err)
+
+		res, err := client.Login(context.Background(), connect.NewRequest(&authv1.LoginRequest{
+			Email:    email,
+			Password: password,
+		}))
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.Msg.AccessToken)
 	})
 
 	t.Run("Login_InvalidCredentials", func(t *testing.T) {
@@ -197,5 +266,92 @@ func TestAuth_Flows(t *testing.T) {
 		_, err = client.Login(context.Background(), loginReq)
 		require.Error(t, err)
 		assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+		// No login event for failed attempts
+		user := verifyUserExists(t, pool, email)
+		require.NotNil(t, user)
+		assert.False(t, verifyLoginEventExists(t, pool, user.ID), "UserLoggedIn event should not be in outbox")
+	})
+
+	t.Run("Refresh_ReuseRevokesFamily", func(t *testing.T) {
+		email := "reuse@example.com"
+		password := "securepass"
+		_, err := client.Register(context.Background(), connect.NewRequest(&authv1.RegisterRequest{
+			Email:       email,
+			Password:    password,
+			FullName:    "Reuse User",
+			PhoneNumber: "+15557777777",
+			CountryCode: "US",
+		}))
+		require.NoError(t, err)
+
+		loginRes, err := client.Login(context.Background(), connect.NewRequest(&authv1.LoginRequest{
+			Email:    email,
+			Password: password,
+		}))
+		require.NoError(t, err)
+		original := loginRes.Msg.RefreshToken
+
+		// First use rotates the token
+		rotated, err := client.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{
+			RefreshToken: original,
+		}))
+		require.NoError(t, err)
+		require.NotEqual(t, original, rotated.Msg.RefreshToken)
+
+		// Replaying the original token is treated as theft
+		_, err = client.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{
+			RefreshToken: original,
+		}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+		// ...and the descendant token issued by the rotation is no longer usable either
+		_, err = client.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{
+			RefreshToken: rotated.Msg.RefreshToken,
+		}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+		user := verifyUserExists(t, pool, email)
+		require.NotNil(t, user)
+		var active int
+		err = pool.QueryRow(context.Background(),
+			"SELECT COUNT(*) FROM refresh_tokens WHERE user_id = $1 AND revoked = false", user.ID).Scan(&active)
+		require.NoError(t, err)
+		assert.Zero(t, active, "all tokens in the family should be revoked")
+	})
+
+	t.Run("Refresh_OtherSessionsSurviveReuse", func(t *testing.T) {
+		email := "reuse-sessions@example.com"
+		password := "securepass"
+		_, err := client.Register(context.Background(), connect.NewRequest(&authv1.RegisterRequest{
+			Email:       email,
+			Password:    password,
+			FullName:    "Reuse Sessions",
+			PhoneNumber: "+15556666666",
+			CountryCode: "US",
+		}))
+		require.NoError(t, err)
+
+		login := func() string {
+			res, err := client.Login(context.Background(), connect.NewRequest(&authv1.LoginRequest{
+				Email:    email,
+				Password: password,
+			}))
+			require.NoError(t, err)
+			return res.Msg.RefreshToken
+		}
+		compromised := login()
+		otherDevice := login()
+
+		_, err = client.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{RefreshToken: compromised}))
+		require.NoError(t, err)
+		_, err = client.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{RefreshToken: compromised}))
+		require.Error(t, err)
+
+		// A separate login is a separate family and keeps working
+		_, err = client.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{RefreshToken: otherDevice}))
+		require.NoError(t, err)
 	})
 }
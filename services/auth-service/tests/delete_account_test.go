@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
+	"github.com/floroz/gavel/pkg/testhelpers"
+)
+
+func TestAuth_DeleteAccount(t *testing.T) {
+	testDB := testhelpers.NewTestDatabase(t, "../migrations")
+	defer testDB.Close()
+
+	client, pool := setupAuthApp(t, testDB.Pool)
+
+	t.Run("RequiresAuth", func(t *testing.T) {
+		_, err := client.DeleteAccount(context.Background(), connect.NewRequest(&authv1.DeleteAccountRequest{}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+	})
+
+	const email = "delete-me@example.com"
+	login := registerAndLogin(t, client, email, "Laptop/1.0", "10.0.0.1")
+	user := verifyUserExists(t, pool, email)
+	require.NotNil(t, user)
+
+	_, err := client.DeleteAccount(context.Background(), authenticated(&authv1.DeleteAccountRequest{}, login.AccessToken))
+	require.NoError(t, err)
+
+	t.Run("CannotLogIn", func(t *testing.T) {
+		_, err := client.Login(context.Background(), connect.NewRequest(&authv1.LoginRequest{
+			Email:    email,
+			Password: "securepass",
+		}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+	})
+
+	t.Run("SessionsAreRevoked", func(t *testing.T) {
+		assert.False(t, verifyTokenExists(t, pool, user.ID), "refresh tokens should be revoked")
+
+		_, err := client.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{
+			RefreshToken: login.RefreshToken,
+		}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+	})
+
+	t.Run("ProfileIsNotFound", func(t *testing.T) {
+		_, err := client.GetProfile(context.Background(), connect.NewRequest(&authv1.GetProfileRequest{
+			UserId: user.ID.String(),
+		}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+
+	t.Run("EmailCannotBeRegisteredAgain", func(t *testing.T) {
+		_, err := client.Register(context.Background(), connect.NewRequest(&authv1.RegisterRequest{
+			Email:       email,
+			Password:    "securepass",
+			FullName:    "Returning User",
+			PhoneNumber: "+15550000000",
+			CountryCode: "US",
+		}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+	})
+
+	t.Run("RowIsKept", func(t *testing.T) {
+		var deleted bool
+		err := pool.QueryRow(context.Background(), `SELECT deleted_at IS NOT NULL FROM users WHERE id = $1`, user.ID).Scan(&deleted)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+	})
+}
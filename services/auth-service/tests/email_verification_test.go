@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/floroz/gavel/pkg/proto"
+	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
+	"github.com/floroz/gavel/pkg/testhelpers"
+	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
+)
+
+// recordingMailer keeps the verification token sent to each user
+type recordingMailer struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func (m *recordingMailer) SendVerificationEmail(_ context.Context, user *users.User, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tokens == nil {
+		m.tokens = make(map[string]string)
+	}
+	m.tokens[user.ID.String()] = token
+	return nil
+}
+
+// tokenFor returns the token emailed to the user
+func (m *recordingMailer) tokenFor(t *testing.T, userID string) string {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token := m.tokens[userID]
+	require.NotEmpty(t, token, "no verification email sent to %s", userID)
+	return token
+}
+
+func TestAuth_VerifyEmail(t *testing.T) {
+	testDB := testhelpers.NewTestDatabase(t, "../migrations")
+	defer testDB.Close()
+
+	mailer := &recordingMailer{}
+	client, pool := setupAuthApp(t, testDB.Pool, users.WithVerificationMailer(mailer))
+
+	register := func(t *testing.T, email string) string {
+		t.Helper()
+		res, err := client.Register(context.Background(), connect.NewRequest(&authv1.RegisterRequest{
+			Email:       email,
+			Password:    "securepass",
+			FullName:    "Verify User",
+			PhoneNumber: "+15550000000",
+			CountryCode: "US",
+		}))
+		require.NoError(t, err)
+		return res.Msg.UserId
+	}
+
+	t.Run("ValidToken", func(t *testing.T) {
+		userID := register(t, "verify-valid@example.com")
+
+		profile, err := client.GetProfile(context.Background(), connect.NewRequest(&authv1.GetProfileRequest{UserId: userID}))
+		require.NoError(t, err)
+		assert.False(t, profile.Msg.EmailVerified, "new accounts start unverified")
+
+		token := mailer.tokenFor(t, userID)
+		var payload []byte
+		err = pool.QueryRow(context.Background(),
+			`SELECT payload FROM outbox_events WHERE event_type = 'user.created' AND aggregate_id = $1`,
+			uuid.MustParse(userID),
+		).Scan(&payload)
+		require.NoError(t, err)
+		var event pb.UserCreated
+		require.NoError(t, proto.Unmarshal(payload, &event))
+		assert.NotContains(t, string(payload), token, "the token must not be persisted in the outbox")
+
+		res, err := client.VerifyEmail(context.Background(), connect.NewRequest(&authv1.VerifyEmailRequest{
+			Token: token,
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, userID, res.Msg.UserId)
+
+		profile, err = client.GetProfile(context.Background(), connect.NewRequest(&authv1.GetProfileRequest{UserId: userID}))
+		require.NoError(t, err)
+		assert.True(t, profile.Msg.EmailVerified)
+	})
+
+	t.Run("AlreadyVerified", func(t *testing.T) {
+		userID := register(t, "verify-twice@example.com")
+		token := mailer.tokenFor(t, userID)
+
+		_, err := client.VerifyEmail(context.Background(), connect.NewRequest(&authv1.VerifyEmailRequest{Token: token}))
+		require.NoError(t, err)
+
+		_, err = client.VerifyEmail(context.Background(), connect.NewRequest(&authv1.VerifyEmailRequest{Token: token}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+	})
+
+	t.Run("ExpiredToken", func(t *testing.T) {
+		userID := register(t, "verify-expired@example.com")
+		token := mailer.tokenFor(t, userID)
+		_, err := pool.Exec(context.Background(),
+			`UPDATE email_verification_tokens SET expires_at = NOW() - INTERVAL '1 minute' WHERE user_id = $1`,
+			uuid.MustParse(userID),
+		)
+		require.NoError(t, err)
+
+		_, err = client.VerifyEmail(context.Background(), connect.NewRequest(&authv1.VerifyEmailRequest{Token: token}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
+	})
+
+	t.Run("UnknownToken", func(t *testing.T) {
+		_, err := client.VerifyEmail(context.Background(), connect.NewRequest(&authv1.VerifyEmailRequest{Token: "not-a-real-token"}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
+	"github.com/floroz/gavel/pkg/testhelpers"
+	infradb "github.com/floroz/gavel/services/auth-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
+)
+
+func TestAuth_UpdateProfile(t *testing.T) {
+	testDB := testhelpers.NewTestDatabase(t, "../migrations")
+	defer testDB.Close()
+
+	client, _ := setupAuthApp(t, testDB.Pool)
+
+	t.Run("RequiresAuth", func(t *testing.T) {
+		_, err := client.UpdateProfile(context.Background(), connect.NewRequest(&authv1.UpdateProfileRequest{
+			FullName:    "Anonymous",
+			CountryCode: "US",
+		}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+	})
+
+	t.Run("UpdatesOwnProfile", func(t *testing.T) {
+		login := registerAndLogin(t, client, "profile-update@example.com", "Laptop/1.0", "10.0.0.1")
+
+		res, err := client.UpdateProfile(context.Background(), authenticated(&authv1.UpdateProfileRequest{
+			FullName:    "Updated Name",
+			AvatarUrl:   "https://cdn.example.com/avatar.png",
+			CountryCode: "DE",
+		}, login.AccessToken))
+		require.NoError(t, err)
+		assert.Equal(t, "Updated Name", res.Msg.FullName)
+		assert.Equal(t, "https://cdn.example.com/avatar.png", res.Msg.AvatarUrl)
+		assert.Equal(t, "DE", res.Msg.CountryCode)
+		assert.Equal(t, "profile-update@example.com", res.Msg.Email)
+
+		profile, err := client.GetProfile(context.Background(), authenticated(&authv1.GetProfileRequest{
+			UserId: res.Msg.Id,
+		}, login.AccessToken))
+		require.NoError(t, err)
+		assert.Equal(t, "Updated Name", profile.Msg.FullName)
+		assert.Equal(t, "https://cdn.example.com/avatar.png", profile.Msg.AvatarUrl)
+		assert.Equal(t, "DE", profile.Msg.CountryCode)
+	})
+
+	t.Run("InvalidCountryCode", func(t *testing.T) {
+		login := registerAndLogin(t, client, "profile-invalid@example.com", "Laptop/1.0", "10.0.0.1")
+
+		_, err := client.UpdateProfile(context.Background(), authenticated(&authv1.UpdateProfileRequest{
+			FullName:    "Updated Name",
+			CountryCode: "Germany",
+		}, login.AccessToken))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+}
+
+func TestAuth_GetProfileNotFound(t *testing.T) {
+	testDB := testhelpers.NewTestDatabase(t, "../migrations")
+	defer testDB.Close()
+
+	client, pool := setupAuthApp(t, testDB.Pool)
+	missingID := uuid.New()
+
+	t.Run("RepositoryReturnsDomainError", func(t *testing.T) {
+		_, err := infradb.NewPostgresUserRepository(pool).GetUserByID(context.Background(), missingID)
+		assert.ErrorIs(t, err, users.ErrUserNotFound)
+		assert.NotErrorIs(t, err, pgx.ErrNoRows)
+	})
+
+	t.Run("HandlerReturnsNotFound", func(t *testing.T) {
+		_, err := client.GetProfile(context.Background(), connect.NewRequest(&authv1.GetProfileRequest{
+			UserId: missingID.String(),
+		}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
+	"github.com/floroz/gavel/pkg/testhelpers"
+	infradb "github.com/floroz/gavel/services/auth-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
+)
+
+func TestAuth_RefreshTokenHashedAtRest(t *testing.T) {
+	testDB := testhelpers.NewTestDatabase(t, "../migrations")
+	defer testDB.Close()
+
+	ctx := context.Background()
+	client, pool := setupAuthApp(t, testDB.Pool)
+	login := registerAndLogin(t, client, "hashed-refresh@example.com", "Laptop/1.0", "10.0.0.1")
+
+	// storedHashes returns the token_hash of every refresh token the user has
+	storedHashes := func(t *testing.T) [][]byte {
+		t.Helper()
+		rows, err := pool.Query(ctx, `
+			SELECT rt.token_hash FROM refresh_tokens rt
+			JOIN users u ON u.id = rt.user_id
+			WHERE u.email = $1
+			ORDER BY rt.created_at`, "hashed-refresh@example.com")
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var hashes [][]byte
+		for rows.Next() {
+			var hash []byte
+			require.NoError(t, rows.Scan(&hash))
+			hashes = append(hashes, hash)
+		}
+		require.NoError(t, rows.Err())
+		return hashes
+	}
+
+	t.Run("Only the SHA-256 of the token is stored", func(t *testing.T) {
+		hashes := storedHashes(t)
+		require.Len(t, hashes, 1)
+
+		want := sha256.Sum256([]byte(login.RefreshToken))
+		assert.Equal(t, want[:], hashes[0])
+		assert.NotContains(t, string(hashes[0]), login.RefreshToken)
+	})
+
+	t.Run("Refresh finds the token by its hash", func(t *testing.T) {
+		res, err := client.Refresh(ctx, connect.NewRequest(&authv1.RefreshRequest{
+			RefreshToken: login.RefreshToken,
+		}))
+		require.NoError(t, err)
+		assert.NotEqual(t, login.RefreshToken, res.Msg.RefreshToken)
+
+		rotated := sha256.Sum256([]byte(res.Msg.RefreshToken))
+		assert.Contains(t, storedHashes(t), rotated[:])
+	})
+}
+
+func TestAuth_PruneExpiredTokens(t *testing.T) {
+	testDB := testhelpers.NewTestDatabase(t, "../migrations")
+	defer testDB.Close()
+
+	ctx := context.Background()
+	client, pool := setupAuthApp(t, testDB.Pool)
+	registerAndLogin(t, client, "prune@example.com", "Laptop/1.0", "10.0.0.1")
+
+	var userID uuid.UUID
+	require.NoError(t, pool.QueryRow(ctx, `SELECT id FROM users WHERE email = $1`, "prune@example.com").Scan(&userID))
+
+	now := time.Now()
+	insertToken := func(t *testing.T, familyID uuid.UUID, expiresAt time.Time) []byte {
+		t.Helper()
+		hash := sha256.Sum256([]byte(uuid.NewString()))
+		_, err := pool.Exec(ctx, `
+			INSERT INTO refresh_tokens (token_hash, user_id, expires_at, created_at, family_id)
+			VALUES ($1, $2, $3, $4, $5)`, hash[:], userID, expiresAt, expiresAt.Add(-time.Hour), familyID)
+		require.NoError(t, err)
+		return hash[:]
+	}
+	tokenExists := func(t *testing.T, hash []byte) bool {
+		t.Helper()
+		var exists bool
+		require.NoError(t, pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM refresh_tokens WHERE token_hash = $1)`, hash).Scan(&exists))
+		return exists
+	}
+
+	// Three expired sessions, more than one batch
+	var expired [][]byte
+	for range 3 {
+		expired = append(expired, insertToken(t, uuid.New(), now.Add(-48*time.Hour)))
+	}
+	valid := insertToken(t, uuid.New(), now.Add(24*time.Hour))
+	// A rotated session: its first token expired but the family is still alive
+	liveFamily := uuid.New()
+	rotated := insertToken(t, liveFamily, now.Add(-time.Hour))
+	current := insertToken(t, liveFamily, now.Add(24*time.Hour))
+
+	deleted, err := users.PruneExpiredTokens(ctx, infradb.NewPostgresTokenRepository(pool), 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(expired)), deleted)
+
+	for _, hash := range expired {
+		assert.False(t, tokenExists(t, hash), "expired token should be deleted")
+	}
+	assert.True(t, tokenExists(t, valid), "valid token should be kept")
+	assert.True(t, tokenExists(t, rotated), "expired token of a live session should be kept for reuse detection")
+	assert.True(t, tokenExists(t, current), "current token of a live session should be kept")
+
+	t.Run("Login still works after pruning", func(t *testing.T) {
+		login := registerAndLogin(t, client, "prune@example.com", "Laptop/1.0", "10.0.0.1")
+		assert.NotEmpty(t, login.RefreshToken)
+	})
+}
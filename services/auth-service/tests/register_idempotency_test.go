@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
+	"github.com/floroz/gavel/pkg/testhelpers"
+)
+
+func TestAuth_RegisterIdempotency(t *testing.T) {
+	testDB := testhelpers.NewTestDatabase(t, "../migrations")
+	defer testDB.Close()
+
+	client, pool := setupAuthApp(t, testDB.Pool)
+
+	newRequest := func(email, key string) *authv1.RegisterRequest {
+		return &authv1.RegisterRequest{
+			Email:          email,
+			Password:       "securepass",
+			FullName:       "Retry User",
+			PhoneNumber:    "+15550000000",
+			CountryCode:    "US",
+			IdempotencyKey: key,
+		}
+	}
+
+	countUserCreated := func(t *testing.T, userID string) int {
+		t.Helper()
+		var count int
+		err := pool.QueryRow(context.Background(),
+			`SELECT COUNT(*) FROM outbox_events WHERE event_type = 'user.created' AND aggregate_id = $1`,
+			uuid.MustParse(userID),
+		).Scan(&count)
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("IdenticalReplay", func(t *testing.T) {
+		req := newRequest("retry@example.com", uuid.NewString())
+
+		first, err := client.Register(context.Background(), connect.NewRequest(req))
+		require.NoError(t, err)
+
+		replay, err := client.Register(context.Background(), connect.NewRequest(req))
+		require.NoError(t, err)
+		assert.Equal(t, first.Msg.UserId, replay.Msg.UserId)
+		assert.Equal(t, 1, countUserCreated(t, first.Msg.UserId), "a replay must not create the user again")
+	})
+
+	t.Run("ConflictingReplay", func(t *testing.T) {
+		key := uuid.NewString()
+		_, err := client.Register(context.Background(), connect.NewRequest(newRequest("first-payload@example.com", key)))
+		require.NoError(t, err)
+
+		_, err = client.Register(context.Background(), connect.NewRequest(newRequest("second-payload@example.com", key)))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+
+		// The key did not register the second email either
+		_, err = client.Register(context.Background(), connect.NewRequest(newRequest("second-payload@example.com", "")))
+		require.NoError(t, err)
+	})
+
+	t.Run("ConcurrentReplays", func(t *testing.T) {
+		req := newRequest("concurrent@example.com", uuid.NewString())
+
+		const attempts = 8
+		userIDs := make([]string, attempts)
+		errs := make([]error, attempts)
+		var wg sync.WaitGroup
+		for i := range attempts {
+			wg.Go(func() {
+				resp, err := client.Register(context.Background(), connect.NewRequest(req))
+				errs[i] = err
+				if err == nil {
+					userIDs[i] = resp.Msg.UserId
+				}
+			})
+		}
+		wg.Wait()
+
+		for i := range attempts {
+			require.NoError(t, errs[i])
+			assert.Equal(t, userIDs[0], userIDs[i])
+		}
+		assert.Equal(t, 1, countUserCreated(t, userIDs[0]))
+	})
+
+	t.Run("DeletedAccountIsNotReplayed", func(t *testing.T) {
+		req := newRequest("deleted-retry@example.com", uuid.NewString())
+		first, err := client.Register(context.Background(), connect.NewRequest(req))
+		require.NoError(t, err)
+
+		_, err = pool.Exec(context.Background(), `UPDATE users SET deleted_at = NOW() WHERE id = $1`, uuid.MustParse(first.Msg.UserId))
+		require.NoError(t, err)
+
+		_, err = client.Register(context.Background(), connect.NewRequest(req))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+	})
+
+	t.Run("ExpiredKeyIsReleased", func(t *testing.T) {
+		key := uuid.NewString()
+		first, err := client.Register(context.Background(), connect.NewRequest(newRequest("expired-key@example.com", key)))
+		require.NoError(t, err)
+
+		_, err = pool.Exec(context.Background(), `UPDATE users SET created_at = NOW() - INTERVAL '25 hours' WHERE id = $1`, uuid.MustParse(first.Msg.UserId))
+		require.NoError(t, err)
+
+		// Another registration can use the key once it has expired
+		second, err := client.Register(context.Background(), connect.NewRequest(newRequest("reused-key@example.com", key)))
+		require.NoError(t, err)
+		assert.NotEqual(t, first.Msg.UserId, second.Msg.UserId)
+	})
+
+	t.Run("WithoutKeyRetryStillConflicts", func(t *testing.T) {
+		req := newRequest("no-key@example.com", "")
+		_, err := client.Register(context.Background(), connect.NewRequest(req))
+		require.NoError(t, err)
+
+		_, err = client.Register(context.Background(), connect.NewRequest(req))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+	})
+}
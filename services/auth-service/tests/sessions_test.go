@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
+	"github.com/floroz/gavel/pkg/proto/auth/v1/authv1connect"
+	"github.com/floroz/gavel/pkg/testhelpers"
+	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
+)
+
+// registerAndLogin creates a user and logs them in from the given device
+func registerAndLogin(t *testing.T, client authv1connect.AuthServiceClient, email, userAgent, ip string) *authv1.LoginResponse {
+	t.Helper()
+	const password = "securepass"
+
+	_, err := client.Register(context.Background(), connect.NewRequest(&authv1.RegisterRequest{
+		Email:       email,
+		Password:    password,
+		FullName:    "Session User",
+		PhoneNumber: "+15550000000",
+		CountryCode: "US",
+	}))
+	if connect.CodeOf(err) != connect.CodeAlreadyExists {
+		require.NoError(t, err)
+	}
+
+	res, err := client.Login(context.Background(), connect.NewRequest(&authv1.LoginRequest{
+		Email:     email,
+		Password:  password,
+		UserAgent: userAgent,
+		IpAddress: ip,
+	}))
+	require.NoError(t, err)
+	return res.Msg
+}
+
+func authenticated[T any](msg *T, accessToken string) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	req.Header().Set("Authorization", "Bearer "+accessToken)
+	return req
+}
+
+func TestAuth_Sessions(t *testing.T) {
+	testDB := testhelpers.NewTestDatabase(t, "../migrations")
+	defer testDB.Close()
+
+	client, _ := setupAuthApp(t, testDB.Pool)
+
+	t.Run("ListSessions_RequiresAuth", func(t *testing.T) {
+		_, err := client.ListSessions(context.Background(), connect.NewRequest(&authv1.ListSessionsRequest{}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+	})
+
+	t.Run("ListSessions_ReturnsDeviceMetadata", func(t *testing.T) {
+		email := "sessions-list@example.com"
+		laptop := registerAndLogin(t, client, email, "Laptop/1.0", "10.0.0.1")
+		registerAndLogin(t, client, email, "Phone/2.0", "10.0.0.2")
+
+		res, err := client.ListSessions(context.Background(), authenticated(&authv1.ListSessionsRequest{}, laptop.AccessToken))
+		require.NoError(t, err)
+		require.Len(t, res.Msg.Sessions, 2)
+
+		byAgent := map[string]*authv1.Session{}
+		for _, s := range res.Msg.Sessions {
+			byAgent[s.UserAgent] = s
+			assert.NotEmpty(t, s.Id)
+			assert.NotNil(t, s.CreatedAt)
+			assert.NotNil(t, s.LastUsedAt)
+			assert.NotNil(t, s.ExpiresAt)
+		}
+		require.Contains(t, byAgent, "Laptop/1.0")
+		require.Contains(t, byAgent, "Phone/2.0")
+		assert.Equal(t, "10.0.0.1", byAgent["Laptop/1.0"].IpAddress)
+		assert.Equal(t, "10.0.0.2", byAgent["Phone/2.0"].IpAddress)
+	})
+
+	t.Run("ListSessions_RefreshKeepsSessionAndUpdatesLastUsed", func(t *testing.T) {
+		login := registerAndLogin(t, client, "sessions-refresh@example.com", "Laptop/1.0", "10.0.0.1")
+
+		before, err := client.ListSessions(context.Background(), authenticated(&authv1.ListSessionsRequest{}, login.AccessToken))
+		require.NoError(t, err)
+		require.Len(t, before.Msg.Sessions, 1)
+
+		_, err = client.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{
+			RefreshToken: login.RefreshToken,
+			UserAgent:    "Laptop/1.1",
+			IpAddress:    "10.0.0.9",
+		}))
+		require.NoError(t, err)
+
+		after, err := client.ListSessions(context.Background(), authenticated(&authv1.ListSessionsRequest{}, login.AccessToken))
+		require.NoError(t, err)
+		require.Len(t, after.Msg.Sessions, 1)
+
+		session := after.Msg.Sessions[0]
+		assert.Equal(t, before.Msg.Sessions[0].Id, session.Id)
+		assert.Equal(t, "Laptop/1.1", session.UserAgent)
+		assert.Equal(t, before.Msg.Sessions[0].CreatedAt.AsTime(), session.CreatedAt.AsTime())
+		assert.False(t, session.LastUsedAt.AsTime().Before(before.Msg.Sessions[0].LastUsedAt.AsTime()))
+	})
+
+	t.Run("RevokeSession_Success", func(t *testing.T) {
+		email := "sessions-revoke@example.com"
+		laptop := registerAndLogin(t, client, email, "Laptop/1.0", "10.0.0.1")
+		phone := registerAndLogin(t, client, email, "Phone/2.0", "10.0.0.2")
+
+		list, err := client.ListSessions(context.Background(), authenticated(&authv1.ListSessionsRequest{}, laptop.AccessToken))
+		require.NoError(t, err)
+		var phoneSessionID string
+		for _, s := range list.Msg.Sessions {
+			if s.UserAgent == "Phone/2.0" {
+				phoneSessionID = s.Id
+			}
+		}
+		require.NotEmpty(t, phoneSessionID)
+
+		_, err = client.RevokeSession(context.Background(), authenticated(&authv1.RevokeSessionRequest{
+			SessionId: phoneSessionID,
+		}, laptop.AccessToken))
+		require.NoError(t, err)
+
+		// The revoked device can no longer refresh
+		_, err = client.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{
+			RefreshToken: phone.RefreshToken,
+		}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+		// Only the laptop session remains
+		list, err = client.ListSessions(context.Background(), authenticated(&authv1.ListSessionsRequest{}, laptop.AccessToken))
+		require.NoError(t, err)
+		require.Len(t, list.Msg.Sessions, 1)
+		assert.Equal(t, "Laptop/1.0", list.Msg.Sessions[0].UserAgent)
+	})
+
+	t.Run("RevokeSession_OtherUsersSessionNotFound", func(t *testing.T) {
+		victim := registerAndLogin(t, client, "sessions-victim@example.com", "Victim/1.0", "10.0.0.1")
+		attacker := registerAndLogin(t, client, "sessions-attacker@example.com", "Attacker/1.0", "10.0.0.66")
+
+		list, err := client.ListSessions(context.Background(), authenticated(&authv1.ListSessionsRequest{}, victim.AccessToken))
+		require.NoError(t, err)
+		require.Len(t, list.Msg.Sessions, 1)
+		victimSessionID := list.Msg.Sessions[0].Id
+
+		_, err = client.RevokeSession(context.Background(), authenticated(&authv1.RevokeSessionRequest{
+			SessionId: victimSessionID,
+		}, attacker.AccessToken))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+		// The victim's session is untouched
+		_, err = client.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{
+			RefreshToken: victim.RefreshToken,
+		}))
+		require.NoError(t, err)
+	})
+
+	t.Run("RevokeSession_UnknownID", func(t *testing.T) {
+		login := registerAndLogin(t, client, "sessions-unknown@example.com", "Laptop/1.0", "10.0.0.1")
+
+		_, err := client.RevokeSession(context.Background(), authenticated(&authv1.RevokeSessionRequest{
+			SessionId: uuid.New().String(),
+		}, login.AccessToken))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+
+	t.Run("RevokeSession_InvalidID", func(t *testing.T) {
+		login := registerAndLogin(t, client, "sessions-invalid@example.com", "Laptop/1.0", "10.0.0.1")
+
+		_, err := client.RevokeSession(context.Background(), authenticated(&authv1.RevokeSessionRequest{
+			SessionId: "not-a-uuid",
+		}, login.AccessToken))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+}
+
+func TestAuth_MaxSessions(t *testing.T) {
+	testDB := testhelpers.NewTestDatabase(t, "../migrations")
+	defer testDB.Close()
+
+	const maxSessions = 3
+	client, _ := setupAuthApp(t, testDB.Pool, users.WithMaxSessions(maxSessions))
+
+	// One login more than the cap, from distinct devices, oldest first
+	var logins []*authv1.LoginResponse
+	for i := range maxSessions + 1 {
+		logins = append(logins, registerAndLogin(t, client, "sessions-cap@example.com", fmt.Sprintf("Device/%d", i), "10.0.0.1"))
+	}
+
+	newest := logins[len(logins)-1]
+	res, err := client.ListSessions(context.Background(), authenticated(&authv1.ListSessionsRequest{}, newest.AccessToken))
+	require.NoError(t, err)
+	require.Len(t, res.Msg.Sessions, maxSessions)
+
+	agents := make([]string, len(res.Msg.Sessions))
+	for i, session := range res.Msg.Sessions {
+		agents[i] = session.UserAgent
+	}
+	assert.ElementsMatch(t, []string{"Device/1", "Device/2", "Device/3"}, agents, "the oldest session is evicted")
+
+	// The evicted session can no longer be refreshed, the others can
+	_, err = client.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{RefreshToken: logins[0].RefreshToken}))
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+	_, err = client.Refresh(context.Background(), connect.NewRequest(&authv1.RefreshRequest{RefreshToken: logins[1].RefreshToken}))
+	assert.NoError(t, err)
+}
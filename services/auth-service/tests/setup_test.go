@@ -2,29 +2,29 @@ package tests
 
 import (
 	"context"
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/x509"
-	"encoding/pem"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"connectrpc.com/connect"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/floroz/gavel/pkg/auth"
 	"github.com/floroz/gavel/pkg/database"
+	pb "github.com/floroz/gavel/pkg/proto"
 	"github.com/floroz/gavel/pkg/proto/auth/v1/authv1connect"
 	"github.com/floroz/gavel/services/auth-service/internal/adapters/api"
 	infradb "github.com/floroz/gavel/services/auth-service/internal/adapters/database"
 	"github.com/floroz/gavel/services/auth-service/internal/domain/users"
 )
 
-// setupAuthApp wires up the application for testing using a real database connection.
-func setupAuthApp(t *testing.T, pool *pgxpool.Pool) (authv1connect.AuthServiceClient, *pgxpool.Pool) {
+// setupAuthApp wires up the application for testing using a real database connection,
+// with opts applied to the service.
+func setupAuthApp(t *testing.T, pool *pgxpool.Pool, opts ...users.Option) (authv1connect.AuthServiceClient, *pgxpool.Pool) {
 	// 1. Initialize Repositories
 	txManager := database.NewPostgresTransactionManager(pool, 5*time.Second)
 	userRepo := infradb.NewPostgresUserRepository(pool)
@@ -32,32 +32,20 @@ func setupAuthApp(t *testing.T, pool *pgxpool.Pool) (authv1connect.AuthServiceCl
 	outboxRepo := infradb.NewPostgresOutboxRepository(pool)
 
 	// 2. Initialize Dependencies
-	// Generate ephemeral RSA keys for testing
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
-	require.NoError(t, err)
-
-	privBytes := x509.MarshalPKCS1PrivateKey(privateKey)
-	privPEM := pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: privBytes,
-	})
-
-	pubBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
-	require.NoError(t, err)
-	pubPEM := pem.EncodeToMemory(&pem.Block{
-		Type:  "PUBLIC KEY",
-		Bytes: pubBytes,
-	})
-
-	signer, err := auth.NewSigner(privPEM, pubPEM, "gavel-auth-service")
+	signer, err := auth.NewDevSigner()
 	require.NoError(t, err)
 
 	// 3. Initialize Service
-	authService := users.NewService(userRepo, tokenRepo, outboxRepo, signer, txManager)
+	authService := users.NewService(userRepo, tokenRepo, outboxRepo, signer, txManager, opts...)
 
 	// 4. Initialize API Handler
 	authHandler := api.NewAuthServiceHandler(authService)
-	path, handler := authv1connect.NewAuthServiceHandler(authHandler)
+
+	authInterceptor := auth.NewAuthInterceptorWithPublicRoutes(signer, api.PublicRoutes())
+	path, handler := authv1connect.NewAuthServiceHandler(
+		authHandler,
+		connect.WithInterceptors(authInterceptor),
+	)
 
 	// 5. Create Test Server
 	mux := http.NewServeMux()
@@ -105,3 +93,24 @@ func verifyOutboxEventExists(t *testing.T, pool *pgxpool.Pool, eventType string)
 	_ = row.Scan(&count)
 	return count > 0
 }
+
+// verifyLoginEventExists checks if a user.logged_in event was written to the outbox for the user.
+func verifyLoginEventExists(t *testing.T, pool *pgxpool.Pool, userID uuid.UUID) bool {
+	t.Helper()
+	rows, err := pool.Query(context.Background(), `SELECT payload FROM outbox_events WHERE event_type = 'user.logged_in'`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var payload []byte
+		require.NoError(t, rows.Scan(&payload))
+
+		var event pb.UserLoggedIn
+		require.NoError(t, proto.Unmarshal(payload, &event))
+		if event.UserId == userID.String() {
+			return true
+		}
+	}
+	require.NoError(t, rows.Err())
+	return false
+}
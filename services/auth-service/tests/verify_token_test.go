@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authv1 "github.com/floroz/gavel/pkg/proto/auth/v1"
+	"github.com/floroz/gavel/pkg/testhelpers"
+)
+
+func TestAuth_VerifyToken(t *testing.T) {
+	testDB := testhelpers.NewTestDatabase(t, "../migrations")
+	defer testDB.Close()
+
+	client, _ := setupAuthApp(t, testDB.Pool)
+
+	t.Run("ValidToken", func(t *testing.T) {
+		login := registerAndLogin(t, client, "verify-token@example.com", "Service/1.0", "10.0.0.1")
+
+		// No Authorization header: the token to verify travels in the body
+		res, err := client.VerifyToken(context.Background(), connect.NewRequest(&authv1.VerifyTokenRequest{
+			AccessToken: login.AccessToken,
+		}))
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.Msg.UserId)
+		assert.Equal(t, "verify-token@example.com", res.Msg.Email)
+		assert.Equal(t, "user", res.Msg.Role)
+		assert.NotEmpty(t, res.Msg.Permissions)
+		require.NotNil(t, res.Msg.ExpiresAt)
+		assert.WithinDuration(t, login.ExpiresAt.AsTime(), res.Msg.ExpiresAt.AsTime(), 2*time.Second)
+	})
+
+	t.Run("GarbageToken", func(t *testing.T) {
+		_, err := client.VerifyToken(context.Background(), connect.NewRequest(&authv1.VerifyTokenRequest{
+			AccessToken: "garbage",
+		}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+	})
+
+	t.Run("EmptyToken", func(t *testing.T) {
+		_, err := client.VerifyToken(context.Background(), connect.NewRequest(&authv1.VerifyTokenRequest{}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+}
@@ -12,15 +12,20 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/rabbitmq/amqp091-go"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
 	"github.com/floroz/gavel/pkg/auth"
 	pkgdb "github.com/floroz/gavel/pkg/database"
 	pkgevents "github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/pkg/health"
+	"github.com/floroz/gavel/pkg/logging"
 	"github.com/floroz/gavel/pkg/proto/bids/v1/bidsv1connect"
+	"github.com/floroz/gavel/pkg/tracing"
 	"github.com/floroz/gavel/services/bid-service/internal/adapters/api"
 	"github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/config"
 	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
 	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
 )
@@ -36,46 +41,38 @@ func main() {
 
 	ctx := context.Background()
 
-	// 1. Load JWT Public Key for token validation
-	publicKeyPath := os.Getenv("JWT_PUBLIC_KEY_PATH")
-	if publicKeyPath == "" {
-		logger.Error("JWT_PUBLIC_KEY_PATH is not set")
-		os.Exit(1)
-	}
-
-	publicKeyPEM, err := os.ReadFile(publicKeyPath)
+	// Export traces when an OTLP endpoint is configured
+	shutdownTracing, err := tracing.Setup(ctx, "bid-service")
 	if err != nil {
-		logger.Error("Failed to read public key", "path", publicKeyPath, "error", err)
+		logger.Error("Failed to set up tracing", "error", err)
 		os.Exit(1)
 	}
+	defer func() {
+		_ = shutdownTracing(context.Background())
+	}()
 
-	issuer := os.Getenv("JWT_ISSUER")
-	if issuer == "" {
-		logger.Error("JWT_ISSUER is not set")
+	cfg, err := config.LoadAPI()
+	if err != nil {
+		logger.Error("Invalid configuration", "error", err)
 		os.Exit(1)
 	}
 
+	// 1. Load JWT Public Key for token validation
 	// Create signer with only public key (for validation only)
-	signer, err := auth.NewSignerFromPublicKey(publicKeyPEM, issuer)
+	signer, err := auth.LoadVerifier(cfg.JWTPublicKey, cfg.JWTIssuer)
 	if err != nil {
 		logger.Error("Failed to create signer", "error", err)
 		os.Exit(1)
 	}
-	logger.Info("JWT public key loaded", "path", publicKeyPath)
+	logger.Info("JWT public key loaded", "kid", signer.KeyID())
 
 	// 2. Initialize Postgres Connection Pool
-	dbURL := os.Getenv("BID_DB_URL")
-
-	if dbURL == "" {
-		logger.Error("BID_DB_URL is not set")
-		os.Exit(1)
-	}
-
-	dbConfig, err := pgxpool.ParseConfig(dbURL)
+	dbConfig, err := pkgdb.ParsePoolConfig(cfg.DatabaseURL, "BID")
 	if err != nil {
 		logger.Error("Unable to parse database config", "error", err)
 		os.Exit(1)
 	}
+	dbConfig.ConnConfig.Tracer = pkgdb.NewQueryTracer(otel.GetTracerProvider())
 
 	pool, err := pgxpool.NewWithConfig(ctx, dbConfig)
 	if err != nil {
@@ -90,14 +87,23 @@ func main() {
 	}
 	logger.Info("Postgres Connected")
 
-	// 2. Check RabbitMQ (Optional for API, but good for health)
-	rabbitURL := os.Getenv("RABBITMQ_URL")
-	if rabbitURL == "" {
-		logger.Error("RABBITMQ_URL is not set")
-		os.Exit(1)
+	// Optional read replicas for read-heavy endpoints
+	var replicas []*pgxpool.Pool
+	if len(cfg.ReplicaURLs) > 0 {
+		replicas, err = pkgdb.ConnectReplicas(ctx, cfg.ReplicaURLs, "BID")
+		if err != nil {
+			logger.Error("Unable to connect to read replicas", "error", err)
+			os.Exit(1)
+		}
+		for _, replica := range replicas {
+			defer replica.Close()
+		}
+		logger.Info("Read replicas connected", "count", len(replicas))
 	}
+	router := pkgdb.NewRouter(pool, replicas...)
 
-	amqpConn, err := amqp091.Dial(rabbitURL)
+	// 2. Check RabbitMQ (Optional for API, but good for health)
+	amqpConn, err := amqp091.Dial(cfg.RabbitMQURL)
 	if err != nil {
 		logger.Error("Failed to connect to RabbitMQ", "error", err)
 		os.Exit(1)
@@ -105,7 +111,16 @@ func main() {
 	defer amqpConn.Close()
 	logger.Info("RabbitMQ Connected")
 
-	rabbitPublisher, err := pkgevents.NewRabbitMQPublisher(amqpConn)
+	// Fail fast if the broker has the exchange with other settings, rather than losing messages
+	if err := pkgevents.DeclareTopology(amqpConn, pkgevents.Topology{}); err != nil {
+		logger.Error("Invalid RabbitMQ topology", "error", err)
+		os.Exit(1)
+	}
+
+	rabbitPublisher, err := pkgevents.NewRabbitMQPublisher(amqpConn,
+		// Re-dial if the broker restarts so the outbox relay keeps publishing
+		pkgevents.WithDialer(func() (*amqp091.Connection, error) { return amqp091.Dial(cfg.RabbitMQURL) }),
+	)
 	if err != nil {
 		logger.Error("Failed to create RabbitMQ publisher", "error", err)
 		os.Exit(1)
@@ -113,24 +128,33 @@ func main() {
 	defer rabbitPublisher.Close()
 
 	// 3. Check Redis (Optional for API, but good for health)
-	redisURL := os.Getenv("REDIS_URL")
-	if redisURL != "" {
-		rdb := redis.NewClient(&redis.Options{Addr: redisURL})
+	// The publisher re-dials the broker, so its connection is the one to check
+	healthChecks := []health.Check{health.Postgres(pool), health.RabbitMQ(rabbitPublisher)}
+	if cfg.RedisURL != "" {
+		rdb := redis.NewClient(&redis.Options{Addr: cfg.RedisURL})
+		defer rdb.Close()
 		if err := rdb.Ping(ctx).Err(); err != nil {
 			logger.Warn("Redis connection failed (API might still work)", "error", err)
 		} else {
 			logger.Info("Redis Connected")
 		}
+		// The API works without Redis, so it does not take the service out of rotation
+		healthChecks = append(healthChecks, health.Optional(health.Redis(rdb)))
 	}
 
 	// 4. Initialize Repositories (Infrastructure Layer)
 	txManager := pkgdb.NewPostgresTransactionManager(pool, 3*time.Second)
-	bidRepo := database.NewPostgresBidRepository(pool)
+	bidRepo := database.NewPostgresBidRepository(pool, database.WithReadReplicas(router))
 	itemRepo := database.NewPostgresItemRepository(pool)
 	outboxRepo := database.NewPostgresOutboxRepository(pool)
 
 	// 5. Initialize Service (Domain Layer)
-	auctionService := bids.NewAuctionService(txManager, bidRepo, itemRepo, outboxRepo)
+	auctionService := bids.NewAuctionService(txManager, bidRepo, itemRepo, outboxRepo,
+		bids.WithMinBidIncrement(cfg.MinIncrement),
+		bids.WithMaxBidAmount(cfg.MaxBidAmount),
+		bids.WithAntiSnipe(cfg.AntiSnipe),
+		bids.WithUrgentBidWindow(cfg.UrgentBidWindow),
+	)
 	itemService := items.NewService(itemRepo)
 
 	// 7. Initialize API Handler (ConnectRPC) with auth interceptor
@@ -146,7 +170,9 @@ func main() {
 	authInterceptor := auth.NewAuthInterceptorWithPublicRoutes(signer, publicRoutes)
 	path, handler := bidsv1connect.NewBidServiceHandler(
 		bidHandler,
-		connect.WithInterceptors(authInterceptor),
+		// Log first; the request id it assigns also correlates the events a call publishes
+		// Permissions are checked against the claims the auth interceptor sets, so it runs after it
+		connect.WithInterceptors(logging.NewInterceptor(logger), authInterceptor, auth.NewPermissionInterceptor(api.RequiredPermissions())),
 	)
 
 	// 7. Start Outbox Relay
@@ -154,9 +180,9 @@ func main() {
 		outboxRepo,
 		rabbitPublisher,
 		txManager,
-		10,               // batch size
-		1*time.Second,    // interval
-		"auction.events", // exchange
+		10,                 // batch size
+		1*time.Second,      // interval
+		pkgevents.Exchange, // exchange
 		logger,
 	)
 
@@ -174,6 +200,11 @@ func main() {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("OK"))
 	})
+	// Per-dependency status; 503 when any of them is unreachable
+	readiness := health.NewChecker(health.DefaultTimeout, healthChecks...)
+	mux.Handle("/healthz", readiness)
+	mux.Handle("/readyz", readiness)
+	mux.Handle("/livez", health.Live())
 
 	// 7. Start Server
 	addr := ":8080"
@@ -6,12 +6,21 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
 
+	pkgdb "github.com/floroz/gavel/pkg/database"
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/pkg/tracing"
+	"github.com/floroz/gavel/services/bid-service/internal/adapters/database"
 	"github.com/floroz/gavel/services/bid-service/internal/adapters/events"
+	"github.com/floroz/gavel/services/bid-service/internal/config"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
 )
 
 func main() {
@@ -35,17 +44,29 @@ func main() {
 		cancel()
 	}()
 
-	// 1. Initialize Postgres Connection Pool
-	dbURL := os.Getenv("BID_DB_URL")
-	if dbURL == "" {
-		logger.Error("BID_DB_URL is not set")
+	// Export traces when an OTLP endpoint is configured
+	shutdownTracing, err := tracing.Setup(ctx, "bid-worker")
+	if err != nil {
+		logger.Error("Failed to set up tracing", "error", err)
 		os.Exit(1)
 	}
-	dbConfig, err := pgxpool.ParseConfig(dbURL)
+	defer func() {
+		_ = shutdownTracing(context.Background())
+	}()
+
+	cfg, err := config.LoadWorker()
+	if err != nil {
+		logger.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// 1. Initialize Postgres Connection Pool
+	dbConfig, err := pkgdb.ParsePoolConfig(cfg.DatabaseURL, "BID")
 	if err != nil {
 		logger.Error("Unable to parse database config", "error", err)
 		os.Exit(1)
 	}
+	dbConfig.ConnConfig.Tracer = pkgdb.NewQueryTracer(otel.GetTracerProvider())
 
 	pool, err := pgxpool.NewWithConfig(ctx, dbConfig)
 	if err != nil {
@@ -61,12 +82,7 @@ func main() {
 	logger.Info("Postgres Connected")
 
 	// 2. Connect to RabbitMQ
-	rabbitURL := os.Getenv("RABBITMQ_URL")
-	if rabbitURL == "" {
-		logger.Error("RABBITMQ_URL is not set")
-		os.Exit(1)
-	}
-	amqpConn, err := amqp.Dial(rabbitURL)
+	amqpConn, err := amqp.Dial(cfg.RabbitMQURL)
 	if err != nil {
 		logger.Error("Failed to connect to RabbitMQ", "error", err)
 		os.Exit(1)
@@ -74,14 +90,37 @@ func main() {
 	defer amqpConn.Close()
 	logger.Info("RabbitMQ Connected")
 
+	// Fail fast if the broker has the exchange with other settings, rather than losing messages
+	if err := pkgevents.DeclareTopology(amqpConn, pkgevents.Topology{}); err != nil {
+		logger.Error("Invalid RabbitMQ topology", "error", err)
+		os.Exit(1)
+	}
+
 	// 3. Initialize Producer
-	producer, err := events.NewBidEventsProducer(pool, amqpConn, logger)
+	producer, err := events.NewBidEventsProducer(pool, amqpConn, logger,
+		// Re-dial if the broker restarts so the relay keeps publishing
+		pkgevents.WithDialer(func() (*amqp.Connection, error) { return amqp.Dial(cfg.RabbitMQURL) }),
+	)
 	if err != nil {
 		logger.Error("Failed to create producer", "error", err)
 		os.Exit(1)
 	}
 	defer producer.Close()
 
+	// 4. Open scheduled auctions once their start time passes, and close them once it ends
+	itemRepo := database.NewPostgresItemRepository(pool)
+	itemService := items.NewService(itemRepo)
+	auctionService := bids.NewAuctionService(
+		pkgdb.NewPostgresTransactionManager(pool, 3*time.Second),
+		database.NewPostgresBidRepository(pool),
+		itemRepo,
+		database.NewPostgresOutboxRepository(pool),
+	)
+	go runAuctionScheduler(ctx, itemService, auctionService, cfg.SchedulerInterval, logger)
+
+	// 5. Prune published outbox events once they are past retention
+	go pkgevents.RunOutboxPruner(ctx, database.NewPostgresOutboxRepository(pool), cfg.OutboxRetention, pkgevents.DefaultOutboxPruneInterval, logger)
+
 	logger.Info("Starting Bid Events Producer...")
 	if runErr := producer.Run(ctx); runErr != nil {
 		logger.Error("Producer failed", "error", runErr)
@@ -93,3 +132,34 @@ func main() {
 
 	logger.Info("Worker stopped")
 }
+
+// runAuctionScheduler opens due scheduled auctions and closes expired ones every interval until
+// ctx is cancelled. Bids also open a due auction on their own, so a missed tick only delays listings;
+// expired auctions already refuse bids, so a missed tick only delays their auction.ended event.
+func runAuctionScheduler(ctx context.Context, itemService *items.Service, auctionService *bids.AuctionService, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			started, err := itemService.StartDueAuctions(ctx)
+			if err != nil {
+				logger.Error("Failed to start scheduled auctions", "error", err)
+			} else if started > 0 {
+				logger.Info("Started scheduled auctions", "count", started)
+			}
+
+			// Errors only affect the auctions named in them; the rest are still closed
+			ended, err := auctionService.EndDueAuctions(ctx, bids.DefaultEndAuctionsBatchSize)
+			if err != nil {
+				logger.Error("Failed to end some auctions", "error", err)
+			}
+			if ended > 0 {
+				logger.Info("Ended auctions", "count", ended)
+			}
+		}
+	}
+}
@@ -30,6 +30,14 @@ func NewBidServiceHandler(auctionService *bids.AuctionService, itemService *item
 	}
 }
 
+// RequiredPermissions maps the procedures only some callers may use to the permission they need,
+// for auth.NewPermissionInterceptor
+func RequiredPermissions() map[string]string {
+	return map[string]string{
+		bidsv1connect.BidServiceForceCancelItemProcedure: auth.PermissionForceCancelItem,
+	}
+}
+
 func (h *BidServiceHandler) PlaceBid(
 	ctx context.Context,
 	req *connect.Request[bidsv1.PlaceBidRequest],
@@ -55,23 +63,21 @@ func (h *BidServiceHandler) PlaceBid(
 	// 3. Execution
 	bid, err := h.auctionService.PlaceBid(ctx, cmd)
 	if err != nil {
-		if errors.Is(err, bids.ErrBidTooLow) || errors.Is(err, bids.ErrAuctionEnded) {
+		if errors.Is(err, bids.ErrBidTooLow) || errors.Is(err, bids.ErrBidIncrementTooSmall) || errors.Is(err, bids.ErrAuctionEnded) || errors.Is(err, bids.ErrAuctionNotStarted) || errors.Is(err, bids.ErrAuctionInactive) {
 			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
 		}
-		if errors.Is(err, bids.ErrInvalidBidAmount) {
+		if errors.Is(err, bids.ErrInvalidBidAmount) || errors.Is(err, bids.ErrBidAmountTooHigh) {
 			return nil, connect.NewError(connect.CodeInvalidArgument, err)
 		}
 		if errors.Is(err, bids.ErrSellerCannotBid) {
 			return nil, connect.NewError(connect.CodePermissionDenied, err)
 		}
-		// Check for "item not found" wrapped error
-		// Since we wrap it with fmt.Errorf("item not found: %w", err), checking string or unwrapping is needed.
-		// A cleaner way is to have a typed error for ItemNotFound in the domain.
-		// For now, simple string check or if the service returned a sentinel.
-		// The service currently returns `fmt.Errorf("item not found: %w", err)`.
-		// Let's rely on the error string for this specific case as per current implementation,
-		// or better, let's assume standard error wrapping.
-		// Ideally, we should define ErrItemNotFound in domain/bids.
+		if errors.Is(err, bids.ErrBidConflict) {
+			return nil, connect.NewError(connect.CodeAborted, err)
+		}
+		if errors.Is(err, items.ErrItemNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
@@ -89,6 +95,51 @@ func (h *BidServiceHandler) PlaceBid(
 	return connect.NewResponse(res), nil
 }
 
+// PurchaseNow buys an item at its buy-now price, ending the auction
+func (h *BidServiceHandler) PurchaseNow(
+	ctx context.Context,
+	req *connect.Request[bidsv1.PurchaseNowRequest],
+) (*connect.Response[bidsv1.PurchaseNowResponse], error) {
+	userID, err := uuid.Parse(auth.MustGetUserID(ctx))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, errors.New("invalid user_id in token"))
+	}
+
+	itemID, err := uuid.Parse(req.Msg.ItemId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid item_id"))
+	}
+
+	bid, err := h.auctionService.PurchaseNow(ctx, bids.PurchaseNowCommand{
+		ItemID: itemID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, bids.ErrAuctionEnded) || errors.Is(err, bids.ErrAuctionNotStarted) || errors.Is(err, bids.ErrAuctionInactive) || errors.Is(err, bids.ErrBuyNowUnavailable) || errors.Is(err, bids.ErrBuyNowExceeded) {
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		}
+		if errors.Is(err, bids.ErrSellerCannotBid) {
+			return nil, connect.NewError(connect.CodePermissionDenied, err)
+		}
+		if errors.Is(err, bids.ErrBidConflict) {
+			return nil, connect.NewError(connect.CodeAborted, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	res := &bidsv1.PurchaseNowResponse{
+		Bid: &bidsv1.Bid{
+			Id:        bid.ID.String(),
+			ItemId:    bid.ItemID.String(),
+			UserId:    bid.UserID.String(),
+			Amount:    bid.Amount,
+			CreatedAt: bid.CreatedAt.Format(time.RFC3339),
+		},
+	}
+
+	return connect.NewResponse(res), nil
+}
+
 // CreateItem creates a new auction item
 func (h *BidServiceHandler) CreateItem(
 	ctx context.Context,
@@ -106,21 +157,33 @@ func (h *BidServiceHandler) CreateItem(
 		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid end_at format"))
 	}
 
+	// Parse optional start time; without one the auction opens immediately
+	var startAt time.Time
+	if req.Msg.StartAt != "" {
+		startAt, err = time.Parse(time.RFC3339, req.Msg.StartAt)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid start_at format"))
+		}
+	}
+
 	// Create command
 	cmd := items.CreateItemCommand{
-		Title:       req.Msg.Title,
-		Description: req.Msg.Description,
-		StartPrice:  req.Msg.StartPrice,
-		EndAt:       endAt,
-		Images:      req.Msg.Images,
-		Category:    req.Msg.Category,
-		SellerID:    userID,
+		Title:        req.Msg.Title,
+		Description:  req.Msg.Description,
+		StartPrice:   req.Msg.StartPrice,
+		ReservePrice: req.Msg.ReservePrice,
+		BuyNowPrice:  req.Msg.BuyNowPrice,
+		StartAt:      startAt,
+		EndAt:        endAt,
+		Images:       req.Msg.Images,
+		Category:     req.Msg.Category,
+		SellerID:     userID,
 	}
 
 	// Execute
 	item, err := h.itemService.CreateItem(ctx, cmd)
 	if err != nil {
-		if errors.Is(err, items.ErrInvalidStartPrice) || errors.Is(err, items.ErrInvalidEndTime) {
+		if errors.Is(err, items.ErrInvalidStartPrice) || errors.Is(err, items.ErrInvalidReserve) || errors.Is(err, items.ErrInvalidBuyNow) || errors.Is(err, items.ErrInvalidEndTime) || errors.Is(err, items.ErrInvalidStartTime) {
 			return nil, connect.NewError(connect.CodeInvalidArgument, err)
 		}
 		return nil, connect.NewError(connect.CodeInternal, err)
@@ -331,14 +394,8 @@ func (h *BidServiceHandler) CancelItem(
 		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid id"))
 	}
 
-	// Create command
-	cmd := items.CancelItemCommand{
-		ItemID: itemID,
-		UserID: userID,
-	}
-
 	// Execute
-	item, err := h.itemService.CancelItem(ctx, cmd)
+	item, err := h.auctionService.CancelItem(ctx, itemID, userID)
 	if err != nil {
 		if errors.Is(err, items.ErrItemNotFound) {
 			return nil, connect.NewError(connect.CodeNotFound, err)
@@ -359,6 +416,38 @@ func (h *BidServiceHandler) CancelItem(
 	return connect.NewResponse(res), nil
 }
 
+// ForceCancelItem cancels any seller's auction item; the permission interceptor admits moderators only
+func (h *BidServiceHandler) ForceCancelItem(
+	ctx context.Context,
+	req *connect.Request[bidsv1.ForceCancelItemRequest],
+) (*connect.Response[bidsv1.ForceCancelItemResponse], error) {
+	adminID, err := uuid.Parse(auth.MustGetUserID(ctx))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, errors.New("invalid user_id in token"))
+	}
+
+	itemID, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid id"))
+	}
+
+	item, err := h.auctionService.ForceCancelItem(ctx, itemID, adminID)
+	if err != nil {
+		if errors.Is(err, items.ErrItemNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		if errors.Is(err, bids.ErrInvalidTransition) {
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	res := &bidsv1.ForceCancelItemResponse{
+		Item: mapItemToProto(item),
+	}
+	return connect.NewResponse(res), nil
+}
+
 // GetItemBids retrieves all bids for an item
 func (h *BidServiceHandler) GetItemBids(
 	ctx context.Context,
@@ -370,15 +459,24 @@ func (h *BidServiceHandler) GetItemBids(
 		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid item_id"))
 	}
 
+	// Decode the page token, if any
+	var cursor *bids.BidCursor
+	if req.Msg.PageToken != "" {
+		cursor, err = bids.ParseBidCursor(req.Msg.PageToken)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid page_token"))
+		}
+	}
+
 	// Execute
-	bidList, err := h.bidRepo.GetBidsByItemID(ctx, itemID)
+	page, err := h.auctionService.ListBids(ctx, itemID, int(req.Msg.PageSize), cursor)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
 	// Map to proto
-	protoBids := make([]*bidsv1.Bid, len(bidList))
-	for i, bid := range bidList {
+	protoBids := make([]*bidsv1.Bid, len(page.Bids))
+	for i, bid := range page.Bids {
 		protoBids[i] = &bidsv1.Bid{
 			Id:        bid.ID.String(),
 			ItemId:    bid.ItemID.String(),
@@ -391,37 +489,53 @@ func (h *BidServiceHandler) GetItemBids(
 	res := &bidsv1.GetItemBidsResponse{
 		Bids: protoBids,
 	}
+	if page.Next != nil {
+		res.NextPageToken = page.Next.Encode()
+	}
 
 	return connect.NewResponse(res), nil
 }
 
-// mapItemToProto converts a domain Item to a proto Item
+// mapItemToProto converts a domain Item to a proto Item.
+// The reserve price is deliberately left out: buyers must never see it.
 func mapItemToProto(item *items.Item) *bidsv1.Item {
 	// Map status
 	var protoStatus bidsv1.ItemStatus
 	switch item.Status {
+	case items.ItemStatusScheduled:
+		protoStatus = bidsv1.ItemStatus_ITEM_STATUS_SCHEDULED
 	case items.ItemStatusActive:
 		protoStatus = bidsv1.ItemStatus_ITEM_STATUS_ACTIVE
 	case items.ItemStatusEnded:
 		protoStatus = bidsv1.ItemStatus_ITEM_STATUS_ENDED
+	case items.ItemStatusEndedUnsold:
+		protoStatus = bidsv1.ItemStatus_ITEM_STATUS_ENDED_UNSOLD
 	case items.ItemStatusCancelled:
 		protoStatus = bidsv1.ItemStatus_ITEM_STATUS_CANCELLED
 	default:
 		protoStatus = bidsv1.ItemStatus_ITEM_STATUS_UNSPECIFIED
 	}
 
+	var bidderID string
+	if item.CurrentHighestBidderID != uuid.Nil {
+		bidderID = item.CurrentHighestBidderID.String()
+	}
+
 	return &bidsv1.Item{
-		Id:                item.ID.String(),
-		Title:             item.Title,
-		Description:       item.Description,
-		StartPrice:        item.StartPrice,
-		CurrentHighestBid: item.CurrentHighestBid,
-		EndAt:             item.EndAt.Format(time.RFC3339),
-		CreatedAt:         item.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:         item.UpdatedAt.Format(time.RFC3339),
-		Images:            item.Images,
-		Category:          item.Category,
-		SellerId:          item.SellerID.String(),
-		Status:            protoStatus,
+		Id:                     item.ID.String(),
+		Title:                  item.Title,
+		Description:            item.Description,
+		StartPrice:             item.StartPrice,
+		CurrentHighestBid:      item.CurrentHighestBid,
+		CurrentHighestBidderId: bidderID,
+		StartAt:                item.StartAt.Format(time.RFC3339),
+		EndAt:                  item.EndAt.Format(time.RFC3339),
+		CreatedAt:              item.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:              item.UpdatedAt.Format(time.RFC3339),
+		Images:                 item.Images,
+		Category:               item.Category,
+		SellerId:               item.SellerID.String(),
+		Status:                 protoStatus,
+		BuyNowPrice:            item.BuyNowPrice,
 	}
 }
@@ -0,0 +1,63 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/auth"
+	bidsv1 "github.com/floroz/gavel/pkg/proto/bids/v1"
+	"github.com/floroz/gavel/pkg/proto/bids/v1/bidsv1connect"
+	"github.com/floroz/gavel/services/bid-service/internal/adapters/api"
+)
+
+func TestBidServiceHandler_ForceCancelItemRequiresPermission(t *testing.T) {
+	signer, err := auth.NewDevSigner()
+	require.NoError(t, err)
+
+	// The handler has no services: only calls the interceptors let through reach it, and an
+	// invalid id makes it answer before touching them
+	path, handler := bidsv1connect.NewBidServiceHandler(
+		api.NewBidServiceHandler(nil, nil, nil),
+		connect.WithInterceptors(
+			auth.NewAuthInterceptorWithPublicRoutes(signer, nil),
+			auth.NewPermissionInterceptor(api.RequiredPermissions()),
+		),
+	)
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client := bidsv1connect.NewBidServiceClient(server.Client(), server.URL)
+
+	forceCancel := func(t *testing.T, permissions []string) error {
+		t.Helper()
+		pair, err := signer.GenerateTokens(uuid.New(), "test@example.com", "Test User", "user", permissions)
+		require.NoError(t, err)
+		req := connect.NewRequest(&bidsv1.ForceCancelItemRequest{Id: "not-a-uuid"})
+		req.Header().Set("Authorization", "Bearer "+pair.AccessToken)
+		_, err = client.ForceCancelItem(context.Background(), req)
+		return err
+	}
+
+	t.Run("caller without the permission is denied", func(t *testing.T) {
+		err := forceCancel(t, []string{"place:bids", "manage:own_items"})
+		assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+	})
+
+	t.Run("caller with the permission reaches the handler", func(t *testing.T) {
+		err := forceCancel(t, []string{auth.PermissionForceCancelItem})
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+
+	t.Run("unauthenticated caller is rejected", func(t *testing.T) {
+		_, err := client.ForceCancelItem(context.Background(), connect.NewRequest(&bidsv1.ForceCancelItemRequest{Id: uuid.NewString()}))
+		assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+	})
+}
@@ -8,17 +8,34 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	pkgdb "github.com/floroz/gavel/pkg/database"
 	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
 )
 
 // PostgresBidRepository implements bids.BidRepository using pgx
 type PostgresBidRepository struct {
-	pool *pgxpool.Pool // Keep pool for read-only operations
+	pool  *pgxpool.Pool // Keep pool for read-only operations
+	reads pkgdb.DBTX    // bid history reads; the pool unless replicas are configured
+}
+
+// BidRepositoryOption configures optional PostgresBidRepository behaviour
+type BidRepositoryOption func(*PostgresBidRepository)
+
+// WithReadReplicas sends bid history reads through router, so they land on replicas
+// instead of the primary
+func WithReadReplicas(router *pkgdb.Router) BidRepositoryOption {
+	return func(r *PostgresBidRepository) {
+		r.reads = router
+	}
 }
 
 // NewPostgresBidRepository creates a new PostgreSQL bid repository
-func NewPostgresBidRepository(pool *pgxpool.Pool) *PostgresBidRepository {
-	return &PostgresBidRepository{pool: pool}
+func NewPostgresBidRepository(pool *pgxpool.Pool, opts ...BidRepositoryOption) *PostgresBidRepository {
+	r := &PostgresBidRepository{pool: pool, reads: pool}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // SaveBid saves a bid using the provided database connection (pool or transaction)
@@ -26,14 +43,16 @@ func (r *PostgresBidRepository) SaveBid(ctx context.Context, tx pgx.Tx, bid *bid
 	query := `
 		INSERT INTO bids (id, item_id, user_id, amount, created_at)
 		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
 	`
-	_, err := tx.Exec(ctx, query,
+	// Read back the stored timestamp, which Postgres keeps to the microsecond
+	err := tx.QueryRow(ctx, query,
 		bid.ID,
 		bid.ItemID,
 		bid.UserID,
 		bid.Amount,
 		bid.CreatedAt,
-	)
+	).Scan(&bid.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to insert bid: %w", err)
 	}
@@ -57,7 +76,7 @@ func (r *PostgresBidRepository) GetBidByID(ctx context.Context, bidID uuid.UUID)
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("bid not found")
+			return nil, bids.ErrBidNotFound
 		}
 		return nil, fmt.Errorf("failed to get bid: %w", err)
 	}
@@ -99,3 +118,136 @@ func (r *PostgresBidRepository) GetBidsByItemID(ctx context.Context, itemID uuid
 
 	return result, nil
 }
+
+// ListBids retrieves up to limit bids for an item, newest first, starting after cursor.
+// Keyset pagination on (created_at, id) keeps pages stable while new bids arrive.
+func (r *PostgresBidRepository) ListBids(ctx context.Context, itemID uuid.UUID, limit int, cursor *bids.BidCursor) ([]*bids.Bid, error) {
+	query := `
+		SELECT id, item_id, user_id, amount, created_at
+		FROM bids
+		WHERE item_id = $1
+	`
+	args := []any{itemID}
+	if cursor != nil {
+		query += " AND (created_at, id) < ($2, $3)"
+		args = append(args, cursor.CreatedAt, cursor.ID)
+	}
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args)+1)
+	args = append(args, limit)
+
+	rows, err := r.reads.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bids: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*bids.Bid
+	for rows.Next() {
+		var bid bids.Bid
+		if err := rows.Scan(
+			&bid.ID,
+			&bid.ItemID,
+			&bid.UserID,
+			&bid.Amount,
+			&bid.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan bid: %w", err)
+		}
+		result = append(result, &bid)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bids: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetHighestBid retrieves the leading bid for an item within a transaction, or nil if there are none
+func (r *PostgresBidRepository) GetHighestBid(ctx context.Context, tx pgx.Tx, itemID uuid.UUID) (*bids.Bid, error) {
+	query := `
+		SELECT id, item_id, user_id, amount, created_at
+		FROM bids
+		WHERE item_id = $1
+		ORDER BY amount DESC, created_at DESC
+		LIMIT 1
+	`
+	var bid bids.Bid
+	err := tx.QueryRow(ctx, query, itemID).Scan(
+		&bid.ID,
+		&bid.ItemID,
+		&bid.UserID,
+		&bid.Amount,
+		&bid.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get highest bid: %w", err)
+	}
+	return &bid, nil
+}
+
+// SaveMaxBid creates or replaces a user's maximum bid on an item.
+// Replacing resets created_at, since a changed maximum loses its place in tie-breaks.
+func (r *PostgresBidRepository) SaveMaxBid(ctx context.Context, tx pgx.Tx, maxBid *bids.MaxBid) error {
+	query := `
+		INSERT INTO proxy_bids (id, item_id, user_id, max_amount, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (item_id, user_id) DO UPDATE SET
+			max_amount = EXCLUDED.max_amount,
+			created_at = EXCLUDED.created_at,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`
+	err := tx.QueryRow(ctx, query,
+		maxBid.ID,
+		maxBid.ItemID,
+		maxBid.UserID,
+		maxBid.MaxAmount,
+		maxBid.CreatedAt,
+		maxBid.UpdatedAt,
+	).Scan(&maxBid.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save max bid: %w", err)
+	}
+	return nil
+}
+
+// GetMaxBidsByItemID retrieves all maximum bids for an item within a transaction
+func (r *PostgresBidRepository) GetMaxBidsByItemID(ctx context.Context, tx pgx.Tx, itemID uuid.UUID) ([]*bids.MaxBid, error) {
+	query := `
+		SELECT id, item_id, user_id, max_amount, created_at, updated_at
+		FROM proxy_bids
+		WHERE item_id = $1
+		ORDER BY max_amount DESC, created_at ASC
+	`
+	rows, err := tx.Query(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query max bids: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*bids.MaxBid
+	for rows.Next() {
+		var maxBid bids.MaxBid
+		if err := rows.Scan(
+			&maxBid.ID,
+			&maxBid.ItemID,
+			&maxBid.UserID,
+			&maxBid.MaxAmount,
+			&maxBid.CreatedAt,
+			&maxBid.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan max bid: %w", err)
+		}
+		result = append(result, &maxBid)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating max bids: %w", err)
+	}
+
+	return result, nil
+}
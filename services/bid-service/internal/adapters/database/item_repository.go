@@ -3,6 +3,8 @@ package database
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -25,8 +27,8 @@ func NewPostgresItemRepository(pool *pgxpool.Pool) *PostgresItemRepository {
 // CreateItem creates a new auction item
 func (r *PostgresItemRepository) CreateItem(ctx context.Context, item *items.Item) error {
 	query := `
-		INSERT INTO items (id, title, description, start_price, current_highest_bid, end_at, created_at, updated_at, images, category, seller_id, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO items (id, title, description, start_price, current_highest_bid, reserve_price, buy_now_price, start_at, end_at, created_at, updated_at, images, category, seller_id, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 	_, err := r.pool.Exec(ctx, query,
 		item.ID,
@@ -34,6 +36,9 @@ func (r *PostgresItemRepository) CreateItem(ctx context.Context, item *items.Ite
 		item.Description,
 		item.StartPrice,
 		item.CurrentHighestBid,
+		item.ReservePrice,
+		item.BuyNowPrice,
+		item.StartAt,
 		item.EndAt,
 		item.CreatedAt,
 		item.UpdatedAt,
@@ -62,7 +67,7 @@ func (r *PostgresItemRepository) GetItemByIDForUpdate(ctx context.Context, tx pg
 // getItemByID is the internal implementation that works with any DBTX
 func (r *PostgresItemRepository) getItemByID(ctx context.Context, db pkgdb.DBTX, itemID uuid.UUID, forUpdate bool) (*items.Item, error) {
 	query := `
-		SELECT id, title, description, start_price, current_highest_bid, end_at, created_at, updated_at, images, category, seller_id, status
+		SELECT id, title, description, start_price, current_highest_bid, current_highest_bidder_id, reserve_price, buy_now_price, start_at, end_at, extension_count, created_at, updated_at, images, category, seller_id, status
 		FROM items
 		WHERE id = $1
 	`
@@ -71,13 +76,19 @@ func (r *PostgresItemRepository) getItemByID(ctx context.Context, db pkgdb.DBTX,
 	}
 
 	var item items.Item
+	var bidderID *uuid.UUID
 	err := db.QueryRow(ctx, query, itemID).Scan(
 		&item.ID,
 		&item.Title,
 		&item.Description,
 		&item.StartPrice,
 		&item.CurrentHighestBid,
+		&bidderID,
+		&item.ReservePrice,
+		&item.BuyNowPrice,
+		&item.StartAt,
 		&item.EndAt,
+		&item.ExtensionCount,
 		&item.CreatedAt,
 		&item.UpdatedAt,
 		&item.Images,
@@ -87,10 +98,11 @@ func (r *PostgresItemRepository) getItemByID(ctx context.Context, db pkgdb.DBTX,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("item not found")
+			return nil, items.ErrItemNotFound
 		}
 		return nil, fmt.Errorf("failed to get item: %w", err)
 	}
+	item.CurrentHighestBidderID = uuidOrNil(bidderID)
 	return &item, nil
 }
 
@@ -114,7 +126,7 @@ func (r *PostgresItemRepository) UpdateItem(ctx context.Context, item *items.Ite
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("item not found")
+		return items.ErrItemNotFound
 	}
 
 	return nil
@@ -122,18 +134,28 @@ func (r *PostgresItemRepository) UpdateItem(ctx context.Context, item *items.Ite
 
 // UpdateStatus updates an item's status
 func (r *PostgresItemRepository) UpdateStatus(ctx context.Context, itemID uuid.UUID, status items.ItemStatus) error {
+	return r.updateStatus(ctx, r.pool, itemID, status)
+}
+
+// UpdateStatusInTx updates an item's status within a transaction
+func (r *PostgresItemRepository) UpdateStatusInTx(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, status items.ItemStatus) error {
+	return r.updateStatus(ctx, tx, itemID, status)
+}
+
+// updateStatus is the internal implementation that works with any DBTX
+func (r *PostgresItemRepository) updateStatus(ctx context.Context, db pkgdb.DBTX, itemID uuid.UUID, status items.ItemStatus) error {
 	query := `
 		UPDATE items
 		SET status = $1, updated_at = NOW()
 		WHERE id = $2
 	`
-	result, err := r.pool.Exec(ctx, query, status, itemID)
+	result, err := db.Exec(ctx, query, status, itemID)
 	if err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("item not found")
+		return items.ErrItemNotFound
 	}
 
 	return nil
@@ -142,7 +164,7 @@ func (r *PostgresItemRepository) UpdateStatus(ctx context.Context, itemID uuid.U
 // ListActiveItems retrieves active items with pagination
 func (r *PostgresItemRepository) ListActiveItems(ctx context.Context, limit, offset int) ([]*items.Item, error) {
 	query := `
-		SELECT id, title, description, start_price, current_highest_bid, end_at, created_at, updated_at, images, category, seller_id, status
+		SELECT id, title, description, start_price, current_highest_bid, current_highest_bidder_id, reserve_price, buy_now_price, start_at, end_at, extension_count, created_at, updated_at, images, category, seller_id, status
 		FROM items
 		WHERE status = $1 AND end_at > NOW()
 		ORDER BY created_at DESC
@@ -157,13 +179,19 @@ func (r *PostgresItemRepository) ListActiveItems(ctx context.Context, limit, off
 	var result []*items.Item
 	for rows.Next() {
 		var item items.Item
+		var bidderID *uuid.UUID
 		err := rows.Scan(
 			&item.ID,
 			&item.Title,
 			&item.Description,
 			&item.StartPrice,
 			&item.CurrentHighestBid,
+			&bidderID,
+			&item.ReservePrice,
+			&item.BuyNowPrice,
+			&item.StartAt,
 			&item.EndAt,
+			&item.ExtensionCount,
 			&item.CreatedAt,
 			&item.UpdatedAt,
 			&item.Images,
@@ -174,6 +202,7 @@ func (r *PostgresItemRepository) ListActiveItems(ctx context.Context, limit, off
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan item: %w", err)
 		}
+		item.CurrentHighestBidderID = uuidOrNil(bidderID)
 		result = append(result, &item)
 	}
 
@@ -187,7 +216,7 @@ func (r *PostgresItemRepository) ListActiveItems(ctx context.Context, limit, off
 // ListItemsBySellerID retrieves all items for a specific seller
 func (r *PostgresItemRepository) ListItemsBySellerID(ctx context.Context, sellerID uuid.UUID, limit, offset int) ([]*items.Item, error) {
 	query := `
-		SELECT id, title, description, start_price, current_highest_bid, end_at, created_at, updated_at, images, category, seller_id, status
+		SELECT id, title, description, start_price, current_highest_bid, current_highest_bidder_id, reserve_price, buy_now_price, start_at, end_at, extension_count, created_at, updated_at, images, category, seller_id, status
 		FROM items
 		WHERE seller_id = $1
 		ORDER BY created_at DESC
@@ -202,13 +231,19 @@ func (r *PostgresItemRepository) ListItemsBySellerID(ctx context.Context, seller
 	var result []*items.Item
 	for rows.Next() {
 		var item items.Item
+		var bidderID *uuid.UUID
 		err := rows.Scan(
 			&item.ID,
 			&item.Title,
 			&item.Description,
 			&item.StartPrice,
 			&item.CurrentHighestBid,
+			&bidderID,
+			&item.ReservePrice,
+			&item.BuyNowPrice,
+			&item.StartAt,
 			&item.EndAt,
+			&item.ExtensionCount,
 			&item.CreatedAt,
 			&item.UpdatedAt,
 			&item.Images,
@@ -219,6 +254,7 @@ func (r *PostgresItemRepository) ListItemsBySellerID(ctx context.Context, seller
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan item: %w", err)
 		}
+		item.CurrentHighestBidderID = uuidOrNil(bidderID)
 		result = append(result, &item)
 	}
 
@@ -229,6 +265,86 @@ func (r *PostgresItemRepository) ListItemsBySellerID(ctx context.Context, seller
 	return result, nil
 }
 
+// SearchItems returns a page of items matching the full-text query, category and status, along
+// with the total number of matches. Empty filters match every item. Text matches are ordered by
+// relevance, and otherwise by end time.
+func (r *PostgresItemRepository) SearchItems(ctx context.Context, query, category string, status items.ItemStatus, limit, offset int) ([]*items.Item, int64, error) {
+	var conditions []string
+	var args []any
+	orderBy := "end_at ASC, id"
+	if query != "" {
+		args = append(args, query)
+		conditions = append(conditions, "search_vector @@ websearch_to_tsquery('english', $1)")
+		orderBy = "ts_rank(search_vector, websearch_to_tsquery('english', $1)) DESC, " + orderBy
+	}
+	if category != "" {
+		args = append(args, category)
+		conditions = append(conditions, fmt.Sprintf("category = $%d", len(args)))
+	}
+	if status != "" {
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM items "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count items: %w", err)
+	}
+
+	searchQuery := fmt.Sprintf(`
+		SELECT id, title, description, start_price, current_highest_bid, current_highest_bidder_id, reserve_price, buy_now_price, start_at, end_at, extension_count, created_at, updated_at, images, category, seller_id, status
+		FROM items
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, where, orderBy, len(args)+1, len(args)+2)
+	rows, err := r.pool.Query(ctx, searchQuery, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search items: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*items.Item
+	for rows.Next() {
+		var item items.Item
+		var bidderID *uuid.UUID
+		err := rows.Scan(
+			&item.ID,
+			&item.Title,
+			&item.Description,
+			&item.StartPrice,
+			&item.CurrentHighestBid,
+			&bidderID,
+			&item.ReservePrice,
+			&item.BuyNowPrice,
+			&item.StartAt,
+			&item.EndAt,
+			&item.ExtensionCount,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&item.Images,
+			&item.Category,
+			&item.SellerID,
+			&item.Status,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan item: %w", err)
+		}
+		item.CurrentHighestBidderID = uuidOrNil(bidderID)
+		result = append(result, &item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, total, nil
+}
+
 // CountBidsByItemID returns the number of bids for a specific item
 func (r *PostgresItemRepository) CountBidsByItemID(ctx context.Context, itemID uuid.UUID) (int64, error) {
 	query := `SELECT COUNT(*) FROM bids WHERE item_id = $1`
@@ -240,21 +356,106 @@ func (r *PostgresItemRepository) CountBidsByItemID(ctx context.Context, itemID u
 	return count, nil
 }
 
-// UpdateHighestBid updates the current highest bid for an item within a transaction
-func (r *PostgresItemRepository) UpdateHighestBid(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, amount int64) error {
+// ActivateDueItems moves scheduled items whose start time has passed to active
+func (r *PostgresItemRepository) ActivateDueItems(ctx context.Context) (int64, error) {
 	query := `
 		UPDATE items
-		SET current_highest_bid = $1, updated_at = NOW()
+		SET status = $1, updated_at = NOW()
+		WHERE status = $2 AND start_at <= NOW()
+	`
+	result, err := r.pool.Exec(ctx, query, items.ItemStatusActive, items.ItemStatusScheduled)
+	if err != nil {
+		return 0, fmt.Errorf("failed to activate due items: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// ListItemIDsDueToEnd returns up to limit active items whose end time has passed, oldest first
+func (r *PostgresItemRepository) ListItemIDsDueToEnd(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM items
+		WHERE status = $1 AND end_at <= NOW()
+		ORDER BY end_at
+		LIMIT $2
+	`
+	rows, err := r.pool.Query(ctx, query, items.ItemStatusActive, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items due to end: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan item id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ExtendEndAt moves an item's end time and counts the extension within a transaction
+func (r *PostgresItemRepository) ExtendEndAt(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, endAt time.Time) error {
+	query := `
+		UPDATE items
+		SET end_at = $1, extension_count = extension_count + 1, updated_at = NOW()
 		WHERE id = $2
 	`
-	result, err := tx.Exec(ctx, query, amount, itemID)
+	result, err := tx.Exec(ctx, query, endAt, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to extend end time: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return items.ErrItemNotFound
+	}
+
+	return nil
+}
+
+// UpdateHighestBid updates the current highest bid and its bidder for an item within a transaction,
+// provided the highest bid is still expected. A uuid.Nil bidder clears it.
+// It fails with pkgdb.ErrConflict if another transaction changed the highest bid first.
+func (r *PostgresItemRepository) UpdateHighestBid(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, expected, amount int64, bidderID uuid.UUID) error {
+	query := `
+		UPDATE items
+		SET current_highest_bid = $1, current_highest_bidder_id = $2, updated_at = NOW()
+		WHERE id = $3 AND current_highest_bid = $4
+	`
+	var bidder *uuid.UUID
+	if bidderID != uuid.Nil {
+		bidder = &bidderID
+	}
+	result, err := tx.Exec(ctx, query, amount, bidder, itemID, expected)
 	if err != nil {
 		return fmt.Errorf("failed to update highest bid: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("item not found")
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM items WHERE id = $1)", itemID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check item: %w", err)
+		}
+		if !exists {
+			return items.ErrItemNotFound
+		}
+		return pkgdb.ErrConflict
 	}
 
 	return nil
 }
+
+// uuidOrNil maps a nullable UUID column to uuid.Nil when NULL
+func uuidOrNil(id *uuid.UUID) uuid.UUID {
+	if id == nil {
+		return uuid.Nil
+	}
+	return *id
+}
@@ -25,13 +25,20 @@ func NewPostgresOutboxRepository(pool *pgxpool.Pool) *PostgresOutboxRepository {
 // SaveEvent saves an outbox event within a transaction
 func (r *PostgresOutboxRepository) SaveEvent(ctx context.Context, tx pgx.Tx, event *pkgevents.OutboxEvent) error {
 	query := `
-		INSERT INTO outbox_events (id, event_type, payload, status, created_at)
-		VALUES ($1, $2, $3, $4::outbox_status, $5)
+		INSERT INTO outbox_events (id, aggregate_id, event_type, payload, headers, priority, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::outbox_status, $8)
 	`
+	var aggregateID *uuid.UUID
+	if event.AggregateID != uuid.Nil {
+		aggregateID = &event.AggregateID
+	}
 	_, err := tx.Exec(ctx, query,
 		event.ID,
+		aggregateID,
 		event.EventType,
 		event.Payload,
+		event.Headers,
+		int16(event.Priority),
 		event.Status,
 		event.CreatedAt,
 	)
@@ -41,16 +48,35 @@ func (r *PostgresOutboxRepository) SaveEvent(ctx context.Context, tx pgx.Tx, eve
 	return nil
 }
 
-// GetPendingEvents retrieves pending events for processing
-// Uses SELECT FOR UPDATE SKIP LOCKED to prevent multiple workers from processing the same event
+// GetPendingEvents claims up to limit pending events for the lifetime of tx, in write order.
+// Uses SELECT FOR UPDATE SKIP LOCKED to prevent multiple workers from processing the same event:
+// a worker claims the oldest pending event of up to limit aggregates, along with the events queued
+// behind each, so an aggregate is only ever being published by one worker at a time. Of those, the
+// limit oldest are returned, which leaves each aggregate's events in order; the aggregates whose
+// events all fall beyond the limit stay locked until tx ends, and are claimed in a later batch.
 func (r *PostgresOutboxRepository) GetPendingEvents(ctx context.Context, tx pgx.Tx, limit int) ([]*pkgevents.OutboxEvent, error) {
 	query := `
-		SELECT id, event_type, payload, status, created_at, processed_at
+		WITH heads AS (
+			SELECT id, aggregate_id
+			FROM outbox_events e
+			WHERE status = $1::outbox_status
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_events earlier
+				WHERE earlier.aggregate_id = e.aggregate_id
+				AND earlier.status = $1::outbox_status
+				AND earlier.seq < e.seq
+			)
+			ORDER BY seq
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		SELECT id, aggregate_id, event_type, payload, headers, priority, status, created_at, processed_at
 		FROM outbox_events
-		WHERE status = $1::outbox_status
-		ORDER BY created_at ASC
+		WHERE id IN (SELECT id FROM heads)
+		OR (status = $1::outbox_status AND aggregate_id IN (SELECT aggregate_id FROM heads))
+		ORDER BY seq
 		LIMIT $2
-		FOR UPDATE SKIP LOCKED
+		FOR UPDATE
 	`
 
 	rows, err := tx.Query(ctx, query, pkgevents.OutboxStatusPending, limit)
@@ -62,19 +88,28 @@ func (r *PostgresOutboxRepository) GetPendingEvents(ctx context.Context, tx pgx.
 	var events []*pkgevents.OutboxEvent
 	for rows.Next() {
 		var event pkgevents.OutboxEvent
+		var aggregateID *uuid.UUID
+		var priority int16
 		if err := rows.Scan(
 			&event.ID,
+			&aggregateID,
 			&event.EventType,
 			&event.Payload,
+			&event.Headers,
+			&priority,
 			&event.Status,
 			&event.CreatedAt,
 			&event.ProcessedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
+		if aggregateID != nil {
+			event.AggregateID = *aggregateID
+		}
+		event.Priority = uint8(priority)
 		events = append(events, &event)
 	}
-	return events, nil
+	return events, rows.Err()
 }
 
 // UpdateEventStatus updates the status of an event
@@ -102,3 +137,16 @@ func (r *PostgresOutboxRepository) UpdateEventStatus(ctx context.Context, tx pgx
 
 	return nil
 }
+
+// DeletePublishedBefore deletes events published before cutoff, returning how many were removed
+func (r *PostgresOutboxRepository) DeletePublishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		DELETE FROM outbox_events
+		WHERE status = $1::outbox_status AND processed_at < $2
+	`
+	result, err := r.pool.Exec(ctx, query, pkgevents.OutboxStatusPublished, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete published events: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -91,4 +91,134 @@ func TestOutboxRepository_Integration(t *testing.T) {
 		assert.Equal(t, string(events.OutboxStatusPublished), status)
 		assert.NotNil(t, processedAt)
 	})
+
+	saveEvents := func(t *testing.T, evts ...*events.OutboxEvent) {
+		t.Helper()
+		tx, err := td.Pool.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+		for _, event := range evts {
+			require.NoError(t, repo.SaveEvent(ctx, tx, event))
+		}
+		require.NoError(t, tx.Commit(ctx))
+	}
+	newEvent := func(aggregateID uuid.UUID) *events.OutboxEvent {
+		return &events.OutboxEvent{
+			ID:          uuid.New(),
+			AggregateID: aggregateID,
+			EventType:   "bid.placed",
+			Payload:     []byte(`{}`),
+			Status:      events.OutboxStatusPending,
+			CreatedAt:   time.Now().UTC(),
+		}
+	}
+	ids := func(evts []*events.OutboxEvent) []uuid.UUID {
+		var result []uuid.UUID
+		for _, event := range evts {
+			result = append(result, event.ID)
+		}
+		return result
+	}
+
+	t.Run("GetPendingEvents_ClaimsAggregatesOnce", func(t *testing.T) {
+		_, err := td.Pool.Exec(ctx, "DELETE FROM outbox_events")
+		require.NoError(t, err)
+
+		itemA, itemB := uuid.New(), uuid.New()
+		a1, b1, a2, unordered := newEvent(itemA), newEvent(itemB), newEvent(itemA), newEvent(uuid.Nil)
+		saveEvents(t, a1, b1, a2, unordered)
+
+		// The first worker claims item A, and is handed its head as the limit allows one event
+		first, err := td.Pool.Begin(ctx)
+		require.NoError(t, err)
+		defer first.Rollback(ctx)
+		claimed, err := repo.GetPendingEvents(ctx, first, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{a1.ID}, ids(claimed))
+		assert.Equal(t, itemA, claimed[0].AggregateID)
+
+		// A concurrent worker skips item A entirely rather than publishing a2 early
+		second, err := td.Pool.Begin(ctx)
+		require.NoError(t, err)
+		defer second.Rollback(ctx)
+		claimed, err = repo.GetPendingEvents(ctx, second, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{b1.ID, unordered.ID}, ids(claimed))
+		assert.Equal(t, uuid.Nil, claimed[1].AggregateID)
+	})
+
+	t.Run("GetPendingEvents_LimitsTotalEvents", func(t *testing.T) {
+		_, err := td.Pool.Exec(ctx, "DELETE FROM outbox_events")
+		require.NoError(t, err)
+
+		itemA, itemB := uuid.New(), uuid.New()
+		a1, a2, b1, a3, b2 := newEvent(itemA), newEvent(itemA), newEvent(itemB), newEvent(itemA), newEvent(itemB)
+		saveEvents(t, a1, a2, b1, a3, b2)
+
+		tx, err := td.Pool.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+		claimed, err := repo.GetPendingEvents(ctx, tx, 2)
+		require.NoError(t, err)
+		// Both items are claimed, but only the oldest two events are handed out
+		assert.Equal(t, []uuid.UUID{a1.ID, a2.ID}, ids(claimed))
+		require.NoError(t, tx.Commit(ctx))
+
+		// With those published, the next batch carries on where each item left off
+		for _, event := range claimed {
+			_, err := td.Pool.Exec(ctx, "UPDATE outbox_events SET status = 'published' WHERE id = $1", event.ID)
+			require.NoError(t, err)
+		}
+		tx, err = td.Pool.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+		claimed, err = repo.GetPendingEvents(ctx, tx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{b1.ID, a3.ID}, ids(claimed))
+	})
+
+	t.Run("GetPendingEvents_ReturnsHeaders", func(t *testing.T) {
+		_, err := td.Pool.Exec(ctx, "DELETE FROM outbox_events")
+		require.NoError(t, err)
+
+		traced, untraced := newEvent(uuid.New()), newEvent(uuid.New())
+		traced.Headers = map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+		saveEvents(t, traced, untraced)
+
+		tx, err := td.Pool.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+		claimed, err := repo.GetPendingEvents(ctx, tx, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 2)
+		assert.Equal(t, traced.Headers, claimed[0].Headers)
+		assert.Nil(t, claimed[1].Headers)
+	})
+
+	t.Run("DeletePublishedBefore_KeepsRecentAndPending", func(t *testing.T) {
+		_, err := td.Pool.Exec(ctx, "DELETE FROM outbox_events")
+		require.NoError(t, err)
+
+		old, recent, pending := newEvent(uuid.Nil), newEvent(uuid.Nil), newEvent(uuid.Nil)
+		saveEvents(t, old, recent, pending)
+		_, err = td.Pool.Exec(ctx, "UPDATE outbox_events SET status = 'published', processed_at = NOW() - INTERVAL '8 days' WHERE id = $1", old.ID)
+		require.NoError(t, err)
+		_, err = td.Pool.Exec(ctx, "UPDATE outbox_events SET status = 'published', processed_at = NOW() WHERE id = $1", recent.ID)
+		require.NoError(t, err)
+
+		deleted, err := repo.DeletePublishedBefore(ctx, time.Now().Add(-events.DefaultOutboxRetention))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		var remaining []uuid.UUID
+		rows, err := td.Pool.Query(ctx, "SELECT id FROM outbox_events ORDER BY seq")
+		require.NoError(t, err)
+		for rows.Next() {
+			var id uuid.UUID
+			require.NoError(t, rows.Scan(&id))
+			remaining = append(remaining, id)
+		}
+		require.NoError(t, rows.Err())
+		assert.Equal(t, []uuid.UUID{recent.ID, pending.ID}, remaining)
+	})
 }
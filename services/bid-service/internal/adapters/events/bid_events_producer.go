@@ -21,8 +21,13 @@ type BidEventsProducer struct {
 }
 
 // NewBidEventsProducer creates a new producer
-func NewBidEventsProducer(pool *pgxpool.Pool, conn *amqp.Connection, logger *slog.Logger) (*BidEventsProducer, error) {
-	publisher, err := pkgevents.NewRabbitMQPublisher(conn)
+func NewBidEventsProducer(
+	pool *pgxpool.Pool,
+	conn *amqp.Connection,
+	logger *slog.Logger,
+	opts ...pkgevents.PublisherOption,
+) (*BidEventsProducer, error) {
+	publisher, err := pkgevents.NewRabbitMQPublisher(conn, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create publisher: %w", err)
 	}
@@ -36,7 +41,7 @@ func NewBidEventsProducer(pool *pgxpool.Pool, conn *amqp.Connection, logger *slo
 		txManager,
 		10,                   // Batch size
 		500*time.Millisecond, // Polling interval
-		"auction.events",     // exchange
+		pkgevents.Exchange,   // exchange
 		logger,
 	)
 
@@ -0,0 +1,133 @@
+package events_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/rabbitmq"
+	"google.golang.org/protobuf/proto"
+
+	pkgdb "github.com/floroz/gavel/pkg/database"
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	pb "github.com/floroz/gavel/pkg/proto"
+	"github.com/floroz/gavel/pkg/testhelpers"
+	"github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/adapters/events"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
+)
+
+// TestOutbidEventPublished places bids through the AuctionService and checks the relay
+// publishes a single user.outbid message addressed to the displaced leader
+func TestOutbidEventPublished(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	rabbitmqContainer, err := rabbitmq.Run(ctx,
+		"rabbitmq:3.12-management-alpine",
+		rabbitmq.WithAdminPassword("password"),
+	)
+	require.NoError(t, err)
+	defer func() {
+		if termErr := rabbitmqContainer.Terminate(ctx); termErr != nil {
+			t.Fatalf("failed to terminate container: %s", termErr)
+		}
+	}()
+
+	amqpURL, err := rabbitmqContainer.AmqpURL(ctx)
+	require.NoError(t, err)
+
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+	dbPool := testDB.Pool
+
+	conn, err := amqp.Dial(amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	producer, err := events.NewBidEventsProducer(dbPool, conn, logger)
+	require.NoError(t, err)
+	defer producer.Close()
+
+	// Bind before any event exists so nothing is missed
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+	defer ch.Close()
+
+	q, err := ch.QueueDeclare("", false, false, true, false, nil)
+	require.NoError(t, err)
+	require.NoError(t, ch.QueueBind(q.Name, bids.EventTypeUserOutbid.String(), "auction.events", false, nil))
+
+	msgs, err := ch.Consume(q.Name, "", true, false, false, false, nil)
+	require.NoError(t, err)
+
+	ctxProducer, cancelProducer := context.WithCancel(ctx)
+	defer cancelProducer()
+	go func() {
+		_ = producer.Run(ctxProducer)
+	}()
+
+	itemRepo := database.NewPostgresItemRepository(dbPool)
+	service := bids.NewAuctionService(
+		pkgdb.NewPostgresTransactionManager(dbPool, 5*time.Second),
+		database.NewPostgresBidRepository(dbPool),
+		itemRepo,
+		database.NewPostgresOutboxRepository(dbPool),
+	)
+
+	item := &items.Item{
+		ID:         uuid.New(),
+		Title:      "Outbid Item",
+		StartPrice: 100,
+		StartAt:    time.Now(),
+		EndAt:      time.Now().Add(time.Hour),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		Images:     []string{},
+		Category:   "test",
+		SellerID:   uuid.New(),
+		Status:     items.ItemStatusActive,
+	}
+	require.NoError(t, itemRepo.CreateItem(ctx, item))
+
+	first, second := uuid.New(), uuid.New()
+	_, err = service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: first, Amount: 200})
+	require.NoError(t, err)
+	_, err = service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: second, Amount: 300})
+	require.NoError(t, err)
+
+	select {
+	case msg := <-msgs:
+		var event pb.UserOutbid
+		require.NoError(t, proto.Unmarshal(msg.Body, &event))
+		assert.Equal(t, first.String(), event.UserId)
+		assert.Equal(t, item.ID.String(), event.ItemId)
+		assert.Equal(t, int64(300), event.Amount)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for outbid event")
+	}
+
+	// Once the relay has drained the outbox, no second outbid event may follow
+	require.Eventually(t, func() bool {
+		var pending int
+		scanErr := dbPool.QueryRow(ctx, "SELECT COUNT(*) FROM outbox_events WHERE status = $1", pkgevents.OutboxStatusPending).Scan(&pending)
+		return scanErr == nil && pending == 0
+	}, 5*time.Second, 100*time.Millisecond, "Outbox should be drained")
+
+	select {
+	case msg := <-msgs:
+		t.Fatalf("unexpected extra outbid event: %s", msg.Body)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
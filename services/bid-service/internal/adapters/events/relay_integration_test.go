@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -137,3 +138,204 @@ func TestRelayIntegrationWithRabbitMQ(t *testing.T) {
 		return status == string(pkgevents.OutboxStatusPublished)
 	}, 2*time.Second, 100*time.Millisecond, "Event status should be updated to 'published'")
 }
+
+// recordingPublisher remembers the payloads published to it, in order
+type recordingPublisher struct {
+	mu       sync.Mutex
+	payloads []string
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, _, _ string, body []byte, _ ...pkgevents.PublishOption) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.payloads = append(p.payloads, string(body))
+	return nil
+}
+
+func (p *recordingPublisher) published() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.payloads...)
+}
+
+// TestConcurrentRelaysPublishEachEventOnce runs two relays against one outbox
+func TestConcurrentRelaysPublishEachEventOnce(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+	dbPool := testDB.Pool
+
+	outboxRepo := database.NewPostgresOutboxRepository(dbPool)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// Interleave events for a handful of items; each payload is the event id
+	const itemCount, eventsPerItem = 5, 20
+	itemIDs := make([]uuid.UUID, itemCount)
+	for i := range itemIDs {
+		itemIDs[i] = uuid.New()
+	}
+	aggregateOf := make(map[string]uuid.UUID)
+	expected := make(map[uuid.UUID][]string)
+
+	tx, err := dbPool.Begin(ctx)
+	require.NoError(t, err)
+	for n := 0; n < eventsPerItem; n++ {
+		for _, itemID := range itemIDs {
+			event := &pkgevents.OutboxEvent{
+				ID:          uuid.New(),
+				AggregateID: itemID,
+				EventType:   bids.EventTypeBidPlaced.String(),
+				Status:      pkgevents.OutboxStatusPending,
+				CreatedAt:   time.Now(),
+			}
+			event.Payload = []byte(event.ID.String())
+			require.NoError(t, outboxRepo.SaveEvent(ctx, tx, event))
+			aggregateOf[event.ID.String()] = itemID
+			expected[itemID] = append(expected[itemID], event.ID.String())
+		}
+	}
+	require.NoError(t, tx.Commit(ctx))
+
+	publisher := &recordingPublisher{}
+	ctxRelay, cancelRelay := context.WithCancel(ctx)
+	defer cancelRelay()
+
+	var wg sync.WaitGroup
+	for range 2 {
+		relay := pkgevents.NewOutboxRelay(
+			outboxRepo,
+			publisher,
+			pkgdb.NewPostgresTransactionManager(dbPool, time.Second),
+			2,
+			time.Millisecond,
+			"auction.events",
+			logger,
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = relay.Run(ctxRelay)
+		}()
+	}
+
+	require.Eventually(t, func() bool {
+		var pending int
+		scanErr := dbPool.QueryRow(ctx, "SELECT COUNT(*) FROM outbox_events WHERE status = 'pending'").Scan(&pending)
+		return scanErr == nil && pending == 0
+	}, 10*time.Second, 20*time.Millisecond, "All events should be published")
+	cancelRelay()
+	wg.Wait()
+
+	// Every event is published exactly once, and each item's events in the order they were saved
+	published := publisher.published()
+	require.Len(t, published, itemCount*eventsPerItem)
+
+	got := make(map[uuid.UUID][]string)
+	for _, payload := range published {
+		itemID, ok := aggregateOf[payload]
+		require.True(t, ok, "unexpected payload %q", payload)
+		got[itemID] = append(got[itemID], payload)
+	}
+	assert.Equal(t, expected, got)
+}
+
+// TestRelayPublishesUrgentBidsAhead relays a backlog of bids and one near an auction's close
+// into a priority queue, which delivers the urgent bid first
+func TestRelayPublishesUrgentBidsAhead(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	rabbitmqContainer, err := rabbitmq.Run(ctx,
+		"rabbitmq:3.12-management-alpine",
+		rabbitmq.WithAdminPassword("password"),
+	)
+	require.NoError(t, err)
+	defer func() {
+		if termErr := rabbitmqContainer.Terminate(ctx); termErr != nil {
+			t.Fatalf("failed to terminate container: %s", termErr)
+		}
+	}()
+	amqpURL, err := rabbitmqContainer.AmqpURL(ctx)
+	require.NoError(t, err)
+
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+	dbPool := testDB.Pool
+
+	conn, err := amqp091.Dial(amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	const queue = "test_urgent_bids"
+	require.NoError(t, pkgevents.DeclareTopology(conn, pkgevents.Topology{Queues: []pkgevents.QueueSpec{{
+		Name:        queue,
+		RoutingKeys: []string{pkgevents.RoutingKeyBidPlaced},
+		MaxRetries:  3,
+		MaxPriority: pkgevents.PriorityHigh,
+	}}}))
+
+	// The urgent bid is saved, and so published, after the backlog
+	outboxRepo := database.NewPostgresOutboxRepository(dbPool)
+	tx, err := dbPool.Begin(ctx)
+	require.NoError(t, err)
+	for _, bid := range []struct {
+		payload  string
+		priority uint8
+	}{
+		{"normal-1", pkgevents.PriorityNormal},
+		{"normal-2", pkgevents.PriorityNormal},
+		{"urgent", pkgevents.PriorityHigh},
+	} {
+		require.NoError(t, outboxRepo.SaveEvent(ctx, tx, &pkgevents.OutboxEvent{
+			ID:          uuid.New(),
+			AggregateID: uuid.New(),
+			EventType:   bids.EventTypeBidPlaced.String(),
+			Payload:     []byte(bid.payload),
+			Status:      pkgevents.OutboxStatusPending,
+			CreatedAt:   time.Now(),
+			Priority:    bid.priority,
+		}))
+	}
+	require.NoError(t, tx.Commit(ctx))
+
+	publisher, err := pkgevents.NewRabbitMQPublisher(conn)
+	require.NoError(t, err)
+	defer publisher.Close()
+	relay := pkgevents.NewOutboxRelay(
+		outboxRepo,
+		publisher,
+		pkgdb.NewPostgresTransactionManager(dbPool, time.Second),
+		10,
+		50*time.Millisecond,
+		pkgevents.Exchange,
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+	)
+	ctxRelay, cancelRelay := context.WithCancel(ctx)
+	defer cancelRelay()
+	go func() {
+		_ = relay.Run(ctxRelay)
+	}()
+
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+	defer ch.Close()
+	require.Eventually(t, func() bool {
+		q, err := ch.QueueDeclarePassive(queue, true, false, false, false, nil)
+		return err == nil && q.Messages == 3
+	}, 10*time.Second, 50*time.Millisecond, "the whole backlog should be relayed")
+
+	var got []string
+	for range 3 {
+		d, ok, err := ch.Get(queue, true)
+		require.NoError(t, err)
+		require.True(t, ok)
+		got = append(got, string(d.Body))
+	}
+	assert.Equal(t, []string{"urgent", "normal-1", "normal-2"}, got)
+}
@@ -0,0 +1,189 @@
+// Package config loads the bid service binaries' settings from the environment. Every
+// setting is read and checked up front, so a misconfigured deployment reports all of its
+// problems at once instead of failing on the first.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/floroz/gavel/pkg/auth"
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+)
+
+// DefaultSchedulerInterval is how often the worker opens and closes due auctions
+const DefaultSchedulerInterval = 30 * time.Second
+
+// ErrMissing is returned, listing the variables, when required settings are not set
+var ErrMissing = errors.New("missing required settings")
+
+// API is the configuration of the bid API (cmd/api)
+type API struct {
+	DatabaseURL  string         // BID_DB_URL
+	ReplicaURLs  []string       // BID_DB_REPLICA_URLS, comma-separated; reads go to the primary without them
+	JWTPublicKey auth.KeySource // JWT_PUBLIC_KEY_PATH or JWT_PUBLIC_KEY (base64 PEM)
+	JWTIssuer    string         // JWT_ISSUER
+	RabbitMQURL  string         // RABBITMQ_URL
+	// RedisURL is the Redis address reported on by the readiness check; it is optional
+	RedisURL string
+	// MinIncrement is read from BID_MIN_INCREMENT_CENTS and BID_MIN_INCREMENT_BPS (basis points
+	// of the current highest bid). Unset means no minimum.
+	MinIncrement bids.BidIncrement
+	MaxBidAmount int64 // BID_MAX_AMOUNT_CENTS
+	// AntiSnipe is read from BID_ANTI_SNIPE_WINDOW and BID_ANTI_SNIPE_EXTENSION (Go durations,
+	// e.g. 60s) and BID_ANTI_SNIPE_MAX_EXTENSIONS. Unset means auctions are never extended.
+	AntiSnipe bids.AntiSnipePolicy
+	// UrgentBidWindow is how close to its end an auction's bids are published with high
+	// priority, ahead of any backlog; 0 turns that off
+	UrgentBidWindow time.Duration // BID_URGENT_BID_WINDOW
+}
+
+// Worker is the configuration of the bid outbox worker and auction scheduler (cmd/worker)
+type Worker struct {
+	DatabaseURL       string        // BID_DB_URL
+	RabbitMQURL       string        // RABBITMQ_URL
+	SchedulerInterval time.Duration // BID_SCHEDULER_INTERVAL
+	OutboxRetention   time.Duration // BID_OUTBOX_RETENTION
+}
+
+// LoadAPI reads the API's settings, falling back to defaults for optional ones
+func LoadAPI() (*API, error) {
+	var l loader
+	cfg := &API{
+		DatabaseURL:  l.required("BID_DB_URL"),
+		ReplicaURLs:  l.list("BID_DB_REPLICA_URLS"),
+		JWTPublicKey: l.key("JWT_PUBLIC_KEY"),
+		JWTIssuer:    l.required("JWT_ISSUER"),
+		RabbitMQURL:  l.required("RABBITMQ_URL"),
+		RedisURL:     os.Getenv("REDIS_URL"),
+		MinIncrement: bids.BidIncrement{
+			Absolute:    l.nonNegativeInt64("BID_MIN_INCREMENT_CENTS"),
+			BasisPoints: l.nonNegativeInt64("BID_MIN_INCREMENT_BPS"),
+		},
+		MaxBidAmount: l.maxBidAmount("BID_MAX_AMOUNT_CENTS"),
+		AntiSnipe: bids.AntiSnipePolicy{
+			Window:        l.nonNegativeDuration("BID_ANTI_SNIPE_WINDOW", 0),
+			Extension:     l.nonNegativeDuration("BID_ANTI_SNIPE_EXTENSION", 0),
+			MaxExtensions: int(l.nonNegativeInt64("BID_ANTI_SNIPE_MAX_EXTENSIONS")),
+		},
+		UrgentBidWindow: l.nonNegativeDuration("BID_URGENT_BID_WINDOW", bids.DefaultUrgentBidWindow),
+	}
+	if err := l.err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadWorker reads the worker's settings, falling back to defaults for optional ones
+func LoadWorker() (*Worker, error) {
+	var l loader
+	cfg := &Worker{
+		DatabaseURL:       l.required("BID_DB_URL"),
+		RabbitMQURL:       l.required("RABBITMQ_URL"),
+		SchedulerInterval: l.positiveDuration("BID_SCHEDULER_INTERVAL", DefaultSchedulerInterval),
+		OutboxRetention:   l.positiveDuration("BID_OUTBOX_RETENTION", pkgevents.DefaultOutboxRetention),
+	}
+	if err := l.err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loader collects every missing and invalid setting it is asked for
+type loader struct {
+	missing []string
+	invalid []error
+}
+
+func (l *loader) required(key string) string {
+	v := os.Getenv(key)
+	if v == "" {
+		l.missing = append(l.missing, key)
+	}
+	return v
+}
+
+// key reads a required key from <name>_PATH or <name>, see auth.KeySourceFromEnv
+func (l *loader) key(name string) auth.KeySource {
+	source := auth.KeySourceFromEnv(name)
+	if !source.IsSet() {
+		l.missing = append(l.missing, name+"_PATH or "+name)
+	}
+	return source
+}
+
+// list splits a comma-separated setting, or returns nil if it is unset
+func (l *loader) list(key string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+func (l *loader) nonNegativeInt64(key string) int64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid %s: %q", key, v))
+		return 0
+	}
+	return n
+}
+
+// maxBidAmount reads a cap on bids in cents, which may only lower bids.DefaultMaxBidAmount
+func (l *loader) maxBidAmount(key string) int64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return bids.DefaultMaxBidAmount
+	}
+	cents, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || cents <= 0 || cents > bids.DefaultMaxBidAmount {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid %s: %q", key, v))
+		return bids.DefaultMaxBidAmount
+	}
+	return cents
+}
+
+func (l *loader) positiveDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid %s: %q", key, v))
+		return def
+	}
+	return d
+}
+
+func (l *loader) nonNegativeDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid %s: %q", key, v))
+		return def
+	}
+	return d
+}
+
+// err reports everything missing as one ErrMissing, along with every invalid value
+func (l *loader) err() error {
+	var errs []error
+	if len(l.missing) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrMissing, strings.Join(l.missing, ", ")))
+	}
+	return errors.Join(append(errs, l.invalid...)...)
+}
@@ -0,0 +1,128 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/auth"
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/services/bid-service/internal/config"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+)
+
+// setEnv replaces every setting the loaders read with env, leaving the rest unset
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range []string{
+		"BID_DB_URL", "BID_DB_REPLICA_URLS", "RABBITMQ_URL", "REDIS_URL", "JWT_PUBLIC_KEY_PATH", "JWT_PUBLIC_KEY", "JWT_ISSUER",
+		"BID_MIN_INCREMENT_CENTS", "BID_MIN_INCREMENT_BPS", "BID_MAX_AMOUNT_CENTS",
+		"BID_ANTI_SNIPE_WINDOW", "BID_ANTI_SNIPE_EXTENSION", "BID_ANTI_SNIPE_MAX_EXTENSIONS",
+		"BID_URGENT_BID_WINDOW", "BID_SCHEDULER_INTERVAL", "BID_OUTBOX_RETENTION",
+	} {
+		t.Setenv(key, env[key])
+	}
+}
+
+func TestLoadAPI(t *testing.T) {
+	t.Run("complete environment", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"BID_DB_URL":                    "postgres://localhost/bid_db",
+			"BID_DB_REPLICA_URLS":           "postgres://replica1/bid_db,postgres://replica2/bid_db",
+			"JWT_PUBLIC_KEY_PATH":           "/keys/public.pem",
+			"JWT_ISSUER":                    "gavel-auth",
+			"RABBITMQ_URL":                  "amqp://localhost:5672/",
+			"REDIS_URL":                     "localhost:6379",
+			"BID_MIN_INCREMENT_CENTS":       "100",
+			"BID_MIN_INCREMENT_BPS":         "500",
+			"BID_MAX_AMOUNT_CENTS":          "1000000",
+			"BID_ANTI_SNIPE_WINDOW":         "2m",
+			"BID_ANTI_SNIPE_EXTENSION":      "1m",
+			"BID_ANTI_SNIPE_MAX_EXTENSIONS": "3",
+			"BID_URGENT_BID_WINDOW":         "0s",
+		})
+
+		cfg, err := config.LoadAPI()
+		require.NoError(t, err)
+		assert.Equal(t, &config.API{
+			DatabaseURL:  "postgres://localhost/bid_db",
+			ReplicaURLs:  []string{"postgres://replica1/bid_db", "postgres://replica2/bid_db"},
+			JWTPublicKey: auth.KeySource{Path: "/keys/public.pem"},
+			JWTIssuer:    "gavel-auth",
+			RabbitMQURL:  "amqp://localhost:5672/",
+			RedisURL:     "localhost:6379",
+			MinIncrement: bids.BidIncrement{Absolute: 100, BasisPoints: 500},
+			MaxBidAmount: 1000000,
+			AntiSnipe:    bids.AntiSnipePolicy{Window: 2 * time.Minute, Extension: time.Minute, MaxExtensions: 3},
+		}, cfg)
+	})
+
+	t.Run("optional settings default", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"BID_DB_URL":     "postgres://localhost/bid_db",
+			"JWT_PUBLIC_KEY": "cHVibGljIGtleQ==",
+			"JWT_ISSUER":     "gavel-auth",
+			"RABBITMQ_URL":   "amqp://localhost:5672/",
+		})
+
+		cfg, err := config.LoadAPI()
+		require.NoError(t, err)
+		assert.Empty(t, cfg.ReplicaURLs)
+		assert.Empty(t, cfg.RedisURL)
+		assert.Equal(t, bids.BidIncrement{}, cfg.MinIncrement)
+		assert.Equal(t, bids.DefaultMaxBidAmount, cfg.MaxBidAmount)
+		assert.Equal(t, bids.AntiSnipePolicy{}, cfg.AntiSnipe)
+		assert.Equal(t, bids.DefaultUrgentBidWindow, cfg.UrgentBidWindow)
+	})
+
+	t.Run("reports every problem at once", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"JWT_ISSUER":                    "gavel-auth",
+			"BID_MIN_INCREMENT_CENTS":       "-1",
+			"BID_MAX_AMOUNT_CENTS":          "100000000001",
+			"BID_ANTI_SNIPE_WINDOW":         "soon",
+			"BID_ANTI_SNIPE_MAX_EXTENSIONS": "many",
+			"BID_URGENT_BID_WINDOW":         "-1m",
+		})
+
+		_, err := config.LoadAPI()
+		require.ErrorIs(t, err, config.ErrMissing)
+		assert.Contains(t, err.Error(), "missing required settings: BID_DB_URL, JWT_PUBLIC_KEY_PATH or JWT_PUBLIC_KEY, RABBITMQ_URL")
+		assert.Contains(t, err.Error(), `invalid BID_MIN_INCREMENT_CENTS: "-1"`)
+		assert.Contains(t, err.Error(), `invalid BID_MAX_AMOUNT_CENTS: "100000000001"`)
+		assert.Contains(t, err.Error(), `invalid BID_ANTI_SNIPE_WINDOW: "soon"`)
+		assert.Contains(t, err.Error(), `invalid BID_ANTI_SNIPE_MAX_EXTENSIONS: "many"`)
+		assert.Contains(t, err.Error(), `invalid BID_URGENT_BID_WINDOW: "-1m"`)
+	})
+}
+
+func TestLoadWorker(t *testing.T) {
+	t.Run("complete environment", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"BID_DB_URL":             "postgres://localhost/bid_db",
+			"RABBITMQ_URL":           "amqp://localhost:5672/",
+			"BID_SCHEDULER_INTERVAL": "5s",
+		})
+
+		cfg, err := config.LoadWorker()
+		require.NoError(t, err)
+		assert.Equal(t, &config.Worker{
+			DatabaseURL:       "postgres://localhost/bid_db",
+			RabbitMQURL:       "amqp://localhost:5672/",
+			SchedulerInterval: 5 * time.Second,
+			OutboxRetention:   pkgevents.DefaultOutboxRetention,
+		}, cfg)
+	})
+
+	t.Run("reports every problem at once", func(t *testing.T) {
+		setEnv(t, map[string]string{"BID_SCHEDULER_INTERVAL": "0s", "BID_OUTBOX_RETENTION": "soon"})
+
+		_, err := config.LoadWorker()
+		require.ErrorIs(t, err, config.ErrMissing)
+		assert.Contains(t, err.Error(), "missing required settings: BID_DB_URL, RABBITMQ_URL")
+		assert.Contains(t, err.Error(), `invalid BID_SCHEDULER_INTERVAL: "0s"`)
+		assert.Contains(t, err.Error(), `invalid BID_OUTBOX_RETENTION: "soon"`)
+	})
+}
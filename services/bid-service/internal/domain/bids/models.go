@@ -1,9 +1,14 @@
 package bids
 
 import (
+	"encoding/base64"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/floroz/gavel/pkg/events"
 )
 
 // Bid represents an auction bid
@@ -15,11 +20,73 @@ type Bid struct {
 	CreatedAt time.Time `db:"created_at"`
 }
 
+// BidCursor marks a position in an item's bid history, which is ordered newest first.
+// A page continues with the bids strictly older than the cursor; ID breaks ties between
+// bids placed at the same instant.
+type BidCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorFor returns the cursor that continues after bid
+func CursorFor(bid *Bid) *BidCursor {
+	return &BidCursor{CreatedAt: bid.CreatedAt, ID: bid.ID}
+}
+
+// Encode returns the cursor as an opaque page token
+func (c BidCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseBidCursor decodes a page token produced by BidCursor.Encode
+func ParseBidCursor(token string) (*BidCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor BidCursor
+	if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if cursor.ID, err = uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return &cursor, nil
+}
+
+// BidPage is one page of an item's bid history. Next is nil on the last page.
+type BidPage struct {
+	Bids []*Bid
+	Next *BidCursor
+}
+
+// MaxBid is the most a user is willing to pay for an item. The service bids on their
+// behalf, never above MaxAmount. CreatedAt is when the current maximum was set and
+// decides ties between equal maximums.
+type MaxBid struct {
+	ID        uuid.UUID `db:"id"`
+	ItemID    uuid.UUID `db:"item_id"`
+	UserID    uuid.UUID `db:"user_id"`
+	MaxAmount int64     `db:"max_amount"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
 // EventType defines the type of event
 type EventType string
 
 const (
-	EventTypeBidPlaced EventType = "bid.placed"
+	EventTypeBidPlaced     EventType = events.RoutingKeyBidPlaced
+	EventTypeItemPurchased EventType = events.RoutingKeyItemPurchased
+	EventTypeUserOutbid    EventType = events.RoutingKeyUserOutbid
+	EventTypeItemCancelled EventType = events.RoutingKeyItemCancelled
+	EventTypeAuctionEnded  EventType = events.RoutingKeyAuctionEnded
 )
 
 func (e EventType) String() string {
@@ -28,7 +95,7 @@ func (e EventType) String() string {
 
 func (e EventType) IsValid() bool {
 	switch e {
-	case EventTypeBidPlaced:
+	case EventTypeBidPlaced, EventTypeItemPurchased, EventTypeUserOutbid, EventTypeItemCancelled, EventTypeAuctionEnded:
 		return true
 	default:
 		return false
@@ -1,9 +1,13 @@
 package bids
 
 import (
+	"encoding/base64"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestEventType_String tests the String method of EventType
@@ -30,6 +34,26 @@ func TestEventType_IsValid(t *testing.T) {
 			eventType: EventTypeBidPlaced,
 			want:      true,
 		},
+		{
+			name:      "valid event type - item.purchased",
+			eventType: EventTypeItemPurchased,
+			want:      true,
+		},
+		{
+			name:      "valid event type - user.outbid",
+			eventType: EventTypeUserOutbid,
+			want:      true,
+		},
+		{
+			name:      "valid event type - item.cancelled",
+			eventType: EventTypeItemCancelled,
+			want:      true,
+		},
+		{
+			name:      "valid event type - auction.ended",
+			eventType: EventTypeAuctionEnded,
+			want:      true,
+		},
 		{
 			name:      "invalid event type - unknown",
 			eventType: EventType("unknown.event"),
@@ -49,3 +73,34 @@ func TestEventType_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestBidCursor_RoundTrip(t *testing.T) {
+	cursor := BidCursor{
+		CreatedAt: time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	parsed, err := ParseBidCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(parsed.CreatedAt))
+	assert.Equal(t, cursor.ID, parsed.ID)
+}
+
+func TestParseBidCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "not base64", token: "%%%"},
+		{name: "missing separator", token: base64.RawURLEncoding.EncodeToString([]byte("2024-05-01T12:30:00Z"))},
+		{name: "bad timestamp", token: base64.RawURLEncoding.EncodeToString([]byte("yesterday|" + uuid.NewString()))},
+		{name: "bad id", token: base64.RawURLEncoding.EncodeToString([]byte("2024-05-01T12:30:00Z|not-a-uuid"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBidCursor(tt.token)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package bids
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -12,14 +13,28 @@ import (
 
 // BidRepository defines the interface for bid persistence
 type BidRepository interface {
-	// SaveBid saves a bid within a transaction
+	// SaveBid saves a bid within a transaction, setting its CreatedAt to the stored value
 	SaveBid(ctx context.Context, tx pgx.Tx, bid *Bid) error
 
-	// GetBidByID retrieves a bid by its ID
+	// GetBidByID retrieves a bid by its ID, or returns ErrBidNotFound
 	GetBidByID(ctx context.Context, bidID uuid.UUID) (*Bid, error)
 
 	// GetBidsByItemID retrieves all bids for an item
 	GetBidsByItemID(ctx context.Context, itemID uuid.UUID) ([]*Bid, error)
+
+	// ListBids retrieves up to limit bids for an item, newest first, starting after cursor.
+	// A nil cursor starts from the most recent bid.
+	ListBids(ctx context.Context, itemID uuid.UUID, limit int, cursor *BidCursor) ([]*Bid, error)
+
+	// GetHighestBid retrieves the leading bid for an item within a transaction, or nil if there are none.
+	// Among equal amounts the most recent bid leads.
+	GetHighestBid(ctx context.Context, tx pgx.Tx, itemID uuid.UUID) (*Bid, error)
+
+	// SaveMaxBid creates or replaces a user's maximum bid on an item within a transaction
+	SaveMaxBid(ctx context.Context, tx pgx.Tx, maxBid *MaxBid) error
+
+	// GetMaxBidsByItemID retrieves all maximum bids for an item within a transaction
+	GetMaxBidsByItemID(ctx context.Context, tx pgx.Tx, itemID uuid.UUID) ([]*MaxBid, error)
 }
 
 // OutboxRepository defines the interface for outbox event persistence
@@ -30,20 +45,31 @@ type OutboxRepository interface {
 
 // ItemRepository defines the interface for item persistence
 type ItemRepository interface {
-	// GetItemByID retrieves an item by its ID
+	// GetItemByID retrieves an item by its ID, or returns items.ErrItemNotFound
 	GetItemByID(ctx context.Context, itemID uuid.UUID) (*items.Item, error)
 
 	// GetItemByIDForUpdate retrieves an item by its ID and locks it for update
 	// This prevents race conditions when multiple users bid on the same item
-	// Must be called within a transaction
+	// Must be called within a transaction. Returns items.ErrItemNotFound if there is no such item
 	GetItemByIDForUpdate(ctx context.Context, tx pgx.Tx, itemID uuid.UUID) (*items.Item, error)
 
-	// UpdateHighestBid updates the current highest bid for an item within a transaction
-	UpdateHighestBid(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, amount int64) error
+	// UpdateHighestBid updates the current highest bid and its bidder for an item within a transaction,
+	// provided the highest bid is still expected. A uuid.Nil bidder clears it.
+	// If another transaction changed the highest bid first, nothing is updated and it fails with database.ErrConflict.
+	UpdateHighestBid(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, expected, amount int64, bidderID uuid.UUID) error
+
+	// ExtendEndAt moves an item's end time and counts the extension within a transaction
+	ExtendEndAt(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, endAt time.Time) error
+
+	// UpdateStatusInTx updates an item's status within a transaction
+	UpdateStatusInTx(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, status items.ItemStatus) error
+
+	// ListItemIDsDueToEnd returns up to limit active items whose end time has passed, oldest first
+	ListItemIDsDueToEnd(ctx context.Context, limit int) ([]uuid.UUID, error)
 }
 
 // EventPublisher defines the interface for publishing events to a message broker
 type EventPublisher interface {
 	// Publish publishes a message to the broker
-	Publish(ctx context.Context, exchange, routingKey string, body []byte) error
+	Publish(ctx context.Context, exchange, routingKey string, body []byte, opts ...events.PublishOption) error
 }
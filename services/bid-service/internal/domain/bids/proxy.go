@@ -0,0 +1,77 @@
+package bids
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// proxyBid is a bid the service places on a user's behalf
+type proxyBid struct {
+	UserID uuid.UUID
+	Amount int64
+}
+
+// proxyCandidate is a user competing for the lead, up to max
+type proxyCandidate struct {
+	userID uuid.UUID
+	max    int64
+	since  time.Time
+}
+
+// resolveProxyBids works out the automatic bids triggered while leader holds the item at current.
+// leaderSince is when the leading bid was placed; leader is uuid.Nil when the item has no bids yet.
+//
+// The user with the highest maximum wins, paying one increment over the runner-up's maximum but
+// never more than their own. Equal maximums go to whoever set theirs first. The returned bids are
+// in the order they should be recorded, the last one being the new leading bid.
+func resolveProxyBids(leader uuid.UUID, current int64, leaderSince time.Time, maxBids []*MaxBid, increment BidIncrement) []proxyBid {
+	var candidates []proxyCandidate
+	if leader != uuid.Nil {
+		candidates = append(candidates, proxyCandidate{userID: leader, max: current, since: leaderSince})
+	}
+
+	for _, mb := range maxBids {
+		if mb.UserID == leader {
+			// The leader competes with their maximum, and ties date from when they set it
+			if mb.MaxAmount >= current {
+				candidates[0].max = mb.MaxAmount
+				candidates[0].since = mb.CreatedAt
+			}
+			continue
+		}
+		// A maximum equal to the current bid can still win the tie if it was set earlier
+		if mb.MaxAmount >= current {
+			candidates = append(candidates, proxyCandidate{userID: mb.UserID, max: mb.MaxAmount, since: mb.CreatedAt})
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].max != candidates[j].max {
+			return candidates[i].max > candidates[j].max
+		}
+		return candidates[i].since.Before(candidates[j].since)
+	})
+
+	winner := candidates[0]
+	second := current
+	if len(candidates) > 1 {
+		second = candidates[1].max
+	}
+	if winner.userID == leader && second <= current {
+		// Nobody can beat the current leader
+		return nil
+	}
+
+	var result []proxyBid
+	if len(candidates) > 1 && candidates[1].max > current {
+		// The runner-up bids all the way to their maximum before being outbid
+		result = append(result, proxyBid{UserID: candidates[1].userID, Amount: candidates[1].max})
+	}
+	price := min(winner.max, second+increment.MinimumFor(second))
+	return append(result, proxyBid{UserID: winner.userID, Amount: price})
+}
@@ -0,0 +1,131 @@
+package bids
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveProxyBids(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	t0 := time.Now().Add(-time.Hour)
+	t1 := t0.Add(time.Minute)
+	t2 := t1.Add(time.Minute)
+	increment := BidIncrement{Absolute: 10}
+
+	maxBid := func(user uuid.UUID, amount int64, at time.Time) *MaxBid {
+		return &MaxBid{ID: uuid.New(), UserID: user, MaxAmount: amount, CreatedAt: at}
+	}
+
+	tests := []struct {
+		name        string
+		leader      uuid.UUID
+		current     int64
+		leaderSince time.Time
+		maxBids     []*MaxBid
+		want        []proxyBid
+	}{
+		{
+			name:        "No max bids",
+			leader:      alice,
+			current:     100,
+			leaderSince: t1,
+			want:        nil,
+		},
+		{
+			name:        "Escalates one increment over a plain bid",
+			leader:      alice,
+			current:     100,
+			leaderSince: t1,
+			maxBids:     []*MaxBid{maxBid(bob, 500, t0)},
+			want:        []proxyBid{{UserID: bob, Amount: 110}},
+		},
+		{
+			name:    "Opening bid uses the minimum increment",
+			leader:  uuid.Nil,
+			current: 0,
+			maxBids: []*MaxBid{maxBid(bob, 500, t0)},
+			want:    []proxyBid{{UserID: bob, Amount: 10}},
+		},
+		{
+			name:        "Leader's max escalates to beat a lower max",
+			leader:      alice,
+			current:     100,
+			leaderSince: t2,
+			maxBids:     []*MaxBid{maxBid(alice, 500, t0), maxBid(bob, 300, t1)},
+			want:        []proxyBid{{UserID: bob, Amount: 300}, {UserID: alice, Amount: 310}},
+		},
+		{
+			name:        "Higher max outbids the leader's max",
+			leader:      alice,
+			current:     100,
+			leaderSince: t2,
+			maxBids:     []*MaxBid{maxBid(alice, 300, t0), maxBid(bob, 500, t1)},
+			want:        []proxyBid{{UserID: alice, Amount: 300}, {UserID: bob, Amount: 310}},
+		},
+		{
+			name:        "Never bids above the max",
+			leader:      alice,
+			current:     100,
+			leaderSince: t2,
+			maxBids:     []*MaxBid{maxBid(alice, 300, t0), maxBid(bob, 305, t1)},
+			want:        []proxyBid{{UserID: alice, Amount: 300}, {UserID: bob, Amount: 305}},
+		},
+		{
+			name:        "Max reached by a plain bid",
+			leader:      alice,
+			current:     350,
+			leaderSince: t2,
+			maxBids:     []*MaxBid{maxBid(bob, 300, t0)},
+			want:        nil,
+		},
+		{
+			name:        "Leader already above other maxes",
+			leader:      alice,
+			current:     200,
+			leaderSince: t1,
+			maxBids:     []*MaxBid{maxBid(alice, 400, t0), maxBid(bob, 150, t1)},
+			want:        nil,
+		},
+		{
+			name:    "Equal maxes favour the earlier one",
+			leader:  uuid.Nil,
+			current: 0,
+			maxBids: []*MaxBid{maxBid(bob, 500, t1), maxBid(alice, 500, t0)},
+			want:    []proxyBid{{UserID: bob, Amount: 500}, {UserID: alice, Amount: 500}},
+		},
+		{
+			name:        "Earlier max wins a tie against a later plain bid",
+			leader:      alice,
+			current:     300,
+			leaderSince: t1,
+			maxBids:     []*MaxBid{maxBid(bob, 300, t0)},
+			want:        []proxyBid{{UserID: bob, Amount: 300}},
+		},
+		{
+			name:        "Later max loses a tie against an earlier plain bid",
+			leader:      alice,
+			current:     300,
+			leaderSince: t0,
+			maxBids:     []*MaxBid{maxBid(bob, 300, t1)},
+			want:        nil,
+		},
+		{
+			name:        "Leader keeps a won tie on later resolutions",
+			leader:      alice,
+			current:     500,
+			leaderSince: t2,
+			maxBids:     []*MaxBid{maxBid(alice, 500, t0), maxBid(bob, 500, t1)},
+			want:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveProxyBids(tt.leader, tt.current, tt.leaderSince, tt.maxBids, increment)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
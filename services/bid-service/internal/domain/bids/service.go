@@ -2,49 +2,176 @@ package bids
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/floroz/gavel/pkg/database"
 	"github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/pkg/money"
 	pb "github.com/floroz/gavel/pkg/proto"
+	"github.com/floroz/gavel/pkg/tracing"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
 )
 
+const tracerName = "github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+
 type PlaceBidCommand struct {
 	ItemID uuid.UUID
 	UserID uuid.UUID
 	Amount int64
 }
 
+// PurchaseNowCommand buys an item outright at its buy-now price
+type PurchaseNowCommand struct {
+	ItemID uuid.UUID
+	UserID uuid.UUID
+}
+
+// SetMaxBidCommand sets the most a user is willing to pay for an item
+type SetMaxBidCommand struct {
+	ItemID    uuid.UUID
+	UserID    uuid.UUID
+	MaxAmount int64
+}
+
 // Validation errors
 var (
-	ErrBidTooLow        = fmt.Errorf("bid amount must be higher than current highest bid")
-	ErrAuctionEnded     = fmt.Errorf("auction has ended")
-	ErrInvalidBidAmount = fmt.Errorf("bid amount must be positive")
-	ErrSellerCannotBid  = fmt.Errorf("seller cannot bid on their own item")
+	ErrBidTooLow            = fmt.Errorf("bid amount must be higher than current highest bid")
+	ErrBidIncrementTooSmall = fmt.Errorf("bid must exceed current highest bid by the minimum increment")
+	ErrAuctionEnded         = fmt.Errorf("auction has ended")
+	ErrAuctionNotStarted    = fmt.Errorf("auction has not started yet")
+	ErrAuctionInactive      = fmt.Errorf("auction is no longer accepting bids")
+	ErrInvalidBidAmount     = fmt.Errorf("bid amount must be positive")
+	ErrBidAmountTooHigh     = fmt.Errorf("bid amount exceeds the maximum allowed")
+	ErrSellerCannotBid      = fmt.Errorf("seller cannot bid on their own item")
+	ErrBuyNowUnavailable    = fmt.Errorf("item has no buy now price")
+	ErrBuyNowExceeded       = fmt.Errorf("bids have already reached the buy now price")
+	ErrInvalidTransition    = fmt.Errorf("invalid item status transition")
+	ErrInvalidCursor        = fmt.Errorf("invalid page cursor")
+	ErrBidConflict          = fmt.Errorf("the highest bid changed while the bid was placed, please retry")
+)
+
+// ErrBidNotFound is returned when no bid has the requested ID
+var ErrBidNotFound = fmt.Errorf("bid not found")
+
+// DefaultMaxBidAmount caps bids at one billion dollars, in cents, unless WithMaxBidAmount says otherwise.
+// It keeps increment arithmetic on the current highest bid far from overflowing int64.
+const DefaultMaxBidAmount int64 = 100_000_000_000
+
+// DefaultUrgentBidWindow is how close to its end an auction's bids are published with
+// events.PriorityHigh, unless WithUrgentBidWindow says otherwise
+const DefaultUrgentBidWindow = 5 * time.Minute
+
+// DefaultEndAuctionsBatchSize is how many expired auctions EndDueAuctions closes per call
+const DefaultEndAuctionsBatchSize = 100
+
+// Bid history pagination bounds
+const (
+	DefaultBidPageSize = 20
+	MaxBidPageSize     = 100
 )
 
-// validateBidAmount checks if the bid amount is higher than the current highest bid
-func validateBidAmount(bidAmount, currentHighest int64) error {
-	if bidAmount <= 0 {
+// BidIncrement is the minimum amount a new bid must beat the current highest bid by.
+// The larger of the absolute amount and the percentage of the current highest bid applies.
+// The zero value only requires a bid to be strictly higher.
+type BidIncrement struct {
+	Absolute    int64 // in cents
+	BasisPoints int64 // percentage of the current highest bid, 100 = 1%
+}
+
+// MinimumFor returns the smallest allowed jump over currentHighest, rounding percentages up
+func (b BidIncrement) MinimumFor(currentHighest int64) int64 {
+	percent := (currentHighest*b.BasisPoints + 9999) / 10000
+	return max(b.Absolute, percent, 1)
+}
+
+// AntiSnipePolicy extends an auction when a bid lands in its final moments, so nobody can
+// win by leaving other bidders no time to respond. The zero value never extends.
+type AntiSnipePolicy struct {
+	Window        time.Duration // bids this close to the end trigger an extension
+	Extension     time.Duration // how far the end moves forward
+	MaxExtensions int           // extensions allowed per item
+}
+
+// ExtendedEnd returns the end time after a bid placed at bidAt, and whether it was extended
+func (p AntiSnipePolicy) ExtendedEnd(bidAt, endAt time.Time, extensions int) (time.Time, bool) {
+	if p.Window <= 0 || p.Extension <= 0 || extensions >= p.MaxExtensions {
+		return endAt, false
+	}
+	if endAt.Sub(bidAt) > p.Window {
+		return endAt, false
+	}
+	return endAt.Add(p.Extension), true
+}
+
+// validateBidAmount checks the bid amount is positive and at most maxAmount, and beats the current
+// highest bid by at least the increment. The increment only applies once there is a bid to beat.
+// Amounts in different currencies cannot be compared and fail with money.ErrCurrencyMismatch.
+func validateBidAmount(bidAmount, currentHighest money.Money, increment BidIncrement, maxAmount int64) error {
+	if !bidAmount.IsPositive() {
 		return ErrInvalidBidAmount
 	}
-	if bidAmount <= currentHighest {
+	if bidAmount.Amount > maxAmount {
+		return ErrBidAmountTooHigh
+	}
+	jump, err := bidAmount.Sub(currentHighest)
+	if err != nil {
+		return err
+	}
+	if !jump.IsPositive() {
 		return ErrBidTooLow
 	}
+	if currentHighest.IsPositive() && jump.Amount < increment.MinimumFor(currentHighest.Amount) {
+		return ErrBidIncrementTooSmall
+	}
 	return nil
 }
 
-// validateAuctionNotEnded checks if the auction has not ended
-func validateAuctionNotEnded(endAt time.Time) error {
-	if time.Now().After(endAt) {
+// inItemCurrency returns amount in the currency items are priced in.
+// Items do not carry a currency of their own yet, so every item is priced in money.DefaultCurrency.
+func inItemCurrency(amount int64) money.Money {
+	return money.New(amount, money.DefaultCurrency)
+}
+
+// validateAuctionOpen checks the item accepts bids: it must be active, started and not past its
+// end time. Otherwise the error says why not, so callers can tell a finished auction from a cancelled one.
+func validateAuctionOpen(item *items.Item) error {
+	if item.IsActive() {
+		return nil
+	}
+	switch item.Status {
+	case items.ItemStatusScheduled:
+		return ErrAuctionNotStarted
+	case items.ItemStatusActive:
+		if !item.HasStarted() {
+			return ErrAuctionNotStarted
+		}
+		// Past its end time, even if the sweep has not closed it yet
 		return ErrAuctionEnded
+	case items.ItemStatusEnded, items.ItemStatusEndedUnsold:
+		return ErrAuctionEnded
+	default:
+		return ErrAuctionInactive
 	}
-	return nil
+}
+
+// openAuction checks the item accepts bids, first opening a scheduled auction whose start
+// time has passed so bids don't have to wait for the next StartDueAuctions sweep
+func (s *AuctionService) openAuction(ctx context.Context, tx pgx.Tx, item *items.Item) error {
+	if item.ActivateIfDue() {
+		if err := s.itemRepo.UpdateStatusInTx(ctx, tx, item.ID, items.ItemStatusActive); err != nil {
+			return fmt.Errorf("failed to start auction: %w", err)
+		}
+	}
+	return validateAuctionOpen(item)
 }
 
 // AuctionService implements the core business logic
@@ -53,6 +180,51 @@ type AuctionService struct {
 	bidRepo    BidRepository
 	itemRepo   ItemRepository
 	outboxRepo OutboxRepository
+
+	minIncrement BidIncrement
+	maxAmount    int64
+	antiSnipe    AntiSnipePolicy
+	urgentWindow time.Duration
+	tracer       trace.Tracer
+}
+
+// Option configures optional AuctionService behaviour
+type Option func(*AuctionService)
+
+// WithMinBidIncrement requires each bid to beat the current highest bid by at least the given increment
+func WithMinBidIncrement(increment BidIncrement) Option {
+	return func(s *AuctionService) {
+		s.minIncrement = increment
+	}
+}
+
+// WithMaxBidAmount overrides DefaultMaxBidAmount as the largest bid accepted, in cents
+func WithMaxBidAmount(amount int64) Option {
+	return func(s *AuctionService) {
+		s.maxAmount = amount
+	}
+}
+
+// WithAntiSnipe extends auctions that receive bids close to their end
+func WithAntiSnipe(policy AntiSnipePolicy) Option {
+	return func(s *AuctionService) {
+		s.antiSnipe = policy
+	}
+}
+
+// WithUrgentBidWindow overrides DefaultUrgentBidWindow. A window of 0 publishes every bid
+// with normal priority.
+func WithUrgentBidWindow(window time.Duration) Option {
+	return func(s *AuctionService) {
+		s.urgentWindow = window
+	}
+}
+
+// WithTracerProvider records spans with tp instead of the global tracer provider
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(s *AuctionService) {
+		s.tracer = tp.Tracer(tracerName)
+	}
 }
 
 // NewAuctionService creates a new auction service
@@ -61,67 +233,479 @@ func NewAuctionService(
 	bidRepo BidRepository,
 	itemRepo ItemRepository,
 	outboxRepo OutboxRepository,
+	opts ...Option,
 ) *AuctionService {
-	return &AuctionService{
-		txManager:  txManager,
-		bidRepo:    bidRepo,
-		itemRepo:   itemRepo,
-		outboxRepo: outboxRepo,
+	s := &AuctionService{
+		txManager:    txManager,
+		bidRepo:      bidRepo,
+		itemRepo:     itemRepo,
+		outboxRepo:   outboxRepo,
+		maxAmount:    DefaultMaxBidAmount,
+		urgentWindow: DefaultUrgentBidWindow,
+		tracer:       otel.Tracer(tracerName),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // PlaceBid implements the transactional outbox pattern
-// It saves the bid and the event in the same database transaction
+// It saves the bid and the event in the same database transaction, so once it
+// returns without error both are guaranteed to be saved. A transaction that loses
+// a race with another bid is retried: besides the item's row lock, the highest bid is
+// only updated if it is still the one the bid was validated against. A conflict that outlasts the
+// retries fails with ErrBidConflict.
 func (s *AuctionService) PlaceBid(ctx context.Context, cmd PlaceBidCommand) (*Bid, error) {
-	// Start transaction
-	tx, err := s.txManager.BeginTx(ctx)
+	ctx, span := s.startSpan(ctx, "bids.PlaceBid", cmd.ItemID, cmd.UserID)
+	defer span.End()
+
+	var bid *Bid
+	err := database.RetryTx(ctx, s.txManager, func(tx pgx.Tx) error {
+		// Lock the item row to prevent race conditions
+		// This ensures that only one transaction can modify this item at a time
+		item, err := s.itemRepo.GetItemByIDForUpdate(ctx, tx, cmd.ItemID)
+		if err != nil {
+			return err
+		}
+
+		// Validate seller cannot bid on own item
+		if item.SellerID == cmd.UserID {
+			return ErrSellerCannotBid
+		}
+
+		if valErr := validateBidAmount(inItemCurrency(cmd.Amount), inItemCurrency(item.CurrentHighestBid), s.minIncrement, s.maxAmount); valErr != nil {
+			return valErr
+		}
+
+		if valErr := s.openAuction(ctx, tx, item); valErr != nil {
+			return valErr
+		}
+
+		// Step 1: Save the bid and its event
+		bid, err = s.recordBid(ctx, tx, item, cmd.UserID, cmd.Amount)
+		if err != nil {
+			return err
+		}
+
+		// Step 2: Let maximum bids respond, then update the item's highest bid and bidder
+		leader, err := s.applyProxyBids(ctx, tx, item, bid)
+		if err != nil {
+			return err
+		}
+		if updateErr := s.itemRepo.UpdateHighestBid(ctx, tx, cmd.ItemID, item.CurrentHighestBid, leader.Amount, leader.UserID); updateErr != nil {
+			return fmt.Errorf("failed to update highest bid: %w", updateErr)
+		}
+		if outbidErr := s.recordOutbid(ctx, tx, item.CurrentHighestBidderID, leader); outbidErr != nil {
+			return outbidErr
+		}
+
+		// Step 3: A late bid pushes the end back, in the same transaction as the bid
+		if extendErr := s.extendIfLate(ctx, tx, item, bid.CreatedAt); extendErr != nil {
+			return extendErr
+		}
+		return nil
+	}, database.RetryOn(database.ErrConflict))
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		err = asBidConflict(err)
+		tracing.RecordError(span, err)
+		return nil, err
 	}
-	defer func() {
-		_ = tx.Rollback(ctx) // Rollback if commit is not called
-	}()
+	return bid, nil
+}
+
+// SetMaxBid records the most a user will pay for an item and bids on their behalf as needed,
+// both now and whenever someone else bids later
+func (s *AuctionService) SetMaxBid(ctx context.Context, cmd SetMaxBidCommand) (*MaxBid, error) {
+	ctx, span := s.startSpan(ctx, "bids.SetMaxBid", cmd.ItemID, cmd.UserID)
+	defer span.End()
+
+	var maxBid *MaxBid
+	err := database.RetryTx(ctx, s.txManager, func(tx pgx.Tx) error {
+		item, err := s.itemRepo.GetItemByIDForUpdate(ctx, tx, cmd.ItemID)
+		if err != nil {
+			return err
+		}
+
+		if item.SellerID == cmd.UserID {
+			return ErrSellerCannotBid
+		}
+
+		if valErr := s.openAuction(ctx, tx, item); valErr != nil {
+			return valErr
+		}
+
+		leading, err := s.bidRepo.GetHighestBid(ctx, tx, cmd.ItemID)
+		if err != nil {
+			return fmt.Errorf("failed to get highest bid: %w", err)
+		}
+
+		// The leader only needs to stay above their own bid; anyone else must be able to outbid it
+		if leading != nil && leading.UserID == cmd.UserID {
+			if cmd.MaxAmount <= item.CurrentHighestBid {
+				return ErrBidTooLow
+			}
+			if cmd.MaxAmount > s.maxAmount {
+				return ErrBidAmountTooHigh
+			}
+		} else if valErr := validateBidAmount(inItemCurrency(cmd.MaxAmount), inItemCurrency(item.CurrentHighestBid), s.minIncrement, s.maxAmount); valErr != nil {
+			return valErr
+		}
 
-	// Lock the item row to prevent race conditions
-	// This ensures that only one transaction can modify this item at a time
-	item, err := s.itemRepo.GetItemByIDForUpdate(ctx, tx, cmd.ItemID)
+		now := time.Now()
+		maxBid = &MaxBid{
+			ID:        uuid.New(),
+			ItemID:    cmd.ItemID,
+			UserID:    cmd.UserID,
+			MaxAmount: cmd.MaxAmount,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if saveErr := s.bidRepo.SaveMaxBid(ctx, tx, maxBid); saveErr != nil {
+			return fmt.Errorf("failed to save max bid: %w", saveErr)
+		}
+
+		leader, err := s.applyProxyBids(ctx, tx, item, leading)
+		if err != nil {
+			return err
+		}
+		if leader != leading {
+			if updateErr := s.itemRepo.UpdateHighestBid(ctx, tx, cmd.ItemID, item.CurrentHighestBid, leader.Amount, leader.UserID); updateErr != nil {
+				return fmt.Errorf("failed to update highest bid: %w", updateErr)
+			}
+			if outbidErr := s.recordOutbid(ctx, tx, item.CurrentHighestBidderID, leader); outbidErr != nil {
+				return outbidErr
+			}
+			if extendErr := s.extendIfLate(ctx, tx, item, now); extendErr != nil {
+				return extendErr
+			}
+		}
+		return nil
+	}, database.RetryOn(database.ErrConflict))
 	if err != nil {
-		return nil, fmt.Errorf("item not found: %w", err)
+		err = asBidConflict(err)
+		tracing.RecordError(span, err)
+		return nil, err
 	}
+	return maxBid, nil
+}
+
+// PurchaseNow ends an auction immediately at its buy-now price. The purchase is recorded as the
+// winning bid, and both the bid and the purchase are published through the outbox.
+func (s *AuctionService) PurchaseNow(ctx context.Context, cmd PurchaseNowCommand) (*Bid, error) {
+	ctx, span := s.startSpan(ctx, "bids.PurchaseNow", cmd.ItemID, cmd.UserID)
+	defer span.End()
+
+	var bid *Bid
+	err := database.RetryTx(ctx, s.txManager, func(tx pgx.Tx) error {
+		item, err := s.itemRepo.GetItemByIDForUpdate(ctx, tx, cmd.ItemID)
+		if err != nil {
+			return err
+		}
+
+		if item.SellerID == cmd.UserID {
+			return ErrSellerCannotBid
+		}
+
+		if valErr := s.openAuction(ctx, tx, item); valErr != nil {
+			return valErr
+		}
+
+		if !item.HasBuyNow() {
+			return ErrBuyNowUnavailable
+		}
+		// The winning bid must beat the current one, so buy-now is gone once bidding reaches it
+		if item.CurrentHighestBid >= item.BuyNowPrice {
+			return ErrBuyNowExceeded
+		}
+
+		bid, err = s.recordBid(ctx, tx, item, cmd.UserID, item.BuyNowPrice)
+		if err != nil {
+			return err
+		}
+
+		if updateErr := s.itemRepo.UpdateHighestBid(ctx, tx, cmd.ItemID, item.CurrentHighestBid, bid.Amount, bid.UserID); updateErr != nil {
+			return fmt.Errorf("failed to update highest bid: %w", updateErr)
+		}
+		if !item.CanTransitionTo(items.ItemStatusEnded) {
+			return ErrInvalidTransition
+		}
+		if updateErr := s.itemRepo.UpdateStatusInTx(ctx, tx, cmd.ItemID, items.ItemStatusEnded); updateErr != nil {
+			return fmt.Errorf("failed to end auction: %w", updateErr)
+		}
 
-	// Validate seller cannot bid on own item
-	if item.SellerID == cmd.UserID {
-		return nil, ErrSellerCannotBid
+		if outbidErr := s.recordOutbid(ctx, tx, item.CurrentHighestBidderID, bid); outbidErr != nil {
+			return outbidErr
+		}
+
+		event := &pb.ItemPurchased{
+			ItemId:      bid.ItemID.String(),
+			BuyerId:     bid.UserID.String(),
+			BidId:       bid.ID.String(),
+			Amount:      bid.Amount,
+			PurchasedAt: timestamppb.New(bid.CreatedAt),
+		}
+		if saveErr := s.saveEvent(ctx, tx, bid.ItemID, EventTypeItemPurchased, event); saveErr != nil {
+			return saveErr
+		}
+		return nil
+	}, database.RetryOn(database.ErrConflict))
+	if err != nil {
+		err = asBidConflict(err)
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return bid, nil
+}
+
+// CancelItem cancels userID's item, provided it has no bids and has not ended.
+// The cancellation is published through the outbox.
+func (s *AuctionService) CancelItem(ctx context.Context, itemID, userID uuid.UUID) (*items.Item, error) {
+	ctx, span := s.startSpan(ctx, "bids.CancelItem", itemID, userID)
+	defer span.End()
+
+	item, err := s.cancelItem(ctx, itemID, userID, false)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return item, nil
+}
+
+// ForceCancelItem cancels any seller's item on behalf of moderator adminID, even one with bids.
+// Checking adminID may moderate is left to the caller; only ended auctions can't be cancelled.
+func (s *AuctionService) ForceCancelItem(ctx context.Context, itemID, adminID uuid.UUID) (*items.Item, error) {
+	ctx, span := s.startSpan(ctx, "bids.ForceCancelItem", itemID, adminID)
+	defer span.End()
+
+	item, err := s.cancelItem(ctx, itemID, adminID, true)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return item, nil
+}
+
+// cancelItem cancels the item, as its seller userID unless force skips the ownership and bid checks.
+// Locking the item keeps bids out until the cancellation commits.
+func (s *AuctionService) cancelItem(ctx context.Context, itemID, userID uuid.UUID, force bool) (*items.Item, error) {
+	var item *items.Item
+	err := database.RetryTx(ctx, s.txManager, func(tx pgx.Tx) error {
+		var err error
+		item, err = s.itemRepo.GetItemByIDForUpdate(ctx, tx, itemID)
+		if err != nil {
+			return err
+		}
+
+		if !force {
+			if !item.IsOwnedBy(userID) {
+				return items.ErrUnauthorized
+			}
+			highest, err := s.bidRepo.GetHighestBid(ctx, tx, itemID)
+			if err != nil {
+				return fmt.Errorf("failed to check bids: %w", err)
+			}
+			if !item.CanBeCancelled(highest != nil) {
+				return items.ErrCannotCancel
+			}
+		}
+		if !item.CanTransitionTo(items.ItemStatusCancelled) {
+			return ErrInvalidTransition
+		}
+		if updateErr := s.itemRepo.UpdateStatusInTx(ctx, tx, itemID, items.ItemStatusCancelled); updateErr != nil {
+			return fmt.Errorf("failed to cancel item: %w", updateErr)
+		}
+		item.Status = items.ItemStatusCancelled
+
+		event := &pb.ItemCancelled{
+			ItemId:      itemID.String(),
+			SellerId:    item.SellerID.String(),
+			CancelledAt: timestamppb.Now(),
+		}
+		return s.saveEvent(ctx, tx, itemID, EventTypeItemCancelled, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// EndDueAuctions closes up to limit active auctions whose end time has passed and returns how
+// many it closed. Each is closed in its own transaction, so one failure does not hold up the rest.
+// An auction already closed, or extended in the meantime, is skipped, so overlapping runs are harmless.
+func (s *AuctionService) EndDueAuctions(ctx context.Context, limit int) (int, error) {
+	if limit <= 0 {
+		limit = DefaultEndAuctionsBatchSize
+	}
+	itemIDs, err := s.itemRepo.ListItemIDsDueToEnd(ctx, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list auctions due to end: %w", err)
+	}
+
+	var ended int
+	var errs []error
+	for _, itemID := range itemIDs {
+		closed, endErr := s.endAuction(ctx, itemID)
+		if endErr != nil {
+			errs = append(errs, fmt.Errorf("item %s: %w", itemID, endErr))
+			continue
+		}
+		if closed {
+			ended++
+		}
+	}
+	return ended, errors.Join(errs...)
+}
+
+// endAuction closes the item's auction if it is still active and past its end time. The highest
+// bid wins if it meets the reserve, and the outcome is published as an auction.ended event.
+func (s *AuctionService) endAuction(ctx context.Context, itemID uuid.UUID) (bool, error) {
+	ctx, span := s.tracer.Start(ctx, "bids.EndAuction", trace.WithAttributes(
+		tracing.AttrItemID.String(itemID.String()),
+	))
+	defer span.End()
+
+	var closed bool
+	err := database.RetryTx(ctx, s.txManager, func(tx pgx.Tx) error {
+		closed = false
+		item, err := s.itemRepo.GetItemByIDForUpdate(ctx, tx, itemID)
+		if err != nil {
+			return err
+		}
+		if item.Status != items.ItemStatusActive || time.Now().Before(item.EndAt) {
+			return nil
+		}
+
+		highest, err := s.bidRepo.GetHighestBid(ctx, tx, itemID)
+		if err != nil {
+			return err
+		}
+		// The bids, not the item's cached highest bid, decide the outcome
+		item.CurrentHighestBid = 0
+		if highest != nil {
+			item.CurrentHighestBid = highest.Amount
+		}
+
+		status := item.EndStatus()
+		if !item.CanTransitionTo(status) {
+			return ErrInvalidTransition
+		}
+		if updateErr := s.itemRepo.UpdateStatusInTx(ctx, tx, itemID, status); updateErr != nil {
+			return fmt.Errorf("failed to end auction: %w", updateErr)
+		}
+
+		event := &pb.AuctionEnded{
+			ItemId:   itemID.String(),
+			SellerId: item.SellerID.String(),
+			Sold:     status == items.ItemStatusEnded,
+			EndedAt:  timestamppb.Now(),
+		}
+		if event.Sold {
+			event.WinnerId = highest.UserID.String()
+			event.WinningBidId = highest.ID.String()
+			event.Amount = highest.Amount
+		}
+		if saveErr := s.saveEvent(ctx, tx, itemID, EventTypeAuctionEnded, event); saveErr != nil {
+			return saveErr
+		}
+		closed = true
+		return nil
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return false, err
+	}
+	return closed, nil
+}
+
+// ListBids returns a page of an item's bids, newest first, continuing after cursor.
+// A non-positive limit falls back to DefaultBidPageSize and larger pages are capped at MaxBidPageSize.
+func (s *AuctionService) ListBids(ctx context.Context, itemID uuid.UUID, limit int, cursor *BidCursor) (*BidPage, error) {
+	if limit <= 0 {
+		limit = DefaultBidPageSize
+	}
+	limit = min(limit, MaxBidPageSize)
+
+	// Fetch one extra bid to learn whether another page follows
+	bidList, err := s.bidRepo.ListBids(ctx, itemID, limit+1, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bids: %w", err)
 	}
 
-	if valErr := validateBidAmount(cmd.Amount, item.CurrentHighestBid); valErr != nil {
-		return nil, valErr
+	page := &BidPage{Bids: bidList}
+	if len(bidList) > limit {
+		page.Bids = bidList[:limit]
+		page.Next = CursorFor(page.Bids[limit-1])
+	}
+	return page, nil
+}
+
+// startSpan starts a span for an operation by userID on itemID
+func (s *AuctionService) startSpan(ctx context.Context, name string, itemID, userID uuid.UUID) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, name, trace.WithAttributes(
+		tracing.AttrItemID.String(itemID.String()),
+		tracing.AttrUserID.String(userID.String()),
+	))
+}
+
+// extendIfLate pushes the item's end time back if a bid placed at bidAt falls in the anti-snipe window
+func (s *AuctionService) extendIfLate(ctx context.Context, tx pgx.Tx, item *items.Item, bidAt time.Time) error {
+	endAt, extended := s.antiSnipe.ExtendedEnd(bidAt, item.EndAt, item.ExtensionCount)
+	if !extended {
+		return nil
+	}
+	if err := s.itemRepo.ExtendEndAt(ctx, tx, item.ID, endAt); err != nil {
+		return fmt.Errorf("failed to extend auction: %w", err)
 	}
+	item.EndAt = endAt
+	item.ExtensionCount++
+	return nil
+}
 
-	if valErr := validateAuctionNotEnded(item.EndAt); valErr != nil {
-		return nil, valErr
+// applyProxyBids places the automatic bids triggered by maximum bids while leading holds the item,
+// and returns the resulting leading bid. leading is nil when the item has no bids yet, and is
+// returned unchanged when no maximum bid beats it.
+func (s *AuctionService) applyProxyBids(ctx context.Context, tx pgx.Tx, item *items.Item, leading *Bid) (*Bid, error) {
+	var (
+		leader uuid.UUID
+		since  time.Time
+		amount int64
+	)
+	if leading != nil {
+		leader, since, amount = leading.UserID, leading.CreatedAt, leading.Amount
 	}
 
-	// Create the bid
+	maxBids, err := s.bidRepo.GetMaxBidsByItemID(ctx, tx, item.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get max bids: %w", err)
+	}
+
+	for _, auto := range resolveProxyBids(leader, amount, since, maxBids, s.minIncrement) {
+		bid, err := s.recordBid(ctx, tx, item, auto.UserID, auto.Amount)
+		if err != nil {
+			return nil, err
+		}
+		leading = bid
+	}
+	return leading, nil
+}
+
+// recordBid saves a bid on item along with its outbox event. Bids in the item's last
+// urgent window are published with high priority, ahead of any backlog.
+func (s *AuctionService) recordBid(ctx context.Context, tx pgx.Tx, item *items.Item, userID uuid.UUID, amount int64) (*Bid, error) {
 	bid := &Bid{
 		ID:        uuid.New(),
-		ItemID:    cmd.ItemID,
-		UserID:    cmd.UserID,
-		Amount:    cmd.Amount,
+		ItemID:    item.ID,
+		UserID:    userID,
+		Amount:    amount,
 		CreatedAt: time.Now(),
 	}
 
-	// Step 1: Save the bid
 	if saveErr := s.bidRepo.SaveBid(ctx, tx, bid); saveErr != nil {
 		return nil, fmt.Errorf("failed to save bid: %w", saveErr)
 	}
 
-	// Step 2: Update the item's highest bid
-	if updateErr := s.itemRepo.UpdateHighestBid(ctx, tx, cmd.ItemID, cmd.Amount); updateErr != nil {
-		return nil, fmt.Errorf("failed to update highest bid: %w", updateErr)
-	}
-
-	// Step 3: Create the event (protobuf message)
+	// Create the event (protobuf message)
 	event := &pb.BidPlaced{
 		BidId:     bid.ID.String(),
 		ItemId:    bid.ItemID.String(),
@@ -130,30 +714,68 @@ func (s *AuctionService) PlaceBid(ctx context.Context, cmd PlaceBidCommand) (*Bi
 		Timestamp: timestamppb.New(bid.CreatedAt),
 	}
 
-	// Marshal the protobuf message
-	payload, marshalErr := proto.Marshal(event)
-	if marshalErr != nil {
-		return nil, fmt.Errorf("failed to marshal event: %w", marshalErr)
+	priority := events.PriorityNormal
+	if s.urgentWindow > 0 && item.EndAt.Sub(bid.CreatedAt) <= s.urgentWindow {
+		priority = events.PriorityHigh
 	}
 
-	// Step 4: Save the event to the outbox (in the same transaction)
-	outboxEvent := &events.OutboxEvent{
-		ID:        uuid.New(),
-		EventType: "bid.placed",
-		Payload:   payload,
-		Status:    events.OutboxStatusPending,
-		CreatedAt: time.Now(),
+	// Save the event to the outbox (in the same transaction)
+	if saveErr := s.saveEventWithPriority(ctx, tx, bid.ItemID, EventTypeBidPlaced, event, priority); saveErr != nil {
+		return nil, saveErr
+	}
+
+	return bid, nil
+}
+
+// recordOutbid tells the previous leader they lost the lead to leading. Nothing fires for an
+// item's first bid, when previous is uuid.Nil, or when the leader raised their own bid.
+func (s *AuctionService) recordOutbid(ctx context.Context, tx pgx.Tx, previous uuid.UUID, leading *Bid) error {
+	if previous == uuid.Nil || leading == nil || previous == leading.UserID {
+		return nil
 	}
 
-	if saveErr := s.outboxRepo.SaveEvent(ctx, tx, outboxEvent); saveErr != nil {
-		return nil, fmt.Errorf("failed to save outbox event: %w", saveErr)
+	event := &pb.UserOutbid{
+		ItemId:   leading.ItemID.String(),
+		UserId:   previous.String(),
+		Amount:   leading.Amount,
+		OutbidAt: timestamppb.New(leading.CreatedAt),
 	}
+	return s.saveEvent(ctx, tx, leading.ItemID, EventTypeUserOutbid, event)
+}
 
-	// Commit the transaction
-	// If this succeeds, both the bid and the event are guaranteed to be saved
-	if commitErr := tx.Commit(ctx); commitErr != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", commitErr)
+// saveEvent marshals event and saves it to the outbox in tx, to be published under eventType.
+// Events about the same item are published in the order they were saved.
+// asBidConflict reports an optimistic concurrency conflict that outlasted the retries as ErrBidConflict
+func asBidConflict(err error) error {
+	if errors.Is(err, database.ErrConflict) {
+		return fmt.Errorf("%w: %w", ErrBidConflict, err)
 	}
+	return err
+}
 
-	return bid, nil
+func (s *AuctionService) saveEvent(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, eventType EventType, event proto.Message) error {
+	return s.saveEventWithPriority(ctx, tx, itemID, eventType, event, events.PriorityNormal)
+}
+
+// saveEventWithPriority is saveEvent for events published with the given message priority
+func (s *AuctionService) saveEventWithPriority(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, eventType EventType, event proto.Message, priority uint8) error {
+	payload, err := events.MarshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	outboxEvent := &events.OutboxEvent{
+		ID:          uuid.New(),
+		AggregateID: itemID,
+		EventType:   eventType.String(),
+		Payload:     payload,
+		Headers:     events.EventHeaders(ctx),
+		Status:      events.OutboxStatusPending,
+		CreatedAt:   time.Now(),
+		Priority:    priority,
+	}
+	if err := s.outboxRepo.SaveEvent(ctx, tx, outboxEvent); err != nil {
+		return fmt.Errorf("failed to save outbox event: %w", err)
+	}
+	return nil
 }
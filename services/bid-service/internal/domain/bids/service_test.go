@@ -1,10 +1,23 @@
 package bids
 
 import (
+	"context"
+	"errors"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/pkg/money"
+	pb "github.com/floroz/gavel/pkg/proto"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
 )
 
 func TestValidateBidAmount(t *testing.T) {
@@ -12,6 +25,8 @@ func TestValidateBidAmount(t *testing.T) {
 		name           string
 		bidAmount      int64
 		currentHighest int64
+		increment      BidIncrement
+		maxAmount      int64
 		wantErr        error
 	}{
 		{
@@ -32,38 +47,486 @@ func TestValidateBidAmount(t *testing.T) {
 			currentHighest: 100,
 			wantErr:        ErrBidTooLow,
 		},
+		{
+			name:           "Non-positive bid",
+			bidAmount:      0,
+			currentHighest: 0,
+			wantErr:        ErrInvalidBidAmount,
+		},
+		{
+			name:           "Negative first bid",
+			bidAmount:      -500,
+			currentHighest: 0,
+			wantErr:        ErrInvalidBidAmount,
+		},
+		{
+			name:           "Negative bid over an existing one",
+			bidAmount:      -500,
+			currentHighest: 100,
+			wantErr:        ErrInvalidBidAmount,
+		},
+		{
+			name:           "Bid at the maximum",
+			bidAmount:      DefaultMaxBidAmount,
+			currentHighest: 100,
+			wantErr:        nil,
+		},
+		{
+			name:           "Bid over the maximum",
+			bidAmount:      DefaultMaxBidAmount + 1,
+			currentHighest: 100,
+			wantErr:        ErrBidAmountTooHigh,
+		},
+		{
+			name:           "Bid near int64 range",
+			bidAmount:      math.MaxInt64,
+			currentHighest: 0,
+			wantErr:        ErrBidAmountTooHigh,
+		},
+		{
+			name:           "Bid over a configured maximum",
+			bidAmount:      5001,
+			currentHighest: 100,
+			maxAmount:      5000,
+			wantErr:        ErrBidAmountTooHigh,
+		},
+		{
+			name:           "One cent outbid without increment",
+			bidAmount:      101,
+			currentHighest: 100,
+			wantErr:        nil,
+		},
+		{
+			name:           "Absolute increment just below threshold",
+			bidAmount:      149,
+			currentHighest: 100,
+			increment:      BidIncrement{Absolute: 50},
+			wantErr:        ErrBidIncrementTooSmall,
+		},
+		{
+			name:           "Absolute increment at threshold",
+			bidAmount:      150,
+			currentHighest: 100,
+			increment:      BidIncrement{Absolute: 50},
+			wantErr:        nil,
+		},
+		{
+			name:           "Percentage increment just below threshold",
+			bidAmount:      10499,
+			currentHighest: 10000,
+			increment:      BidIncrement{BasisPoints: 500},
+			wantErr:        ErrBidIncrementTooSmall,
+		},
+		{
+			name:           "Percentage increment at threshold",
+			bidAmount:      10500,
+			currentHighest: 10000,
+			increment:      BidIncrement{BasisPoints: 500},
+			wantErr:        nil,
+		},
+		{
+			name:           "Percentage increment rounds up",
+			bidAmount:      1003,
+			currentHighest: 999,
+			increment:      BidIncrement{BasisPoints: 50}, // 0.5% of 999 = 4.995
+			wantErr:        ErrBidIncrementTooSmall,
+		},
+		{
+			name:           "Larger of absolute and percentage applies",
+			bidAmount:      10400,
+			currentHighest: 10000,
+			increment:      BidIncrement{Absolute: 100, BasisPoints: 500},
+			wantErr:        ErrBidIncrementTooSmall,
+		},
+		{
+			name:           "First bid is not subject to increment",
+			bidAmount:      10,
+			currentHighest: 0,
+			increment:      BidIncrement{Absolute: 50},
+			wantErr:        nil,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateBidAmount(tt.bidAmount, tt.currentHighest)
+			maxAmount := tt.maxAmount
+			if maxAmount == 0 {
+				maxAmount = DefaultMaxBidAmount
+			}
+			err := validateBidAmount(inItemCurrency(tt.bidAmount), inItemCurrency(tt.currentHighest), tt.increment, maxAmount)
 			assert.Equal(t, tt.wantErr, err)
 		})
 	}
 }
 
-func TestValidateAuctionNotEnded(t *testing.T) {
+func TestValidateBidAmount_CurrencyMismatch(t *testing.T) {
+	err := validateBidAmount(money.New(2000, "EUR"), money.New(1000, "USD"), BidIncrement{}, DefaultMaxBidAmount)
+	assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+
+	// A first bid must still be in the item's currency
+	err = validateBidAmount(money.New(2000, "EUR"), money.New(0, "USD"), BidIncrement{}, DefaultMaxBidAmount)
+	assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+}
+
+func TestValidateAuctionOpen(t *testing.T) {
+	now := time.Now()
 	tests := []struct {
 		name    string
+		status  items.ItemStatus
+		startAt time.Time
 		endAt   time.Time
 		wantErr error
 	}{
 		{
 			name:    "Auction active",
-			endAt:   time.Now().Add(1 * time.Hour),
+			status:  items.ItemStatusActive,
+			startAt: now.Add(-time.Hour),
+			endAt:   now.Add(time.Hour),
 			wantErr: nil,
 		},
 		{
-			name:    "Auction ended",
-			endAt:   time.Now().Add(-1 * time.Hour),
+			name:    "Auction expired but not yet closed",
+			status:  items.ItemStatusActive,
+			startAt: now.Add(-2 * time.Hour),
+			endAt:   now.Add(-time.Hour),
+			wantErr: ErrAuctionEnded,
+		},
+		{
+			name:    "Auction ended by status",
+			status:  items.ItemStatusEnded,
+			startAt: now.Add(-time.Hour),
+			endAt:   now.Add(time.Hour),
+			wantErr: ErrAuctionEnded,
+		},
+		{
+			name:    "Auction ended unsold",
+			status:  items.ItemStatusEndedUnsold,
+			startAt: now.Add(-2 * time.Hour),
+			endAt:   now.Add(-time.Hour),
 			wantErr: ErrAuctionEnded,
 		},
+		{
+			name:    "Cancelled item with a future end time",
+			status:  items.ItemStatusCancelled,
+			startAt: now.Add(-time.Hour),
+			endAt:   now.Add(time.Hour),
+			wantErr: ErrAuctionInactive,
+		},
+		{
+			name:    "Cancelled item that had not started",
+			status:  items.ItemStatusCancelled,
+			startAt: now.Add(time.Hour),
+			endAt:   now.Add(2 * time.Hour),
+			wantErr: ErrAuctionInactive,
+		},
+		{
+			name:    "Scheduled item",
+			status:  items.ItemStatusScheduled,
+			startAt: now.Add(time.Hour),
+			endAt:   now.Add(2 * time.Hour),
+			wantErr: ErrAuctionNotStarted,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateAuctionNotEnded(tt.endAt)
-			assert.Equal(t, tt.wantErr, err)
+			item := &items.Item{Status: tt.status, StartAt: tt.startAt, EndAt: tt.endAt}
+			assert.Equal(t, tt.wantErr, validateAuctionOpen(item))
 		})
 	}
 }
+
+func TestAntiSnipePolicy_ExtendedEnd(t *testing.T) {
+	policy := AntiSnipePolicy{
+		Window:        60 * time.Second,
+		Extension:     2 * time.Minute,
+		MaxExtensions: 3,
+	}
+	endAt := time.Now().Add(10 * time.Minute)
+
+	tests := []struct {
+		name         string
+		policy       AntiSnipePolicy
+		bidAt        time.Time
+		extensions   int
+		wantEnd      time.Time
+		wantExtended bool
+	}{
+		{
+			name:         "Bid outside window",
+			policy:       policy,
+			bidAt:        endAt.Add(-61 * time.Second),
+			wantEnd:      endAt,
+			wantExtended: false,
+		},
+		{
+			name:         "Bid at window edge",
+			policy:       policy,
+			bidAt:        endAt.Add(-60 * time.Second),
+			wantEnd:      endAt.Add(2 * time.Minute),
+			wantExtended: true,
+		},
+		{
+			name:         "Bid inside window",
+			policy:       policy,
+			bidAt:        endAt.Add(-5 * time.Second),
+			extensions:   2,
+			wantEnd:      endAt.Add(2 * time.Minute),
+			wantExtended: true,
+		},
+		{
+			name:         "Max extensions reached",
+			policy:       policy,
+			bidAt:        endAt.Add(-5 * time.Second),
+			extensions:   3,
+			wantEnd:      endAt,
+			wantExtended: false,
+		},
+		{
+			name:         "Disabled policy",
+			policy:       AntiSnipePolicy{},
+			bidAt:        endAt.Add(-5 * time.Second),
+			wantEnd:      endAt,
+			wantExtended: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotEnd, gotExtended := tt.policy.ExtendedEnd(tt.bidAt, endAt, tt.extensions)
+			assert.Equal(t, tt.wantExtended, gotExtended)
+			assert.True(t, tt.wantEnd.Equal(gotEnd), "end: want %v, got %v", tt.wantEnd, gotEnd)
+		})
+	}
+}
+
+// fakeTxManager runs functions in a no-op transaction; repositories are mocked
+type fakeTxManager struct{}
+
+func (fakeTxManager) BeginTx(context.Context) (pgx.Tx, error) { return nil, nil }
+
+func (fakeTxManager) BeginTxWithOptions(context.Context, pgx.TxOptions) (pgx.Tx, error) {
+	return nil, nil
+}
+
+func (fakeTxManager) WithTx(_ context.Context, fn func(pgx.Tx) error) error { return fn(nil) }
+
+// The mocks implement what CancelItem uses; anything else panics on the nil embedded interface
+type mockItemRepository struct {
+	mock.Mock
+	ItemRepository
+}
+
+func (m *mockItemRepository) GetItemByIDForUpdate(ctx context.Context, tx pgx.Tx, itemID uuid.UUID) (*items.Item, error) {
+	args := m.Called(ctx, tx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*items.Item), args.Error(1)
+}
+
+func (m *mockItemRepository) UpdateStatusInTx(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, status items.ItemStatus) error {
+	args := m.Called(ctx, tx, itemID, status)
+	return args.Error(0)
+}
+
+type mockBidRepository struct {
+	mock.Mock
+	BidRepository
+}
+
+func (m *mockBidRepository) GetHighestBid(ctx context.Context, tx pgx.Tx, itemID uuid.UUID) (*Bid, error) {
+	args := m.Called(ctx, tx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Bid), args.Error(1)
+}
+
+type mockOutboxRepository struct {
+	mock.Mock
+	OutboxRepository
+}
+
+func (m *mockOutboxRepository) SaveEvent(ctx context.Context, tx pgx.Tx, event *events.OutboxEvent) error {
+	args := m.Called(ctx, tx, event)
+	return args.Error(0)
+}
+
+func TestAuctionService_CancelItem(t *testing.T) {
+	itemID := uuid.New()
+	ownerID := uuid.New()
+	otherUserID := uuid.New()
+	errOutboxDown := errors.New("outbox down")
+
+	tests := []struct {
+		name      string
+		userID    uuid.UUID
+		setupMock func(*mockItemRepository, *mockBidRepository, *mockOutboxRepository)
+		wantErr   error
+	}{
+		{
+			name:   "successfully cancels item with no bids",
+			userID: ownerID,
+			setupMock: func(itemRepo *mockItemRepository, bidRepo *mockBidRepository, outboxRepo *mockOutboxRepository) {
+				itemRepo.On("GetItemByIDForUpdate", mock.Anything, mock.Anything, itemID).Return(&items.Item{
+					ID:       itemID,
+					SellerID: ownerID,
+					Status:   items.ItemStatusActive,
+				}, nil)
+				bidRepo.On("GetHighestBid", mock.Anything, mock.Anything, itemID).Return(nil, nil)
+				itemRepo.On("UpdateStatusInTx", mock.Anything, mock.Anything, itemID, items.ItemStatusCancelled).Return(nil)
+				outboxRepo.On("SaveEvent", mock.Anything, mock.Anything, mock.MatchedBy(func(e *events.OutboxEvent) bool {
+					return e.AggregateID == itemID && e.EventType == EventTypeItemCancelled.String()
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "successfully cancels scheduled item",
+			userID: ownerID,
+			setupMock: func(itemRepo *mockItemRepository, bidRepo *mockBidRepository, outboxRepo *mockOutboxRepository) {
+				itemRepo.On("GetItemByIDForUpdate", mock.Anything, mock.Anything, itemID).Return(&items.Item{
+					ID:       itemID,
+					SellerID: ownerID,
+					Status:   items.ItemStatusScheduled,
+				}, nil)
+				bidRepo.On("GetHighestBid", mock.Anything, mock.Anything, itemID).Return(nil, nil)
+				itemRepo.On("UpdateStatusInTx", mock.Anything, mock.Anything, itemID, items.ItemStatusCancelled).Return(nil)
+				outboxRepo.On("SaveEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:   "fails when item not found",
+			userID: ownerID,
+			setupMock: func(itemRepo *mockItemRepository, _ *mockBidRepository, _ *mockOutboxRepository) {
+				itemRepo.On("GetItemByIDForUpdate", mock.Anything, mock.Anything, itemID).Return(nil, items.ErrItemNotFound)
+			},
+			wantErr: items.ErrItemNotFound,
+		},
+		{
+			name:   "fails when user is not owner",
+			userID: otherUserID,
+			setupMock: func(itemRepo *mockItemRepository, _ *mockBidRepository, _ *mockOutboxRepository) {
+				itemRepo.On("GetItemByIDForUpdate", mock.Anything, mock.Anything, itemID).Return(&items.Item{
+					ID:       itemID,
+					SellerID: ownerID,
+					Status:   items.ItemStatusActive,
+				}, nil)
+			},
+			wantErr: items.ErrUnauthorized,
+		},
+		{
+			name:   "fails when item has bids",
+			userID: ownerID,
+			setupMock: func(itemRepo *mockItemRepository, bidRepo *mockBidRepository, _ *mockOutboxRepository) {
+				itemRepo.On("GetItemByIDForUpdate", mock.Anything, mock.Anything, itemID).Return(&items.Item{
+					ID:       itemID,
+					SellerID: ownerID,
+					Status:   items.ItemStatusActive,
+				}, nil)
+				bidRepo.On("GetHighestBid", mock.Anything, mock.Anything, itemID).Return(&Bid{ItemID: itemID, Amount: 500}, nil)
+			},
+			wantErr: items.ErrCannotCancel,
+		},
+		{
+			name:   "fails when item is not active",
+			userID: ownerID,
+			setupMock: func(itemRepo *mockItemRepository, bidRepo *mockBidRepository, _ *mockOutboxRepository) {
+				itemRepo.On("GetItemByIDForUpdate", mock.Anything, mock.Anything, itemID).Return(&items.Item{
+					ID:       itemID,
+					SellerID: ownerID,
+					Status:   items.ItemStatusEnded,
+				}, nil)
+				bidRepo.On("GetHighestBid", mock.Anything, mock.Anything, itemID).Return(nil, nil)
+			},
+			wantErr: items.ErrCannotCancel,
+		},
+		{
+			name:   "fails when the outbox write fails",
+			userID: ownerID,
+			setupMock: func(itemRepo *mockItemRepository, bidRepo *mockBidRepository, outboxRepo *mockOutboxRepository) {
+				itemRepo.On("GetItemByIDForUpdate", mock.Anything, mock.Anything, itemID).Return(&items.Item{
+					ID:       itemID,
+					SellerID: ownerID,
+					Status:   items.ItemStatusActive,
+				}, nil)
+				bidRepo.On("GetHighestBid", mock.Anything, mock.Anything, itemID).Return(nil, nil)
+				itemRepo.On("UpdateStatusInTx", mock.Anything, mock.Anything, itemID, items.ItemStatusCancelled).Return(nil)
+				outboxRepo.On("SaveEvent", mock.Anything, mock.Anything, mock.Anything).Return(errOutboxDown)
+			},
+			wantErr: errOutboxDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			itemRepo := new(mockItemRepository)
+			bidRepo := new(mockBidRepository)
+			outboxRepo := new(mockOutboxRepository)
+			tt.setupMock(itemRepo, bidRepo, outboxRepo)
+
+			service := NewAuctionService(fakeTxManager{}, bidRepo, itemRepo, outboxRepo)
+			item, err := service.CancelItem(context.Background(), itemID, tt.userID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, item)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, item)
+				assert.Equal(t, items.ItemStatusCancelled, item.Status)
+			}
+
+			itemRepo.AssertExpectations(t)
+			bidRepo.AssertExpectations(t)
+			outboxRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuctionService_ForceCancelItem(t *testing.T) {
+	itemID := uuid.New()
+	sellerID := uuid.New()
+	adminID := uuid.New()
+
+	t.Run("cancels another seller's item with bids", func(t *testing.T) {
+		itemRepo := new(mockItemRepository)
+		bidRepo := new(mockBidRepository)
+		outboxRepo := new(mockOutboxRepository)
+		itemRepo.On("GetItemByIDForUpdate", mock.Anything, mock.Anything, itemID).Return(&items.Item{
+			ID:                itemID,
+			SellerID:          sellerID,
+			Status:            items.ItemStatusActive,
+			CurrentHighestBid: 500,
+		}, nil)
+		itemRepo.On("UpdateStatusInTx", mock.Anything, mock.Anything, itemID, items.ItemStatusCancelled).Return(nil)
+		outboxRepo.On("SaveEvent", mock.Anything, mock.Anything, mock.MatchedBy(func(e *events.OutboxEvent) bool {
+			var event pb.ItemCancelled
+			return e.EventType == EventTypeItemCancelled.String() &&
+				proto.Unmarshal(e.Payload, &event) == nil && event.SellerId == sellerID.String()
+		})).Return(nil)
+
+		service := NewAuctionService(fakeTxManager{}, bidRepo, itemRepo, outboxRepo)
+		item, err := service.ForceCancelItem(context.Background(), itemID, adminID)
+
+		require.NoError(t, err)
+		assert.Equal(t, items.ItemStatusCancelled, item.Status)
+		itemRepo.AssertExpectations(t)
+		outboxRepo.AssertExpectations(t)
+	})
+
+	t.Run("fails when the auction has ended", func(t *testing.T) {
+		itemRepo := new(mockItemRepository)
+		itemRepo.On("GetItemByIDForUpdate", mock.Anything, mock.Anything, itemID).Return(&items.Item{
+			ID:       itemID,
+			SellerID: sellerID,
+			Status:   items.ItemStatusEnded,
+		}, nil)
+
+		service := NewAuctionService(fakeTxManager{}, new(mockBidRepository), itemRepo, new(mockOutboxRepository))
+		item, err := service.ForceCancelItem(context.Background(), itemID, adminID)
+
+		assert.ErrorIs(t, err, ErrInvalidTransition)
+		assert.Nil(t, item)
+	})
+}
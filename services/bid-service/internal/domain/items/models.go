@@ -10,15 +10,17 @@ import (
 type ItemStatus string
 
 const (
-	ItemStatusActive    ItemStatus = "active"
-	ItemStatusEnded     ItemStatus = "ended"
-	ItemStatusCancelled ItemStatus = "cancelled"
+	ItemStatusScheduled   ItemStatus = "scheduled"
+	ItemStatusActive      ItemStatus = "active"
+	ItemStatusEnded       ItemStatus = "ended"
+	ItemStatusEndedUnsold ItemStatus = "ended_unsold"
+	ItemStatusCancelled   ItemStatus = "cancelled"
 )
 
 // IsValid checks if the status is valid
 func (s ItemStatus) IsValid() bool {
 	switch s {
-	case ItemStatusActive, ItemStatusEnded, ItemStatusCancelled:
+	case ItemStatusScheduled, ItemStatusActive, ItemStatusEnded, ItemStatusEndedUnsold, ItemStatusCancelled:
 		return true
 	default:
 		return false
@@ -32,23 +34,79 @@ type Item struct {
 	Description       string
 	StartPrice        int64 // in cents/micros
 	CurrentHighestBid int64
-	EndAt             time.Time
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
-	Images            []string
-	Category          string
-	SellerID          uuid.UUID
-	Status            ItemStatus
+	// CurrentHighestBidderID holds CurrentHighestBid; uuid.Nil while the item has no bids
+	CurrentHighestBidderID uuid.UUID
+	ReservePrice           int64 // hidden from buyers; 0 means no reserve
+	BuyNowPrice            int64 // 0 means no buy-now option
+	StartAt                time.Time
+	EndAt                  time.Time
+	ExtensionCount         int // times a late bid has pushed EndAt back
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+	Images                 []string
+	Category               string
+	SellerID               uuid.UUID
+	Status                 ItemStatus
 }
 
-// IsActive returns true if the item is in active status and has not ended
+// IsActive returns true if the item is in active status and between its start and end times
 func (i *Item) IsActive() bool {
-	return i.Status == ItemStatusActive && time.Now().Before(i.EndAt)
+	now := time.Now()
+	return i.Status == ItemStatusActive && !now.Before(i.StartAt) && now.Before(i.EndAt)
 }
 
-// CanBeCancelled returns true if the item can be cancelled (active and no bids)
+// HasStarted returns true once the item's start time has passed
+func (i *Item) HasStarted() bool {
+	return !time.Now().Before(i.StartAt)
+}
+
+// ActivateIfDue moves a scheduled item to active once its start time has passed.
+// It reports whether the status changed, so callers know to persist it.
+func (i *Item) ActivateIfDue() bool {
+	if i.Status != ItemStatusScheduled || !i.HasStarted() {
+		return false
+	}
+	i.Status = ItemStatusActive
+	return true
+}
+
+// CanTransitionTo reports whether the item may move from its current status to next.
+// Scheduled items open or get cancelled, active items end or get cancelled,
+// and ended, ended unsold and cancelled are final.
+func (i *Item) CanTransitionTo(next ItemStatus) bool {
+	switch i.Status {
+	case ItemStatusScheduled:
+		return next == ItemStatusActive || next == ItemStatusCancelled
+	case ItemStatusActive:
+		return next == ItemStatusEnded || next == ItemStatusEndedUnsold || next == ItemStatusCancelled
+	default:
+		return false
+	}
+}
+
+// HasBuyNow returns true if the item can be bought outright
+func (i *Item) HasBuyNow() bool {
+	return i.BuyNowPrice > 0
+}
+
+// ReserveMet returns true if the highest bid reaches the reserve price.
+// An item without a reserve needs at least one bid.
+func (i *Item) ReserveMet() bool {
+	return i.CurrentHighestBid > 0 && i.CurrentHighestBid >= i.ReservePrice
+}
+
+// EndStatus returns the status the item takes when its auction ends:
+// sold (ended) if the reserve was met, otherwise ended unsold
+func (i *Item) EndStatus() ItemStatus {
+	if i.ReserveMet() {
+		return ItemStatusEnded
+	}
+	return ItemStatusEndedUnsold
+}
+
+// CanBeCancelled returns true if the item can be cancelled (not yet ended and no bids)
 func (i *Item) CanBeCancelled(hasBids bool) bool {
-	return i.Status == ItemStatusActive && !hasBids
+	return (i.Status == ItemStatusActive || i.Status == ItemStatusScheduled) && !hasBids
 }
 
 // IsOwnedBy returns true if the item is owned by the given user
@@ -14,6 +14,11 @@ func TestItemStatus_IsValid(t *testing.T) {
 		status ItemStatus
 		want   bool
 	}{
+		{
+			name:   "scheduled status is valid",
+			status: ItemStatusScheduled,
+			want:   true,
+		},
 		{
 			name:   "active status is valid",
 			status: ItemStatusActive,
@@ -24,6 +29,11 @@ func TestItemStatus_IsValid(t *testing.T) {
 			status: ItemStatusEnded,
 			want:   true,
 		},
+		{
+			name:   "ended unsold status is valid",
+			status: ItemStatusEndedUnsold,
+			want:   true,
+		},
 		{
 			name:   "cancelled status is valid",
 			status: ItemStatusCancelled,
@@ -82,6 +92,60 @@ func TestItem_IsActive(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "active item below reserve is still active",
+			item: &Item{
+				Status:            ItemStatusActive,
+				EndAt:             time.Now().Add(1 * time.Hour),
+				CurrentHighestBid: 100,
+				ReservePrice:      5000,
+			},
+			want: true,
+		},
+		{
+			name: "ended unsold item",
+			item: &Item{
+				Status: ItemStatusEndedUnsold,
+				EndAt:  time.Now().Add(-1 * time.Hour),
+			},
+			want: false,
+		},
+		{
+			name: "active item that has started",
+			item: &Item{
+				Status:  ItemStatusActive,
+				StartAt: time.Now().Add(-1 * time.Hour),
+				EndAt:   time.Now().Add(1 * time.Hour),
+			},
+			want: true,
+		},
+		{
+			name: "active item before its start time",
+			item: &Item{
+				Status:  ItemStatusActive,
+				StartAt: time.Now().Add(1 * time.Hour),
+				EndAt:   time.Now().Add(2 * time.Hour),
+			},
+			want: false,
+		},
+		{
+			name: "scheduled item before its start time",
+			item: &Item{
+				Status:  ItemStatusScheduled,
+				StartAt: time.Now().Add(1 * time.Hour),
+				EndAt:   time.Now().Add(2 * time.Hour),
+			},
+			want: false,
+		},
+		{
+			name: "scheduled item past its start time is not active until opened",
+			item: &Item{
+				Status:  ItemStatusScheduled,
+				StartAt: time.Now().Add(-1 * time.Hour),
+				EndAt:   time.Now().Add(1 * time.Hour),
+			},
+			want: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -92,6 +156,138 @@ func TestItem_IsActive(t *testing.T) {
 	}
 }
 
+func TestItem_ActivateIfDue(t *testing.T) {
+	tests := []struct {
+		name       string
+		item       *Item
+		want       bool
+		wantStatus ItemStatus
+	}{
+		{
+			name: "scheduled item past its start time opens",
+			item: &Item{
+				Status:  ItemStatusScheduled,
+				StartAt: time.Now().Add(-1 * time.Minute),
+				EndAt:   time.Now().Add(1 * time.Hour),
+			},
+			want:       true,
+			wantStatus: ItemStatusActive,
+		},
+		{
+			name: "scheduled item before its start time stays scheduled",
+			item: &Item{
+				Status:  ItemStatusScheduled,
+				StartAt: time.Now().Add(1 * time.Hour),
+				EndAt:   time.Now().Add(2 * time.Hour),
+			},
+			want:       false,
+			wantStatus: ItemStatusScheduled,
+		},
+		{
+			name: "active item is left alone",
+			item: &Item{
+				Status:  ItemStatusActive,
+				StartAt: time.Now().Add(-1 * time.Hour),
+				EndAt:   time.Now().Add(1 * time.Hour),
+			},
+			want:       false,
+			wantStatus: ItemStatusActive,
+		},
+		{
+			name: "cancelled item is never opened",
+			item: &Item{
+				Status:  ItemStatusCancelled,
+				StartAt: time.Now().Add(-1 * time.Hour),
+				EndAt:   time.Now().Add(1 * time.Hour),
+			},
+			want:       false,
+			wantStatus: ItemStatusCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.item.ActivateIfDue())
+			assert.Equal(t, tt.wantStatus, tt.item.Status)
+		})
+	}
+}
+
+func TestItem_ReserveMet(t *testing.T) {
+	tests := []struct {
+		name       string
+		item       *Item
+		want       bool
+		wantStatus ItemStatus
+	}{
+		{
+			name:       "highest bid above reserve",
+			item:       &Item{CurrentHighestBid: 6000, ReservePrice: 5000},
+			want:       true,
+			wantStatus: ItemStatusEnded,
+		},
+		{
+			name:       "highest bid equal to reserve",
+			item:       &Item{CurrentHighestBid: 5000, ReservePrice: 5000},
+			want:       true,
+			wantStatus: ItemStatusEnded,
+		},
+		{
+			name:       "highest bid below reserve",
+			item:       &Item{CurrentHighestBid: 4999, ReservePrice: 5000},
+			want:       false,
+			wantStatus: ItemStatusEndedUnsold,
+		},
+		{
+			name:       "no reserve with bids",
+			item:       &Item{CurrentHighestBid: 100},
+			want:       true,
+			wantStatus: ItemStatusEnded,
+		},
+		{
+			name:       "no reserve without bids",
+			item:       &Item{},
+			want:       false,
+			wantStatus: ItemStatusEndedUnsold,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.item.ReserveMet())
+			assert.Equal(t, tt.wantStatus, tt.item.EndStatus())
+		})
+	}
+}
+
+func TestItem_CanTransitionTo(t *testing.T) {
+	statuses := []ItemStatus{ItemStatusScheduled, ItemStatusActive, ItemStatusEnded, ItemStatusEndedUnsold, ItemStatusCancelled}
+	allowed := map[ItemStatus]map[ItemStatus]bool{
+		ItemStatusScheduled: {
+			ItemStatusActive:    true,
+			ItemStatusCancelled: true,
+		},
+		ItemStatusActive: {
+			ItemStatusEnded:       true,
+			ItemStatusEndedUnsold: true,
+			ItemStatusCancelled:   true,
+		},
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			t.Run(string(from)+" to "+string(to), func(t *testing.T) {
+				item := &Item{Status: from}
+				assert.Equal(t, allowed[from][to], item.CanTransitionTo(to))
+			})
+		}
+		t.Run(string(from)+" to invalid", func(t *testing.T) {
+			item := &Item{Status: from}
+			assert.False(t, item.CanTransitionTo(ItemStatus("invalid")))
+		})
+	}
+}
+
 func TestItem_CanBeCancelled(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -115,6 +311,14 @@ func TestItem_CanBeCancelled(t *testing.T) {
 			hasBids: true,
 			want:    false,
 		},
+		{
+			name: "scheduled item can be cancelled",
+			item: &Item{
+				Status: ItemStatusScheduled,
+			},
+			hasBids: false,
+			want:    true,
+		},
 		{
 			name: "ended item cannot be cancelled",
 			item: &Item{
@@ -12,12 +12,12 @@ type Repository interface {
 	// CreateItem creates a new auction item
 	CreateItem(ctx context.Context, item *Item) error
 
-	// GetItemByID retrieves an item by its ID
+	// GetItemByID retrieves an item by its ID, or returns ErrItemNotFound
 	GetItemByID(ctx context.Context, itemID uuid.UUID) (*Item, error)
 
 	// GetItemByIDForUpdate retrieves an item by its ID and locks it for update
 	// This prevents race conditions when multiple users bid on the same item
-	// Must be called within a transaction
+	// Must be called within a transaction. Returns ErrItemNotFound if there is no such item
 	GetItemByIDForUpdate(ctx context.Context, tx pgx.Tx, itemID uuid.UUID) (*Item, error)
 
 	// UpdateItem updates an item's editable fields (title, description, images, category)
@@ -26,8 +26,14 @@ type Repository interface {
 	// UpdateStatus updates an item's status
 	UpdateStatus(ctx context.Context, itemID uuid.UUID, status ItemStatus) error
 
-	// UpdateHighestBid updates the current highest bid for an item within a transaction
-	UpdateHighestBid(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, amount int64) error
+	// ActivateDueItems moves scheduled items whose start time has passed to active
+	// and returns how many were activated
+	ActivateDueItems(ctx context.Context) (int64, error)
+
+	// UpdateHighestBid updates the current highest bid and its bidder for an item within a transaction,
+	// provided the highest bid is still expected. A uuid.Nil bidder clears it.
+	// If another transaction changed the highest bid first, nothing is updated and it fails with database.ErrConflict.
+	UpdateHighestBid(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, expected, amount int64, bidderID uuid.UUID) error
 
 	// ListActiveItems retrieves active items with pagination
 	ListActiveItems(ctx context.Context, limit, offset int) ([]*Item, error)
@@ -35,6 +41,10 @@ type Repository interface {
 	// ListItemsBySellerID retrieves all items for a specific seller
 	ListItemsBySellerID(ctx context.Context, sellerID uuid.UUID, limit, offset int) ([]*Item, error)
 
+	// SearchItems returns a page of items matching the full-text query, category and status,
+	// along with the total number of matches. Empty filters match every item.
+	SearchItems(ctx context.Context, query, category string, status ItemStatus, limit, offset int) ([]*Item, int64, error)
+
 	// CountBidsByItemID returns the number of bids for a specific item
 	CountBidsByItemID(ctx context.Context, itemID uuid.UUID) (int64, error)
 }
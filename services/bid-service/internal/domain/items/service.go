@@ -3,6 +3,7 @@ package items
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,23 +12,37 @@ import (
 // Service errors
 var (
 	ErrInvalidStartPrice = fmt.Errorf("start price must be greater than 0")
+	ErrInvalidReserve    = fmt.Errorf("reserve price cannot be negative")
+	ErrInvalidBuyNow     = fmt.Errorf("buy now price must be above the start price and not below the reserve")
 	ErrInvalidEndTime    = fmt.Errorf("end time must be in the future")
+	ErrInvalidStartTime  = fmt.Errorf("start time must be before the end time")
 	ErrItemNotFound      = fmt.Errorf("item not found")
 	ErrUnauthorized      = fmt.Errorf("unauthorized: only the owner can perform this action")
 	ErrCannotCancel      = fmt.Errorf("cannot cancel item: item has bids or is not active")
 	ErrItemNotActive     = fmt.Errorf("item is not active")
 	ErrSellerCannotBid   = fmt.Errorf("seller cannot bid on their own item")
+	ErrInvalidTransition = fmt.Errorf("invalid item status transition")
+	ErrInvalidStatus     = fmt.Errorf("invalid item status")
+)
+
+// Search pagination bounds
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
 )
 
 // CreateItemCommand represents the command to create a new item
 type CreateItemCommand struct {
-	Title       string
-	Description string
-	StartPrice  int64
-	EndAt       time.Time
-	Images      []string
-	Category    string
-	SellerID    uuid.UUID
+	Title        string
+	Description  string
+	StartPrice   int64
+	ReservePrice int64
+	BuyNowPrice  int64
+	StartAt      time.Time // zero starts the auction immediately
+	EndAt        time.Time
+	Images       []string
+	Category     string
+	SellerID     uuid.UUID
 }
 
 // UpdateItemCommand represents the command to update an item
@@ -40,12 +55,6 @@ type UpdateItemCommand struct {
 	Category    string
 }
 
-// CancelItemCommand represents the command to cancel an item
-type CancelItemCommand struct {
-	ItemID uuid.UUID
-	UserID uuid.UUID
-}
-
 // ListItemsQuery represents pagination parameters for listing items
 type ListItemsQuery struct {
 	Limit  int
@@ -59,6 +68,21 @@ type ListSellerItemsQuery struct {
 	Offset   int
 }
 
+// SearchItemsQuery filters and paginates an item search. Empty filters match every item.
+type SearchItemsQuery struct {
+	Query    string // full-text match on title and description
+	Category string
+	Status   ItemStatus
+	Limit    int
+	Offset   int
+}
+
+// SearchItemsResult is a page of search results
+type SearchItemsResult struct {
+	Items []*Item
+	Total int64 // matches across all pages
+}
+
 // Service implements the core business logic for items
 type Service struct {
 	repo Repository
@@ -76,11 +100,30 @@ func (s *Service) CreateItem(ctx context.Context, cmd CreateItemCommand) (*Item,
 		return nil, ErrInvalidStartPrice
 	}
 
+	if cmd.ReservePrice < 0 {
+		return nil, ErrInvalidReserve
+	}
+
+	// A buy-now price is optional, but must be worth more than bidding from the start
+	if cmd.BuyNowPrice < 0 || (cmd.BuyNowPrice > 0 && (cmd.BuyNowPrice <= cmd.StartPrice || cmd.BuyNowPrice < cmd.ReservePrice)) {
+		return nil, ErrInvalidBuyNow
+	}
+
 	// Validate end time
-	if !cmd.EndAt.After(time.Now()) {
+	now := time.Now()
+	if !cmd.EndAt.After(now) {
 		return nil, ErrInvalidEndTime
 	}
 
+	// A future start time schedules the auction instead of opening it now
+	startAt, status := now, ItemStatusActive
+	if cmd.StartAt.After(now) {
+		startAt, status = cmd.StartAt, ItemStatusScheduled
+	}
+	if !startAt.Before(cmd.EndAt) {
+		return nil, ErrInvalidStartTime
+	}
+
 	// Create item
 	item := &Item{
 		ID:                uuid.New(),
@@ -88,13 +131,16 @@ func (s *Service) CreateItem(ctx context.Context, cmd CreateItemCommand) (*Item,
 		Description:       cmd.Description,
 		StartPrice:        cmd.StartPrice,
 		CurrentHighestBid: 0,
+		ReservePrice:      cmd.ReservePrice,
+		BuyNowPrice:       cmd.BuyNowPrice,
+		StartAt:           startAt,
 		EndAt:             cmd.EndAt,
-		CreatedAt:         time.Now(),
-		UpdatedAt:         time.Now(),
+		CreatedAt:         now,
+		UpdatedAt:         now,
 		Images:            cmd.Images,
 		Category:          cmd.Category,
 		SellerID:          cmd.SellerID,
-		Status:            ItemStatusActive,
+		Status:            status,
 	}
 
 	if err := s.repo.CreateItem(ctx, item); err != nil {
@@ -108,7 +154,7 @@ func (s *Service) CreateItem(ctx context.Context, cmd CreateItemCommand) (*Item,
 func (s *Service) GetItem(ctx context.Context, itemID uuid.UUID) (*Item, error) {
 	item, err := s.repo.GetItemByID(ctx, itemID)
 	if err != nil {
-		return nil, ErrItemNotFound
+		return nil, err
 	}
 	return item, nil
 }
@@ -131,12 +177,33 @@ func (s *Service) ListSellerItems(ctx context.Context, query ListSellerItemsQuer
 	return items, nil
 }
 
+// SearchItems finds items by text, category and status. Text matches are ordered by relevance,
+// and otherwise by end time. A non-positive limit falls back to DefaultSearchLimit and larger
+// pages are capped at MaxSearchLimit.
+func (s *Service) SearchItems(ctx context.Context, query SearchItemsQuery) (*SearchItemsResult, error) {
+	if query.Status != "" && !query.Status.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	limit = min(limit, MaxSearchLimit)
+	offset := max(query.Offset, 0)
+
+	found, total, err := s.repo.SearchItems(ctx, strings.TrimSpace(query.Query), strings.TrimSpace(query.Category), query.Status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search items: %w", err)
+	}
+	return &SearchItemsResult{Items: found, Total: total}, nil
+}
+
 // UpdateItem updates an item's editable fields
 func (s *Service) UpdateItem(ctx context.Context, cmd UpdateItemCommand) (*Item, error) {
 	// Get the item
 	item, err := s.repo.GetItemByID(ctx, cmd.ItemID)
 	if err != nil {
-		return nil, ErrItemNotFound
+		return nil, err
 	}
 
 	// Check ownership
@@ -158,47 +225,21 @@ func (s *Service) UpdateItem(ctx context.Context, cmd UpdateItemCommand) (*Item,
 	return item, nil
 }
 
-// CancelItem cancels an auction item
-func (s *Service) CancelItem(ctx context.Context, cmd CancelItemCommand) (*Item, error) {
-	// Get the item
-	item, err := s.repo.GetItemByID(ctx, cmd.ItemID)
-	if err != nil {
-		return nil, ErrItemNotFound
-	}
-
-	// Check ownership
-	if !item.IsOwnedBy(cmd.UserID) {
-		return nil, ErrUnauthorized
-	}
-
-	// Check if item has bids
-	bidCount, err := s.repo.CountBidsByItemID(ctx, cmd.ItemID)
+// StartDueAuctions opens every scheduled auction whose start time has passed
+// and returns how many were opened
+func (s *Service) StartDueAuctions(ctx context.Context) (int64, error) {
+	started, err := s.repo.ActivateDueItems(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check bids: %w", err)
+		return 0, fmt.Errorf("failed to start due auctions: %w", err)
 	}
-
-	hasBids := bidCount > 0
-
-	// Check if item can be cancelled
-	if !item.CanBeCancelled(hasBids) {
-		return nil, ErrCannotCancel
-	}
-
-	// Update status to cancelled
-	if err := s.repo.UpdateStatus(ctx, cmd.ItemID, ItemStatusCancelled); err != nil {
-		return nil, fmt.Errorf("failed to cancel item: %w", err)
-	}
-
-	// Update item status and return
-	item.Status = ItemStatusCancelled
-	return item, nil
+	return started, nil
 }
 
 // ValidateSellerCannotBid checks if a user is trying to bid on their own item
 func (s *Service) ValidateSellerCannotBid(ctx context.Context, itemID, userID uuid.UUID) error {
 	item, err := s.repo.GetItemByID(ctx, itemID)
 	if err != nil {
-		return ErrItemNotFound
+		return err
 	}
 
 	if item.SellerID == userID {
@@ -48,8 +48,13 @@ func (m *MockRepository) UpdateStatus(ctx context.Context, itemID uuid.UUID, sta
 	return args.Error(0)
 }
 
-func (m *MockRepository) UpdateHighestBid(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, amount int64) error {
-	args := m.Called(ctx, tx, itemID, amount)
+func (m *MockRepository) ActivateDueItems(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) UpdateHighestBid(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, expected, amount int64, bidderID uuid.UUID) error {
+	args := m.Called(ctx, tx, itemID, expected, amount, bidderID)
 	return args.Error(0)
 }
 
@@ -69,6 +74,14 @@ func (m *MockRepository) ListItemsBySellerID(ctx context.Context, sellerID uuid.
 	return args.Get(0).([]*Item), args.Error(1)
 }
 
+func (m *MockRepository) SearchItems(ctx context.Context, query, category string, status ItemStatus, limit, offset int) ([]*Item, int64, error) {
+	args := m.Called(ctx, query, category, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*Item), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockRepository) CountBidsByItemID(ctx context.Context, itemID uuid.UUID) (int64, error) {
 	args := m.Called(ctx, itemID)
 	return args.Get(0).(int64), args.Error(1)
@@ -105,6 +118,56 @@ func TestService_CreateItem(t *testing.T) {
 				assert.Equal(t, int64(0), item.CurrentHighestBid)
 			},
 		},
+		{
+			name: "schedules item with a future start time",
+			cmd: CreateItemCommand{
+				Title:      "Test Item",
+				StartPrice: 1000,
+				StartAt:    time.Now().Add(1 * time.Hour),
+				EndAt:      time.Now().Add(24 * time.Hour),
+				SellerID:   uuid.New(),
+			},
+			setupMock: func(repo *MockRepository) {
+				repo.On("CreateItem", mock.Anything, mock.AnythingOfType("*items.Item")).Return(nil)
+			},
+			wantErr: nil,
+			checkResult: func(t *testing.T, item *Item) {
+				assert.Equal(t, ItemStatusScheduled, item.Status)
+				assert.False(t, item.HasStarted())
+			},
+		},
+		{
+			name: "past start time opens item immediately",
+			cmd: CreateItemCommand{
+				Title:      "Test Item",
+				StartPrice: 1000,
+				StartAt:    time.Now().Add(-1 * time.Hour),
+				EndAt:      time.Now().Add(24 * time.Hour),
+				SellerID:   uuid.New(),
+			},
+			setupMock: func(repo *MockRepository) {
+				repo.On("CreateItem", mock.Anything, mock.AnythingOfType("*items.Item")).Return(nil)
+			},
+			wantErr: nil,
+			checkResult: func(t *testing.T, item *Item) {
+				assert.Equal(t, ItemStatusActive, item.Status)
+				assert.True(t, item.IsActive())
+			},
+		},
+		{
+			name: "fails when start time is not before end time",
+			cmd: CreateItemCommand{
+				Title:      "Test Item",
+				StartPrice: 1000,
+				StartAt:    time.Now().Add(48 * time.Hour),
+				EndAt:      time.Now().Add(24 * time.Hour),
+				SellerID:   uuid.New(),
+			},
+			setupMock: func(repo *MockRepository) {
+				// No repo calls expected
+			},
+			wantErr: ErrInvalidStartTime,
+		},
 		{
 			name: "fails with invalid start price (zero)",
 			cmd: CreateItemCommand{
@@ -144,6 +207,86 @@ func TestService_CreateItem(t *testing.T) {
 			},
 			wantErr: ErrInvalidEndTime,
 		},
+		{
+			name: "successfully creates item with reserve",
+			cmd: CreateItemCommand{
+				Title:        "Test Item",
+				StartPrice:   1000,
+				ReservePrice: 5000,
+				EndAt:        time.Now().Add(24 * time.Hour),
+				SellerID:     uuid.New(),
+			},
+			setupMock: func(repo *MockRepository) {
+				repo.On("CreateItem", mock.Anything, mock.MatchedBy(func(item *Item) bool {
+					return item.ReservePrice == 5000
+				})).Return(nil)
+			},
+			wantErr: nil,
+			checkResult: func(t *testing.T, item *Item) {
+				assert.Equal(t, int64(5000), item.ReservePrice)
+			},
+		},
+		{
+			name: "fails with negative reserve",
+			cmd: CreateItemCommand{
+				Title:        "Test Item",
+				StartPrice:   1000,
+				ReservePrice: -1,
+				EndAt:        time.Now().Add(24 * time.Hour),
+				SellerID:     uuid.New(),
+			},
+			setupMock: func(repo *MockRepository) {
+				// No repo calls expected
+			},
+			wantErr: ErrInvalidReserve,
+		},
+		{
+			name: "successfully creates item with buy now price",
+			cmd: CreateItemCommand{
+				Title:       "Test Item",
+				StartPrice:  1000,
+				BuyNowPrice: 8000,
+				EndAt:       time.Now().Add(24 * time.Hour),
+				SellerID:    uuid.New(),
+			},
+			setupMock: func(repo *MockRepository) {
+				repo.On("CreateItem", mock.Anything, mock.AnythingOfType("*items.Item")).Return(nil)
+			},
+			wantErr: nil,
+			checkResult: func(t *testing.T, item *Item) {
+				assert.Equal(t, int64(8000), item.BuyNowPrice)
+				assert.True(t, item.HasBuyNow())
+			},
+		},
+		{
+			name: "fails with buy now price not above start price",
+			cmd: CreateItemCommand{
+				Title:       "Test Item",
+				StartPrice:  1000,
+				BuyNowPrice: 1000,
+				EndAt:       time.Now().Add(24 * time.Hour),
+				SellerID:    uuid.New(),
+			},
+			setupMock: func(repo *MockRepository) {
+				// No repo calls expected
+			},
+			wantErr: ErrInvalidBuyNow,
+		},
+		{
+			name: "fails with buy now price below reserve",
+			cmd: CreateItemCommand{
+				Title:        "Test Item",
+				StartPrice:   1000,
+				ReservePrice: 5000,
+				BuyNowPrice:  4000,
+				EndAt:        time.Now().Add(24 * time.Hour),
+				SellerID:     uuid.New(),
+			},
+			setupMock: func(repo *MockRepository) {
+				// No repo calls expected
+			},
+			wantErr: ErrInvalidBuyNow,
+		},
 	}
 
 	for _, tt := range tests {
@@ -208,7 +351,7 @@ func TestService_UpdateItem(t *testing.T) {
 				UserID: ownerID,
 			},
 			setupMock: func(repo *MockRepository) {
-				repo.On("GetItemByID", mock.Anything, itemID).Return(nil, errors.New("not found"))
+				repo.On("GetItemByID", mock.Anything, itemID).Return(nil, ErrItemNotFound)
 			},
 			wantErr: ErrItemNotFound,
 		},
@@ -251,114 +394,76 @@ func TestService_UpdateItem(t *testing.T) {
 	}
 }
 
-func TestService_CancelItem(t *testing.T) {
-	itemID := uuid.New()
-	ownerID := uuid.New()
-	otherUserID := uuid.New()
+func TestService_SearchItems(t *testing.T) {
+	t.Run("passes trimmed filters and returns the total", func(t *testing.T) {
+		repo := new(MockRepository)
+		found := []*Item{{ID: uuid.New(), Title: "Vintage camera"}}
+		repo.On("SearchItems", mock.Anything, "vintage camera", "electronics", ItemStatusActive, 10, 20).
+			Return(found, int64(21), nil)
+
+		service := NewService(repo)
+		result, err := service.SearchItems(context.Background(), SearchItemsQuery{
+			Query:    "  vintage camera ",
+			Category: "electronics ",
+			Status:   ItemStatusActive,
+			Limit:    10,
+			Offset:   20,
+		})
 
-	tests := []struct {
-		name      string
-		cmd       CancelItemCommand
-		setupMock func(*MockRepository)
-		wantErr   error
-	}{
-		{
-			name: "successfully cancels item with no bids",
-			cmd: CancelItemCommand{
-				ItemID: itemID,
-				UserID: ownerID,
-			},
-			setupMock: func(repo *MockRepository) {
-				repo.On("GetItemByID", mock.Anything, itemID).Return(&Item{
-					ID:       itemID,
-					SellerID: ownerID,
-					Status:   ItemStatusActive,
-				}, nil)
-				repo.On("CountBidsByItemID", mock.Anything, itemID).Return(int64(0), nil)
-				repo.On("UpdateStatus", mock.Anything, itemID, ItemStatusCancelled).Return(nil)
-			},
-			wantErr: nil,
-		},
-		{
-			name: "fails when item not found",
-			cmd: CancelItemCommand{
-				ItemID: itemID,
-				UserID: ownerID,
-			},
-			setupMock: func(repo *MockRepository) {
-				repo.On("GetItemByID", mock.Anything, itemID).Return(nil, errors.New("not found"))
-			},
-			wantErr: ErrItemNotFound,
-		},
-		{
-			name: "fails when user is not owner",
-			cmd: CancelItemCommand{
-				ItemID: itemID,
-				UserID: otherUserID,
-			},
-			setupMock: func(repo *MockRepository) {
-				repo.On("GetItemByID", mock.Anything, itemID).Return(&Item{
-					ID:       itemID,
-					SellerID: ownerID,
-					Status:   ItemStatusActive,
-				}, nil)
-			},
-			wantErr: ErrUnauthorized,
-		},
-		{
-			name: "fails when item has bids",
-			cmd: CancelItemCommand{
-				ItemID: itemID,
-				UserID: ownerID,
-			},
-			setupMock: func(repo *MockRepository) {
-				repo.On("GetItemByID", mock.Anything, itemID).Return(&Item{
-					ID:       itemID,
-					SellerID: ownerID,
-					Status:   ItemStatusActive,
-				}, nil)
-				repo.On("CountBidsByItemID", mock.Anything, itemID).Return(int64(5), nil)
-			},
-			wantErr: ErrCannotCancel,
-		},
-		{
-			name: "fails when item is not active",
-			cmd: CancelItemCommand{
-				ItemID: itemID,
-				UserID: ownerID,
-			},
-			setupMock: func(repo *MockRepository) {
-				repo.On("GetItemByID", mock.Anything, itemID).Return(&Item{
-					ID:       itemID,
-					SellerID: ownerID,
-					Status:   ItemStatusEnded,
-				}, nil)
-				repo.On("CountBidsByItemID", mock.Anything, itemID).Return(int64(0), nil)
-			},
-			wantErr: ErrCannotCancel,
-		},
-	}
+		assert.NoError(t, err)
+		assert.Equal(t, found, result.Items)
+		assert.Equal(t, int64(21), result.Total)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("bounds the page size and offset", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("SearchItems", mock.Anything, "", "", ItemStatus(""), DefaultSearchLimit, 0).
+			Return([]*Item{}, int64(0), nil).Once()
+		repo.On("SearchItems", mock.Anything, "", "", ItemStatus(""), MaxSearchLimit, 0).
+			Return([]*Item{}, int64(0), nil).Once()
+
+		service := NewService(repo)
+		_, err := service.SearchItems(context.Background(), SearchItemsQuery{Offset: -5})
+		assert.NoError(t, err)
+		_, err = service.SearchItems(context.Background(), SearchItemsQuery{Limit: 1000})
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects an unknown status", func(t *testing.T) {
+		repo := new(MockRepository)
+
+		service := NewService(repo)
+		_, err := service.SearchItems(context.Background(), SearchItemsQuery{Status: "sold"})
+
+		assert.ErrorIs(t, err, ErrInvalidStatus)
+		repo.AssertNotCalled(t, "SearchItems")
+	})
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := new(MockRepository)
-			tt.setupMock(repo)
+func TestService_StartDueAuctions(t *testing.T) {
+	t.Run("returns how many auctions opened", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("ActivateDueItems", mock.Anything).Return(int64(3), nil)
 
-			service := NewService(repo)
-			item, err := service.CancelItem(context.Background(), tt.cmd)
+		service := NewService(repo)
+		started, err := service.StartDueAuctions(context.Background())
 
-			if tt.wantErr != nil {
-				assert.ErrorIs(t, err, tt.wantErr)
-				assert.Nil(t, item)
-			} else {
-				assert.NoError(t, err)
-				assert.NotNil(t, item)
-				assert.Equal(t, ItemStatusCancelled, item.Status)
-			}
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), started)
+		repo.AssertExpectations(t)
+	})
 
-			repo.AssertExpectations(t)
-		})
-	}
+	t.Run("wraps repository errors", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("ActivateDueItems", mock.Anything).Return(int64(0), errors.New("db down"))
+
+		service := NewService(repo)
+		_, err := service.StartDueAuctions(context.Background())
+
+		assert.ErrorContains(t, err, "failed to start due auctions")
+	})
 }
 
 func TestService_ValidateSellerCannotBid(t *testing.T) {
@@ -402,7 +507,7 @@ func TestService_ValidateSellerCannotBid(t *testing.T) {
 			itemID: itemID,
 			userID: otherUserID,
 			setupMock: func(repo *MockRepository) {
-				repo.On("GetItemByID", mock.Anything, itemID).Return(nil, errors.New("not found"))
+				repo.On("GetItemByID", mock.Anything, itemID).Return(nil, ErrItemNotFound)
 			},
 			wantErr: ErrItemNotFound,
 		},
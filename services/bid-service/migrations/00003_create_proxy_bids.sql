@@ -0,0 +1,14 @@
+-- +goose Up
+-- A user's maximum (proxy) bid on an item; the service bids on their behalf up to max_amount
+CREATE TABLE proxy_bids (
+    id UUID PRIMARY KEY,
+    item_id UUID NOT NULL REFERENCES items(id),
+    user_id UUID NOT NULL,
+    max_amount BIGINT NOT NULL CHECK (max_amount > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (item_id, user_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS proxy_bids;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Hidden minimum sale price; 0 means no reserve
+ALTER TABLE items ADD COLUMN reserve_price BIGINT NOT NULL DEFAULT 0 CHECK (reserve_price >= 0);
+
+-- Auctions that end without meeting the reserve (or without bids) don't sell
+ALTER TYPE item_status ADD VALUE 'ended_unsold';
+
+-- +goose Down
+-- Postgres cannot drop an enum value, so 'ended_unsold' stays on item_status
+UPDATE items SET status = 'ended' WHERE status = 'ended_unsold';
+ALTER TABLE items DROP COLUMN IF EXISTS reserve_price;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Price at which a buyer can end the auction immediately; 0 means no buy-now option
+ALTER TABLE items ADD COLUMN buy_now_price BIGINT NOT NULL DEFAULT 0 CHECK (buy_now_price >= 0);
+
+-- +goose Down
+ALTER TABLE items DROP COLUMN IF EXISTS buy_now_price;
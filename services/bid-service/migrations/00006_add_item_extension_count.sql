@@ -0,0 +1,6 @@
+-- +goose Up
+-- How many times a late bid has pushed end_at back (anti-sniping)
+ALTER TABLE items ADD COLUMN extension_count INT NOT NULL DEFAULT 0 CHECK (extension_count >= 0);
+
+-- +goose Down
+ALTER TABLE items DROP COLUMN IF EXISTS extension_count;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Sellers can schedule an auction to open in the future
+ALTER TYPE item_status ADD VALUE 'scheduled';
+
+ALTER TABLE items ADD COLUMN start_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+UPDATE items SET start_at = created_at WHERE created_at IS NOT NULL;
+
+-- +goose Down
+-- Postgres cannot drop an enum value, so 'scheduled' stays on item_status
+ALTER TABLE items DROP COLUMN IF EXISTS start_at;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Who holds current_highest_bid, so outbid users can be told; NULL while the item has no bids
+ALTER TABLE items ADD COLUMN current_highest_bidder_id UUID;
+
+UPDATE items i SET current_highest_bidder_id = (
+    SELECT b.user_id FROM bids b
+    WHERE b.item_id = i.id
+    ORDER BY b.amount DESC, b.created_at DESC
+    LIMIT 1
+);
+
+-- +goose Down
+ALTER TABLE items DROP COLUMN IF EXISTS current_highest_bidder_id;
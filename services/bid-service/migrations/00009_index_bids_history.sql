@@ -0,0 +1,6 @@
+-- +goose Up
+-- Supports keyset pagination of an item's bid history, newest first
+CREATE INDEX idx_bids_item_created_id ON bids(item_id, created_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_bids_item_created_id;
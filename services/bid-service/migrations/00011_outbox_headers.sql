@@ -0,0 +1,6 @@
+-- +goose Up
+-- headers are published with the event; they carry the trace context it was written in
+ALTER TABLE outbox_events ADD COLUMN headers JSONB;
+
+-- +goose Down
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS headers;
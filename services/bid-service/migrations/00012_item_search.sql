@@ -0,0 +1,14 @@
+-- +goose Up
+-- Full-text search over items, with title matches ranked above description matches
+ALTER TABLE items ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_items_search_vector ON items USING GIN (search_vector);
+CREATE INDEX idx_items_category ON items(category);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_items_category;
+DROP INDEX IF EXISTS idx_items_search_vector;
+ALTER TABLE items DROP COLUMN IF EXISTS search_vector;
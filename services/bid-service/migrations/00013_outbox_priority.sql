@@ -0,0 +1,6 @@
+-- +goose Up
+-- priority is published as the message priority, so time-sensitive events can overtake a backlog
+ALTER TABLE outbox_events ADD COLUMN priority SMALLINT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS priority;
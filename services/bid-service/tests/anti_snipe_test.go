@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/database"
+	infradb "github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
+)
+
+func TestAntiSnipeExtension(t *testing.T) {
+	testDB := sharedDB.Database(t)
+	pool := testDB.Pool
+	ctx := context.Background()
+
+	itemRepo := infradb.NewPostgresItemRepository(pool)
+	service := bids.NewAuctionService(
+		database.NewPostgresTransactionManager(pool, 5*time.Second),
+		infradb.NewPostgresBidRepository(pool),
+		itemRepo,
+		infradb.NewPostgresOutboxRepository(pool),
+		bids.WithAntiSnipe(bids.AntiSnipePolicy{
+			Window:        time.Minute,
+			Extension:     30 * time.Second,
+			MaxExtensions: 2,
+		}),
+	)
+
+	newItem := func(t *testing.T, endsIn time.Duration) *items.Item {
+		t.Helper()
+		item := &items.Item{
+			ID:        uuid.New(),
+			Title:     "Snipe Target",
+			EndAt:     time.Now().Add(endsIn).Truncate(time.Microsecond),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Images:    []string{},
+			Category:  "test",
+			SellerID:  uuid.New(),
+			Status:    items.ItemStatusActive,
+		}
+		seedTestItem(t, pool, item)
+		return item
+	}
+
+	t.Run("Bid outside window does not extend", func(t *testing.T) {
+		item := newItem(t, time.Hour)
+
+		_, err := service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: uuid.New(), Amount: 100})
+		require.NoError(t, err)
+
+		stored, err := itemRepo.GetItemByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.True(t, item.EndAt.Equal(stored.EndAt))
+		assert.Equal(t, 0, stored.ExtensionCount)
+	})
+
+	t.Run("Late bids extend up to the cap", func(t *testing.T) {
+		item := newItem(t, 10*time.Second)
+
+		for i, amount := range []int64{100, 200, 300} {
+			_, err := service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: uuid.New(), Amount: amount})
+			require.NoError(t, err, "bid %d", i)
+		}
+
+		stored, err := itemRepo.GetItemByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, stored.ExtensionCount)
+		assert.True(t, item.EndAt.Add(time.Minute).Equal(stored.EndAt), "want %v, got %v", item.EndAt.Add(time.Minute), stored.EndAt)
+	})
+}
@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/database"
+	infradb "github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
+)
+
+func TestUpdateHighestBidConflict(t *testing.T) {
+	testDB := sharedDB.Database(t)
+	pool := testDB.Pool
+	ctx := context.Background()
+
+	txManager := database.NewPostgresTransactionManager(pool, 5*time.Second)
+	itemRepo := infradb.NewPostgresItemRepository(pool)
+
+	item := &items.Item{
+		ID:         uuid.New(),
+		Title:      "Contested Item",
+		StartPrice: 100,
+		StartAt:    time.Now(),
+		EndAt:      time.Now().Add(time.Hour),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		Images:     []string{},
+		Category:   "test",
+		SellerID:   uuid.New(),
+		Status:     items.ItemStatusActive,
+	}
+	require.NoError(t, itemRepo.CreateItem(ctx, item))
+
+	t.Run("Two bids validated against the same highest bid, one wins", func(t *testing.T) {
+		// Both transactions read a highest bid of 0 and try to replace it without a row lock;
+		// the second UPDATE waits for the first to commit and then no longer matches
+		bidders := []uuid.UUID{uuid.New(), uuid.New()}
+		start := make(chan struct{})
+		errs := make([]error, len(bidders))
+
+		var wg sync.WaitGroup
+		for i, bidderID := range bidders {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				errs[i] = txManager.WithTx(ctx, func(tx pgx.Tx) error {
+					return itemRepo.UpdateHighestBid(ctx, tx, item.ID, 0, int64(500+i), bidderID)
+				})
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		var winner int
+		var wins int
+		for i, err := range errs {
+			if err == nil {
+				wins++
+				winner = i
+				continue
+			}
+			assert.ErrorIs(t, err, database.ErrConflict)
+		}
+		require.Equal(t, 1, wins, "exactly one update should win")
+
+		stored, err := itemRepo.GetItemByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(500+winner), stored.CurrentHighestBid)
+		assert.Equal(t, bidders[winner], stored.CurrentHighestBidderID)
+	})
+
+	t.Run("A stale expected bid conflicts", func(t *testing.T) {
+		err := txManager.WithTx(ctx, func(tx pgx.Tx) error {
+			return itemRepo.UpdateHighestBid(ctx, tx, item.ID, 0, 900, uuid.New())
+		})
+		assert.ErrorIs(t, err, database.ErrConflict)
+	})
+
+	t.Run("A missing item is not a conflict", func(t *testing.T) {
+		err := txManager.WithTx(ctx, func(tx pgx.Tx) error {
+			return itemRepo.UpdateHighestBid(ctx, tx, uuid.New(), 0, 900, uuid.New())
+		})
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, database.ErrConflict)
+	})
+}
+
+// staleItemRepository makes the first conflicts UpdateHighestBid calls expect a highest bid
+// that is no longer stored, as if another bid had been placed in between
+type staleItemRepository struct {
+	*infradb.PostgresItemRepository
+	conflicts atomic.Int32
+}
+
+func (r *staleItemRepository) UpdateHighestBid(ctx context.Context, tx pgx.Tx, itemID uuid.UUID, expected, amount int64, bidderID uuid.UUID) error {
+	if r.conflicts.Add(-1) >= 0 {
+		expected--
+	}
+	return r.PostgresItemRepository.UpdateHighestBid(ctx, tx, itemID, expected, amount, bidderID)
+}
+
+func TestPlaceBidConflict(t *testing.T) {
+	testDB := sharedDB.Database(t)
+	pool := testDB.Pool
+	ctx := context.Background()
+
+	txManager := database.NewPostgresTransactionManager(pool, 5*time.Second)
+	itemRepo := &staleItemRepository{PostgresItemRepository: infradb.NewPostgresItemRepository(pool)}
+	bidRepo := infradb.NewPostgresBidRepository(pool)
+	service := bids.NewAuctionService(txManager, bidRepo, itemRepo, infradb.NewPostgresOutboxRepository(pool))
+
+	newItem := func(t *testing.T) *items.Item {
+		t.Helper()
+		item := &items.Item{
+			ID:         uuid.New(),
+			Title:      "Contested Item",
+			StartPrice: 100,
+			StartAt:    time.Now(),
+			EndAt:      time.Now().Add(time.Hour),
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+			Images:     []string{},
+			Category:   "test",
+			SellerID:   uuid.New(),
+			Status:     items.ItemStatusActive,
+		}
+		require.NoError(t, itemRepo.CreateItem(ctx, item))
+		return item
+	}
+
+	t.Run("A conflict is retried", func(t *testing.T) {
+		item := newItem(t)
+		itemRepo.conflicts.Store(1)
+
+		bid, err := service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: uuid.New(), Amount: 500})
+		require.NoError(t, err)
+
+		stored, err := itemRepo.GetItemByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(500), stored.CurrentHighestBid)
+		assert.Equal(t, bid.UserID, stored.CurrentHighestBidderID)
+
+		// The attempt that conflicted was rolled back with its bid
+		placed, err := bidRepo.GetBidsByItemID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Len(t, placed, 1)
+	})
+
+	t.Run("A conflict that outlasts the retries fails", func(t *testing.T) {
+		item := newItem(t)
+		itemRepo.conflicts.Store(100)
+		defer itemRepo.conflicts.Store(0)
+
+		_, err := service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: uuid.New(), Amount: 500})
+		assert.ErrorIs(t, err, bids.ErrBidConflict)
+
+		placed, err := bidRepo.GetBidsByItemID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Empty(t, placed)
+	})
+
+	t.Run("Concurrent bids keep the highest", func(t *testing.T) {
+		item := newItem(t)
+
+		const bidders = 10
+		errs := make([]error, bidders)
+		var wg sync.WaitGroup
+		for i := range bidders {
+			wg.Go(func() {
+				_, errs[i] = service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: uuid.New(), Amount: int64(500 + i*100)})
+			})
+		}
+		wg.Wait()
+
+		// A bid may lose to a higher one placed first, but never to a conflict
+		for _, err := range errs {
+			if err != nil {
+				assert.ErrorIs(t, err, bids.ErrBidTooLow)
+			}
+		}
+		stored, err := itemRepo.GetItemByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(500+(bidders-1)*100), stored.CurrentHighestBid)
+	})
+}
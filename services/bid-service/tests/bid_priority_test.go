@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/database"
+	"github.com/floroz/gavel/pkg/events"
+	infradb "github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
+)
+
+func TestBidPlacedPriority(t *testing.T) {
+	testDB := sharedDB.Database(t)
+	pool := testDB.Pool
+	ctx := context.Background()
+
+	outboxRepo := infradb.NewPostgresOutboxRepository(pool)
+	service := bids.NewAuctionService(
+		database.NewPostgresTransactionManager(pool, 5*time.Second),
+		infradb.NewPostgresBidRepository(pool),
+		infradb.NewPostgresItemRepository(pool),
+		outboxRepo,
+		bids.WithUrgentBidWindow(time.Minute),
+	)
+
+	bidPriority := func(t *testing.T, endsIn time.Duration) uint8 {
+		t.Helper()
+		item := &items.Item{
+			ID:        uuid.New(),
+			Title:     "Closing Item",
+			EndAt:     time.Now().Add(endsIn),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Images:    []string{},
+			Category:  "test",
+			SellerID:  uuid.New(),
+			Status:    items.ItemStatusActive,
+		}
+		seedTestItem(t, pool, item)
+
+		_, err := service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: uuid.New(), Amount: 100})
+		require.NoError(t, err)
+
+		var priority int16
+		err = pool.QueryRow(ctx,
+			"SELECT priority FROM outbox_events WHERE event_type = $1 AND aggregate_id = $2",
+			events.RoutingKeyBidPlaced, item.ID,
+		).Scan(&priority)
+		require.NoError(t, err)
+		return uint8(priority)
+	}
+
+	t.Run("Bid well before the end has normal priority", func(t *testing.T) {
+		assert.Equal(t, events.PriorityNormal, bidPriority(t, time.Hour))
+	})
+
+	t.Run("Bid in the urgent window has high priority", func(t *testing.T) {
+		assert.Equal(t, events.PriorityHigh, bidPriority(t, 30*time.Second))
+	})
+}
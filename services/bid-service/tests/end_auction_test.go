@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/floroz/gavel/pkg/database"
+	pb "github.com/floroz/gavel/pkg/proto"
+	infradb "github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
+)
+
+func TestEndDueAuctions(t *testing.T) {
+	testDB := sharedDB.Database(t)
+	pool := testDB.Pool
+	ctx := context.Background()
+
+	itemRepo := infradb.NewPostgresItemRepository(pool)
+	service := bids.NewAuctionService(
+		database.NewPostgresTransactionManager(pool, 5*time.Second),
+		infradb.NewPostgresBidRepository(pool),
+		itemRepo,
+		infradb.NewPostgresOutboxRepository(pool),
+	)
+
+	newItem := func(t *testing.T, endsIn time.Duration, reserve int64) *items.Item {
+		t.Helper()
+		item := &items.Item{
+			ID:           uuid.New(),
+			Title:        "Ending Item",
+			StartPrice:   100,
+			ReservePrice: reserve,
+			StartAt:      time.Now().Add(-2 * time.Hour),
+			EndAt:        time.Now().Add(endsIn),
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+			Images:       []string{},
+			Category:     "test",
+			SellerID:     uuid.New(),
+			Status:       items.ItemStatusActive,
+		}
+		require.NoError(t, itemRepo.CreateItem(ctx, item))
+		return item
+	}
+
+	seedBid := func(t *testing.T, itemID, userID uuid.UUID, amount int64) uuid.UUID {
+		t.Helper()
+		bidID := uuid.New()
+		_, err := pool.Exec(ctx,
+			"INSERT INTO bids (id, item_id, user_id, amount, created_at) VALUES ($1, $2, $3, $4, $5)",
+			bidID, itemID, userID, amount, time.Now(),
+		)
+		require.NoError(t, err)
+		return bidID
+	}
+
+	endedEvents := func(t *testing.T, itemID uuid.UUID) []*pb.AuctionEnded {
+		t.Helper()
+		rows, err := pool.Query(ctx,
+			"SELECT payload FROM outbox_events WHERE event_type = 'auction.ended' AND aggregate_id = $1", itemID,
+		)
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var result []*pb.AuctionEnded
+		for rows.Next() {
+			var payload []byte
+			require.NoError(t, rows.Scan(&payload))
+			var event pb.AuctionEnded
+			require.NoError(t, proto.Unmarshal(payload, &event))
+			result = append(result, &event)
+		}
+		require.NoError(t, rows.Err())
+		return result
+	}
+
+	t.Run("Expired item is sold to the highest bidder exactly once", func(t *testing.T) {
+		item := newItem(t, -time.Minute, 300)
+		seedBid(t, item.ID, uuid.New(), 200)
+		winnerID := uuid.New()
+		winningBidID := seedBid(t, item.ID, winnerID, 500)
+		live := newItem(t, time.Hour, 0)
+
+		ended, err := service.EndDueAuctions(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, ended)
+
+		// A second sweep finds nothing left to close
+		ended, err = service.EndDueAuctions(ctx, 0)
+		require.NoError(t, err)
+		assert.Zero(t, ended)
+
+		stored, err := itemRepo.GetItemByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, items.ItemStatusEnded, stored.Status)
+
+		events := endedEvents(t, item.ID)
+		require.Len(t, events, 1)
+		assert.True(t, events[0].Sold)
+		assert.Equal(t, item.SellerID.String(), events[0].SellerId)
+		assert.Equal(t, winnerID.String(), events[0].WinnerId)
+		assert.Equal(t, winningBidID.String(), events[0].WinningBidId)
+		assert.Equal(t, int64(500), events[0].Amount)
+
+		// Auctions still running are left alone
+		stored, err = itemRepo.GetItemByID(ctx, live.ID)
+		require.NoError(t, err)
+		assert.Equal(t, items.ItemStatusActive, stored.Status)
+		assert.Empty(t, endedEvents(t, live.ID))
+	})
+
+	t.Run("Expired item below its reserve ends unsold", func(t *testing.T) {
+		item := newItem(t, -time.Minute, 1000)
+		seedBid(t, item.ID, uuid.New(), 500)
+
+		ended, err := service.EndDueAuctions(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, ended)
+
+		stored, err := itemRepo.GetItemByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, items.ItemStatusEndedUnsold, stored.Status)
+
+		events := endedEvents(t, item.ID)
+		require.Len(t, events, 1)
+		assert.False(t, events[0].Sold)
+		assert.Empty(t, events[0].WinnerId)
+		assert.Zero(t, events[0].Amount)
+	})
+
+	t.Run("Expired item refuses bids before the sweep", func(t *testing.T) {
+		item := newItem(t, -time.Minute, 0)
+
+		_, err := service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: uuid.New(), Amount: 200})
+		assert.ErrorIs(t, err, bids.ErrAuctionEnded)
+
+		ended, err := service.EndDueAuctions(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, ended)
+
+		stored, err := itemRepo.GetItemByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, items.ItemStatusEndedUnsold, stored.Status)
+	})
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/database"
+	infradb "github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
+)
+
+func TestHighestBidderTracking(t *testing.T) {
+	testDB := sharedDB.Database(t)
+	pool := testDB.Pool
+	ctx := context.Background()
+
+	txManager := database.NewPostgresTransactionManager(pool, 5*time.Second)
+	itemRepo := infradb.NewPostgresItemRepository(pool)
+	service := bids.NewAuctionService(
+		txManager,
+		infradb.NewPostgresBidRepository(pool),
+		itemRepo,
+		infradb.NewPostgresOutboxRepository(pool),
+	)
+
+	newItem := func(t *testing.T, buyNow int64) uuid.UUID {
+		t.Helper()
+		item := &items.Item{
+			ID:          uuid.New(),
+			Title:       "Tracked Item",
+			StartPrice:  100,
+			BuyNowPrice: buyNow,
+			StartAt:     time.Now(),
+			EndAt:       time.Now().Add(time.Hour),
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+			Images:      []string{},
+			Category:    "test",
+			SellerID:    uuid.New(),
+			Status:      items.ItemStatusActive,
+		}
+		require.NoError(t, itemRepo.CreateItem(ctx, item))
+		return item.ID
+	}
+
+	bidder := func(t *testing.T, itemID uuid.UUID) uuid.UUID {
+		t.Helper()
+		item, err := itemRepo.GetItemByID(ctx, itemID)
+		require.NoError(t, err)
+		return item.CurrentHighestBidderID
+	}
+
+	t.Run("New item has no bidder", func(t *testing.T) {
+		itemID := newItem(t, 0)
+		assert.Equal(t, uuid.Nil, bidder(t, itemID))
+	})
+
+	t.Run("Each winning bid takes over", func(t *testing.T) {
+		itemID := newItem(t, 0)
+		alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+
+		_, err := service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: itemID, UserID: alice, Amount: 200})
+		require.NoError(t, err)
+		assert.Equal(t, alice, bidder(t, itemID))
+
+		_, err = service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: itemID, UserID: bob, Amount: 300})
+		require.NoError(t, err)
+		assert.Equal(t, bob, bidder(t, itemID))
+
+		// A rejected bid leaves the leader in place
+		_, err = service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: itemID, UserID: carol, Amount: 300})
+		assert.ErrorIs(t, err, bids.ErrBidTooLow)
+		assert.Equal(t, bob, bidder(t, itemID))
+
+		// A maximum bid takes the lead through an automatic bid
+		_, err = service.SetMaxBid(ctx, bids.SetMaxBidCommand{ItemID: itemID, UserID: carol, MaxAmount: 1000})
+		require.NoError(t, err)
+		assert.Equal(t, carol, bidder(t, itemID))
+
+		// A manual bid below carol's maximum is answered on carol's behalf
+		_, err = service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: itemID, UserID: alice, Amount: 500})
+		require.NoError(t, err)
+		assert.Equal(t, carol, bidder(t, itemID))
+	})
+
+	t.Run("Buying now makes the buyer the highest bidder", func(t *testing.T) {
+		itemID := newItem(t, 5000)
+		alice, bob := uuid.New(), uuid.New()
+
+		_, err := service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: itemID, UserID: alice, Amount: 200})
+		require.NoError(t, err)
+
+		_, err = service.PurchaseNow(ctx, bids.PurchaseNowCommand{ItemID: itemID, UserID: bob})
+		require.NoError(t, err)
+		assert.Equal(t, bob, bidder(t, itemID))
+	})
+
+	t.Run("Withdrawn lead clears the bidder", func(t *testing.T) {
+		itemID := newItem(t, 0)
+		_, err := service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: itemID, UserID: uuid.New(), Amount: 200})
+		require.NoError(t, err)
+
+		// Resetting the item with uuid.Nil, as removing its only bid would, stores no bidder
+		tx, err := txManager.BeginTx(ctx)
+		require.NoError(t, err)
+		require.NoError(t, itemRepo.UpdateHighestBid(ctx, tx, itemID, 200, 0, uuid.Nil))
+		require.NoError(t, tx.Commit(ctx))
+
+		item, err := itemRepo.GetItemByID(ctx, itemID)
+		require.NoError(t, err)
+		assert.Equal(t, uuid.Nil, item.CurrentHighestBidderID)
+		assert.Equal(t, int64(0), item.CurrentHighestBid)
+	})
+}
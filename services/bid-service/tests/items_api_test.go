@@ -7,24 +7,49 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
+	pb "github.com/floroz/gavel/pkg/proto"
 	bidsv1 "github.com/floroz/gavel/pkg/proto/bids/v1"
-	"github.com/floroz/gavel/pkg/testhelpers"
 	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
 )
 
 func TestAPI_CreateItem(t *testing.T) {
-	testDB := testhelpers.NewTestDatabase(t, "../migrations")
-	defer testDB.Close()
+	testDB := sharedDB.Database(t)
 
-	client, _, authConfig := setupBidApp(t, testDB.Pool)
+	client, pool, authConfig := setupBidApp(t, testDB.Pool)
 
 	userID := uuid.New()
 	token := authConfig.generateTestToken(t, userID)
 	ctx := context.Background()
 
+	t.Run("stores reserve price without exposing it", func(t *testing.T) {
+		req := &bidsv1.CreateItemRequest{
+			Title:        "Reserved Item",
+			StartPrice:   1000,
+			ReservePrice: 50000,
+			EndAt:        time.Now().Add(48 * time.Hour).Format(time.RFC3339),
+		}
+
+		r := connect.NewRequest(req)
+		r.Header().Set("Authorization", "Bearer "+token)
+		resp, err := client.CreateItem(ctx, r)
+		require.NoError(t, err)
+
+		var reserve int64
+		err = pool.QueryRow(ctx, "SELECT reserve_price FROM items WHERE id = $1", resp.Msg.Item.Id).Scan(&reserve)
+		require.NoError(t, err)
+		assert.Equal(t, int64(50000), reserve)
+
+		// The public item view has no reserve, so the amount must not appear anywhere in it
+		getResp, err := client.GetItem(ctx, connect.NewRequest(&bidsv1.GetItemRequest{Id: resp.Msg.Item.Id}))
+		require.NoError(t, err)
+		assert.NotContains(t, getResp.Msg.Item.String(), "50000")
+	})
+
 	t.Run("successfully creates item", func(t *testing.T) {
 		req := &bidsv1.CreateItemRequest{
 			Title:       "Test Auction Item",
@@ -95,8 +120,7 @@ func TestAPI_CreateItem(t *testing.T) {
 }
 
 func TestAPI_GetItem(t *testing.T) {
-	testDB := testhelpers.NewTestDatabase(t, "../migrations")
-	defer testDB.Close()
+	testDB := sharedDB.Database(t)
 
 	client, pool, _ := setupBidApp(t, testDB.Pool)
 	ctx := context.Background()
@@ -156,8 +180,7 @@ func TestAPI_GetItem(t *testing.T) {
 }
 
 func TestAPI_ListItems(t *testing.T) {
-	testDB := testhelpers.NewTestDatabase(t, "../migrations")
-	defer testDB.Close()
+	testDB := sharedDB.Database(t)
 
 	client, pool, _ := setupBidApp(t, testDB.Pool)
 	ctx := context.Background()
@@ -211,8 +234,7 @@ func TestAPI_ListItems(t *testing.T) {
 }
 
 func TestAPI_ListSellerItems(t *testing.T) {
-	testDB := testhelpers.NewTestDatabase(t, "../migrations")
-	defer testDB.Close()
+	testDB := sharedDB.Database(t)
 
 	client, pool, authConfig := setupBidApp(t, testDB.Pool)
 	ctx := context.Background()
@@ -280,8 +302,7 @@ func TestAPI_ListSellerItems(t *testing.T) {
 }
 
 func TestAPI_UpdateItem(t *testing.T) {
-	testDB := testhelpers.NewTestDatabase(t, "../migrations")
-	defer testDB.Close()
+	testDB := sharedDB.Database(t)
 
 	client, pool, authConfig := setupBidApp(t, testDB.Pool)
 	ctx := context.Background()
@@ -362,8 +383,7 @@ func TestAPI_UpdateItem(t *testing.T) {
 }
 
 func TestAPI_CancelItem(t *testing.T) {
-	testDB := testhelpers.NewTestDatabase(t, "../migrations")
-	defer testDB.Close()
+	testDB := sharedDB.Database(t)
 
 	client, pool, authConfig := setupBidApp(t, testDB.Pool)
 	ctx := context.Background()
@@ -396,6 +416,17 @@ func TestAPI_CancelItem(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, resp.Msg.Item)
 		assert.Equal(t, bidsv1.ItemStatus_ITEM_STATUS_CANCELLED, resp.Msg.Item.Status)
+
+		// The cancellation is published through the outbox
+		var payload []byte
+		err = pool.QueryRow(ctx,
+			"SELECT payload FROM outbox_events WHERE event_type = 'item.cancelled' AND aggregate_id = $1", item.ID,
+		).Scan(&payload)
+		require.NoError(t, err)
+		var event pb.ItemCancelled
+		require.NoError(t, proto.Unmarshal(payload, &event))
+		assert.Equal(t, item.ID.String(), event.ItemId)
+		assert.Equal(t, ownerID.String(), event.SellerId)
 	})
 
 	t.Run("fails when item has bids", func(t *testing.T) {
@@ -433,6 +464,7 @@ func TestAPI_CancelItem(t *testing.T) {
 		require.Error(t, err)
 		assert.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
 		assert.Contains(t, err.Error(), "cannot cancel")
+		assertNoCancelledEvent(t, pool, item.ID)
 	})
 
 	t.Run("fails when user is not owner", func(t *testing.T) {
@@ -463,12 +495,89 @@ func TestAPI_CancelItem(t *testing.T) {
 		_, err := client.CancelItem(ctx, r)
 		require.Error(t, err)
 		assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+		assertNoCancelledEvent(t, pool, item.ID)
+
+		getRes, err := client.GetItem(ctx, connect.NewRequest(&bidsv1.GetItemRequest{Id: item.ID.String()}))
+		require.NoError(t, err)
+		assert.Equal(t, bidsv1.ItemStatus_ITEM_STATUS_ACTIVE, getRes.Msg.Item.Status)
+	})
+}
+
+// assertNoCancelledEvent checks that no cancellation of itemID was saved to the outbox
+func assertNoCancelledEvent(t *testing.T, pool *pgxpool.Pool, itemID uuid.UUID) {
+	t.Helper()
+	var count int
+	err := pool.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM outbox_events WHERE event_type = 'item.cancelled' AND aggregate_id = $1", itemID,
+	).Scan(&count)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestAPI_ForceCancelItem(t *testing.T) {
+	testDB := sharedDB.Database(t)
+
+	client, pool, authConfig := setupBidApp(t, testDB.Pool)
+	ctx := context.Background()
+
+	// seedItemWithBid seeds another seller's active item that already has a bid
+	seedItemWithBid := func(t *testing.T) *items.Item {
+		t.Helper()
+		item := &items.Item{
+			ID:         uuid.New(),
+			Title:      "Reported Item",
+			StartPrice: 1000,
+			EndAt:      time.Now().Add(24 * time.Hour),
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+			Images:     []string{},
+			SellerID:   uuid.New(),
+			Status:     items.ItemStatusActive,
+		}
+		seedTestItem(t, pool, item)
+		_, err := pool.Exec(ctx, `
+			INSERT INTO bids (id, item_id, user_id, amount, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, uuid.New(), item.ID, uuid.New(), int64(1500), time.Now())
+		require.NoError(t, err)
+		return item
+	}
+
+	t.Run("admin cancels another seller's item with bids", func(t *testing.T) {
+		item := seedItemWithBid(t)
+
+		r := connect.NewRequest(&bidsv1.ForceCancelItemRequest{Id: item.ID.String()})
+		r.Header().Set("Authorization", "Bearer "+authConfig.generateAdminToken(t, uuid.New()))
+		resp, err := client.ForceCancelItem(ctx, r)
+		require.NoError(t, err)
+		assert.Equal(t, bidsv1.ItemStatus_ITEM_STATUS_CANCELLED, resp.Msg.Item.Status)
+
+		var payload []byte
+		err = pool.QueryRow(ctx,
+			"SELECT payload FROM outbox_events WHERE event_type = 'item.cancelled' AND aggregate_id = $1", item.ID,
+		).Scan(&payload)
+		require.NoError(t, err)
+		var event pb.ItemCancelled
+		require.NoError(t, proto.Unmarshal(payload, &event))
+		assert.Equal(t, item.SellerID.String(), event.SellerId)
+	})
+
+	t.Run("fails without the force_cancel permission", func(t *testing.T) {
+		item := seedItemWithBid(t)
+
+		// Even the seller needs the permission
+		r := connect.NewRequest(&bidsv1.ForceCancelItemRequest{Id: item.ID.String()})
+		r.Header().Set("Authorization", "Bearer "+authConfig.generateTestToken(t, item.SellerID))
+		_, err := client.ForceCancelItem(ctx, r)
+		require.Error(t, err)
+		assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+		assertNoCancelledEvent(t, pool, item.ID)
+		assert.Equal(t, items.ItemStatusActive, getTestItem(t, pool, item.ID).Status)
 	})
 }
 
 func TestAPI_GetItemBids(t *testing.T) {
-	testDB := testhelpers.NewTestDatabase(t, "../migrations")
-	defer testDB.Close()
+	testDB := sharedDB.Database(t)
 
 	client, pool, _ := setupBidApp(t, testDB.Pool)
 	ctx := context.Background()
@@ -506,6 +615,36 @@ func TestAPI_GetItemBids(t *testing.T) {
 		require.NotNil(t, resp.Msg)
 
 		assert.Equal(t, 3, len(resp.Msg.Bids))
+		assert.Empty(t, resp.Msg.NextPageToken)
+	})
+
+	t.Run("pages through bids with page_token", func(t *testing.T) {
+		first, err := client.GetItemBids(ctx, connect.NewRequest(&bidsv1.GetItemBidsRequest{
+			ItemId:   item.ID.String(),
+			PageSize: 2,
+		}))
+		require.NoError(t, err)
+		require.Len(t, first.Msg.Bids, 2)
+		require.NotEmpty(t, first.Msg.NextPageToken)
+
+		second, err := client.GetItemBids(ctx, connect.NewRequest(&bidsv1.GetItemBidsRequest{
+			ItemId:    item.ID.String(),
+			PageSize:  2,
+			PageToken: first.Msg.NextPageToken,
+		}))
+		require.NoError(t, err)
+		require.Len(t, second.Msg.Bids, 1)
+		assert.Empty(t, second.Msg.NextPageToken)
+		assert.Equal(t, int64(1000), second.Msg.Bids[0].Amount, "oldest bid comes last")
+	})
+
+	t.Run("rejects malformed page_token", func(t *testing.T) {
+		_, err := client.GetItemBids(ctx, connect.NewRequest(&bidsv1.GetItemBidsRequest{
+			ItemId:    item.ID.String(),
+			PageToken: "not-a-token",
+		}))
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
 	})
 
 	t.Run("returns empty list for item with no bids", func(t *testing.T) {
@@ -535,8 +674,7 @@ func TestAPI_GetItemBids(t *testing.T) {
 }
 
 func TestAPI_SellerCannotBidOnOwnItem(t *testing.T) {
-	testDB := testhelpers.NewTestDatabase(t, "../migrations")
-	defer testDB.Close()
+	testDB := sharedDB.Database(t)
 
 	client, pool, authConfig := setupBidApp(t, testDB.Pool)
 	ctx := context.Background()
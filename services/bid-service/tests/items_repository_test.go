@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -105,8 +106,8 @@ func TestItemRepository_GetItemByID(t *testing.T) {
 	t.Run("get non-existent item", func(t *testing.T) {
 		nonExistentID := uuid.New()
 		_, err := repo.GetItemByID(ctx, nonExistentID)
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "item not found")
+		assert.ErrorIs(t, err, items.ErrItemNotFound)
+		assert.NotErrorIs(t, err, pgx.ErrNoRows)
 	})
 }
 
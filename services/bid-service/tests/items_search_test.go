@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	infradb "github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
+)
+
+func TestSearchItems(t *testing.T) {
+	testDB := sharedDB.Database(t)
+	ctx := context.Background()
+
+	itemRepo := infradb.NewPostgresItemRepository(testDB.Pool)
+	service := items.NewService(itemRepo)
+
+	seed := func(t *testing.T, title, description, category string, status items.ItemStatus, endsIn time.Duration) *items.Item {
+		t.Helper()
+		item := &items.Item{
+			ID:          uuid.New(),
+			Title:       title,
+			Description: description,
+			StartPrice:  100,
+			StartAt:     time.Now(),
+			EndAt:       time.Now().Add(endsIn),
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+			Images:      []string{},
+			Category:    category,
+			SellerID:    uuid.New(),
+			Status:      status,
+		}
+		require.NoError(t, itemRepo.CreateItem(ctx, item))
+		return item
+	}
+
+	camera := seed(t, "Vintage film camera", "A 35mm rangefinder in working order", "electronics", items.ItemStatusActive, 3*time.Hour)
+	lens := seed(t, "Portrait lens", "Fits most vintage camera bodies", "electronics", items.ItemStatusActive, 2*time.Hour)
+	chair := seed(t, "Vintage oak chair", "Mid-century dining chair", "furniture", items.ItemStatusActive, time.Hour)
+	radio := seed(t, "Transistor radio", "Vintage portable radio", "electronics", items.ItemStatusEnded, -time.Hour)
+
+	ids := func(found []*items.Item) []uuid.UUID {
+		result := make([]uuid.UUID, len(found))
+		for i, item := range found {
+			result[i] = item.ID
+		}
+		return result
+	}
+
+	t.Run("Text matches rank title matches first", func(t *testing.T) {
+		result, err := service.SearchItems(ctx, items.SearchItemsQuery{Query: "camera"})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{camera.ID, lens.ID}, ids(result.Items))
+		assert.Equal(t, int64(2), result.Total)
+	})
+
+	t.Run("Text match is stemmed", func(t *testing.T) {
+		result, err := service.SearchItems(ctx, items.SearchItemsQuery{Query: "chairs"})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{chair.ID}, ids(result.Items))
+	})
+
+	t.Run("Category filter", func(t *testing.T) {
+		result, err := service.SearchItems(ctx, items.SearchItemsQuery{Query: "vintage", Category: "furniture"})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{chair.ID}, ids(result.Items))
+		assert.Equal(t, int64(1), result.Total)
+	})
+
+	t.Run("Status filter", func(t *testing.T) {
+		result, err := service.SearchItems(ctx, items.SearchItemsQuery{Category: "electronics", Status: items.ItemStatusEnded})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{radio.ID}, ids(result.Items))
+	})
+
+	t.Run("Without text, results are ordered by end time", func(t *testing.T) {
+		result, err := service.SearchItems(ctx, items.SearchItemsQuery{Status: items.ItemStatusActive})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{chair.ID, lens.ID, camera.ID}, ids(result.Items))
+	})
+
+	t.Run("Total counts matches beyond the page", func(t *testing.T) {
+		result, err := service.SearchItems(ctx, items.SearchItemsQuery{Category: "electronics", Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{lens.ID}, ids(result.Items))
+		assert.Equal(t, int64(3), result.Total)
+	})
+
+	t.Run("No matches", func(t *testing.T) {
+		result, err := service.SearchItems(ctx, items.SearchItemsQuery{Query: "submarine"})
+		require.NoError(t, err)
+		assert.Empty(t, result.Items)
+		assert.Zero(t, result.Total)
+	})
+}
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/database"
+	infradb "github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
+)
+
+func TestListBids(t *testing.T) {
+	testDB := sharedDB.Database(t)
+	pool := testDB.Pool
+	ctx := context.Background()
+
+	service := bids.NewAuctionService(
+		database.NewPostgresTransactionManager(pool, 5*time.Second),
+		infradb.NewPostgresBidRepository(pool),
+		infradb.NewPostgresItemRepository(pool),
+		infradb.NewPostgresOutboxRepository(pool),
+	)
+
+	item := &items.Item{
+		ID:        uuid.New(),
+		Title:     "History Item",
+		EndAt:     time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Images:    []string{},
+		Category:  "test",
+		SellerID:  uuid.New(),
+		Status:    items.ItemStatusActive,
+	}
+	seedTestItem(t, pool, item)
+
+	// 25 bids, a second apart, with pairs sharing a timestamp so the id tie-break is exercised
+	const total = 25
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i := range total {
+		_, err := pool.Exec(ctx, `
+			INSERT INTO bids (id, item_id, user_id, amount, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, uuid.New(), item.ID, uuid.New(), int64(100+i), base.Add(time.Duration(i/2)*time.Second))
+		require.NoError(t, err)
+	}
+
+	t.Run("Pages cover every bid once, newest first", func(t *testing.T) {
+		var (
+			all    []*bids.Bid
+			cursor *bids.BidCursor
+			pages  int
+		)
+		for {
+			page, err := service.ListBids(ctx, item.ID, 10, cursor)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(page.Bids), 10)
+			all = append(all, page.Bids...)
+			pages++
+			if page.Next == nil {
+				break
+			}
+			cursor = page.Next
+		}
+
+		assert.Equal(t, 3, pages)
+		require.Len(t, all, total)
+
+		seen := make(map[uuid.UUID]bool, total)
+		for i, bid := range all {
+			assert.False(t, seen[bid.ID], "bid %s returned twice", bid.ID)
+			seen[bid.ID] = true
+			if i == 0 {
+				continue
+			}
+			prev := all[i-1]
+			assert.False(t, bid.CreatedAt.After(prev.CreatedAt), "bids must be ordered newest first")
+			if bid.CreatedAt.Equal(prev.CreatedAt) {
+				assert.Less(t, bid.ID.String(), prev.ID.String(), "ties are ordered by id descending")
+			}
+		}
+	})
+
+	t.Run("Full page size leaves no next cursor when nothing follows", func(t *testing.T) {
+		page, err := service.ListBids(ctx, item.ID, total, nil)
+		require.NoError(t, err)
+		assert.Len(t, page.Bids, total)
+		assert.Nil(t, page.Next)
+	})
+
+	t.Run("Page size defaults and caps", func(t *testing.T) {
+		page, err := service.ListBids(ctx, item.ID, 0, nil)
+		require.NoError(t, err)
+		assert.Len(t, page.Bids, bids.DefaultBidPageSize)
+		assert.NotNil(t, page.Next)
+
+		page, err = service.ListBids(ctx, item.ID, 1000, nil)
+		require.NoError(t, err)
+		assert.Len(t, page.Bids, total)
+	})
+
+	t.Run("Bids placed after the first page do not shift later pages", func(t *testing.T) {
+		first, err := service.ListBids(ctx, item.ID, 5, nil)
+		require.NoError(t, err)
+
+		_, err = pool.Exec(ctx, `
+			INSERT INTO bids (id, item_id, user_id, amount, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, uuid.New(), item.ID, uuid.New(), int64(10_000), time.Now())
+		require.NoError(t, err)
+
+		second, err := service.ListBids(ctx, item.ID, 5, first.Next)
+		require.NoError(t, err)
+		require.NotEmpty(t, second.Bids)
+		assert.True(t, second.Bids[0].CreatedAt.Before(first.Bids[len(first.Bids)-1].CreatedAt) ||
+			second.Bids[0].CreatedAt.Equal(first.Bids[len(first.Bids)-1].CreatedAt))
+		assert.NotEqual(t, first.Bids[len(first.Bids)-1].ID, second.Bids[0].ID)
+	})
+}
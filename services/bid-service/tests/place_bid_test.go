@@ -11,15 +11,16 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/floroz/gavel/pkg/database"
 	bidsv1 "github.com/floroz/gavel/pkg/proto/bids/v1"
-	"github.com/floroz/gavel/pkg/testhelpers"
+	infradb "github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
 	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
 )
 
 func TestPlaceBid_Scenarios(t *testing.T) {
 	// Setup DB Container
-	testDB := testhelpers.NewTestDatabase(t, "../migrations")
-	defer testDB.Close()
+	testDB := sharedDB.Database(t)
 
 	// Setup Application
 	client, pool, authConfig := setupBidApp(t, testDB.Pool)
@@ -71,7 +72,7 @@ func TestPlaceBid_Scenarios(t *testing.T) {
 
 		_, err := client.PlaceBid(context.Background(), req)
 		require.Error(t, err)
-		assert.Equal(t, connect.CodeInternal, connect.CodeOf(err))
+		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
 	})
 
 	t.Run("Failure_BidTooLow", func(t *testing.T) {
@@ -130,6 +131,40 @@ func TestPlaceBid_Scenarios(t *testing.T) {
 		assert.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
 	})
 
+	// Closed items refuse bids by status alone, however long they had left to run
+	for name, status := range map[string]items.ItemStatus{
+		"Failure_CancelledItem":   items.ItemStatusCancelled,
+		"Failure_EndedByStatus":   items.ItemStatusEnded,
+		"Failure_EndedUnsoldItem": items.ItemStatusEndedUnsold,
+	} {
+		t.Run(name, func(t *testing.T) {
+			itemID := uuid.New()
+			seedTestItem(t, pool, &items.Item{
+				ID:         itemID,
+				Title:      "Closed Auction",
+				StartPrice: 1000,
+				EndAt:      time.Now().Add(1 * time.Hour),
+				CreatedAt:  time.Now(),
+				UpdatedAt:  time.Now(),
+				Images:     []string{},
+				Category:   "test",
+				SellerID:   uuid.New(),
+				Status:     status,
+			})
+
+			req := connect.NewRequest(&bidsv1.PlaceBidRequest{
+				ItemId: itemID.String(),
+				Amount: 3000,
+			})
+			req.Header().Set("Authorization", "Bearer "+authConfig.generateTestToken(t, uuid.New()))
+
+			_, err := client.PlaceBid(context.Background(), req)
+			require.Error(t, err)
+			assert.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
+			assert.Equal(t, int64(0), getTestItem(t, pool, itemID).CurrentHighestBid)
+		})
+	}
+
 	t.Run("Failure_NegativeAmount", func(t *testing.T) {
 		itemID := uuid.New()
 		testItem := &items.Item{
@@ -281,3 +316,51 @@ func TestPlaceBid_Scenarios(t *testing.T) {
 		assert.Equal(t, 1, successCount, "Only one bid should succeed for the same amount")
 	})
 }
+
+func TestPlaceBid_ReturnsPersistedBid(t *testing.T) {
+	testDB := sharedDB.Database(t)
+	pool := testDB.Pool
+	ctx := context.Background()
+
+	itemRepo := infradb.NewPostgresItemRepository(pool)
+	bidRepo := infradb.NewPostgresBidRepository(pool)
+	service := bids.NewAuctionService(
+		database.NewPostgresTransactionManager(pool, 5*time.Second),
+		bidRepo,
+		itemRepo,
+		infradb.NewPostgresOutboxRepository(pool),
+	)
+
+	item := &items.Item{
+		ID:         uuid.New(),
+		Title:      "Confirmed Item",
+		StartPrice: 100,
+		StartAt:    time.Now(),
+		EndAt:      time.Now().Add(time.Hour),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		Images:     []string{},
+		Category:   "test",
+		SellerID:   uuid.New(),
+		Status:     items.ItemStatusActive,
+	}
+	require.NoError(t, itemRepo.CreateItem(ctx, item))
+
+	userID := uuid.New()
+	bid, err := service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: userID, Amount: 250})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, uuid.Nil, bid.ID)
+	assert.Equal(t, item.ID, bid.ItemID)
+	assert.Equal(t, userID, bid.UserID)
+	assert.Equal(t, int64(250), bid.Amount)
+	assert.WithinDuration(t, time.Now(), bid.CreatedAt, 5*time.Second)
+
+	// The returned bid is the one stored, timestamp included
+	stored, err := bidRepo.GetBidByID(ctx, bid.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.CreatedAt.Equal(bid.CreatedAt), "returned CreatedAt should match the stored one")
+
+	_, err = bidRepo.GetBidByID(ctx, uuid.New())
+	assert.ErrorIs(t, err, bids.ErrBidNotFound)
+}
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/database"
+	infradb "github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
+)
+
+func TestProxyBidding(t *testing.T) {
+	testDB := sharedDB.Database(t)
+	pool := testDB.Pool
+	ctx := context.Background()
+
+	bidRepo := infradb.NewPostgresBidRepository(pool)
+	service := bids.NewAuctionService(
+		database.NewPostgresTransactionManager(pool, 5*time.Second),
+		bidRepo,
+		infradb.NewPostgresItemRepository(pool),
+		infradb.NewPostgresOutboxRepository(pool),
+		bids.WithMinBidIncrement(bids.BidIncrement{Absolute: 100}),
+	)
+
+	newItem := func(t *testing.T) uuid.UUID {
+		t.Helper()
+		item := &items.Item{
+			ID:        uuid.New(),
+			Title:     "Proxy Item",
+			EndAt:     time.Now().Add(time.Hour),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Images:    []string{},
+			Category:  "test",
+			SellerID:  uuid.New(),
+			Status:    items.ItemStatusActive,
+		}
+		seedTestItem(t, pool, item)
+		return item.ID
+	}
+
+	t.Run("Max bid escalates against a manual bid", func(t *testing.T) {
+		itemID := newItem(t)
+		proxyUser, manualUser := uuid.New(), uuid.New()
+
+		_, err := service.SetMaxBid(ctx, bids.SetMaxBidCommand{ItemID: itemID, UserID: proxyUser, MaxAmount: 5000})
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), getTestItem(t, pool, itemID).CurrentHighestBid, "opening bid is one increment")
+
+		_, err = service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: itemID, UserID: manualUser, Amount: 2000})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2100), getTestItem(t, pool, itemID).CurrentHighestBid)
+		history, err := bidRepo.GetBidsByItemID(ctx, itemID)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		assert.Equal(t, proxyUser, history[0].UserID, "proxy user leads")
+		assert.Equal(t, int64(2100), history[0].Amount)
+	})
+
+	t.Run("Max reached hands the lead over", func(t *testing.T) {
+		itemID := newItem(t)
+		proxyUser, manualUser := uuid.New(), uuid.New()
+
+		_, err := service.SetMaxBid(ctx, bids.SetMaxBidCommand{ItemID: itemID, UserID: proxyUser, MaxAmount: 1000})
+		require.NoError(t, err)
+
+		_, err = service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: itemID, UserID: manualUser, Amount: 1500})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1500), getTestItem(t, pool, itemID).CurrentHighestBid)
+		history, err := bidRepo.GetBidsByItemID(ctx, itemID)
+		require.NoError(t, err)
+		assert.Equal(t, manualUser, history[0].UserID)
+	})
+
+	t.Run("Equal maxes favour the earlier one", func(t *testing.T) {
+		itemID := newItem(t)
+		first, second := uuid.New(), uuid.New()
+
+		_, err := service.SetMaxBid(ctx, bids.SetMaxBidCommand{ItemID: itemID, UserID: first, MaxAmount: 3000})
+		require.NoError(t, err)
+		_, err = service.SetMaxBid(ctx, bids.SetMaxBidCommand{ItemID: itemID, UserID: second, MaxAmount: 3000})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(3000), getTestItem(t, pool, itemID).CurrentHighestBid)
+		history, err := bidRepo.GetBidsByItemID(ctx, itemID)
+		require.NoError(t, err)
+		assert.Equal(t, first, history[0].UserID)
+	})
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bidsv1 "github.com/floroz/gavel/pkg/proto/bids/v1"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
+)
+
+func TestPurchaseNow_Scenarios(t *testing.T) {
+	testDB := sharedDB.Database(t)
+
+	client, pool, authConfig := setupBidApp(t, testDB.Pool)
+	ctx := context.Background()
+
+	seedBuyNowItem := func(t *testing.T, status items.ItemStatus, currentHighest int64) uuid.UUID {
+		t.Helper()
+		item := &items.Item{
+			ID:                uuid.New(),
+			Title:             "Buy Now Item",
+			StartPrice:        1000,
+			CurrentHighestBid: currentHighest,
+			EndAt:             time.Now().Add(1 * time.Hour),
+			CreatedAt:         time.Now(),
+			UpdatedAt:         time.Now(),
+			Images:            []string{},
+			Category:          "test",
+			SellerID:          uuid.New(),
+			Status:            status,
+		}
+		seedTestItem(t, pool, item)
+		_, err := pool.Exec(ctx, "UPDATE items SET buy_now_price = 5000 WHERE id = $1", item.ID)
+		require.NoError(t, err)
+		return item.ID
+	}
+
+	purchase := func(itemID, userID uuid.UUID) (*connect.Response[bidsv1.PurchaseNowResponse], error) {
+		req := connect.NewRequest(&bidsv1.PurchaseNowRequest{ItemId: itemID.String()})
+		req.Header().Set("Authorization", "Bearer "+authConfig.generateTestToken(t, userID))
+		return client.PurchaseNow(ctx, req)
+	}
+
+	t.Run("Success_EndsAuctionAtBuyNowPrice", func(t *testing.T) {
+		itemID := seedBuyNowItem(t, items.ItemStatusActive, 2000)
+		buyerID := uuid.New()
+		eventsBefore := countOutboxEvents(t, pool)
+
+		res, err := purchase(itemID, buyerID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5000), res.Msg.Bid.Amount)
+		assert.Equal(t, buyerID.String(), res.Msg.Bid.UserId)
+
+		getRes, err := client.GetItem(ctx, connect.NewRequest(&bidsv1.GetItemRequest{Id: itemID.String()}))
+		require.NoError(t, err)
+		assert.Equal(t, bidsv1.ItemStatus_ITEM_STATUS_ENDED, getRes.Msg.Item.Status)
+		assert.Equal(t, int64(5000), getRes.Msg.Item.CurrentHighestBid)
+
+		// Both the winning bid and the purchase are published
+		assert.Equal(t, eventsBefore+2, countOutboxEvents(t, pool))
+		var purchased int
+		err = pool.QueryRow(ctx, "SELECT COUNT(*) FROM outbox_events WHERE event_type = 'item.purchased'").Scan(&purchased)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purchased)
+
+		// The auction no longer accepts bids
+		bidReq := connect.NewRequest(&bidsv1.PlaceBidRequest{ItemId: itemID.String(), Amount: 9000})
+		bidReq.Header().Set("Authorization", "Bearer "+authConfig.generateTestToken(t, uuid.New()))
+		_, err = client.PlaceBid(ctx, bidReq)
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
+	})
+
+	t.Run("Failure_EndedItem", func(t *testing.T) {
+		itemID := seedBuyNowItem(t, items.ItemStatusEnded, 0)
+
+		_, err := purchase(itemID, uuid.New())
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
+	})
+
+	t.Run("Failure_CancelledItem", func(t *testing.T) {
+		itemID := seedBuyNowItem(t, items.ItemStatusCancelled, 0)
+
+		_, err := purchase(itemID, uuid.New())
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
+	})
+
+	t.Run("Failure_BidsAboveBuyNowPrice", func(t *testing.T) {
+		itemID := seedBuyNowItem(t, items.ItemStatusActive, 6000)
+
+		_, err := purchase(itemID, uuid.New())
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
+		assert.Contains(t, err.Error(), "buy now price")
+	})
+
+	t.Run("Failure_SellerCannotBuy", func(t *testing.T) {
+		itemID := seedBuyNowItem(t, items.ItemStatusActive, 0)
+		var sellerID uuid.UUID
+		require.NoError(t, pool.QueryRow(ctx, "SELECT seller_id FROM items WHERE id = $1", itemID).Scan(&sellerID))
+
+		_, err := purchase(itemID, sellerID)
+		require.Error(t, err)
+		assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+	})
+}
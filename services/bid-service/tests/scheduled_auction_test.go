@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/database"
+	infradb "github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
+)
+
+func TestScheduledAuctions(t *testing.T) {
+	testDB := sharedDB.Database(t)
+	pool := testDB.Pool
+	ctx := context.Background()
+
+	itemRepo := infradb.NewPostgresItemRepository(pool)
+	itemService := items.NewService(itemRepo)
+	service := bids.NewAuctionService(
+		database.NewPostgresTransactionManager(pool, 5*time.Second),
+		infradb.NewPostgresBidRepository(pool),
+		itemRepo,
+		infradb.NewPostgresOutboxRepository(pool),
+	)
+
+	newScheduledItem := func(t *testing.T, startsIn time.Duration) *items.Item {
+		t.Helper()
+		item := &items.Item{
+			ID:         uuid.New(),
+			Title:      "Scheduled Item",
+			StartPrice: 100,
+			StartAt:    time.Now().Add(startsIn),
+			EndAt:      time.Now().Add(time.Hour),
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+			Images:     []string{},
+			Category:   "test",
+			SellerID:   uuid.New(),
+			Status:     items.ItemStatusScheduled,
+		}
+		require.NoError(t, itemRepo.CreateItem(ctx, item))
+		return item
+	}
+
+	t.Run("Bid before start is rejected", func(t *testing.T) {
+		item := newScheduledItem(t, time.Hour)
+
+		_, err := service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: uuid.New(), Amount: 200})
+		assert.ErrorIs(t, err, bids.ErrAuctionNotStarted)
+
+		stored, err := itemRepo.GetItemByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, items.ItemStatusScheduled, stored.Status)
+		assert.Equal(t, int64(0), stored.CurrentHighestBid)
+	})
+
+	t.Run("Bid after start opens the auction", func(t *testing.T) {
+		item := newScheduledItem(t, -time.Minute)
+
+		_, err := service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: uuid.New(), Amount: 200})
+		require.NoError(t, err)
+
+		stored, err := itemRepo.GetItemByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, items.ItemStatusActive, stored.Status)
+		assert.Equal(t, int64(200), stored.CurrentHighestBid)
+	})
+
+	t.Run("StartDueAuctions only opens due items", func(t *testing.T) {
+		due := newScheduledItem(t, -time.Minute)
+		future := newScheduledItem(t, time.Hour)
+
+		started, err := itemService.StartDueAuctions(ctx)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, started, int64(1))
+
+		stored, err := itemRepo.GetItemByID(ctx, due.ID)
+		require.NoError(t, err)
+		assert.Equal(t, items.ItemStatusActive, stored.Status)
+		assert.True(t, stored.IsActive())
+
+		stored, err = itemRepo.GetItemByID(ctx, future.ID)
+		require.NoError(t, err)
+		assert.Equal(t, items.ItemStatusScheduled, stored.Status)
+	})
+}
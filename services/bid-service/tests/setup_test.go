@@ -8,6 +8,7 @@ import (
 	"encoding/pem"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
@@ -19,12 +20,23 @@ import (
 	"github.com/floroz/gavel/pkg/auth"
 	"github.com/floroz/gavel/pkg/database"
 	"github.com/floroz/gavel/pkg/proto/bids/v1/bidsv1connect"
+	"github.com/floroz/gavel/pkg/testhelpers"
 	"github.com/floroz/gavel/services/bid-service/internal/adapters/api"
 	infradb "github.com/floroz/gavel/services/bid-service/internal/adapters/database"
 	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
 	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
 )
 
+// sharedDB is the Postgres container every test in this package runs against.
+// Each test gets it back with all tables truncated.
+var sharedDB = testhelpers.NewSharedTestDatabase("../migrations")
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	sharedDB.Close()
+	os.Exit(code)
+}
+
 // testAuthConfig holds the auth configuration for tests
 type testAuthConfig struct {
 	signer *auth.Signer
@@ -83,7 +95,7 @@ func setupBidApp(t *testing.T, pool *pgxpool.Pool) (bidsv1connect.BidServiceClie
 	authInterceptor := auth.NewAuthInterceptorWithPublicRoutes(signer, publicRoutes)
 	path, handler := bidsv1connect.NewBidServiceHandler(
 		bidHandler,
-		connect.WithInterceptors(authInterceptor),
+		connect.WithInterceptors(authInterceptor, auth.NewPermissionInterceptor(api.RequiredPermissions())),
 	)
 
 	// 5. Create a test HTTP server
@@ -104,11 +116,19 @@ func setupBidApp(t *testing.T, pool *pgxpool.Pool) (bidsv1connect.BidServiceClie
 // generateTestToken creates a valid JWT token for the given userID
 func (c *testAuthConfig) generateTestToken(t *testing.T, userID uuid.UUID) string {
 	t.Helper()
-	pair, err := c.signer.GenerateTokens(userID, "test@example.com", "Test User", nil)
+	pair, err := c.signer.GenerateTokens(userID, "test@example.com", "Test User", "user", nil)
 	require.NoError(t, err, "Failed to generate test token")
 	return pair.AccessToken
 }
 
+// generateAdminToken creates a valid JWT token for userID holding the moderator permissions
+func (c *testAuthConfig) generateAdminToken(t *testing.T, userID uuid.UUID) string {
+	t.Helper()
+	pair, err := c.signer.GenerateTokens(userID, "admin@example.com", "Test Admin", "admin", []string{auth.PermissionForceCancelItem})
+	require.NoError(t, err, "Failed to generate admin token")
+	return pair.AccessToken
+}
+
 // seedTestItem inserts a test item into the database directly.
 func seedTestItem(t *testing.T, pool *pgxpool.Pool, item *items.Item) {
 	t.Helper()
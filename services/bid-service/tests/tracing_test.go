@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/floroz/gavel/pkg/database"
+	"github.com/floroz/gavel/pkg/tracing"
+	infradb "github.com/floroz/gavel/services/bid-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/bids"
+	"github.com/floroz/gavel/services/bid-service/internal/domain/items"
+)
+
+func TestPlaceBidTracing(t *testing.T) {
+	testDB := sharedDB.Database(t)
+	ctx := context.Background()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	cfg, err := pgxpool.ParseConfig(testDB.ConnStr)
+	require.NoError(t, err)
+	cfg.ConnConfig.Tracer = database.NewQueryTracer(tp)
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	itemRepo := infradb.NewPostgresItemRepository(pool)
+	service := bids.NewAuctionService(
+		database.NewPostgresTransactionManager(pool, 5*time.Second),
+		infradb.NewPostgresBidRepository(pool),
+		itemRepo,
+		infradb.NewPostgresOutboxRepository(pool),
+		bids.WithTracerProvider(tp),
+	)
+
+	item := &items.Item{
+		ID:         uuid.New(),
+		Title:      "Traced Item",
+		StartPrice: 100,
+		StartAt:    time.Now(),
+		EndAt:      time.Now().Add(time.Hour),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		Images:     []string{},
+		Category:   "test",
+		SellerID:   uuid.New(),
+		Status:     items.ItemStatusActive,
+	}
+	require.NoError(t, itemRepo.CreateItem(ctx, item))
+	exporter.Reset()
+
+	userID := uuid.New()
+	_, err = service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: userID, Amount: 200})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	var root *tracetest.SpanStub
+	for i := range spans {
+		if spans[i].Name == "bids.PlaceBid" {
+			root = &spans[i]
+		}
+	}
+	require.NotNil(t, root, "PlaceBid should record a span")
+	assert.False(t, root.Parent.IsValid(), "PlaceBid should start a new trace")
+	assert.Contains(t, root.Attributes, tracing.AttrItemID.String(item.ID.String()))
+	assert.Contains(t, root.Attributes, tracing.AttrUserID.String(userID.String()))
+
+	// Every statement of the transaction hangs off the bid's span
+	var queries int
+	for _, span := range spans {
+		if span.Name != "db.query" {
+			continue
+		}
+		queries++
+		assert.Equal(t, root.SpanContext.TraceID(), span.SpanContext.TraceID())
+		assert.Equal(t, root.SpanContext.SpanID(), span.Parent.SpanID())
+		assert.Contains(t, span.Attributes, attribute.String("db.system", "postgresql"))
+	}
+	assert.NotZero(t, queries, "repository calls should record query spans")
+
+	// A rejected bid marks its span as failed
+	exporter.Reset()
+	_, err = service.PlaceBid(ctx, bids.PlaceBidCommand{ItemID: item.ID, UserID: uuid.New(), Amount: 150})
+	require.ErrorIs(t, err, bids.ErrBidTooLow)
+
+	for _, span := range exporter.GetSpans() {
+		if span.Name == "bids.PlaceBid" {
+			assert.Equal(t, codes.Error, span.Status.Code)
+			return
+		}
+	}
+	t.Fatal("rejected bid should record a span")
+}
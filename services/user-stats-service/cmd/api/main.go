@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
@@ -15,9 +16,11 @@ import (
 
 	"github.com/floroz/gavel/pkg/auth"
 	pkgdb "github.com/floroz/gavel/pkg/database"
+	"github.com/floroz/gavel/pkg/profiling"
 	"github.com/floroz/gavel/pkg/proto/userstats/v1/userstatsv1connect"
 	"github.com/floroz/gavel/services/user-stats-service/internal/adapters/api"
 	"github.com/floroz/gavel/services/user-stats-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/user-stats-service/internal/config"
 	"github.com/floroz/gavel/services/user-stats-service/internal/domain/userstats"
 )
 
@@ -32,40 +35,23 @@ func main() {
 
 	ctx := context.Background()
 
-	// 1. Load JWT Public Key for token validation
-	publicKeyPath := os.Getenv("JWT_PUBLIC_KEY_PATH")
-	if publicKeyPath == "" {
-		logger.Error("JWT_PUBLIC_KEY_PATH is not set")
-		os.Exit(1)
-	}
-
-	publicKeyPEM, err := os.ReadFile(publicKeyPath)
+	cfg, err := config.LoadAPI()
 	if err != nil {
-		logger.Error("Failed to read public key", "path", publicKeyPath, "error", err)
-		os.Exit(1)
-	}
-
-	issuer := os.Getenv("JWT_ISSUER")
-	if issuer == "" {
-		logger.Error("JWT_ISSUER is not set")
+		logger.Error("Invalid configuration", "error", err)
 		os.Exit(1)
 	}
 
+	// 1. Load JWT Public Key for token validation
 	// Create signer with only public key (for validation only)
-	signer, err := auth.NewSignerFromPublicKey(publicKeyPEM, issuer)
+	signer, err := auth.LoadVerifier(cfg.JWTPublicKey, cfg.JWTIssuer)
 	if err != nil {
 		logger.Error("Failed to create signer", "error", err)
 		os.Exit(1)
 	}
-	logger.Info("JWT public key loaded", "path", publicKeyPath)
+	logger.Info("JWT public key loaded", "kid", signer.KeyID())
 
 	// 2. Initialize Postgres Connection Pool
-	dbURL := os.Getenv("USER_STATS_DB_URL")
-	if dbURL == "" {
-		logger.Error("USER_STATS_DB_URL is not set")
-		os.Exit(1)
-	}
-	dbConfig, err := pgxpool.ParseConfig(dbURL)
+	dbConfig, err := pkgdb.ParsePoolConfig(cfg.DatabaseURL, "USER_STATS")
 	if err != nil {
 		logger.Error("Unable to parse database config", "error", err)
 		os.Exit(1)
@@ -83,9 +69,24 @@ func main() {
 	}
 	logger.Info("Postgres Connected")
 
+	// Optional read replicas for read-heavy endpoints
+	var replicas []*pgxpool.Pool
+	if len(cfg.ReplicaURLs) > 0 {
+		replicas, err = pkgdb.ConnectReplicas(ctx, cfg.ReplicaURLs, "USER_STATS")
+		if err != nil {
+			logger.Error("Unable to connect to read replicas", "error", err)
+			os.Exit(1)
+		}
+		for _, replica := range replicas {
+			defer replica.Close()
+		}
+		logger.Info("Read replicas connected", "count", len(replicas))
+	}
+	router := pkgdb.NewRouter(pool, replicas...)
+
 	// 2. Initialize Dependencies
 	txManager := pkgdb.NewPostgresTransactionManager(pool, 5*time.Second)
-	statsRepo := database.NewUserStatsRepository(pool)
+	statsRepo := database.NewUserStatsRepository(pool, database.WithReadReplicas(router))
 	statsService := userstats.NewService(statsRepo, txManager)
 
 	// 4. Initialize API Handler with auth interceptor
@@ -103,6 +104,21 @@ func main() {
 		_, _ = w.Write([]byte("OK"))
 	})
 
+	// Live profiling, off unless USER_STATS_API_PPROF_ENABLED is set
+	pprofSrv, err := profiling.NewServer("USER_STATS_API")
+	if err != nil {
+		logger.Error("Unable to configure pprof", "error", err)
+		os.Exit(1)
+	}
+	if pprofSrv != nil {
+		go func() {
+			logger.Info("Serving pprof", "addr", pprofSrv.Addr)
+			if err := pprofSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("pprof server failed", "error", err)
+			}
+		}()
+	}
+
 	// 4. Start Server
 	addr := ":8081" // Use 8081 for Stats Service API to avoid conflict with Bid API (8080)
 	logger.Info("Starting User Stats Service API", "addr", addr)
@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,12 +13,20 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
 	"golang.org/x/sync/errgroup"
 
 	pkgdb "github.com/floroz/gavel/pkg/database"
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/pkg/profiling"
+	"github.com/floroz/gavel/pkg/tracing"
 	"github.com/floroz/gavel/services/user-stats-service/internal/adapters/database"
 	"github.com/floroz/gavel/services/user-stats-service/internal/adapters/events"
+	"github.com/floroz/gavel/services/user-stats-service/internal/config"
 	"github.com/floroz/gavel/services/user-stats-service/internal/domain/userstats"
 )
 
@@ -40,17 +51,28 @@ func main() {
 		cancel()
 	}()
 
-	// 1. Initialize Postgres Connection Pool
-	dbURL := os.Getenv("USER_STATS_DB_URL")
-	if dbURL == "" {
-		logger.Error("USER_STATS_DB_URL is not set")
+	shutdownTracing, err := tracing.Setup(ctx, "user-stats-worker")
+	if err != nil {
+		logger.Error("Failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		_ = shutdownTracing(context.Background())
+	}()
+
+	cfg, err := config.LoadWorker()
+	if err != nil {
+		logger.Error("Invalid configuration", "error", err)
 		os.Exit(1)
 	}
-	dbConfig, err := pgxpool.ParseConfig(dbURL)
+
+	// 1. Initialize Postgres Connection Pool
+	dbConfig, err := pkgdb.ParsePoolConfig(cfg.DatabaseURL, "USER_STATS")
 	if err != nil {
 		logger.Error("Unable to parse database config", "error", err)
 		os.Exit(1)
 	}
+	dbConfig.ConnConfig.Tracer = pkgdb.NewQueryTracer(otel.GetTracerProvider())
 	pool, err := pgxpool.NewWithConfig(ctx, dbConfig)
 	if err != nil {
 		logger.Error("Unable to create connection pool", "error", err)
@@ -67,27 +89,86 @@ func main() {
 	// 2. Initialize Dependencies
 	txManager := pkgdb.NewPostgresTransactionManager(pool, 5*time.Second)
 	statsRepo := database.NewUserStatsRepository(pool)
-	statsService := userstats.NewService(statsRepo, txManager)
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	consumerMetrics := events.NewMetrics(reg)
+	statsService := userstats.NewService(statsRepo, txManager, userstats.WithLatencyRecorder(consumerMetrics))
 
 	// 3. Connect to RabbitMQ
-	rabbitURL := os.Getenv("RABBITMQ_URL")
-	if rabbitURL == "" {
-		logger.Error("RABBITMQ_URL is not set")
-		os.Exit(1)
-	}
-	amqpConn, err := amqp.Dial(rabbitURL)
+	amqpConn, err := amqp.Dial(cfg.RabbitMQURL)
 	if err != nil {
 		logger.Error("Failed to connect to RabbitMQ", "error", err)
 		os.Exit(1)
 	}
 	defer amqpConn.Close()
 
+	// Fail fast if the broker has our queues with other settings, rather than losing messages
+	if err := pkgevents.DeclareTopology(amqpConn, events.Topology(events.DefaultMaxRetries)); err != nil {
+		logger.Error("Invalid RabbitMQ topology", "error", err)
+		os.Exit(1)
+	}
+
 	// 4. Start Consumers
-	bidConsumer := events.NewBidConsumer(amqpConn, statsService, logger)
-	userConsumer := events.NewUserConsumer(amqpConn, statsService, logger)
+	// Re-dial if the broker restarts so the worker survives broker blips
+	redial := events.WithDialer(func() (*amqp.Connection, error) { return amqp.Dial(cfg.RabbitMQURL) })
+	metrics := events.WithMetrics(consumerMetrics)
+	timeout := events.WithProcessingTimeout(cfg.ProcessingTimeout)
+	// Back off between retries so a failing dependency is not hammered in a hot loop
+	backoff := events.WithRetryBackoff(events.DefaultRetryBackoffInitial, events.DefaultRetryBackoffMax)
+	bidConsumer := events.NewBidConsumer(amqpConn, statsService, logger, redial, metrics, timeout, backoff)
+	userConsumer := events.NewUserConsumer(amqpConn, statsService, logger, redial, metrics, timeout, backoff)
+	auctionConsumer := events.NewAuctionConsumer(amqpConn, statsService, logger, redial, metrics, timeout, backoff)
 
 	g, gCtx := errgroup.WithContext(ctx)
 
+	// 5. Expose metrics for scraping
+	metricsAddr := cfg.MetricsAddr
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}))
+	metricsSrv := &http.Server{
+		Addr:              metricsAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	g.Go(func() error {
+		logger.Info("Serving metrics", "addr", metricsAddr)
+		if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("metrics server failed: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		<-gCtx.Done()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		return metricsSrv.Shutdown(shutdownCtx)
+	})
+
+	// 6. Live profiling, off unless USER_STATS_WORKER_PPROF_ENABLED is set
+	pprofSrv, err := profiling.NewServer("USER_STATS_WORKER")
+	if err != nil {
+		logger.Error("Unable to configure pprof", "error", err)
+		os.Exit(1)
+	}
+	if pprofSrv != nil {
+		g.Go(func() error {
+			logger.Info("Serving pprof", "addr", pprofSrv.Addr)
+			if err := pprofSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("pprof server failed: %w", err)
+			}
+			return nil
+		})
+
+		g.Go(func() error {
+			<-gCtx.Done()
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer shutdownCancel()
+			return pprofSrv.Shutdown(shutdownCtx)
+		})
+	}
+
 	g.Go(func() error {
 		logger.Info("Starting bid consumer...")
 		return bidConsumer.Run(gCtx)
@@ -98,6 +179,11 @@ func main() {
 		return userConsumer.Run(gCtx)
 	})
 
+	g.Go(func() error {
+		logger.Info("Starting auction consumer...")
+		return auctionConsumer.Run(gCtx)
+	})
+
 	if err := g.Wait(); err != nil {
 		logger.Error("Consumers failed", "error", err)
 		// Don't exit here immediately if context was canceled?
@@ -34,24 +34,34 @@ func (h *UserStatsServiceHandler) GetUserStats(
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, errors.New("invalid user_id in token"))
 	}
+	// A user_id asks for someone else's stats, e.g. for their profile page
+	if req.Msg.UserId != "" {
+		userID, err = uuid.Parse(req.Msg.UserId)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid user_id"))
+		}
+	}
 
 	stats, err := h.service.GetUserStats(ctx, userID)
 	if err != nil {
+		if errors.Is(err, userstats.ErrUserStatsNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
-	if stats == nil {
-		return nil, connect.NewError(connect.CodeNotFound, errors.New("user stats not found"))
-	}
-
 	res := &userstatsv1.UserStatsResponse{
 		Stats: &userstatsv1.UserStats{
-			UserId:        stats.UserID.String(),
-			TotalBids:     stats.TotalBidsPlaced,
-			TotalAmount:   stats.TotalAmountBid,
-			LastUpdatedAt: stats.LastBidAt.Format(time.RFC3339),
+			UserId:           stats.UserID.String(),
+			TotalBids:        stats.TotalBidsPlaced,
+			TotalAmount:      stats.TotalAmountBid,
+			TotalAuctionsWon: stats.TotalAuctionsWon,
+			TotalSpent:       stats.TotalSpent,
 		},
 	}
+	if !stats.LastBidAt.IsZero() {
+		res.Stats.LastUpdatedAt = stats.LastBidAt.Format(time.RFC3339)
+	}
 
 	return connect.NewResponse(res), nil
 }
@@ -57,7 +57,7 @@ func generateTestKeys(t *testing.T) ([]byte, []byte) {
 // generateTestToken creates a valid JWT token for the given userID
 func (c *testAuthConfig) generateTestToken(t *testing.T, userID uuid.UUID) string {
 	t.Helper()
-	pair, err := c.signer.GenerateTokens(userID, "test@example.com", "Test User", nil)
+	pair, err := c.signer.GenerateTokens(userID, "test@example.com", "Test User", "user", nil)
 	require.NoError(t, err, "Failed to generate test token")
 	return pair.AccessToken
 }
@@ -160,6 +160,52 @@ func TestUserStatsServiceHandler_GetUserStats_Integration(t *testing.T) {
 		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
 	})
 
+	t.Run("OtherUser", func(t *testing.T) {
+		userID := uuid.New()
+		seedUserStats(t, testDB.Pool, &userstats.UserStats{
+			UserID:          userID,
+			TotalBidsPlaced: 2,
+			TotalAmountBid:  700,
+			LastBidAt:       time.Now(),
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		})
+
+		req := connect.NewRequest(&userstatsv1.GetUserStatsRequest{UserId: userID.String()})
+		req.Header().Set("Authorization", "Bearer "+authConfig.generateTestToken(t, uuid.New()))
+
+		res, err := client.GetUserStats(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, userID.String(), res.Msg.Stats.UserId)
+		assert.Equal(t, int64(2), res.Msg.Stats.TotalBids)
+		assert.Equal(t, int64(700), res.Msg.Stats.TotalAmount)
+	})
+
+	t.Run("NoBidsYet", func(t *testing.T) {
+		// Created by the user.created consumer, before any bid
+		userID := uuid.New()
+		_, err := testDB.Pool.Exec(context.Background(), `INSERT INTO user_stats (user_id) VALUES ($1)`, userID)
+		require.NoError(t, err)
+
+		req := connect.NewRequest(&userstatsv1.GetUserStatsRequest{UserId: userID.String()})
+		req.Header().Set("Authorization", "Bearer "+authConfig.generateTestToken(t, userID))
+
+		res, err := client.GetUserStats(context.Background(), req)
+		require.NoError(t, err)
+		assert.Zero(t, res.Msg.Stats.TotalBids)
+		assert.Zero(t, res.Msg.Stats.TotalAmount)
+		assert.Empty(t, res.Msg.Stats.LastUpdatedAt)
+	})
+
+	t.Run("InvalidUserID", func(t *testing.T) {
+		req := connect.NewRequest(&userstatsv1.GetUserStatsRequest{UserId: "not-a-uuid"})
+		req.Header().Set("Authorization", "Bearer "+authConfig.generateTestToken(t, uuid.New()))
+
+		_, err := client.GetUserStats(context.Background(), req)
+		require.Error(t, err)
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+
 	t.Run("Unauthenticated", func(t *testing.T) {
 		// Request without auth header
 		req := connect.NewRequest(&userstatsv1.GetUserStatsRequest{})
@@ -9,18 +9,36 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	pkgdb "github.com/floroz/gavel/pkg/database"
 	"github.com/floroz/gavel/services/user-stats-service/internal/domain/userstats"
 )
 
 type UserStatsRepository struct {
-	pool *pgxpool.Pool
+	pool  *pgxpool.Pool
+	reads pkgdb.DBTX // pure reads; the pool unless replicas are configured
 }
 
-func NewUserStatsRepository(pool *pgxpool.Pool) *UserStatsRepository {
-	return &UserStatsRepository{pool: pool}
+// RepositoryOption configures optional UserStatsRepository behaviour
+type RepositoryOption func(*UserStatsRepository)
+
+// WithReadReplicas sends the stats and leaderboard reads through router, so they
+// land on replicas instead of the primary
+func WithReadReplicas(router *pkgdb.Router) RepositoryOption {
+	return func(r *UserStatsRepository) {
+		r.reads = router
+	}
+}
+
+func NewUserStatsRepository(pool *pgxpool.Pool, opts ...RepositoryOption) *UserStatsRepository {
+	r := &UserStatsRepository{pool: pool, reads: pool}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
-// IncrementUserStats increments the user's bid stats atomically
+// IncrementUserStats increments the user's bid stats atomically.
+// last_bid_at only moves forward, so a late-delivered older bid cannot rewind it.
 func (r *UserStatsRepository) IncrementUserStats(ctx context.Context, tx pgx.Tx, userID uuid.UUID, amount int64, lastBidAt time.Time) error {
 	query := `
 		INSERT INTO user_stats (user_id, total_bids_placed, total_amount_bid, last_bid_at, created_at, updated_at)
@@ -28,7 +46,7 @@ func (r *UserStatsRepository) IncrementUserStats(ctx context.Context, tx pgx.Tx,
 		ON CONFLICT (user_id) DO UPDATE SET
 			total_bids_placed = user_stats.total_bids_placed + 1,
 			total_amount_bid = user_stats.total_amount_bid + EXCLUDED.total_amount_bid,
-			last_bid_at = EXCLUDED.last_bid_at,
+			last_bid_at = GREATEST(user_stats.last_bid_at, EXCLUDED.last_bid_at),
 			updated_at = NOW()
 	`
 	_, err := tx.Exec(ctx, query,
@@ -42,43 +60,202 @@ func (r *UserStatsRepository) IncrementUserStats(ctx context.Context, tx pgx.Tx,
 	return nil
 }
 
-func (r *UserStatsRepository) CreateUserStats(ctx context.Context, tx pgx.Tx, userID uuid.UUID, createdAt time.Time) error {
+// RecordAuctionWon increments the user's auctions won and total spent atomically
+func (r *UserStatsRepository) RecordAuctionWon(ctx context.Context, tx pgx.Tx, userID uuid.UUID, amount int64) error {
 	query := `
-		INSERT INTO user_stats (user_id, total_bids_placed, total_amount_bid, last_bid_at, created_at, updated_at)
-		VALUES ($1, 0, 0, NULL, $2, $2)
-		ON CONFLICT (user_id) DO NOTHING
+		INSERT INTO user_stats (user_id, total_auctions_won, total_spent, created_at, updated_at)
+		VALUES ($1, 1, $2, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			total_auctions_won = user_stats.total_auctions_won + 1,
+			total_spent = user_stats.total_spent + EXCLUDED.total_spent,
+			updated_at = NOW()
+	`
+	_, err := tx.Exec(ctx, query, userID, amount)
+	if err != nil {
+		return fmt.Errorf("failed to record auction won: %w", err)
+	}
+	return nil
+}
+
+// CreateUserStats inserts empty stats for a new user. A user whose bids were counted before
+// their UserCreated event arrived keeps their totals and gains their email and country.
+func (r *UserStatsRepository) CreateUserStats(ctx context.Context, tx pgx.Tx, stats *userstats.UserStats) error {
+	query := `
+		INSERT INTO user_stats (user_id, email, country_code, total_bids_placed, total_amount_bid, last_bid_at, created_at, updated_at)
+		VALUES ($1, $2, $3, 0, 0, NULL, $4, $4)
+		ON CONFLICT (user_id) DO UPDATE SET
+			email = EXCLUDED.email,
+			country_code = EXCLUDED.country_code,
+			updated_at = NOW()
 	`
-	_, err := tx.Exec(ctx, query, userID, createdAt)
+	_, err := tx.Exec(ctx, query, stats.UserID, stats.Email, stats.CountryCode, stats.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create user stats: %w", err)
 	}
 	return nil
 }
 
+// BackfillUserStats upserts precomputed stats for many users using a single batch.
+// Bid totals are replaced, while last_bid_at only moves forward, as in IncrementUserStats.
+func (r *UserStatsRepository) BackfillUserStats(ctx context.Context, tx pgx.Tx, stats []*userstats.UserStats) error {
+	return r.backfillUserStats(ctx, tx, stats)
+}
+
+func (r *UserStatsRepository) backfillUserStats(ctx context.Context, db pkgdb.DBTX, stats []*userstats.UserStats) error {
+	query := `
+		INSERT INTO user_stats (user_id, total_bids_placed, total_amount_bid, last_bid_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			total_bids_placed = EXCLUDED.total_bids_placed,
+			total_amount_bid = EXCLUDED.total_amount_bid,
+			last_bid_at = GREATEST(user_stats.last_bid_at, EXCLUDED.last_bid_at),
+			updated_at = NOW()
+	`
+	batch := &pgx.Batch{}
+	for _, s := range stats {
+		var lastBidAt *time.Time
+		if !s.LastBidAt.IsZero() {
+			lastBidAt = &s.LastBidAt
+		}
+		createdAt := s.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+		batch.Queue(query, s.UserID, s.TotalBidsPlaced, s.TotalAmountBid, lastBidAt, createdAt)
+	}
+
+	results := db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for _, s := range stats {
+		if _, err := results.Exec(); err != nil {
+			return fmt.Errorf("failed to backfill stats for user %s: %w", s.UserID, err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("failed to backfill user stats: %w", err)
+	}
+	return nil
+}
+
+// AddUserStats upserts bid count and amount increments for many users using a single batch
+func (r *UserStatsRepository) AddUserStats(ctx context.Context, tx pgx.Tx, deltas []*userstats.UserStats) error {
+	query := `
+		INSERT INTO user_stats (user_id, total_bids_placed, total_amount_bid, last_bid_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			total_bids_placed = user_stats.total_bids_placed + EXCLUDED.total_bids_placed,
+			total_amount_bid = user_stats.total_amount_bid + EXCLUDED.total_amount_bid,
+			last_bid_at = GREATEST(user_stats.last_bid_at, EXCLUDED.last_bid_at),
+			updated_at = NOW()
+	`
+	batch := &pgx.Batch{}
+	for _, d := range deltas {
+		var lastBidAt *time.Time
+		if !d.LastBidAt.IsZero() {
+			lastBidAt = &d.LastBidAt
+		}
+		batch.Queue(query, d.UserID, d.TotalBidsPlaced, d.TotalAmountBid, lastBidAt)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for _, d := range deltas {
+		if _, err := results.Exec(); err != nil {
+			return fmt.Errorf("failed to add stats for user %s: %w", d.UserID, err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("failed to add user stats: %w", err)
+	}
+	return nil
+}
+
 func (r *UserStatsRepository) GetUserStats(ctx context.Context, userID uuid.UUID) (*userstats.UserStats, error) {
 	query := `
-		SELECT user_id, total_bids_placed, total_amount_bid, last_bid_at, created_at, updated_at
+		SELECT user_id, email, country_code, total_bids_placed, total_amount_bid, last_bid_at,
+			total_auctions_won, total_spent, created_at, updated_at
 		FROM user_stats
 		WHERE user_id = $1
 	`
 	var userStats userstats.UserStats
-	err := r.pool.QueryRow(ctx, query, userID).Scan(
+	var lastBidAt *time.Time // NULL until the user's first bid
+	err := r.reads.QueryRow(ctx, query, userID).Scan(
 		&userStats.UserID,
+		&userStats.Email,
+		&userStats.CountryCode,
 		&userStats.TotalBidsPlaced,
 		&userStats.TotalAmountBid,
-		&userStats.LastBidAt,
+		&lastBidAt,
+		&userStats.TotalAuctionsWon,
+		&userStats.TotalSpent,
 		&userStats.CreatedAt,
 		&userStats.UpdatedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, nil
+			return nil, userstats.ErrUserStatsNotFound
 		}
 		return nil, fmt.Errorf("failed to get user stats: %w", err)
 	}
+	if lastBidAt != nil {
+		userStats.LastBidAt = *lastBidAt
+	}
 	return &userStats, nil
 }
 
+// GetLeaderboard retrieves users who have placed bids, ordered by total amount bid (non-transactional read)
+func (r *UserStatsRepository) GetLeaderboard(ctx context.Context, limit, offset int) ([]*userstats.LeaderboardEntry, error) {
+	return r.getLeaderboard(ctx, r.reads, limit, offset)
+}
+
+// getLeaderboard is the internal implementation that works with any DBTX
+func (r *UserStatsRepository) getLeaderboard(ctx context.Context, db pkgdb.DBTX, limit, offset int) ([]*userstats.LeaderboardEntry, error) {
+	// RANK() is computed over the whole table before LIMIT/OFFSET, so ranks stay global across pages.
+	// user_id breaks ties so pagination is stable.
+	query := `
+		SELECT RANK() OVER (ORDER BY total_amount_bid DESC) AS rank,
+			user_id, total_bids_placed, total_amount_bid, last_bid_at,
+			total_auctions_won, total_spent, created_at, updated_at
+		FROM user_stats
+		WHERE total_bids_placed > 0
+		ORDER BY total_amount_bid DESC, user_id
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*userstats.LeaderboardEntry
+	for rows.Next() {
+		var entry userstats.LeaderboardEntry
+		err := rows.Scan(
+			&entry.Rank,
+			&entry.UserID,
+			&entry.TotalBidsPlaced,
+			&entry.TotalAmountBid,
+			&entry.LastBidAt,
+			&entry.TotalAuctionsWon,
+			&entry.TotalSpent,
+			&entry.CreatedAt,
+			&entry.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan leaderboard entry: %w", err)
+		}
+		result = append(result, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
 func (r *UserStatsRepository) MarkEventProcessed(ctx context.Context, tx pgx.Tx, eventID uuid.UUID) error {
 	query := `INSERT INTO processed_events (event_id) VALUES ($1)`
 	_, err := tx.Exec(ctx, query, eventID)
@@ -88,6 +265,35 @@ func (r *UserStatsRepository) MarkEventProcessed(ctx context.Context, tx pgx.Tx,
 	return nil
 }
 
+// MarkEventsProcessed inserts the event IDs in one statement. An ID a concurrent transaction is
+// inserting waits for it to finish, so each event is claimed by exactly one caller.
+func (r *UserStatsRepository) MarkEventsProcessed(ctx context.Context, tx pgx.Tx, eventIDs []uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		INSERT INTO processed_events (event_id)
+		SELECT unnest($1::uuid[])
+		ON CONFLICT (event_id) DO NOTHING
+		RETURNING event_id
+	`
+	rows, err := tx.Query(ctx, query, eventIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark events processed: %w", err)
+	}
+	defer rows.Close()
+
+	var marked []uuid.UUID
+	for rows.Next() {
+		var eventID uuid.UUID
+		if err := rows.Scan(&eventID); err != nil {
+			return nil, fmt.Errorf("failed to scan processed event: %w", err)
+		}
+		marked = append(marked, eventID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return marked, nil
+}
+
 func (r *UserStatsRepository) IsEventProcessed(ctx context.Context, tx pgx.Tx, eventID uuid.UUID) (bool, error) {
 	query := `SELECT 1 FROM processed_events WHERE event_id = $1`
 	var exists int
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ackCall is one acknowledgement sent to the broker
+type ackCall struct {
+	tag      uint64
+	multiple bool
+}
+
+// nackCall is one negative acknowledgement sent to the broker
+type nackCall struct {
+	tag     uint64
+	requeue bool
+}
+
+// recordingAcknowledger stands in for the channel deliveries are acknowledged on.
+// Like the channel, it may be acked from several workers at once.
+type recordingAcknowledger struct {
+	mu    sync.Mutex
+	acks  []ackCall
+	nacks []nackCall
+}
+
+func (r *recordingAcknowledger) Ack(tag uint64, multiple bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.acks = append(r.acks, ackCall{tag, multiple})
+	return nil
+}
+
+func (r *recordingAcknowledger) Nack(tag uint64, _ bool, requeue bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nacks = append(r.nacks, nackCall{tag, requeue})
+	return nil
+}
+
+func (r *recordingAcknowledger) Reject(uint64, bool) error { return nil }
+
+func TestAcker(t *testing.T) {
+	delivery := func(ch *recordingAcknowledger, tag uint64) amqp.Delivery {
+		return amqp.Delivery{Acknowledger: ch, DeliveryTag: tag}
+	}
+
+	t.Run("acks each delivery without batching", func(t *testing.T) {
+		ch := &recordingAcknowledger{}
+		acks := newConsumerConfig(nil).newAcker()
+		assert.Nil(t, acks.tick(), "no ticker without batching")
+
+		require.NoError(t, acks.ack(delivery(ch, 1)))
+		require.NoError(t, acks.ack(delivery(ch, 2)))
+
+		assert.Equal(t, []ackCall{{1, false}, {2, false}}, ch.acks)
+	})
+
+	t.Run("acks a full batch at once", func(t *testing.T) {
+		ch := &recordingAcknowledger{}
+		acks := newConsumerConfig([]ConsumerOption{WithBatchAck(3, time.Hour)}).newAcker()
+		defer acks.close()
+
+		require.NoError(t, acks.ack(delivery(ch, 1)))
+		require.NoError(t, acks.ack(delivery(ch, 2)))
+		assert.Empty(t, ch.acks, "a partial batch is held back")
+
+		// Tag 3 failed and was nacked on its own, so the batch continues at 4
+		require.NoError(t, acks.ack(delivery(ch, 4)))
+		assert.Equal(t, []ackCall{{4, true}}, ch.acks)
+	})
+
+	t.Run("close flushes a partial batch", func(t *testing.T) {
+		ch := &recordingAcknowledger{}
+		acks := newConsumerConfig([]ConsumerOption{WithBatchAck(10, time.Hour)}).newAcker()
+
+		require.NoError(t, acks.ack(delivery(ch, 1)))
+		require.NoError(t, acks.ack(delivery(ch, 2)))
+		require.NoError(t, acks.close())
+		require.NoError(t, acks.close(), "nothing left to flush")
+
+		assert.Equal(t, []ackCall{{2, true}}, ch.acks)
+	})
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"google.golang.org/protobuf/proto"
+
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	pb "github.com/floroz/gavel/pkg/proto"
+	"github.com/floroz/gavel/services/user-stats-service/internal/domain/userstats"
+)
+
+// AuctionConsumer consumes auction events and credits winners in user statistics
+type AuctionConsumer struct {
+	*consumer
+	service *userstats.Service
+}
+
+// NewAuctionConsumer creates a new auction consumer
+func NewAuctionConsumer(conn *amqp.Connection, service *userstats.Service, logger *slog.Logger, opts ...ConsumerOption) *AuctionConsumer {
+	c := &AuctionConsumer{service: service}
+	c.consumer = newConsumer("AuctionConsumer", conn, logger, auctionsQueue, map[string]HandlerFunc{
+		pkgevents.RoutingKeyAuctionEnded:  c.handleAuctionEnded,
+		pkgevents.RoutingKeyItemPurchased: c.handleItemPurchased,
+	}, opts)
+	return c
+}
+
+// handleAuctionEnded credits the winner of an auction in the statistics
+func (c *AuctionConsumer) handleAuctionEnded(ctx context.Context, d amqp.Delivery) error {
+	var event pb.AuctionEnded
+	if err := proto.Unmarshal(d.Body, &event); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal auction ended event: %v", ErrUnprocessable, err)
+	}
+
+	// Map to Domain DTO
+	// We use ItemId as EventID because an auction ends only once per item.
+	itemID, err := uuid.Parse(event.ItemId)
+	if err != nil {
+		return fmt.Errorf("%w: invalid item id: %v", ErrUnprocessable, err)
+	}
+	var winnerID uuid.UUID
+	if event.Sold {
+		winnerID, err = uuid.Parse(event.WinnerId)
+		if err != nil {
+			return fmt.Errorf("%w: invalid winner id: %v", ErrUnprocessable, err)
+		}
+	}
+
+	auctionEvent := userstats.AuctionEndedEvent{
+		EventID:  itemID, // Using ItemID as EventID
+		ItemID:   itemID,
+		WinnerID: winnerID,
+		Amount:   event.Amount,
+		EndedAt:  event.EndedAt.AsTime(),
+	}
+
+	// Call Service (Idempotent)
+	return c.service.ProcessAuctionEnded(ctx, auctionEvent)
+}
+
+// handleItemPurchased credits the buyer of an item bought outright. A purchase ends the auction
+// without an auction ended event, so the buyer is its winner.
+func (c *AuctionConsumer) handleItemPurchased(ctx context.Context, d amqp.Delivery) error {
+	var event pb.ItemPurchased
+	if err := proto.Unmarshal(d.Body, &event); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal item purchased event: %v", ErrUnprocessable, err)
+	}
+
+	// Keyed by ItemId like auction ended, so an item is credited once however its auction ended
+	itemID, err := uuid.Parse(event.ItemId)
+	if err != nil {
+		return fmt.Errorf("%w: invalid item id: %v", ErrUnprocessable, err)
+	}
+	buyerID, err := uuid.Parse(event.BuyerId)
+	if err != nil {
+		return fmt.Errorf("%w: invalid buyer id: %v", ErrUnprocessable, err)
+	}
+
+	auctionEvent := userstats.AuctionEndedEvent{
+		EventID:  itemID,
+		ItemID:   itemID,
+		WinnerID: buyerID,
+		Amount:   event.Amount,
+		EndedAt:  event.PurchasedAt.AsTime(),
+	}
+
+	// Call Service (Idempotent)
+	return c.service.ProcessAuctionEnded(ctx, auctionEvent)
+}
@@ -0,0 +1,138 @@
+package events_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	pb "github.com/floroz/gavel/pkg/proto"
+	"github.com/floroz/gavel/services/user-stats-service/internal/adapters/events"
+)
+
+func (env *consumerEnv) publishAuctionEvent(t *testing.T, routingKey string, event proto.Message) {
+	t.Helper()
+	body, err := proto.Marshal(event)
+	require.NoError(t, err)
+
+	err = env.publishCh.PublishWithContext(context.Background(), "auction.events", routingKey, false, false, amqp.Publishing{
+		ContentType: "application/x-protobuf",
+		Body:        body,
+	})
+	require.NoError(t, err)
+}
+
+// waitForWins waits until userID has won wins auctions and returns their total spent
+func (env *consumerEnv) waitForWins(t *testing.T, userID uuid.UUID, wins int) int64 {
+	t.Helper()
+	var totalSpent int64
+	require.Eventually(t, func() bool {
+		var won int
+		scanErr := env.dbPool.QueryRow(context.Background(),
+			"SELECT total_auctions_won, total_spent FROM user_stats WHERE user_id = $1", userID,
+		).Scan(&won, &totalSpent)
+		return scanErr == nil && won == wins
+	}, 5*time.Second, 100*time.Millisecond, "user should have won %d auctions", wins)
+	return totalSpent
+}
+
+func TestAuctionConsumerIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	env := setupConsumerEnv(t)
+
+	conn, err := amqp.Dial(env.amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	consumer := events.NewAuctionConsumer(conn, env.statsService, logger)
+	runConsumer(t, consumer)
+
+	winnerID := uuid.New()
+	ended := &pb.AuctionEnded{
+		ItemId:   uuid.New().String(),
+		Sold:     true,
+		WinnerId: winnerID.String(),
+		Amount:   300,
+		EndedAt:  timestamppb.Now(),
+	}
+
+	t.Run("auction ended credits the winner", func(t *testing.T) {
+		env.publishAuctionEvent(t, pkgevents.RoutingKeyAuctionEnded, ended)
+		assert.Equal(t, int64(300), env.waitForWins(t, winnerID, 1))
+	})
+
+	t.Run("item purchased credits the buyer", func(t *testing.T) {
+		env.publishAuctionEvent(t, pkgevents.RoutingKeyItemPurchased, &pb.ItemPurchased{
+			ItemId:      uuid.New().String(),
+			BuyerId:     winnerID.String(),
+			BidId:       uuid.New().String(),
+			Amount:      500,
+			PurchasedAt: timestamppb.Now(),
+		})
+		assert.Equal(t, int64(800), env.waitForWins(t, winnerID, 2))
+	})
+
+	t.Run("replayed and unsold auctions change nothing", func(t *testing.T) {
+		env.publishAuctionEvent(t, pkgevents.RoutingKeyAuctionEnded, ended)
+		env.publishAuctionEvent(t, pkgevents.RoutingKeyAuctionEnded, &pb.AuctionEnded{
+			ItemId:  uuid.New().String(),
+			EndedAt: timestamppb.Now(),
+		})
+		// Deliveries are handled in order, so once the barrier lands the others have been seen
+		env.publishAuctionEvent(t, pkgevents.RoutingKeyAuctionEnded, &pb.AuctionEnded{
+			ItemId:   uuid.New().String(),
+			Sold:     true,
+			WinnerId: winnerID.String(),
+			Amount:   100,
+			EndedAt:  timestamppb.Now(),
+		})
+		assert.Equal(t, int64(900), env.waitForWins(t, winnerID, 3))
+	})
+}
+
+func TestAuctionConsumerRejectsInvalidIDs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	env := setupConsumerEnv(t)
+
+	conn, err := amqp.Dial(env.amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	consumer := events.NewAuctionConsumer(conn, env.statsService, logger)
+	runConsumer(t, consumer)
+
+	env.publishAuctionEvent(t, pkgevents.RoutingKeyItemPurchased, &pb.ItemPurchased{
+		ItemId:      uuid.New().String(),
+		BuyerId:     "not-a-uuid",
+		Amount:      500,
+		PurchasedAt: timestamppb.Now(),
+	})
+
+	var msg amqp.Delivery
+	require.Eventually(t, func() bool {
+		var ok bool
+		msg, ok, err = env.publishCh.Get("user_stats_auctions.dlq", true)
+		return err == nil && ok
+	}, 5*time.Second, 100*time.Millisecond, "Malformed purchase should be dead-lettered")
+
+	var dead pb.ItemPurchased
+	require.NoError(t, proto.Unmarshal(msg.Body, &dead))
+	assert.Equal(t, "not-a-uuid", dead.BuyerId)
+}
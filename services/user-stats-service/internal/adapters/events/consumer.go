@@ -0,0 +1,869 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	"github.com/floroz/gavel/pkg/tracing"
+)
+
+const tracerName = "github.com/floroz/gavel/services/user-stats-service/internal/adapters/events"
+
+// Default reconnect backoff bounds for consumers
+const (
+	DefaultReconnectMinBackoff = 500 * time.Millisecond
+	DefaultReconnectMaxBackoff = 30 * time.Second
+)
+
+// DefaultMaxRetries is how many times a failing delivery is redelivered
+// before it is moved to the dead-letter queue
+const DefaultMaxRetries = 5
+
+// DeadLetterExchange receives deliveries that were rejected or ran out of retries.
+// Each consumer queue gets a "<queue>.dlq" bound to it under the queue's own name.
+const DeadLetterExchange = pkgevents.DeadLetterExchange
+
+// DefaultDrainTimeout bounds how long a consumer keeps processing the delivery in flight
+// once it is asked to stop
+const DefaultDrainTimeout = 10 * time.Second
+
+// DefaultProcessingTimeout bounds how long a single delivery may take to process. One that
+// takes longer, e.g. on a hung database, is requeued so it does not stall the consumer.
+const DefaultProcessingTimeout = 30 * time.Second
+
+// Default bounds of the delay before a failed delivery is retried, see WithRetryBackoff
+const (
+	DefaultRetryBackoffInitial = time.Second
+	DefaultRetryBackoffMax     = time.Minute
+)
+
+// Headers of deliveries republished to be retried after a delay. RetryAttemptHeader counts the
+// retries so far; RetryRoutingKeyHeader keeps the routing key the event was published under,
+// as the retry queue hands it back under the queue's name.
+const (
+	RetryAttemptHeader    = "x-retry-attempt"
+	RetryRoutingKeyHeader = "x-retry-routing-key"
+)
+
+// DefaultPrefetchCount bounds how many unacknowledged deliveries the broker
+// pushes to a consumer at once
+const DefaultPrefetchCount = 10
+
+// DefaultConcurrency is how many deliveries a consumer handles at once
+const DefaultConcurrency = 1
+
+// Queues the consumers read from. The bids and users queues were classic queues under names
+// without a suffix, and the bids queue then a quorum queue under the _v2 suffix; those are
+// retired, see Topology.
+//
+// The bids queue is a priority queue, so bids near an auction's close, published with
+// pkgevents.PriorityHigh, overtake a backlog. Only what is still waiting in the queue is
+// reordered, not the prefetched deliveries a consumer already holds. Bid stats do not depend on
+// the order bids arrive in. The broker does not count their redeliveries either, so the bid
+// consumer retries with WithRetryBackoff.
+const (
+	bidsQueue     = "user_stats_bids_v3"
+	usersQueue    = "user_stats_users_v2"
+	auctionsQueue = "user_stats_auctions"
+)
+
+// retiredQueues are the queues the bids and users queues replace, with their bindings
+var retiredQueues = []pkgevents.QueueSpec{
+	{Name: "user_stats_bids", RoutingKeys: []string{pkgevents.RoutingKeyBidPlaced}},
+	{Name: "user_stats_bids_v2", RoutingKeys: []string{pkgevents.RoutingKeyBidPlaced}},
+	{Name: "user_stats_users", RoutingKeys: []string{pkgevents.RoutingKeyUserCreated}},
+}
+
+// errConnectionLost is returned when the connection is gone and cannot be re-dialed
+var errConnectionLost = errors.New("connection closed and no dialer configured")
+
+// errChannelClosed is returned when the broker stops delivering, e.g. because the channel closed
+var errChannelClosed = errors.New("channel closed")
+
+// DialFunc opens a new broker connection
+type DialFunc func() (*amqp.Connection, error)
+
+// ConsumerOption configures optional consumer behaviour
+type ConsumerOption func(*consumerConfig)
+
+type consumerConfig struct {
+	dial        DialFunc
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	prefetch    int
+	concurrency int
+	maxRetries  int
+	drain       time.Duration
+	timeout     time.Duration
+	retryMin    time.Duration
+	retryMax    time.Duration
+	batchSize   int
+	batchEvery  time.Duration
+	tracer      trace.Tracer
+	metrics     *Metrics
+	queue       string
+	routingKeys []string
+	exchange    string
+}
+
+func newConsumerConfig(opts []ConsumerOption) consumerConfig {
+	cfg := consumerConfig{
+		minBackoff:  DefaultReconnectMinBackoff,
+		maxBackoff:  DefaultReconnectMaxBackoff,
+		prefetch:    DefaultPrefetchCount,
+		concurrency: DefaultConcurrency,
+		maxRetries:  DefaultMaxRetries,
+		drain:       DefaultDrainTimeout,
+		timeout:     DefaultProcessingTimeout,
+		tracer:      otel.Tracer(tracerName),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.metrics == nil {
+		cfg.metrics = NewMetrics(nil)
+	}
+	return cfg
+}
+
+// WithDialer lets the consumer re-dial the broker when the connection is lost.
+// Without it only the channel can be recovered.
+func WithDialer(dial DialFunc) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.dial = dial
+	}
+}
+
+// WithReconnectBackoff overrides the exponential backoff bounds used while reconnecting
+func WithReconnectBackoff(minBackoff, maxBackoff time.Duration) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.minBackoff = minBackoff
+		cfg.maxBackoff = maxBackoff
+	}
+}
+
+// WithPrefetch overrides DefaultPrefetchCount. Lower values spread a backlog more
+// fairly across workers; higher values trade memory for throughput.
+func WithPrefetch(count int) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.prefetch = count
+	}
+}
+
+// WithConcurrency handles up to n deliveries at once, each on a worker of its own, which pays off
+// when handlers mostly wait on the database. There is no ordering across workers: deliveries
+// are processed, and acked, in whatever order they finish. The prefetch count still bounds the
+// deliveries in flight, so workers beyond it sit idle. Batch acks are turned off, as a multiple
+// ack could cover a delivery another worker is still processing.
+func WithConcurrency(n int) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.concurrency = n
+	}
+}
+
+// WithMaxRetries overrides DefaultMaxRetries
+func WithMaxRetries(retries int) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.maxRetries = retries
+	}
+}
+
+// WithRetryBackoff retries a failed delivery after a delay, starting at initial and doubling with
+// every attempt up to maxDelay, instead of requeueing it straight away. The delivery is republished
+// to the retry queue of the consumer's queue and acked; once it has been retried the max retries
+// times it is rejected to the dead-letter queue.
+func WithRetryBackoff(initial, maxDelay time.Duration) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.retryMin = initial
+		cfg.retryMax = maxDelay
+	}
+}
+
+// WithDrainTimeout overrides DefaultDrainTimeout
+func WithDrainTimeout(timeout time.Duration) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.drain = timeout
+	}
+}
+
+// WithProcessingTimeout overrides DefaultProcessingTimeout; zero or less disables it
+func WithProcessingTimeout(timeout time.Duration) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.timeout = timeout
+	}
+}
+
+// WithBatchAck acks successful deliveries together, with a single multiple ack once size have
+// accumulated or every interval, whichever comes first. Failed deliveries are still nacked
+// straight away. It saves a round trip per delivery, but deliveries processed since the last
+// batch are redelivered if the consumer dies before acking them. size should not exceed the
+// prefetch count, or the broker pauses until the interval flushes the batch.
+func WithBatchAck(size int, interval time.Duration) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.batchSize = size
+		cfg.batchEvery = interval
+	}
+}
+
+// WithTracerProvider records processing spans with tp instead of the global tracer provider
+func WithTracerProvider(tp trace.TracerProvider) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// WithMetrics records delivery metrics in m, which several consumers may share
+func WithMetrics(m *Metrics) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.metrics = m
+	}
+}
+
+// WithQueue consumes from queue instead of the consumer's default queue, so isolated
+// environments sharing a broker do not take each other's deliveries. Its dead-letter
+// queue is named after it.
+func WithQueue(queue string) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.queue = queue
+	}
+}
+
+// WithRoutingKeys binds the queue to each of keys instead of the routing key the consumer's
+// events are published under by default. Deliveries keep their routing key, so the consumer
+// can tell the events apart.
+func WithRoutingKeys(keys ...string) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.routingKeys = keys
+	}
+}
+
+// WithExchange binds the queue on exchange instead of pkgevents.Exchange
+func WithExchange(exchange string) ConsumerOption {
+	return func(cfg *consumerConfig) {
+		cfg.exchange = exchange
+	}
+}
+
+// queueSpec is the queue a consumer whose default queue is queue reads from,
+// with the overrides of WithQueue, WithRoutingKeys and WithExchange applied
+func (cfg consumerConfig) queueSpec(queue string) pkgevents.QueueSpec {
+	spec := queueSpec(queue, cfg.maxRetries)
+	if cfg.queue != "" {
+		spec.Name = cfg.queue
+	}
+	if len(cfg.routingKeys) > 0 {
+		spec.RoutingKeys = cfg.routingKeys
+	}
+	spec.Exchange = cfg.exchange
+	return spec
+}
+
+// consumer is the part every consumer shares: it consumes a queue, re-establishing the channel and
+// connection when they are lost, and dispatches each delivery to the handler for its routing key
+type consumer struct {
+	name       string
+	conn       *amqp.Connection
+	logger     *slog.Logger
+	config     consumerConfig
+	queue      pkgevents.QueueSpec
+	dispatcher *Dispatcher
+}
+
+// newConsumer creates a consumer, called name in logs, of defaultQueue or the queue opts configure.
+// handlers handle the events published under their routing key; see handlerFor for which of
+// them handles the deliveries under each key the queue is bound to.
+func newConsumer(name string, conn *amqp.Connection, logger *slog.Logger, defaultQueue string, handlers map[string]HandlerFunc, opts []ConsumerOption) *consumer {
+	cfg := newConsumerConfig(opts)
+	c := &consumer{
+		name:       name,
+		conn:       conn,
+		logger:     logger,
+		config:     cfg,
+		queue:      cfg.queueSpec(defaultQueue),
+		dispatcher: NewDispatcher(),
+	}
+	// Deliveries under a key none of them handles are rejected to the DLQ rather than misread
+	for _, key := range c.queue.RoutingKeys {
+		if h, ok := handlerFor(key, handlers); ok {
+			c.dispatcher.Handle(key, h)
+		}
+	}
+	return c
+}
+
+// handlerFor returns the handler for deliveries under the bound key: the one for the event type
+// key names, either exactly or as its last segments like "staging.user.created" does, or else
+// the only handler of a consumer of a single event type
+func handlerFor(key string, handlers map[string]HandlerFunc) (HandlerFunc, bool) {
+	if h, ok := handlers[key]; ok {
+		return h, true
+	}
+	for eventKey, h := range handlers {
+		if strings.HasSuffix(key, "."+eventKey) {
+			return h, true
+		}
+	}
+	if len(handlers) == 1 {
+		for _, h := range handlers {
+			return h, true
+		}
+	}
+	return nil, false
+}
+
+// Handle registers h for deliveries under routingKey in place of the consumer's own handler.
+// The queue must also be bound to routingKey, e.g. with WithRoutingKeys. Handlers are
+// registered before Run.
+func (c *consumer) Handle(routingKey string, h HandlerFunc) {
+	c.dispatcher.Handle(routingKey, h)
+}
+
+// Run starts the consumer loop. If the channel or connection is lost it is re-established
+// with exponential backoff, so Run only returns once ctx is cancelled, or with an error
+// if the connection is gone and no dialer was configured.
+func (c *consumer) Run(ctx context.Context) error {
+	backoff := c.config.minBackoff
+	for {
+		consumed, err := c.consume(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, errConnectionLost) {
+			return err
+		}
+		if consumed {
+			// The last session was healthy, so start over with a short wait
+			backoff = c.config.minBackoff
+		}
+
+		c.logger.Warn(c.name+" disconnected, reconnecting", "error", err, "backoff", backoff)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, c.config.maxBackoff)
+	}
+}
+
+// consume runs a single consuming session until the channel closes or ctx is cancelled.
+// Once ctx is cancelled no new deliveries are taken, but the one being handled is finished.
+// It reports whether consuming had started, so Run can reset its backoff.
+func (c *consumer) consume(ctx context.Context) (bool, error) {
+	conn, err := c.connection()
+	if err != nil {
+		return false, err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return false, fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+
+	// Setup Exchange & Queue
+	if setupErr := c.setupRabbitMQ(ch); setupErr != nil {
+		return false, fmt.Errorf("failed to setup rabbitmq: %w", setupErr)
+	}
+
+	// Bound in-flight deliveries; with manual acks the broker would otherwise push the whole backlog
+	if err := ch.Qos(c.config.prefetch, 0, false); err != nil {
+		return false, fmt.Errorf("failed to set qos: %w", err)
+	}
+
+	msgs, err := ch.Consume(
+		c.queue.Name, // queue
+		"",           // consumer tag
+		false,        // auto-ack
+		false,        // exclusive
+		false,        // no-local
+		false,        // no-wait
+		nil,          // args
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to start consuming: %w", err)
+	}
+
+	c.logger.Info(c.name + " waiting for messages...")
+
+	return true, c.config.handleDeliveries(ctx, conn, msgs, c.logger, c.handle)
+}
+
+// connection returns an open connection, re-dialing if the current one was lost
+func (c *consumer) connection() (*amqp.Connection, error) {
+	if !c.conn.IsClosed() {
+		return c.conn, nil
+	}
+	if c.config.dial == nil {
+		return nil, errConnectionLost
+	}
+
+	conn, err := c.config.dial()
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial: %w", err)
+	}
+	c.conn = conn
+	return conn, nil
+}
+
+func (c *consumer) handle(ctx context.Context, d amqp.Delivery, acks *acker) {
+	c.config.dispatch(ctx, c.queue.Name, c.dispatcher, c.logger, d, acks)
+}
+
+func (c *consumer) setupRabbitMQ(ch pkgevents.TopologyChannel) error {
+	return queueTopology(c.queue).Declare(ch)
+}
+
+// checkSchemaVersion fails with ErrUnprocessable for a delivery published with a schema
+// version this build cannot read, so it is dead-lettered rather than misread into the stats
+func checkSchemaVersion(d amqp.Delivery) error {
+	if err := pkgevents.CheckSchemaVersion(d.Headers); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnprocessable, err)
+	}
+	return nil
+}
+
+// handleDeliveries hands each delivery of msgs to handle, on as many workers as the concurrency,
+// until msgs closes or ctx is cancelled. Once ctx is cancelled no new deliveries are taken, but
+// those being handled are finished.
+// With WithRetryBackoff, failed deliveries are republished for a delayed retry on a channel of conn.
+func (cfg consumerConfig) handleDeliveries(ctx context.Context, conn *amqp.Connection, msgs <-chan amqp.Delivery, logger *slog.Logger, handle func(context.Context, amqp.Delivery, *acker)) error {
+	// Flushed before the channel closes, so a finished batch is not redelivered
+	acks := cfg.newAcker()
+	if cfg.retryMin > 0 {
+		publisher, err := newConfirmPublisher(conn)
+		if err != nil {
+			return err
+		}
+		defer publisher.close()
+		acks.publisher = publisher
+	}
+	defer func() {
+		if err := acks.close(); err != nil {
+			logger.Error("Failed to Ack batch", "error", err)
+		}
+	}()
+
+	if cfg.concurrency > 1 {
+		var wg sync.WaitGroup
+		var closed atomic.Bool
+		for range cfg.concurrency {
+			wg.Go(func() {
+				if !cfg.handleEach(ctx, msgs, acks, handle) {
+					closed.Store(true)
+				}
+			})
+		}
+		wg.Wait()
+		if closed.Load() {
+			return errChannelClosed
+		}
+		return nil
+	}
+
+	for {
+		// Both cases may be ready at once; stopping takes priority over the next delivery
+		if ctx.Err() != nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-acks.tick():
+			if err := acks.flush(); err != nil {
+				logger.Error("Failed to Ack batch", "error", err)
+			}
+		case d, ok := <-msgs:
+			if !ok {
+				return errChannelClosed
+			}
+			processing, cancel := cfg.processingContext(ctx)
+			handle(processing, restoreRoutingKey(d), acks)
+			cancel()
+		}
+	}
+}
+
+// handleEach is a single worker of handleDeliveries. It reports false once msgs has closed.
+func (cfg consumerConfig) handleEach(ctx context.Context, msgs <-chan amqp.Delivery, acks *acker, handle func(context.Context, amqp.Delivery, *acker)) bool {
+	for {
+		if ctx.Err() != nil {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return true
+		case d, ok := <-msgs:
+			if !ok {
+				return false
+			}
+			processing, cancel := cfg.processingContext(ctx)
+			handle(processing, restoreRoutingKey(d), acks)
+			cancel()
+		}
+	}
+}
+
+// dispatch hands d to the handler dispatcher has for its routing key, then acks it on success,
+// rejects it to the DLQ when retrying cannot help and retries it otherwise
+func (cfg consumerConfig) dispatch(ctx context.Context, queue string, dispatcher *Dispatcher, logger *slog.Logger, d amqp.Delivery, acks *acker) {
+	ctx, span := cfg.startSpan(ctx, queue, d)
+	defer span.End()
+
+	// Tag every log line with the user action this message belongs to
+	correlation := pkgevents.CorrelationFromHeaders(d.Headers)
+	logger = logger.With("correlation_id", correlation.CorrelationID, "causation_id", correlation.CausationID)
+
+	// Anything not acked or requeued below has been rejected to the DLQ
+	start := time.Now()
+	outcome := OutcomeRejected
+	cfg.metrics.Received(d.RoutingKey).Inc()
+	defer func() { cfg.metrics.observe(d.RoutingKey, outcome, time.Since(start)) }()
+
+	logger.Info("Received message", "routing_key", d.RoutingKey)
+
+	err := checkSchemaVersion(d)
+	if err == nil {
+		err = dispatcher.Dispatch(ctx, d)
+	}
+	switch {
+	case errors.Is(err, ErrUnknownRoutingKey), errors.Is(err, ErrUnprocessable):
+		// Retrying cannot fix a message nothing handles or a handler rejected; straight to the DLQ
+		logger.Error("Rejecting message", "error", err, "routing_key", d.RoutingKey)
+		tracing.RecordError(span, err)
+		if nackErr := d.Nack(false, false); nackErr != nil {
+			logger.Error("Failed to Nack message", "error", nackErr)
+		}
+	case err != nil:
+		logger.Error("Failed to process event", "error", err, "routing_key", d.RoutingKey)
+		tracing.RecordError(span, err)
+		// Retried until the retry budget is spent, then dead-lettered
+		var retryErr error
+		outcome, retryErr = acks.retry(ctx, queue, d)
+		if retryErr != nil {
+			logger.Error("Failed to retry message", "error", retryErr)
+		}
+	default:
+		// Ack on success
+		outcome = OutcomeSuccess
+		if ackErr := acks.ack(d); ackErr != nil {
+			logger.Error("Failed to Ack message", "error", ackErr)
+		}
+		logger.Info("Successfully processed event", "routing_key", d.RoutingKey)
+	}
+}
+
+// processingContext returns the context a delivery is handled in. It is not cancelled with ctx
+// straight away but up to the drain timeout later, so a delivery in flight at shutdown is
+// finished and acked instead of failing and being redelivered after a restart. It expires
+// after the processing timeout, failing the delivery so it is requeued.
+func (cfg consumerConfig) processingContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	processing, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	if cfg.timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		processing, cancelTimeout = context.WithTimeout(processing, cfg.timeout)
+		cancelParent := cancel
+		cancel = func() {
+			cancelTimeout()
+			cancelParent()
+		}
+	}
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.AfterFunc(cfg.drain, cancel)
+		context.AfterFunc(processing, func() { timer.Stop() })
+	})
+	return processing, func() {
+		stop()
+		cancel()
+	}
+}
+
+// retryPublisher republishes deliveries to be retried to a retry queue. It only returns
+// once the broker has taken responsibility for the message.
+type retryPublisher interface {
+	publish(ctx context.Context, queue string, msg amqp.Publishing) error
+}
+
+// confirmPublisher is the retryPublisher of a consuming session. It publishes on a channel of its
+// own in confirm mode and with the mandatory flag, so a retry that the broker refused or could not
+// route, e.g. because the retry queue is missing, fails instead of being dropped.
+type confirmPublisher struct {
+	// mu serializes publish, since confirms are matched to publishes in order
+	mu      sync.Mutex
+	ch      *amqp.Channel
+	returns chan amqp.Return
+}
+
+func newConfirmPublisher(conn *amqp.Connection) (*confirmPublisher, error) {
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open retry channel: %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	return &confirmPublisher{ch: ch, returns: ch.NotifyReturn(make(chan amqp.Return, 16))}, nil
+}
+
+func (p *confirmPublisher) publish(ctx context.Context, queue string, msg amqp.Publishing) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, pkgevents.DefaultConfirmTimeout)
+	defer cancel()
+
+	// Returns left over from an earlier publish that timed out are not for this one
+	p.drainReturns()
+
+	confirmation, err := p.ch.PublishWithDeferredConfirmWithContext(ctx, "", queue, true, false, msg)
+	if err != nil {
+		return fmt.Errorf("failed to publish: %w", err)
+	}
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed waiting for publish confirm: %w", err)
+	}
+	if !acked {
+		return pkgevents.ErrPublishNacked
+	}
+	// The broker sends basic.return before the ack, so a return for this message is already queued
+	if ret, ok := p.drainReturns(); ok {
+		return fmt.Errorf("%w: %s", pkgevents.ErrPublishReturned, ret.ReplyText)
+	}
+	return nil
+}
+
+// drainReturns empties the queued returns, reporting the last one
+func (p *confirmPublisher) drainReturns() (amqp.Return, bool) {
+	var last amqp.Return
+	var returned bool
+	for {
+		select {
+		case ret, ok := <-p.returns:
+			if !ok {
+				return last, returned
+			}
+			last, returned = ret, true
+		default:
+			return last, returned
+		}
+	}
+}
+
+func (p *confirmPublisher) close() {
+	p.ch.Close()
+}
+
+// acker acknowledges the deliveries of one channel: successfully processed ones either one at a
+// time or, with WithBatchAck, in batches, and failed ones by retrying them. Only an acker that
+// does not batch is safe for concurrent use.
+type acker struct {
+	size    int
+	pending int
+	last    amqp.Delivery
+	ticker  *time.Ticker
+
+	// publisher is set once the channel is open; retries are delayed only with one
+	publisher  retryPublisher
+	maxRetries int
+	retryMin   time.Duration
+	retryMax   time.Duration
+}
+
+func (cfg consumerConfig) newAcker() *acker {
+	a := &acker{maxRetries: cfg.maxRetries, retryMin: cfg.retryMin, retryMax: cfg.retryMax}
+	if cfg.concurrency <= 1 {
+		a.size = cfg.batchSize
+	}
+	if a.batching() && cfg.batchEvery > 0 {
+		a.ticker = time.NewTicker(cfg.batchEvery)
+	}
+	return a
+}
+
+func (a *acker) batching() bool {
+	return a.size > 1
+}
+
+// ack acknowledges d, or holds it back until the batch is full
+func (a *acker) ack(d amqp.Delivery) error {
+	if !a.batching() {
+		return d.Ack(false)
+	}
+	a.last = d
+	a.pending++
+	if a.pending >= a.size {
+		return a.flush()
+	}
+	return nil
+}
+
+// flush acks every delivery held back. Acking the latest with multiple=true covers the earlier
+// ones too; deliveries nacked in between are no longer outstanding, so they are not affected.
+func (a *acker) flush() error {
+	if a.pending == 0 {
+		return nil
+	}
+	a.pending = 0
+	return a.last.Ack(true)
+}
+
+// retry hands a failed delivery of queue back to be processed again and returns the outcome.
+// With WithRetryBackoff it is republished to the retry queue with a delay and acked once the
+// broker confirms the copy, or rejected to the DLQ once its retries are spent. Otherwise it is requeued straight away, and the broker
+// dead-letters it once the queue's delivery limit is reached.
+func (a *acker) retry(ctx context.Context, queue string, d amqp.Delivery) (string, error) {
+	if a.publisher == nil || a.retryMin <= 0 {
+		return OutcomeRetry, d.Nack(false, true)
+	}
+
+	attempt := retryAttempt(d.Headers) + 1
+	if attempt > a.maxRetries {
+		return OutcomeRejected, d.Nack(false, false)
+	}
+
+	headers := maps.Clone(d.Headers)
+	if headers == nil {
+		headers = amqp.Table{}
+	}
+	headers[RetryAttemptHeader] = int64(attempt)
+	headers[RetryRoutingKeyHeader] = d.RoutingKey
+
+	// Republished even when processing ran out of time, as that is what is being retried
+	err := a.publisher.publish(context.WithoutCancel(ctx), pkgevents.RetryQueue(queue), amqp.Publishing{
+		Headers:         headers,
+		ContentType:     d.ContentType,
+		ContentEncoding: d.ContentEncoding,
+		DeliveryMode:    amqp.Persistent,
+		Priority:        d.Priority,
+		MessageId:       d.MessageId,
+		Timestamp:       d.Timestamp,
+		Expiration:      strconv.FormatInt(a.retryDelay(attempt).Milliseconds(), 10),
+		Body:            d.Body,
+	})
+	if err != nil {
+		// Requeueing straight away is the only way left not to lose the delivery
+		return OutcomeRetry, errors.Join(fmt.Errorf("failed to republish for retry: %w", err), d.Nack(false, true))
+	}
+	return OutcomeRetry, a.ack(d)
+}
+
+// retryDelay is how long the given retry attempt waits, doubling from retryMin up to retryMax
+func (a *acker) retryDelay(attempt int) time.Duration {
+	delay := a.retryMin
+	for range attempt - 1 {
+		if delay >= a.retryMax {
+			break
+		}
+		delay *= 2
+	}
+	return min(delay, a.retryMax)
+}
+
+// retryAttempt returns how many times a delivery with headers has been retried after a delay
+func retryAttempt(headers amqp.Table) int {
+	switch v := headers[RetryAttemptHeader].(type) {
+	case int64:
+		return int(v)
+	case int32:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// restoreRoutingKey gives a retried delivery back the routing key it was published under
+func restoreRoutingKey(d amqp.Delivery) amqp.Delivery {
+	if key, ok := d.Headers[RetryRoutingKeyHeader].(string); ok && key != "" {
+		d.RoutingKey = key
+	}
+	return d
+}
+
+// tick fires when held back deliveries are due to be flushed; it never fires without batching
+func (a *acker) tick() <-chan time.Time {
+	if a.ticker == nil {
+		return nil
+	}
+	return a.ticker.C
+}
+
+// close flushes what is left and stops the ticker
+func (a *acker) close() error {
+	if a.ticker != nil {
+		a.ticker.Stop()
+	}
+	return a.flush()
+}
+
+// startSpan starts the span for processing d, continuing the trace carried in its headers
+func (cfg consumerConfig) startSpan(ctx context.Context, queue string, d amqp.Delivery) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, pkgevents.HeaderCarrier(d.Headers))
+	return cfg.tracer.Start(ctx, d.RoutingKey+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", queue),
+			attribute.String("messaging.rabbitmq.destination.routing_key", d.RoutingKey),
+			attribute.String("messaging.message.id", d.MessageId),
+		),
+	)
+}
+
+// queueRoutingKeys are the routing keys each consumer queue is bound to on pkgevents.Exchange
+var queueRoutingKeys = map[string][]string{
+	bidsQueue:     {pkgevents.RoutingKeyBidPlaced},
+	usersQueue:    {pkgevents.RoutingKeyUserCreated},
+	auctionsQueue: {pkgevents.RoutingKeyAuctionEnded, pkgevents.RoutingKeyItemPurchased},
+}
+
+// queueMaxPriorities are the priority queues among the consumer queues
+var queueMaxPriorities = map[string]uint8{
+	bidsQueue: pkgevents.PriorityHigh,
+}
+
+// Topology is the broker topology of every user stats consumer, with deliveries retried
+// maxRetries times before they are dead-lettered. The worker declares it on startup, so a
+// queue left over with other settings stops it before it consumes anything.
+//
+// It retires the queues of earlier deployments: they stop taking new events, which go to the
+// queues replacing them, and are deleted once the previous workers have drained them. Events
+// routed to both are counted once, as their IDs are recorded.
+func Topology(maxRetries int) pkgevents.Topology {
+	topology := pkgevents.Topology{Retired: retiredQueues}
+	for _, queue := range []string{bidsQueue, usersQueue, auctionsQueue} {
+		topology.Queues = append(topology.Queues, queueSpec(queue, maxRetries))
+	}
+	return topology
+}
+
+// queueTopology is the part of the topology a single consumer relies on
+func queueTopology(spec pkgevents.QueueSpec) pkgevents.Topology {
+	return pkgevents.Topology{Queues: []pkgevents.QueueSpec{spec}}
+}
+
+func queueSpec(queue string, maxRetries int) pkgevents.QueueSpec {
+	return pkgevents.QueueSpec{
+		Name:         queue,
+		RoutingKeys:  queueRoutingKeys[queue],
+		MaxRetries:   maxRetries,
+		MaxPriority:  queueMaxPriorities[queue],
+		DelayedRetry: true,
+	}
+}
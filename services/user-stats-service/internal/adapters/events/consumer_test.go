@@ -0,0 +1,224 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgevents "github.com/floroz/gavel/pkg/events"
+)
+
+func TestConsumer_ProcessingTimeout(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	consumer := NewUserConsumer(nil, nil, slog.New(slog.DiscardHandler), WithProcessingTimeout(timeout))
+
+	// A handler stuck well past the deadline, e.g. on a hung database
+	consumer.Handle("user.slow", func(ctx context.Context, _ amqp.Delivery) error {
+		select {
+		case <-time.After(5 * time.Second):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	ch := &recordingAcknowledger{}
+	processing, cancel := consumer.config.processingContext(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	consumer.handle(processing, amqp.Delivery{Acknowledger: ch, DeliveryTag: 1, RoutingKey: "user.slow"}, consumer.config.newAcker())
+
+	assert.Less(t, time.Since(start), time.Second, "the consumer is freed once the deadline passes")
+	assert.Empty(t, ch.acks)
+	assert.Equal(t, []nackCall{{1, true}}, ch.nacks, "the delivery is requeued for retry")
+}
+
+func TestConsumer_ProcessingContext(t *testing.T) {
+	t.Run("expires after the processing timeout", func(t *testing.T) {
+		cfg := newConsumerConfig([]ConsumerOption{WithProcessingTimeout(time.Minute)})
+		processing, cancel := cfg.processingContext(context.Background())
+		defer cancel()
+
+		deadline, ok := processing.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	})
+
+	t.Run("no deadline when disabled", func(t *testing.T) {
+		cfg := newConsumerConfig([]ConsumerOption{WithProcessingTimeout(0)})
+		processing, cancel := cfg.processingContext(context.Background())
+		defer cancel()
+
+		_, ok := processing.Deadline()
+		assert.False(t, ok)
+	})
+}
+
+func TestConsumer_Concurrency(t *testing.T) {
+	const (
+		burst   = 20
+		latency = 20 * time.Millisecond
+	)
+
+	// processBurst handles a burst of I/O bound deliveries and returns how long it took,
+	// along with the acknowledgements sent
+	processBurst := func(t *testing.T, opts ...ConsumerOption) (time.Duration, *recordingAcknowledger) {
+		t.Helper()
+		consumer := NewUserConsumer(nil, nil, slog.New(slog.DiscardHandler), opts...)
+		consumer.Handle("user.slow", func(context.Context, amqp.Delivery) error {
+			time.Sleep(latency)
+			return nil
+		})
+
+		ch := &recordingAcknowledger{}
+		msgs := make(chan amqp.Delivery, burst)
+		for tag := uint64(1); tag <= burst; tag++ {
+			msgs <- amqp.Delivery{Acknowledger: ch, DeliveryTag: tag, RoutingKey: "user.slow"}
+		}
+		close(msgs)
+
+		start := time.Now()
+		err := consumer.config.handleDeliveries(context.Background(), nil, msgs, consumer.logger, consumer.handle)
+		elapsed := time.Since(start)
+		assert.ErrorIs(t, err, errChannelClosed)
+		return elapsed, ch
+	}
+
+	// ackedOnce asserts every delivery of the burst was acked exactly once, on its own
+	ackedOnce := func(t *testing.T, ch *recordingAcknowledger) {
+		t.Helper()
+		want := make([]ackCall, 0, burst)
+		for tag := uint64(1); tag <= burst; tag++ {
+			want = append(want, ackCall{tag, false})
+		}
+		assert.ElementsMatch(t, want, ch.acks)
+		assert.Empty(t, ch.nacks)
+	}
+
+	sequential, ch := processBurst(t)
+	ackedOnce(t, ch)
+
+	concurrent, ch := processBurst(t, WithConcurrency(5), WithBatchAck(5, time.Hour))
+	ackedOnce(t, ch)
+
+	assert.Less(t, concurrent, sequential/2, "a burst is handled faster with concurrency")
+}
+
+// publishCall is one message republished for a retry
+type publishCall struct {
+	queue string
+	msg   amqp.Publishing
+}
+
+// recordingPublisher stands in for the channel retries are republished on. It fails every
+// publish with err, as the broker would when it does not confirm one.
+type recordingPublisher struct {
+	published []publishCall
+	err       error
+}
+
+func (r *recordingPublisher) publish(_ context.Context, queue string, msg amqp.Publishing) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.published = append(r.published, publishCall{queue, msg})
+	return nil
+}
+
+func TestAcker_Retry(t *testing.T) {
+	ctx := context.Background()
+	newAcker := func(opts ...ConsumerOption) (*acker, *recordingPublisher) {
+		acks := newConsumerConfig(opts).newAcker()
+		publisher := &recordingPublisher{}
+		acks.publisher = publisher
+		return acks, publisher
+	}
+	delivery := func(ch *recordingAcknowledger, headers amqp.Table) amqp.Delivery {
+		return amqp.Delivery{Acknowledger: ch, DeliveryTag: 1, RoutingKey: "user.created", Headers: headers, Body: []byte("payload")}
+	}
+
+	t.Run("requeues straight away without backoff", func(t *testing.T) {
+		acks, publisher := newAcker()
+		ch := &recordingAcknowledger{}
+
+		outcome, err := acks.retry(ctx, usersQueue, delivery(ch, nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, OutcomeRetry, outcome)
+		assert.Equal(t, []nackCall{{1, true}}, ch.nacks)
+		assert.Empty(t, publisher.published)
+	})
+
+	t.Run("republishes with a growing delay and attempt count", func(t *testing.T) {
+		acks, publisher := newAcker(WithRetryBackoff(100*time.Millisecond, time.Second), WithMaxRetries(3))
+		ch := &recordingAcknowledger{}
+
+		for _, headers := range []amqp.Table{nil, {RetryAttemptHeader: int64(1), pkgevents.CorrelationIDHeader: "req-1"}} {
+			outcome, err := acks.retry(ctx, usersQueue, delivery(ch, headers))
+			require.NoError(t, err)
+			assert.Equal(t, OutcomeRetry, outcome)
+		}
+
+		require.Len(t, publisher.published, 2)
+		first, second := publisher.published[0], publisher.published[1]
+		assert.Equal(t, pkgevents.RetryQueue(usersQueue), first.queue)
+		assert.Equal(t, "100", first.msg.Expiration)
+		assert.Equal(t, int64(1), first.msg.Headers[RetryAttemptHeader])
+		assert.Equal(t, "user.created", first.msg.Headers[RetryRoutingKeyHeader])
+		assert.Equal(t, []byte("payload"), first.msg.Body)
+
+		assert.Equal(t, "200", second.msg.Expiration, "the delay doubles")
+		assert.Equal(t, int64(2), second.msg.Headers[RetryAttemptHeader])
+		assert.Equal(t, "req-1", second.msg.Headers[pkgevents.CorrelationIDHeader], "other headers are kept")
+
+		assert.Equal(t, []ackCall{{1, false}, {1, false}}, ch.acks, "the original is acked once republished")
+		assert.Empty(t, ch.nacks)
+	})
+
+	t.Run("requeues when the republish is not confirmed", func(t *testing.T) {
+		acks, publisher := newAcker(WithRetryBackoff(100*time.Millisecond, time.Second), WithMaxRetries(3))
+		publisher.err = pkgevents.ErrPublishReturned
+		ch := &recordingAcknowledger{}
+
+		outcome, err := acks.retry(ctx, usersQueue, delivery(ch, nil))
+		require.ErrorIs(t, err, pkgevents.ErrPublishReturned)
+
+		assert.Equal(t, OutcomeRetry, outcome)
+		assert.Empty(t, ch.acks, "the original is only acked once its copy is confirmed")
+		assert.Equal(t, []nackCall{{1, true}}, ch.nacks)
+	})
+
+	t.Run("rejects to the DLQ once retries are spent", func(t *testing.T) {
+		acks, publisher := newAcker(WithRetryBackoff(100*time.Millisecond, time.Second), WithMaxRetries(3))
+		ch := &recordingAcknowledger{}
+
+		outcome, err := acks.retry(ctx, usersQueue, delivery(ch, amqp.Table{RetryAttemptHeader: int64(3)}))
+		require.NoError(t, err)
+
+		assert.Equal(t, OutcomeRejected, outcome)
+		assert.Equal(t, []nackCall{{1, false}}, ch.nacks)
+		assert.Empty(t, publisher.published)
+	})
+
+	t.Run("delay is capped", func(t *testing.T) {
+		acks, _ := newAcker(WithRetryBackoff(time.Second, 5*time.Second))
+		assert.Equal(t, time.Second, acks.retryDelay(1))
+		assert.Equal(t, 4*time.Second, acks.retryDelay(3))
+		assert.Equal(t, 5*time.Second, acks.retryDelay(4))
+		assert.Equal(t, 5*time.Second, acks.retryDelay(50))
+	})
+}
+
+func TestRestoreRoutingKey(t *testing.T) {
+	retried := amqp.Delivery{RoutingKey: usersQueue, Headers: amqp.Table{RetryRoutingKeyHeader: "user.created"}}
+	assert.Equal(t, "user.created", restoreRoutingKey(retried).RoutingKey)
+
+	fresh := amqp.Delivery{RoutingKey: "user.created"}
+	assert.Equal(t, "user.created", restoreRoutingKey(fresh).RoutingKey)
+}
@@ -0,0 +1,40 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgevents "github.com/floroz/gavel/pkg/events"
+)
+
+func TestUserConsumer_LogsCorrelationID(t *testing.T) {
+	var logs bytes.Buffer
+	consumer := NewUserConsumer(nil, nil, slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	// A malformed payload is rejected before the service is called
+	consumer.handle(context.Background(), amqp.Delivery{
+		Acknowledger: &recordingAcknowledger{},
+		RoutingKey:   "user.created",
+		Headers: amqp.Table{
+			pkgevents.CorrelationIDHeader: "req-123",
+			pkgevents.CausationIDHeader:   "req-123",
+		},
+		Body: []byte("not a protobuf"),
+	}, newConsumerConfig(nil).newAcker())
+
+	lines := bytes.Split(bytes.TrimSpace(logs.Bytes()), []byte("\n"))
+	require.NotEmpty(t, lines)
+	for _, line := range lines {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(line, &entry))
+		assert.Equal(t, "req-123", entry["correlation_id"], "in %q", entry["msg"])
+		assert.Equal(t, "req-123", entry["causation_id"])
+	}
+}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrUnprocessable marks a delivery retrying cannot fix, such as a malformed payload.
+// Handlers wrap it so the delivery is rejected straight to the dead-letter queue.
+var ErrUnprocessable = errors.New("unprocessable delivery")
+
+// ErrUnknownRoutingKey is returned for a delivery no handler is registered for
+var ErrUnknownRoutingKey = errors.New("no handler for routing key")
+
+// HandlerFunc handles one delivery. An error wrapping ErrUnprocessable rejects the delivery
+// to the dead-letter queue; any other error requeues it to be retried.
+type HandlerFunc func(ctx context.Context, d amqp.Delivery) error
+
+// Dispatcher routes deliveries to the handler registered for their routing key, so a queue
+// can carry several event types. Handlers are registered before consuming starts; it is not
+// safe to register them concurrently with Dispatch.
+type Dispatcher struct {
+	handlers map[string]HandlerFunc
+}
+
+// NewDispatcher creates a dispatcher with no handlers
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string]HandlerFunc)}
+}
+
+// Handle registers h for deliveries published under routingKey, replacing any handler
+// registered for it before
+func (r *Dispatcher) Handle(routingKey string, h HandlerFunc) {
+	r.handlers[routingKey] = h
+}
+
+// Dispatch hands d to the handler registered for its routing key
+func (r *Dispatcher) Dispatch(ctx context.Context, d amqp.Delivery) error {
+	h, ok := r.handlers[d.RoutingKey]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownRoutingKey, d.RoutingKey)
+	}
+	return h(ctx, d)
+}
@@ -0,0 +1,168 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	pb "github.com/floroz/gavel/pkg/proto"
+)
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("routes each delivery to the handler for its routing key", func(t *testing.T) {
+		var created, updated []string
+		dispatcher := NewDispatcher()
+		dispatcher.Handle("user.created", func(_ context.Context, d amqp.Delivery) error {
+			created = append(created, string(d.Body))
+			return nil
+		})
+		dispatcher.Handle("user.updated", func(_ context.Context, d amqp.Delivery) error {
+			updated = append(updated, string(d.Body))
+			return nil
+		})
+
+		require.NoError(t, dispatcher.Dispatch(ctx, amqp.Delivery{RoutingKey: "user.created", Body: []byte("first")}))
+		require.NoError(t, dispatcher.Dispatch(ctx, amqp.Delivery{RoutingKey: "user.updated", Body: []byte("second")}))
+
+		assert.Equal(t, []string{"first"}, created)
+		assert.Equal(t, []string{"second"}, updated)
+	})
+
+	t.Run("returns the handler's error", func(t *testing.T) {
+		errHandler := errors.New("handler failed")
+		dispatcher := NewDispatcher()
+		dispatcher.Handle("user.created", func(context.Context, amqp.Delivery) error { return errHandler })
+
+		assert.ErrorIs(t, dispatcher.Dispatch(ctx, amqp.Delivery{RoutingKey: "user.created"}), errHandler)
+	})
+
+	t.Run("unknown routing key", func(t *testing.T) {
+		err := NewDispatcher().Dispatch(ctx, amqp.Delivery{RoutingKey: "user.deleted"})
+		assert.ErrorIs(t, err, ErrUnknownRoutingKey)
+		assert.ErrorContains(t, err, "user.deleted")
+	})
+}
+
+func TestUserConsumer_HandlesEveryBoundRoutingKey(t *testing.T) {
+	consumer := NewUserConsumer(nil, nil, slog.New(slog.DiscardHandler), WithRoutingKeys("staging.user.created", "user.imported"))
+
+	for _, key := range []string{"staging.user.created", "user.imported"} {
+		// Reaching the user created handler, which rejects the payload, rather than no handler at all
+		err := consumer.dispatcher.Dispatch(context.Background(), amqp.Delivery{RoutingKey: key, Body: []byte("not a protobuf")})
+		assert.ErrorIs(t, err, ErrUnprocessable, key)
+		assert.NotErrorIs(t, err, ErrUnknownRoutingKey, key)
+	}
+
+	err := consumer.dispatcher.Dispatch(context.Background(), amqp.Delivery{RoutingKey: pkgevents.RoutingKeyUserCreated})
+	assert.ErrorIs(t, err, ErrUnknownRoutingKey, "the default key is not bound")
+}
+
+func TestHandlerFor(t *testing.T) {
+	handled := func(name string) HandlerFunc {
+		return func(context.Context, amqp.Delivery) error { return errors.New(name) }
+	}
+	single := map[string]HandlerFunc{"user.created": handled("created")}
+	multiple := map[string]HandlerFunc{"auction.ended": handled("ended"), "item.purchased": handled("purchased")}
+
+	tests := map[string]struct {
+		handlers map[string]HandlerFunc
+		key      string
+		want     string
+	}{
+		"exact key":                          {multiple, "item.purchased", "purchased"},
+		"prefixed key":                       {multiple, "staging.auction.ended", "ended"},
+		"any key of a single event type":     {single, "user.imported", "created"},
+		"unknown key of several event types": {multiple, "auction.cancelled", ""},
+		"partial segment is not a match":     {multiple, "preauction.ended", ""},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h, ok := handlerFor(tt.key, tt.handlers)
+			if tt.want == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.EqualError(t, h(context.Background(), amqp.Delivery{}), tt.want)
+		})
+	}
+}
+
+func TestUserConsumer_RejectsUnroutableDeliveries(t *testing.T) {
+	var logs bytes.Buffer
+	consumer := NewUserConsumer(nil, nil, slog.New(slog.NewJSONHandler(&logs, nil)))
+	consumer.Handle("user.updated", func(context.Context, amqp.Delivery) error {
+		return errors.New("database unavailable")
+	})
+
+	tests := map[string]struct {
+		delivery    amqp.Delivery
+		wantRequeue bool
+	}{
+		"unknown routing key": {
+			delivery: amqp.Delivery{RoutingKey: "user.deleted", Body: []byte("payload")},
+		},
+		"malformed payload": {
+			delivery: amqp.Delivery{RoutingKey: "user.created", Body: []byte("not a protobuf")},
+		},
+		"unsupported schema version": {
+			delivery: amqp.Delivery{
+				RoutingKey: "user.updated",
+				Headers:    amqp.Table{pkgevents.SchemaVersionHeader: "2"},
+			},
+		},
+		"handler failure is retried": {
+			delivery:    amqp.Delivery{RoutingKey: "user.updated"},
+			wantRequeue: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ch := &recordingAcknowledger{}
+			tt.delivery.Acknowledger = ch
+			tt.delivery.DeliveryTag = 1
+
+			consumer.handle(context.Background(), tt.delivery, newConsumerConfig(nil).newAcker())
+
+			assert.Empty(t, ch.acks)
+			assert.Equal(t, []nackCall{{1, tt.wantRequeue}}, ch.nacks)
+		})
+	}
+	assert.Contains(t, logs.String(), "user.deleted", "the unknown routing key is logged")
+}
+
+func TestBidAndAuctionConsumers_RejectUnknownRoutingKeys(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	consumers := map[string]interface {
+		handle(context.Context, amqp.Delivery, *acker)
+	}{
+		"bid":     NewBidConsumer(nil, nil, logger, WithRoutingKeys(pkgevents.RoutingKeyBidPlaced, "bid.retracted")),
+		"auction": NewAuctionConsumer(nil, nil, logger, WithRoutingKeys(pkgevents.RoutingKeyAuctionEnded, "auction.cancelled")),
+	}
+	for name, consumer := range consumers {
+		t.Run(name, func(t *testing.T) {
+			// A valid user created payload must not be misread as the consumer's own event
+			payload, err := proto.Marshal(&pb.UserCreated{UserId: uuid.NewString()})
+			require.NoError(t, err)
+			ch := &recordingAcknowledger{}
+
+			consumer.handle(context.Background(), amqp.Delivery{
+				Acknowledger: ch, DeliveryTag: 1, RoutingKey: pkgevents.RoutingKeyUserCreated, Body: payload,
+			}, newConsumerConfig(nil).newAcker())
+
+			assert.Empty(t, ch.acks)
+			assert.Equal(t, []nackCall{{1, false}}, ch.nacks)
+		})
+	}
+}
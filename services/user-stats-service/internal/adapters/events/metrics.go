@@ -0,0 +1,91 @@
+package events
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Outcomes of handling a delivery, used as the "outcome" metric label
+const (
+	OutcomeSuccess  = "success"  // processed and acked
+	OutcomeRetry    = "retry"    // requeued, or republished with a delay, for another attempt
+	OutcomeRejected = "rejected" // nacked straight to the dead-letter queue
+)
+
+// Metrics counts the deliveries consumers handle, labelled by routing key and outcome, and
+// times how long the events they apply took to arrive
+type Metrics struct {
+	received  *prometheus.CounterVec
+	processed *prometheus.CounterVec
+	nacked    *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	latency   *prometheus.HistogramVec
+}
+
+// NewMetrics creates consumer metrics and registers them on reg.
+// A nil reg leaves them unregistered, which is how consumers without WithMetrics count.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		received: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "user_stats",
+			Subsystem: "consumer",
+			Name:      "messages_received_total",
+			Help:      "Deliveries received from the broker.",
+		}, []string{"routing_key"}),
+		processed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "user_stats",
+			Subsystem: "consumer",
+			Name:      "messages_processed_total",
+			Help:      "Deliveries handled, by outcome.",
+		}, []string{"routing_key", "outcome"}),
+		nacked: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "user_stats",
+			Subsystem: "consumer",
+			Name:      "messages_nacked_total",
+			Help:      "Deliveries nacked, either requeued or dead-lettered.",
+		}, []string{"routing_key", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "user_stats",
+			Subsystem: "consumer",
+			Name:      "processing_duration_seconds",
+			Help:      "Time taken to handle a delivery, by outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"routing_key", "outcome"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "user_stats",
+			Name:      "event_latency_seconds",
+			Help:      "Time from an event being stamped by its producer to it being applied to the stats.",
+			// From 10ms up to ~5 minutes, since events wait in the outbox and the queue
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 15),
+		}, []string{"event_type"}),
+	}
+	if reg != nil {
+		reg.MustRegister(m.received, m.processed, m.nacked, m.duration, m.latency)
+	}
+	return m
+}
+
+// Received returns the counter of deliveries received under routingKey
+func (m *Metrics) Received(routingKey string) prometheus.Counter {
+	return m.received.WithLabelValues(routingKey)
+}
+
+// Processed returns the counter of deliveries under routingKey handled with outcome
+func (m *Metrics) Processed(routingKey, outcome string) prometheus.Counter {
+	return m.processed.WithLabelValues(routingKey, outcome)
+}
+
+// ObserveEventLatency implements userstats.LatencyRecorder
+func (m *Metrics) ObserveEventLatency(eventType string, latency time.Duration) {
+	m.latency.WithLabelValues(eventType).Observe(latency.Seconds())
+}
+
+// observe records a delivery under routingKey that took elapsed to reach outcome
+func (m *Metrics) observe(routingKey, outcome string, elapsed time.Duration) {
+	m.processed.WithLabelValues(routingKey, outcome).Inc()
+	if outcome != OutcomeSuccess {
+		m.nacked.WithLabelValues(routingKey, outcome).Inc()
+	}
+	m.duration.WithLabelValues(routingKey, outcome).Observe(elapsed.Seconds())
+}
@@ -0,0 +1,37 @@
+package events_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/services/user-stats-service/internal/adapters/events"
+	"github.com/floroz/gavel/services/user-stats-service/internal/domain/userstats"
+)
+
+func TestMetrics_ObserveEventLatency(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	var recorder userstats.LatencyRecorder = events.NewMetrics(reg)
+
+	recorder.ObserveEventLatency(userstats.EventTypeUserCreated, 2*time.Second)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	var found bool
+	for _, family := range families {
+		if family.GetName() != "user_stats_event_latency_seconds" {
+			continue
+		}
+		found = true
+		require.Len(t, family.GetMetric(), 1)
+		metric := family.GetMetric()[0]
+		assert.Equal(t, "event_type", metric.GetLabel()[0].GetName())
+		assert.Equal(t, userstats.EventTypeUserCreated, metric.GetLabel()[0].GetValue())
+		assert.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+		assert.Equal(t, 2.0, metric.GetHistogram().GetSampleSum())
+	}
+	assert.True(t, found, "latency histogram is registered")
+}
@@ -0,0 +1,106 @@
+package events
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgevents "github.com/floroz/gavel/pkg/events"
+)
+
+// binding is one queue bound to an exchange
+type binding struct {
+	queue, key, exchange string
+}
+
+// recordingTopology stands in for the channel consumers declare their topology on
+type recordingTopology struct {
+	bindings []binding
+}
+
+func (r *recordingTopology) ExchangeDeclare(string, string, bool, bool, bool, bool, amqp.Table) error {
+	return nil
+}
+
+func (r *recordingTopology) QueueDeclare(name string, _, _, _, _ bool, _ amqp.Table) (amqp.Queue, error) {
+	return amqp.Queue{Name: name}, nil
+}
+
+func (r *recordingTopology) QueueBind(name, key, exchange string, _ bool, _ amqp.Table) error {
+	r.bindings = append(r.bindings, binding{name, key, exchange})
+	return nil
+}
+
+func TestConsumers_BindPublishedRoutingKeys(t *testing.T) {
+	// The keys publishers send these events with, e.g. as outbox event types
+	tests := map[string]struct {
+		setup func(pkgevents.TopologyChannel) error
+		want  binding
+	}{
+		"bid consumer": {
+			setup: NewBidConsumer(nil, nil, nil).setupRabbitMQ,
+			want:  binding{bidsQueue, pkgevents.RoutingKeyBidPlaced, pkgevents.Exchange},
+		},
+		"user consumer": {
+			setup: NewUserConsumer(nil, nil, nil).setupRabbitMQ,
+			want:  binding{usersQueue, pkgevents.RoutingKeyUserCreated, pkgevents.Exchange},
+		},
+		"user consumer with overrides": {
+			setup: NewUserConsumer(nil, nil, nil,
+				WithQueue("staging_user_stats_users"), WithRoutingKeys("staging.user.created"), WithExchange("staging.events"),
+			).setupRabbitMQ,
+			want: binding{"staging_user_stats_users", "staging.user.created", "staging.events"},
+		},
+		"auction consumer": {
+			setup: NewAuctionConsumer(nil, nil, nil).setupRabbitMQ,
+			want:  binding{auctionsQueue, pkgevents.RoutingKeyAuctionEnded, pkgevents.Exchange},
+		},
+		"auction consumer buy now": {
+			setup: NewAuctionConsumer(nil, nil, nil).setupRabbitMQ,
+			want:  binding{auctionsQueue, pkgevents.RoutingKeyItemPurchased, pkgevents.Exchange},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ch := &recordingTopology{}
+			require.NoError(t, tt.setup(ch))
+
+			assert.Contains(t, ch.bindings, tt.want)
+		})
+	}
+}
+
+func TestTopology_RetiresReplacedQueues(t *testing.T) {
+	topology := Topology(DefaultMaxRetries)
+
+	var retired []string
+	for _, q := range topology.Retired {
+		retired = append(retired, q.Name)
+	}
+	assert.ElementsMatch(t, []string{"user_stats_bids", "user_stats_bids_v2", "user_stats_users"}, retired)
+	for _, q := range topology.Queues {
+		assert.NotContains(t, retired, q.Name, "a queue cannot replace itself")
+	}
+}
+
+func TestTopology_BidsQueueIsPriorityQueue(t *testing.T) {
+	for _, q := range Topology(DefaultMaxRetries).Queues {
+		if q.Name == bidsQueue {
+			assert.Equal(t, pkgevents.PriorityHigh, q.MaxPriority, "bids near an auction's close overtake a backlog")
+			assert.True(t, q.DelayedRetry, "the broker does not count retries on a priority queue")
+		} else {
+			assert.Zero(t, q.MaxPriority, q.Name)
+		}
+	}
+}
+
+func TestConsumers_BindEveryRoutingKey(t *testing.T) {
+	ch := &recordingTopology{}
+	consumer := NewUserConsumer(nil, nil, nil, WithRoutingKeys(pkgevents.RoutingKeyUserCreated, "user.updated"))
+	require.NoError(t, consumer.setupRabbitMQ(ch))
+
+	assert.Contains(t, ch.bindings, binding{usersQueue, pkgevents.RoutingKeyUserCreated, pkgevents.Exchange})
+	assert.Contains(t, ch.bindings, binding{usersQueue, "user.updated", pkgevents.Exchange})
+}
@@ -0,0 +1,719 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/rabbitmq"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/floroz/gavel/pkg/database"
+	pkgevents "github.com/floroz/gavel/pkg/events"
+	pb "github.com/floroz/gavel/pkg/proto"
+	"github.com/floroz/gavel/pkg/testhelpers"
+	infradb "github.com/floroz/gavel/services/user-stats-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/user-stats-service/internal/adapters/events"
+	"github.com/floroz/gavel/services/user-stats-service/internal/domain/userstats"
+)
+
+// consumerEnv is a broker and database wired up for consumer tests
+type consumerEnv struct {
+	amqpURL      string
+	httpURL      string
+	dbPool       *pgxpool.Pool
+	statsService *userstats.Service
+	publishCh    *amqp.Channel
+}
+
+func setupConsumerEnv(t *testing.T) *consumerEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	// 1. Start RabbitMQ Container
+	rabbitmqContainer, err := rabbitmq.Run(ctx,
+		"rabbitmq:3.12-management-alpine",
+		rabbitmq.WithAdminPassword("password"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if termErr := rabbitmqContainer.Terminate(ctx); termErr != nil {
+			t.Fatalf("failed to terminate container: %s", termErr)
+		}
+	})
+
+	amqpURL, err := rabbitmqContainer.AmqpURL(ctx)
+	require.NoError(t, err)
+	httpURL, err := rabbitmqContainer.HttpURL(ctx)
+	require.NoError(t, err)
+
+	// 2. Setup Postgres
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	t.Cleanup(testDB.Close)
+
+	// 3. Setup Dependencies
+	txManager := database.NewPostgresTransactionManager(testDB.Pool, time.Second)
+	statsRepo := infradb.NewUserStatsRepository(testDB.Pool)
+
+	// 4. Publisher side
+	publishConn, err := amqp.Dial(amqpURL)
+	require.NoError(t, err)
+	t.Cleanup(func() { publishConn.Close() })
+
+	publishCh, err := publishConn.Channel()
+	require.NoError(t, err)
+
+	return &consumerEnv{
+		amqpURL:      amqpURL,
+		httpURL:      httpURL,
+		dbPool:       testDB.Pool,
+		statsService: userstats.NewService(statsRepo, txManager),
+		publishCh:    publishCh,
+	}
+}
+
+// runConsumer starts the consumer in the background and returns a channel with Run's result
+func runConsumer(t *testing.T, consumer interface{ Run(context.Context) error }) (context.CancelFunc, <-chan error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- consumer.Run(ctx)
+	}()
+	t.Cleanup(cancel)
+
+	// Give the consumer time to declare and bind its queue
+	time.Sleep(1 * time.Second)
+	return cancel, errChan
+}
+
+func (env *consumerEnv) publishUserCreated(t *testing.T, userID uuid.UUID) {
+	t.Helper()
+	env.publishUserCreatedTo(t, "auction.events", "user.created", userID)
+}
+
+func (env *consumerEnv) publishUserCreatedTo(t *testing.T, exchange, routingKey string, userID uuid.UUID) {
+	t.Helper()
+	body, err := proto.Marshal(&pb.UserCreated{
+		UserId:      userID.String(),
+		Email:       userID.String() + "@example.com",
+		FullName:    "Test User",
+		CountryCode: "US",
+		CreatedAt:   timestamppb.Now(),
+	})
+	require.NoError(t, err)
+
+	err = env.publishCh.PublishWithContext(context.Background(), exchange, routingKey, false, false, amqp.Publishing{
+		ContentType: "application/x-protobuf",
+		Body:        body,
+	})
+	require.NoError(t, err)
+}
+
+func (env *consumerEnv) waitForStats(t *testing.T, userID uuid.UUID) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		var count int
+		scanErr := env.dbPool.QueryRow(context.Background(), "SELECT COUNT(*) FROM user_stats WHERE user_id = $1", userID).Scan(&count)
+		return scanErr == nil && count == 1
+	}, 10*time.Second, 100*time.Millisecond, "User stats should be created")
+}
+
+func TestUserConsumerReconnect(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	env := setupConsumerEnv(t)
+
+	// Setup Consumer with a dialer so it can recover the connection
+	consumerConn, err := amqp.Dial(env.amqpURL)
+	require.NoError(t, err)
+
+	consumer := events.NewUserConsumer(consumerConn, env.statsService, logger,
+		events.WithDialer(func() (*amqp.Connection, error) { return amqp.Dial(env.amqpURL) }),
+		events.WithReconnectBackoff(50*time.Millisecond, 500*time.Millisecond),
+	)
+	cancelConsumer, errChan := runConsumer(t, consumer)
+
+	// 1. Baseline: consumer is processing
+	first := uuid.New()
+	env.publishUserCreated(t, first)
+	env.waitForStats(t, first)
+
+	// 2. Cancel the consumer's subscription mid-consume by deleting its queue.
+	// The consumer must re-declare the queue and resume.
+	_, err = env.publishCh.QueueDelete("user_stats_users_v2", false, false, false)
+	require.NoError(t, err)
+	time.Sleep(1 * time.Second)
+
+	second := uuid.New()
+	env.publishUserCreated(t, second)
+	env.waitForStats(t, second)
+
+	// 3. Drop the consumer's connection entirely; it must re-dial and resume.
+	require.NoError(t, consumerConn.Close())
+	time.Sleep(1 * time.Second)
+
+	third := uuid.New()
+	env.publishUserCreated(t, third)
+	env.waitForStats(t, third)
+
+	// 4. Run only returns once the context is cancelled
+	select {
+	case err := <-errChan:
+		t.Fatalf("consumer stopped unexpectedly: %v", err)
+	default:
+	}
+
+	cancelConsumer()
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("consumer did not stop after context cancellation")
+	}
+}
+
+func TestUserConsumerPrefetch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	env := setupConsumerEnv(t)
+
+	conn, err := amqp.Dial(env.amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	consumer := events.NewUserConsumer(conn, env.statsService, logger, events.WithPrefetch(3))
+	runConsumer(t, consumer)
+
+	// 1. The broker reports the configured prefetch for our consumer
+	require.Eventually(t, func() bool {
+		req, err := http.NewRequest(http.MethodGet, env.httpURL+"/api/consumers", nil)
+		require.NoError(t, err)
+		req.SetBasicAuth("guest", "password")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return false
+		}
+		defer res.Body.Close()
+
+		var consumers []struct {
+			PrefetchCount int `json:"prefetch_count"`
+			Queue         struct {
+				Name string `json:"name"`
+			} `json:"queue"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&consumers); err != nil {
+			return false
+		}
+		for _, c := range consumers {
+			if c.Queue.Name == "user_stats_users_v2" {
+				return c.PrefetchCount == 3
+			}
+		}
+		return false
+	}, 15*time.Second, 500*time.Millisecond, "consumer should be registered with prefetch 3")
+
+	// 2. A backlog larger than the prefetch window is still fully processed.
+	// Missing acks would stall the consumer after 3 deliveries.
+	userIDs := make([]uuid.UUID, 10)
+	for i := range userIDs {
+		userIDs[i] = uuid.New()
+		env.publishUserCreated(t, userIDs[i])
+	}
+	for _, userID := range userIDs {
+		env.waitForStats(t, userID)
+	}
+
+	require.Eventually(t, func() bool {
+		q, err := env.publishCh.QueueDeclarePassive("user_stats_users_v2", true, false, false, false, nil)
+		return err == nil && q.Messages == 0
+	}, 5*time.Second, 100*time.Millisecond, "queue should be drained")
+}
+
+func TestUserConsumerDeadLetter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	env := setupConsumerEnv(t)
+
+	// Make processing always fail for one user. The sequence is not transactional,
+	// so it counts attempts even though each failed transaction rolls back.
+	poisonID := uuid.New()
+	_, err := env.dbPool.Exec(ctx, `
+		CREATE SEQUENCE poison_attempts;
+		CREATE FUNCTION fail_poison_user() RETURNS trigger AS $$
+		BEGIN
+			IF NEW.user_id = '`+poisonID.String()+`' THEN
+				PERFORM nextval('poison_attempts');
+				RAISE EXCEPTION 'poison user';
+			END IF;
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql;
+		CREATE TRIGGER fail_poison_user BEFORE INSERT ON user_stats
+			FOR EACH ROW EXECUTE FUNCTION fail_poison_user();
+	`)
+	require.NoError(t, err)
+
+	conn, err := amqp.Dial(env.amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	const maxRetries = 2
+	consumer := events.NewUserConsumer(conn, env.statsService, logger, events.WithMaxRetries(maxRetries))
+	runConsumer(t, consumer)
+
+	// getDeadLetter waits for the next message on the DLQ and returns the reason it was dead-lettered
+	getDeadLetter := func(t *testing.T) (amqp.Delivery, string) {
+		t.Helper()
+		var msg amqp.Delivery
+		require.Eventually(t, func() bool {
+			var ok bool
+			msg, ok, err = env.publishCh.Get("user_stats_users_v2.dlq", true)
+			return err == nil && ok
+		}, 10*time.Second, 100*time.Millisecond, "message should land in the DLQ")
+
+		deaths, ok := msg.Headers["x-death"].([]interface{})
+		require.True(t, ok, "dead-lettered message should carry x-death")
+		require.NotEmpty(t, deaths)
+		death, ok := deaths[0].(amqp.Table)
+		require.True(t, ok)
+		reason, _ := death["reason"].(string)
+		return msg, reason
+	}
+
+	t.Run("always failing message lands in the DLQ after retries", func(t *testing.T) {
+		env.publishUserCreated(t, poisonID)
+
+		msg, reason := getDeadLetter(t)
+		assert.Equal(t, "delivery_limit", reason)
+
+		var event pb.UserCreated
+		require.NoError(t, proto.Unmarshal(msg.Body, &event))
+		assert.Equal(t, poisonID.String(), event.UserId)
+
+		var attempts int64
+		require.NoError(t, env.dbPool.QueryRow(ctx, "SELECT last_value FROM poison_attempts").Scan(&attempts))
+		assert.Equal(t, int64(maxRetries+1), attempts, "initial delivery plus retries")
+	})
+
+	t.Run("malformed message is dead-lettered without retries", func(t *testing.T) {
+		err := env.publishCh.PublishWithContext(ctx, "auction.events", "user.created", false, false, amqp.Publishing{
+			ContentType: "application/x-protobuf",
+			Body:        []byte("not a protobuf message"),
+		})
+		require.NoError(t, err)
+
+		msg, reason := getDeadLetter(t)
+		assert.Equal(t, "rejected", reason)
+		assert.Equal(t, []byte("not a protobuf message"), msg.Body)
+	})
+
+	t.Run("message of an unsupported schema version is dead-lettered without retries", func(t *testing.T) {
+		futureID := uuid.New()
+		body, err := proto.Marshal(&pb.UserCreated{
+			UserId:      futureID.String(),
+			Email:       futureID.String() + "@example.com",
+			FullName:    "Future User",
+			CountryCode: "US",
+			CreatedAt:   timestamppb.Now(),
+		})
+		require.NoError(t, err)
+		err = env.publishCh.PublishWithContext(ctx, "auction.events", "user.created", false, false, amqp.Publishing{
+			ContentType: "application/x-protobuf",
+			Headers:     amqp.Table{pkgevents.SchemaVersionHeader: "2"},
+			Body:        body,
+		})
+		require.NoError(t, err)
+
+		msg, reason := getDeadLetter(t)
+		assert.Equal(t, "rejected", reason)
+		assert.Equal(t, "2", msg.Headers[pkgevents.SchemaVersionHeader])
+
+		var count int
+		require.NoError(t, env.dbPool.QueryRow(ctx, "SELECT COUNT(*) FROM user_stats WHERE user_id = $1", futureID).Scan(&count))
+		assert.Zero(t, count, "the event must not be applied")
+	})
+
+	t.Run("healthy messages keep flowing", func(t *testing.T) {
+		userID := uuid.New()
+		env.publishUserCreated(t, userID)
+		env.waitForStats(t, userID)
+	})
+}
+
+func TestUserConsumerMetrics(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	env := setupConsumerEnv(t)
+
+	conn, err := amqp.Dial(env.amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	reg := prometheus.NewRegistry()
+	metrics := events.NewMetrics(reg)
+	consumer := events.NewUserConsumer(conn, env.statsService, logger, events.WithMetrics(metrics))
+	runConsumer(t, consumer)
+
+	processed := metrics.Processed("user.created", events.OutcomeSuccess)
+	before := testutil.ToFloat64(processed)
+
+	userID := uuid.New()
+	env.publishUserCreated(t, userID)
+	env.waitForStats(t, userID)
+
+	// The delivery is acked, and counted, just after the stats are committed
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(processed) == before+1
+	}, 5*time.Second, 50*time.Millisecond, "processed counter should increment")
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.Received("user.created")))
+	assert.Zero(t, testutil.ToFloat64(metrics.Processed("user.created", events.OutcomeRetry)))
+
+	// The registry exposes them for scraping
+	count, err := testutil.GatherAndCount(reg, "user_stats_consumer_messages_processed_total", "user_stats_consumer_processing_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestUserConsumerDrainsOnShutdown(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	env := setupConsumerEnv(t)
+
+	// Slow down processing for one user, and signal when it has started. The sequence is
+	// not transactional, so the test sees it while the insert is still running.
+	slowID := uuid.New()
+	_, err := env.dbPool.Exec(ctx, `
+		CREATE SEQUENCE slow_started;
+		CREATE FUNCTION slow_user() RETURNS trigger AS $$
+		BEGIN
+			IF NEW.user_id = '`+slowID.String()+`' THEN
+				PERFORM nextval('slow_started');
+				PERFORM pg_sleep(0.5);
+			END IF;
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql;
+		CREATE TRIGGER slow_user BEFORE INSERT ON user_stats
+			FOR EACH ROW EXECUTE FUNCTION slow_user();
+	`)
+	require.NoError(t, err)
+
+	conn, err := amqp.Dial(env.amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	reg := prometheus.NewRegistry()
+	metrics := events.NewMetrics(reg)
+	consumer := events.NewUserConsumer(conn, env.statsService, logger, events.WithMetrics(metrics))
+	cancel, errChan := runConsumer(t, consumer)
+
+	env.publishUserCreated(t, slowID)
+	require.Eventually(t, func() bool {
+		var started bool
+		scanErr := env.dbPool.QueryRow(ctx, "SELECT is_called FROM slow_started").Scan(&started)
+		return scanErr == nil && started
+	}, 10*time.Second, 10*time.Millisecond, "processing should start")
+
+	// Stop while the message is mid-processing
+	cancel()
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	case <-time.After(events.DefaultDrainTimeout + 5*time.Second):
+		t.Fatal("Run should return once the in-flight message is finished")
+	}
+
+	// By the time Run returns the message was committed and acked, not requeued
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.Processed("user.created", events.OutcomeSuccess)))
+	assert.Zero(t, testutil.ToFloat64(metrics.Processed("user.created", events.OutcomeRetry)))
+
+	var count int
+	require.NoError(t, env.dbPool.QueryRow(ctx, "SELECT COUNT(*) FROM user_stats WHERE user_id = $1", slowID).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	queue, err := env.publishCh.QueueDeclarePassive("user_stats_users_v2", true, false, false, false, nil)
+	require.NoError(t, err)
+	assert.Zero(t, queue.Messages, "the in-flight message should not be redelivered")
+}
+
+func TestUserConsumerBatchAck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	env := setupConsumerEnv(t)
+
+	poisonID := uuid.New()
+	_, err := env.dbPool.Exec(ctx, `
+		CREATE FUNCTION fail_poison_user() RETURNS trigger AS $$
+		BEGIN
+			IF NEW.user_id = '`+poisonID.String()+`' THEN
+				RAISE EXCEPTION 'poison user';
+			END IF;
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql;
+		CREATE TRIGGER fail_poison_user BEFORE INSERT ON user_stats
+			FOR EACH ROW EXECUTE FUNCTION fail_poison_user();
+	`)
+	require.NoError(t, err)
+
+	conn, err := amqp.Dial(env.amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// A batch larger than the traffic, so only the interval acks it
+	consumer := events.NewUserConsumer(conn, env.statsService, logger,
+		events.WithBatchAck(5, 500*time.Millisecond),
+		events.WithMaxRetries(1),
+	)
+	runConsumer(t, consumer)
+
+	first, last := uuid.New(), uuid.New()
+	env.publishUserCreated(t, first)
+	env.publishUserCreated(t, poisonID)
+	env.publishUserCreated(t, last)
+	env.waitForStats(t, first)
+	env.waitForStats(t, last)
+
+	// The failed message was not swept up by the multiple ack: it ran out of retries instead
+	require.Eventually(t, func() bool {
+		msg, ok, getErr := env.publishCh.Get("user_stats_users_v2.dlq", true)
+		if getErr != nil || !ok {
+			return false
+		}
+		var event pb.UserCreated
+		return proto.Unmarshal(msg.Body, &event) == nil && event.UserId == poisonID.String()
+	}, 10*time.Second, 100*time.Millisecond, "failed message should be dead-lettered")
+
+	// The successful ones are acked by the interval flush while the consumer keeps running
+	require.Eventually(t, func() bool {
+		req, err := http.NewRequest(http.MethodGet, env.httpURL+"/api/queues/%2F/user_stats_users_v2", nil)
+		require.NoError(t, err)
+		req.SetBasicAuth("guest", "password")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return false
+		}
+		defer res.Body.Close()
+
+		var queue struct {
+			Messages       *int `json:"messages"`
+			Unacknowledged *int `json:"messages_unacknowledged"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&queue); err != nil || queue.Messages == nil || queue.Unacknowledged == nil {
+			return false
+		}
+		return *queue.Messages == 0 && *queue.Unacknowledged == 0
+	}, 20*time.Second, 500*time.Millisecond, "batched deliveries should be acked")
+}
+
+func TestUserConsumerIsolatedQueues(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	env := setupConsumerEnv(t)
+
+	conn, err := amqp.Dial(env.amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Two environments sharing the broker, each with its own queue and binding
+	const exchange = "isolated.events"
+	environments := map[string]*events.Metrics{"env_a": events.NewMetrics(nil), "env_b": events.NewMetrics(nil)}
+	for name, metrics := range environments {
+		consumer := events.NewUserConsumer(conn, env.statsService, logger,
+			events.WithQueue(name+"_user_stats_users"),
+			events.WithRoutingKeys(name+".user.created"),
+			events.WithExchange(exchange),
+			events.WithMetrics(metrics),
+		)
+		runConsumer(t, consumer)
+	}
+
+	for name := range environments {
+		userID := uuid.New()
+		env.publishUserCreatedTo(t, exchange, name+".user.created", userID)
+		env.waitForStats(t, userID)
+	}
+
+	// Each consumer only received what was published for its own environment
+	for name, metrics := range environments {
+		for other := range environments {
+			want := float64(0)
+			if other == name {
+				want = 1
+			}
+			assert.Equal(t, want, testutil.ToFloat64(metrics.Received(other+".user.created")), "%s received %s", name, other)
+		}
+	}
+}
+
+func TestUserConsumerMultipleRoutingKeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	env := setupConsumerEnv(t)
+
+	conn, err := amqp.Dial(env.amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	keys := []string{"user.created", "user.imported"}
+	metrics := events.NewMetrics(nil)
+	consumer := events.NewUserConsumer(conn, env.statsService, logger,
+		events.WithQueue("multi_key_user_stats_users"),
+		events.WithRoutingKeys(keys...),
+		events.WithMetrics(metrics),
+	)
+	runConsumer(t, consumer)
+
+	for _, key := range keys {
+		userID := uuid.New()
+		env.publishUserCreatedTo(t, "auction.events", key, userID)
+		env.waitForStats(t, userID)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.Received(key)), "delivery under %s", key)
+	}
+}
+
+func TestUserConsumerHandleOverridesRoutingKey(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	env := setupConsumerEnv(t)
+
+	conn, err := amqp.Dial(env.amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	consumer := events.NewUserConsumer(conn, env.statsService, logger,
+		events.WithQueue("custom_handler_user_stats_users"),
+		events.WithRoutingKeys("user.created", "user.imported"),
+	)
+	imported := make(chan amqp.Delivery, 1)
+	consumer.Handle("user.imported", func(_ context.Context, d amqp.Delivery) error {
+		imported <- d
+		return nil
+	})
+	runConsumer(t, consumer)
+
+	createdID := uuid.New()
+	env.publishUserCreatedTo(t, "auction.events", "user.created", createdID)
+	env.waitForStats(t, createdID)
+
+	env.publishUserCreatedTo(t, "auction.events", "user.imported", uuid.New())
+	select {
+	case d := <-imported:
+		assert.Equal(t, "user.imported", d.RoutingKey)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the user.imported delivery was not received")
+	}
+}
+
+func TestUserConsumerDelayedRetry(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	env := setupConsumerEnv(t)
+
+	conn, err := amqp.Dial(env.amqpURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	const (
+		queue = "retry_user_stats_users"
+		delay = 500 * time.Millisecond
+	)
+	consumer := events.NewUserConsumer(conn, env.statsService, logger,
+		events.WithQueue(queue),
+		events.WithRoutingKeys("user.flaky"),
+		events.WithRetryBackoff(delay, 5*time.Second),
+		events.WithMaxRetries(2),
+	)
+
+	// attempt is one delivery of the flaky event, which fails every time
+	type attempt struct {
+		at         time.Time
+		routingKey string
+		retry      any
+	}
+	attempts := make(chan attempt, 10)
+	consumer.Handle("user.flaky", func(_ context.Context, d amqp.Delivery) error {
+		attempts <- attempt{time.Now(), d.RoutingKey, d.Headers[events.RetryAttemptHeader]}
+		return errors.New("temporarily unavailable")
+	})
+	runConsumer(t, consumer)
+
+	err = env.publishCh.PublishWithContext(context.Background(), "auction.events", "user.flaky", false, false, amqp.Publishing{
+		Body: []byte("flaky"),
+	})
+	require.NoError(t, err)
+
+	next := func(t *testing.T) attempt {
+		t.Helper()
+		select {
+		case a := <-attempts:
+			return a
+		case <-time.After(10 * time.Second):
+			t.Fatal("the delivery was not retried")
+			return attempt{}
+		}
+	}
+	first, second, third := next(t), next(t), next(t)
+
+	assert.Nil(t, first.retry)
+	assert.Equal(t, int64(1), second.retry, "the attempt counter travels in the headers")
+	assert.Equal(t, int64(2), third.retry)
+	assert.Equal(t, "user.flaky", second.routingKey, "retries keep their routing key")
+
+	assert.GreaterOrEqual(t, second.at.Sub(first.at), delay, "the second attempt is delayed")
+	assert.GreaterOrEqual(t, third.at.Sub(second.at), 2*delay, "the delay grows")
+
+	// Out of retries, it is dead-lettered rather than retried again
+	require.Eventually(t, func() bool {
+		msg, ok, getErr := env.publishCh.Get(queue+".dlq", true)
+		return getErr == nil && ok && string(msg.Body) == "flaky"
+	}, 10*time.Second, 100*time.Millisecond, "message should land in the DLQ")
+	assert.Empty(t, attempts)
+}
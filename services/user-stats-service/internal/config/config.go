@@ -0,0 +1,129 @@
+// Package config loads the user stats service binaries' settings from the environment. Every
+// setting is read and checked up front, so a misconfigured deployment reports all of its
+// problems at once instead of failing on the first.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/floroz/gavel/pkg/auth"
+	"github.com/floroz/gavel/services/user-stats-service/internal/adapters/events"
+)
+
+// DefaultMetricsAddr is where the worker serves /metrics
+const DefaultMetricsAddr = ":9090"
+
+// ErrMissing is returned, listing the variables, when required settings are not set
+var ErrMissing = errors.New("missing required settings")
+
+// API is the configuration of the user stats API (cmd/api)
+type API struct {
+	DatabaseURL  string         // USER_STATS_DB_URL
+	ReplicaURLs  []string       // USER_STATS_DB_REPLICA_URLS, comma-separated; reads go to the primary without them
+	JWTPublicKey auth.KeySource // JWT_PUBLIC_KEY_PATH or JWT_PUBLIC_KEY (base64 PEM)
+	JWTIssuer    string         // JWT_ISSUER
+}
+
+// Worker is the configuration of the user stats consumers (cmd/worker)
+type Worker struct {
+	DatabaseURL string // USER_STATS_DB_URL
+	RabbitMQURL string // RABBITMQ_URL
+	// ProcessingTimeout bounds how long a single event may take to apply
+	ProcessingTimeout time.Duration // USER_STATS_PROCESSING_TIMEOUT
+	MetricsAddr       string        // USER_STATS_METRICS_ADDR
+}
+
+// LoadAPI reads the API's settings, falling back to defaults for optional ones
+func LoadAPI() (*API, error) {
+	var l loader
+	cfg := &API{
+		DatabaseURL:  l.required("USER_STATS_DB_URL"),
+		ReplicaURLs:  l.list("USER_STATS_DB_REPLICA_URLS"),
+		JWTPublicKey: l.key("JWT_PUBLIC_KEY"),
+		JWTIssuer:    l.required("JWT_ISSUER"),
+	}
+	if err := l.err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadWorker reads the worker's settings, falling back to defaults for optional ones
+func LoadWorker() (*Worker, error) {
+	var l loader
+	cfg := &Worker{
+		DatabaseURL:       l.required("USER_STATS_DB_URL"),
+		RabbitMQURL:       l.required("RABBITMQ_URL"),
+		ProcessingTimeout: l.positiveDuration("USER_STATS_PROCESSING_TIMEOUT", events.DefaultProcessingTimeout),
+		MetricsAddr:       l.optional("USER_STATS_METRICS_ADDR", DefaultMetricsAddr),
+	}
+	if err := l.err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loader collects every missing and invalid setting it is asked for
+type loader struct {
+	missing []string
+	invalid []error
+}
+
+func (l *loader) required(key string) string {
+	v := os.Getenv(key)
+	if v == "" {
+		l.missing = append(l.missing, key)
+	}
+	return v
+}
+
+func (l *loader) optional(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// key reads a required key from <name>_PATH or <name>, see auth.KeySourceFromEnv
+func (l *loader) key(name string) auth.KeySource {
+	source := auth.KeySourceFromEnv(name)
+	if !source.IsSet() {
+		l.missing = append(l.missing, name+"_PATH or "+name)
+	}
+	return source
+}
+
+// list splits a comma-separated setting, or returns nil if it is unset
+func (l *loader) list(key string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+func (l *loader) positiveDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		l.invalid = append(l.invalid, fmt.Errorf("invalid %s: %q", key, v))
+		return def
+	}
+	return d
+}
+
+// err reports everything missing as one ErrMissing, along with every invalid value
+func (l *loader) err() error {
+	var errs []error
+	if len(l.missing) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrMissing, strings.Join(l.missing, ", ")))
+	}
+	return errors.Join(append(errs, l.invalid...)...)
+}
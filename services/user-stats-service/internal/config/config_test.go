@@ -0,0 +1,93 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/auth"
+	"github.com/floroz/gavel/services/user-stats-service/internal/adapters/events"
+	"github.com/floroz/gavel/services/user-stats-service/internal/config"
+)
+
+// setEnv replaces every setting the loaders read with env, leaving the rest unset
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range []string{
+		"USER_STATS_DB_URL", "USER_STATS_DB_REPLICA_URLS", "RABBITMQ_URL", "JWT_PUBLIC_KEY_PATH", "JWT_PUBLIC_KEY", "JWT_ISSUER",
+		"USER_STATS_PROCESSING_TIMEOUT", "USER_STATS_METRICS_ADDR",
+	} {
+		t.Setenv(key, env[key])
+	}
+}
+
+func TestLoadAPI(t *testing.T) {
+	t.Run("complete environment", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"USER_STATS_DB_URL":          "postgres://localhost/user_stats_db",
+			"USER_STATS_DB_REPLICA_URLS": "postgres://replica1/user_stats_db,postgres://replica2/user_stats_db",
+			"JWT_PUBLIC_KEY":             "cHVibGljIGtleQ==",
+			"JWT_ISSUER":                 "gavel-auth",
+		})
+
+		cfg, err := config.LoadAPI()
+		require.NoError(t, err)
+		assert.Equal(t, &config.API{
+			DatabaseURL:  "postgres://localhost/user_stats_db",
+			ReplicaURLs:  []string{"postgres://replica1/user_stats_db", "postgres://replica2/user_stats_db"},
+			JWTPublicKey: auth.KeySource{Base64: "cHVibGljIGtleQ=="},
+			JWTIssuer:    "gavel-auth",
+		}, cfg)
+	})
+
+	t.Run("reports every missing key at once", func(t *testing.T) {
+		setEnv(t, nil)
+
+		_, err := config.LoadAPI()
+		require.ErrorIs(t, err, config.ErrMissing)
+		assert.Contains(t, err.Error(), "missing required settings: USER_STATS_DB_URL, JWT_PUBLIC_KEY_PATH or JWT_PUBLIC_KEY, JWT_ISSUER")
+	})
+}
+
+func TestLoadWorker(t *testing.T) {
+	t.Run("complete environment", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"USER_STATS_DB_URL":             "postgres://localhost/user_stats_db",
+			"RABBITMQ_URL":                  "amqp://localhost:5672/",
+			"USER_STATS_PROCESSING_TIMEOUT": "10s",
+			"USER_STATS_METRICS_ADDR":       ":9191",
+		})
+
+		cfg, err := config.LoadWorker()
+		require.NoError(t, err)
+		assert.Equal(t, &config.Worker{
+			DatabaseURL:       "postgres://localhost/user_stats_db",
+			RabbitMQURL:       "amqp://localhost:5672/",
+			ProcessingTimeout: 10 * time.Second,
+			MetricsAddr:       ":9191",
+		}, cfg)
+	})
+
+	t.Run("optional settings default", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"USER_STATS_DB_URL": "postgres://localhost/user_stats_db",
+			"RABBITMQ_URL":      "amqp://localhost:5672/",
+		})
+
+		cfg, err := config.LoadWorker()
+		require.NoError(t, err)
+		assert.Equal(t, events.DefaultProcessingTimeout, cfg.ProcessingTimeout)
+		assert.Equal(t, config.DefaultMetricsAddr, cfg.MetricsAddr)
+	})
+
+	t.Run("reports every problem at once", func(t *testing.T) {
+		setEnv(t, map[string]string{"USER_STATS_PROCESSING_TIMEOUT": "-5s"})
+
+		_, err := config.LoadWorker()
+		require.ErrorIs(t, err, config.ErrMissing)
+		assert.Contains(t, err.Error(), "missing required settings: USER_STATS_DB_URL, RABBITMQ_URL")
+		assert.Contains(t, err.Error(), `invalid USER_STATS_PROCESSING_TIMEOUT: "-5s"`)
+	})
+}
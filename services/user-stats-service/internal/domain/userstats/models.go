@@ -7,12 +7,23 @@ import (
 )
 
 type UserStats struct {
-	UserID          uuid.UUID
-	TotalBidsPlaced int64
-	TotalAmountBid  int64
-	LastBidAt       time.Time
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	UserID           uuid.UUID
+	Email            string
+	CountryCode      string
+	TotalBidsPlaced  int64
+	TotalAmountBid   int64
+	LastBidAt        time.Time
+	TotalAuctionsWon int64
+	TotalSpent       int64
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// LeaderboardEntry is a user's stats along with their position on the leaderboard.
+// Users with the same total amount share a rank.
+type LeaderboardEntry struct {
+	Rank int64
+	UserStats
 }
 
 type ProcessedEvent struct {
@@ -29,6 +40,16 @@ type BidPlacedEvent struct {
 	Timestamp time.Time
 }
 
+// AuctionEndedEvent represents the domain event for a closed auction.
+// WinnerID is uuid.Nil when the auction ended unsold.
+type AuctionEndedEvent struct {
+	EventID  uuid.UUID
+	ItemID   uuid.UUID
+	WinnerID uuid.UUID
+	Amount   int64
+	EndedAt  time.Time
+}
+
 // UserCreatedEvent represents the domain event for a new user
 type UserCreatedEvent struct {
 	EventID     uuid.UUID
@@ -6,21 +6,53 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+
+	"github.com/floroz/gavel/pkg/events"
+)
+
+// Event types, as labelled by a LatencyRecorder
+const (
+	EventTypeBidPlaced    = events.RoutingKeyBidPlaced
+	EventTypeUserCreated  = events.RoutingKeyUserCreated
+	EventTypeAuctionEnded = events.RoutingKeyAuctionEnded
 )
 
+// LatencyRecorder observes how long events took from being stamped by their producer to
+// being applied to the stats
+type LatencyRecorder interface {
+	ObserveEventLatency(eventType string, latency time.Duration)
+}
+
 type Repository interface {
 	// IncrementUserStats increments the bid count and total amount for a user (Upsert)
 	IncrementUserStats(ctx context.Context, tx pgx.Tx, userID uuid.UUID, amount int64, lastBidAt time.Time) error
 
-	// CreateUserStats initializes stats for a new user (Idempotent)
-	CreateUserStats(ctx context.Context, tx pgx.Tx, userID uuid.UUID, createdAt time.Time) error
+	// RecordAuctionWon counts a won auction and its price towards the user's stats (Upsert)
+	RecordAuctionWon(ctx context.Context, tx pgx.Tx, userID uuid.UUID, amount int64) error
+	// CreateUserStats initializes stats for a new user, or fills in the email and country of
+	// stats already created by their bids (Idempotent)
+	CreateUserStats(ctx context.Context, tx pgx.Tx, stats *UserStats) error
 
-	// GetUserStats retrieves stats for a user
+	// BackfillUserStats writes precomputed stats for many users in one round trip, replacing
+	// their totals. last_bid_at only moves forward.
+	BackfillUserStats(ctx context.Context, tx pgx.Tx, stats []*UserStats) error
+
+	// AddUserStats adds each entry's bid count and amount to the user's stats in one round
+	// trip, creating missing rows. last_bid_at only moves forward.
+	AddUserStats(ctx context.Context, tx pgx.Tx, deltas []*UserStats) error
+
+	// GetUserStats retrieves stats for a user, or returns ErrUserStatsNotFound
 	GetUserStats(ctx context.Context, userID uuid.UUID) (*UserStats, error)
 
+	// GetLeaderboard retrieves users ordered by total amount bid, highest first, with pagination
+	GetLeaderboard(ctx context.Context, limit, offset int) ([]*LeaderboardEntry, error)
+
 	// MarkEventProcessed marks an event as processed to prevent duplicates
 	MarkEventProcessed(ctx context.Context, tx pgx.Tx, eventID uuid.UUID) error
 
+	// MarkEventsProcessed marks many events as processed and returns the ones that were not already
+	MarkEventsProcessed(ctx context.Context, tx pgx.Tx, eventIDs []uuid.UUID) ([]uuid.UUID, error)
+
 	// IsEventProcessed checks if an event has already been processed
 	IsEventProcessed(ctx context.Context, tx pgx.Tx, eventID uuid.UUID) (bool, error)
 }
@@ -1,102 +1,328 @@
 package userstats
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/floroz/gavel/pkg/database"
 )
 
+// Leaderboard pagination bounds
+const (
+	DefaultLeaderboardLimit = 20
+	MaxLeaderboardLimit     = 100
+)
+
+// BackfillBatchSize is how many users BackfillUserStats writes per batch
+const BackfillBatchSize = 500
+
+// ErrUserStatsNotFound is returned when no stats are recorded for a user
+var ErrUserStatsNotFound = fmt.Errorf("user stats not found")
+
+// Backfill validation errors
+var (
+	ErrInvalidStats = fmt.Errorf("invalid user stats")
+	ErrInvalidEvent = fmt.Errorf("invalid bid placed event")
+)
+
+// ErrInvalidUserEvent is returned when a UserCreated event cannot be normalized, e.g. its
+// country code is not two letters. Redelivering it will not help.
+var ErrInvalidUserEvent = fmt.Errorf("invalid user created event")
+
 type Service struct {
 	repo      Repository
 	txManager database.TransactionManager
+	latency   LatencyRecorder
+	now       func() time.Time
 }
 
-func NewService(repo Repository, txManager database.TransactionManager) *Service {
-	return &Service{
+// Option configures optional Service behaviour
+type Option func(*Service)
+
+// WithLatencyRecorder records the end-to-end latency of every event the Service applies.
+// Redelivered events, and events without a timestamp, are not recorded.
+func WithLatencyRecorder(recorder LatencyRecorder) Option {
+	return func(s *Service) {
+		s.latency = recorder
+	}
+}
+
+// WithClock overrides time.Now, which end-to-end latency is measured against
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
+
+func NewService(repo Repository, txManager database.TransactionManager, opts ...Option) *Service {
+	s := &Service{
 		repo:      repo,
 		txManager: txManager,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-func (s *Service) ProcessBidPlaced(ctx context.Context, event BidPlacedEvent) error {
-	// 1. Start Transaction
-	tx, err := s.txManager.BeginTx(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+// observeLatency records the time since an event of eventType was stamped at stampedAt
+func (s *Service) observeLatency(eventType string, stampedAt time.Time) {
+	if s.latency == nil || stampedAt.IsZero() {
+		return
 	}
-	defer func() {
-		_ = tx.Rollback(ctx)
-	}()
+	// Producer clocks can run ahead of ours
+	s.latency.ObserveEventLatency(eventType, max(s.now().Sub(stampedAt), 0))
+}
 
-	// 2. Check Idempotency (Has this event been processed?)
-	isProcessed, err := s.repo.IsEventProcessed(ctx, tx, event.EventID)
-	if err != nil {
-		return fmt.Errorf("failed to check idempotency: %w", err)
+func (s *Service) ProcessBidPlaced(ctx context.Context, event BidPlacedEvent) error {
+	applied := false
+	err := s.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		// 1. Check Idempotency (Has this event been processed?)
+		isProcessed, err := s.repo.IsEventProcessed(ctx, tx, event.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to check idempotency: %w", err)
+		}
+		if isProcessed {
+			// Already processed, acknowledge and return (Idempotent Success)
+			return nil
+		}
+
+		// 2. Update User Stats (Increment/Upsert)
+		// We no longer construct a struct with "1". We explicitly call Increment.
+		if err := s.repo.IncrementUserStats(ctx, tx, event.UserID, event.Amount, event.Timestamp); err != nil {
+			return fmt.Errorf("failed to increment user stats: %w", err)
+		}
+
+		// 3. Mark Event as Processed
+		if err := s.repo.MarkEventProcessed(ctx, tx, event.EventID); err != nil {
+			return fmt.Errorf("failed to mark event as processed: %w", err)
+		}
+
+		applied = true
+		return nil
+	})
+	if err == nil && applied {
+		s.observeLatency(EventTypeBidPlaced, event.Timestamp)
 	}
-	if isProcessed {
-		// Already processed, acknowledge and return (Idempotent Success)
+	return err
+}
+
+// ProcessAuctionEnded attributes the winning bid of a closed auction to its winner. Unsold
+// auctions have no winner and change nothing. The event ID is recorded in the same transaction,
+// so a redelivered event is not counted twice.
+func (s *Service) ProcessAuctionEnded(ctx context.Context, event AuctionEndedEvent) error {
+	if event.WinnerID == uuid.Nil {
 		return nil
 	}
 
-	// 3. Update User Stats (Increment/Upsert)
-	// We no longer construct a struct with "1". We explicitly call Increment.
-	if err := s.repo.IncrementUserStats(ctx, tx, event.UserID, event.Amount, event.Timestamp); err != nil {
-		return fmt.Errorf("failed to increment user stats: %w", err)
+	applied := false
+	err := s.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		// 1. Check Idempotency
+		isProcessed, err := s.repo.IsEventProcessed(ctx, tx, event.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to check idempotency: %w", err)
+		}
+		if isProcessed {
+			return nil
+		}
+
+		// 2. Credit the Winner
+		if err := s.repo.RecordAuctionWon(ctx, tx, event.WinnerID, event.Amount); err != nil {
+			return fmt.Errorf("failed to record auction won: %w", err)
+		}
+
+		// 3. Mark Event as Processed
+		if err := s.repo.MarkEventProcessed(ctx, tx, event.EventID); err != nil {
+			return fmt.Errorf("failed to mark event as processed: %w", err)
+		}
+
+		applied = true
+		return nil
+	})
+	if err == nil && applied {
+		s.observeLatency(EventTypeAuctionEnded, event.EndedAt)
 	}
+	return err
+}
 
-	// 4. Mark Event as Processed
-	if err := s.repo.MarkEventProcessed(ctx, tx, event.EventID); err != nil {
-		return fmt.Errorf("failed to mark event as processed: %w", err)
+// ProcessUserCreated initializes stats for a new user. The event ID is recorded in the same
+// transaction as the stats write, so redelivered events are acknowledged without side effects.
+// The email is stored lowercase and the country code uppercase, so stats group consistently.
+func (s *Service) ProcessUserCreated(ctx context.Context, event UserCreatedEvent) error {
+	countryCode, err := normalizeCountryCode(event.CountryCode)
+	if err != nil {
+		return err
+	}
+	stats := &UserStats{
+		UserID:      event.UserID,
+		Email:       strings.ToLower(strings.TrimSpace(event.Email)),
+		CountryCode: countryCode,
+		CreatedAt:   event.CreatedAt,
 	}
 
-	// 5. Commit
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	applied := false
+	err = s.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		// 1. Check Idempotency
+		isProcessed, err := s.repo.IsEventProcessed(ctx, tx, event.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to check idempotency: %w", err)
+		}
+		if isProcessed {
+			return nil
+		}
+
+		// 2. Create User Stats
+		if err := s.repo.CreateUserStats(ctx, tx, stats); err != nil {
+			return fmt.Errorf("failed to create user stats: %w", err)
+		}
+
+		// 3. Mark Event as Processed
+		if err := s.repo.MarkEventProcessed(ctx, tx, event.EventID); err != nil {
+			return fmt.Errorf("failed to mark event as processed: %w", err)
+		}
+
+		applied = true
+		return nil
+	})
+	if err == nil && applied {
+		s.observeLatency(EventTypeUserCreated, event.CreatedAt)
 	}
+	return err
+}
 
-	return nil
+// normalizeCountryCode uppercases an ISO 3166-1 alpha-2 code. The country is optional, so an
+// empty code is kept empty.
+func normalizeCountryCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return "", nil
+	}
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return "", fmt.Errorf("%w: country code %q is not 2 letters", ErrInvalidUserEvent, code)
+	}
+	return code, nil
 }
 
-func (s *Service) ProcessUserCreated(ctx context.Context, event UserCreatedEvent) error {
-	// 1. Start Transaction
-	tx, err := s.txManager.BeginTx(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+// BackfillUserStats writes precomputed stats, e.g. rebuilt from bid history, replacing the
+// stored bid totals; auctions won and total spent are left as they are. Rows are sent in batches of BackfillBatchSize, all in one transaction, so
+// either every user is backfilled or none is.
+func (s *Service) BackfillUserStats(ctx context.Context, stats []*UserStats) error {
+	for _, st := range stats {
+		if st == nil || st.UserID == uuid.Nil || st.TotalBidsPlaced < 0 || st.TotalAmountBid < 0 {
+			return ErrInvalidStats
+		}
+	}
+	if len(stats) == 0 {
+		return nil
 	}
-	defer func() {
-		_ = tx.Rollback(ctx)
-	}()
 
-	// 2. Check Idempotency
-	isProcessed, err := s.repo.IsEventProcessed(ctx, tx, event.EventID)
-	if err != nil {
-		return fmt.Errorf("failed to check idempotency: %w", err)
+	return s.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		for chunk := range slices.Chunk(stats, BackfillBatchSize) {
+			if err := s.repo.BackfillUserStats(ctx, tx, chunk); err != nil {
+				return fmt.Errorf("failed to backfill user stats: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// BackfillStats replays BidPlaced events, e.g. the whole bid history, much faster than the
+// consumer would. In one transaction it records the event IDs in processed_events, sums the
+// events not seen before per user, and adds the sums to each user's stats in batches of
+// BackfillBatchSize. Events already processed, by the consumer or an earlier backfill, and
+// duplicates within events are skipped, so a backfill can safely be re-run or overlap live traffic.
+func (s *Service) BackfillStats(ctx context.Context, events []BidPlacedEvent) error {
+	for _, event := range events {
+		if event.EventID == uuid.Nil || event.UserID == uuid.Nil || event.Amount < 0 {
+			return ErrInvalidEvent
+		}
 	}
-	if isProcessed {
+	if len(events) == 0 {
 		return nil
 	}
 
-	// 3. Create User Stats
-	if err := s.repo.CreateUserStats(ctx, tx, event.UserID, event.CreatedAt); err != nil {
-		return fmt.Errorf("failed to create user stats: %w", err)
+	eventIDs := make([]uuid.UUID, len(events))
+	for i, event := range events {
+		eventIDs[i] = event.EventID
 	}
 
-	// 4. Mark Event as Processed
-	if err := s.repo.MarkEventProcessed(ctx, tx, event.EventID); err != nil {
-		return fmt.Errorf("failed to mark event as processed: %w", err)
+	return s.txManager.WithTx(ctx, func(tx pgx.Tx) error {
+		marked, err := s.repo.MarkEventsProcessed(ctx, tx, eventIDs)
+		if err != nil {
+			return fmt.Errorf("failed to mark events as processed: %w", err)
+		}
+
+		deltas := aggregateBids(events, marked)
+		for chunk := range slices.Chunk(deltas, BackfillBatchSize) {
+			if err := s.repo.AddUserStats(ctx, tx, chunk); err != nil {
+				return fmt.Errorf("failed to add user stats: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// aggregateBids sums the events whose IDs are in include per user, once per event ID.
+// Users are ordered by ID so concurrent backfills lock their rows in the same order.
+func aggregateBids(events []BidPlacedEvent, include []uuid.UUID) []*UserStats {
+	pending := make(map[uuid.UUID]bool, len(include))
+	for _, id := range include {
+		pending[id] = true
 	}
 
-	// 5. Commit
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	byUser := make(map[uuid.UUID]*UserStats)
+	for _, event := range events {
+		if !pending[event.EventID] {
+			continue
+		}
+		delete(pending, event.EventID)
+
+		stats, ok := byUser[event.UserID]
+		if !ok {
+			stats = &UserStats{UserID: event.UserID}
+			byUser[event.UserID] = stats
+		}
+		stats.TotalBidsPlaced++
+		stats.TotalAmountBid += event.Amount
+		if event.Timestamp.After(stats.LastBidAt) {
+			stats.LastBidAt = event.Timestamp
+		}
 	}
 
-	return nil
+	deltas := slices.Collect(maps.Values(byUser))
+	slices.SortFunc(deltas, func(a, b *UserStats) int {
+		return bytes.Compare(a.UserID[:], b.UserID[:])
+	})
+	return deltas
 }
 
+// GetUserStats returns a user's stats, or ErrUserStatsNotFound if none are recorded. Users are
+// recorded when they are created, so one who has not bid yet gets zero totals and a zero LastBidAt.
 func (s *Service) GetUserStats(ctx context.Context, userID uuid.UUID) (*UserStats, error) {
 	return s.repo.GetUserStats(ctx, userID)
 }
+
+// GetLeaderboard returns the top bidders by total amount bid. A non-positive limit falls back
+// to DefaultLeaderboardLimit and larger pages are capped at MaxLeaderboardLimit.
+func (s *Service) GetLeaderboard(ctx context.Context, limit, offset int) ([]*LeaderboardEntry, error) {
+	if limit <= 0 {
+		limit = DefaultLeaderboardLimit
+	}
+	limit = min(limit, MaxLeaderboardLimit)
+	offset = max(offset, 0)
+
+	entries, err := s.repo.GetLeaderboard(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,520 @@
+package userstats_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/floroz/gavel/pkg/database"
+	"github.com/floroz/gavel/pkg/testhelpers"
+	infradb "github.com/floroz/gavel/services/user-stats-service/internal/adapters/database"
+	"github.com/floroz/gavel/services/user-stats-service/internal/domain/userstats"
+)
+
+func TestService_ProcessUserCreated_Idempotent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+
+	txManager := database.NewPostgresTransactionManager(testDB.Pool, time.Second)
+	service := userstats.NewService(infradb.NewUserStatsRepository(testDB.Pool), txManager)
+
+	userID := uuid.New()
+	event := userstats.UserCreatedEvent{
+		EventID:     uuid.New(),
+		UserID:      userID,
+		Email:       "redelivered@example.com",
+		FullName:    "Redelivered User",
+		CountryCode: "US",
+		CreatedAt:   time.Now(),
+	}
+
+	// Simulate a redelivery after a lost ack
+	require.NoError(t, service.ProcessUserCreated(ctx, event))
+	require.NoError(t, service.ProcessUserCreated(ctx, event))
+
+	var statsRows int
+	err := testDB.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM user_stats WHERE user_id = $1", userID).Scan(&statsRows)
+	require.NoError(t, err)
+	assert.Equal(t, 1, statsRows)
+
+	var processedRows int
+	err = testDB.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM processed_events WHERE event_id = $1", event.EventID).Scan(&processedRows)
+	require.NoError(t, err)
+	assert.Equal(t, 1, processedRows, "event should be recorded exactly once")
+}
+
+func TestService_ProcessUserCreated_Normalizes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+
+	txManager := database.NewPostgresTransactionManager(testDB.Pool, time.Second)
+	repo := infradb.NewUserStatsRepository(testDB.Pool)
+	service := userstats.NewService(repo, txManager)
+
+	t.Run("Mixed case email and country", func(t *testing.T) {
+		userID := uuid.New()
+		require.NoError(t, service.ProcessUserCreated(ctx, userstats.UserCreatedEvent{
+			EventID:     userID,
+			UserID:      userID,
+			Email:       "  Jane.Doe@Example.COM ",
+			CountryCode: "gb ",
+			CreatedAt:   time.Now(),
+		}))
+
+		stats, err := repo.GetUserStats(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, "jane.doe@example.com", stats.Email)
+		assert.Equal(t, "GB", stats.CountryCode)
+	})
+
+	t.Run("Stats created by an earlier bid keep their totals", func(t *testing.T) {
+		userID := uuid.New()
+		require.NoError(t, service.ProcessBidPlaced(ctx, userstats.BidPlacedEvent{
+			EventID: uuid.New(), UserID: userID, Amount: 700, Timestamp: time.Now(),
+		}))
+		require.NoError(t, service.ProcessUserCreated(ctx, userstats.UserCreatedEvent{
+			EventID: userID, UserID: userID, Email: "Late@Example.com", CountryCode: "De", CreatedAt: time.Now(),
+		}))
+
+		stats, err := repo.GetUserStats(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, "late@example.com", stats.Email)
+		assert.Equal(t, "DE", stats.CountryCode)
+		assert.Equal(t, int64(1), stats.TotalBidsPlaced)
+		assert.Equal(t, int64(700), stats.TotalAmountBid)
+	})
+}
+
+// latencyRecorder keeps every latency observed, by event type
+type latencyRecorder map[string][]time.Duration
+
+func (r latencyRecorder) ObserveEventLatency(eventType string, latency time.Duration) {
+	r[eventType] = append(r[eventType], latency)
+}
+
+func TestService_ProcessUserCreated_RecordsLatency(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	recorder := latencyRecorder{}
+	service := userstats.NewService(
+		infradb.NewUserStatsRepository(testDB.Pool),
+		database.NewPostgresTransactionManager(testDB.Pool, time.Second),
+		userstats.WithLatencyRecorder(recorder),
+		userstats.WithClock(func() time.Time { return createdAt.Add(1500 * time.Millisecond) }),
+	)
+
+	userID := uuid.New()
+	event := userstats.UserCreatedEvent{EventID: userID, UserID: userID, Email: "latency@example.com", CreatedAt: createdAt}
+	require.NoError(t, service.ProcessUserCreated(ctx, event))
+	// A redelivery changes nothing, so it is not observed again
+	require.NoError(t, service.ProcessUserCreated(ctx, event))
+
+	assert.Equal(t, []time.Duration{1500 * time.Millisecond}, recorder[userstats.EventTypeUserCreated])
+}
+
+func TestService_ProcessUserCreated_InvalidCountryCode(t *testing.T) {
+	// Rejected before any database work, so no repository is needed
+	service := userstats.NewService(nil, nil)
+
+	for _, code := range []string{"USA", "U1", "u"} {
+		t.Run(code, func(t *testing.T) {
+			err := service.ProcessUserCreated(context.Background(), userstats.UserCreatedEvent{
+				EventID: uuid.New(), UserID: uuid.New(), CountryCode: code,
+			})
+			assert.ErrorIs(t, err, userstats.ErrInvalidUserEvent)
+		})
+	}
+}
+
+func TestService_GetLeaderboard(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+
+	txManager := database.NewPostgresTransactionManager(testDB.Pool, time.Second)
+	service := userstats.NewService(infradb.NewUserStatsRepository(testDB.Pool), txManager)
+
+	// Seed users with distinct totals, one tie, and one user who never bid
+	amounts := []int64{500, 3000, 1200, 3000, 800}
+	userIDs := make([]uuid.UUID, len(amounts))
+	for i, amount := range amounts {
+		userIDs[i] = uuid.New()
+		_, err := testDB.Pool.Exec(ctx, `
+			INSERT INTO user_stats (user_id, total_bids_placed, total_amount_bid, last_bid_at)
+			VALUES ($1, $2, $3, NOW())
+		`, userIDs[i], i+1, amount)
+		require.NoError(t, err)
+	}
+	_, err := testDB.Pool.Exec(ctx, `INSERT INTO user_stats (user_id) VALUES ($1)`, uuid.New())
+	require.NoError(t, err)
+
+	t.Run("orders by total amount bid", func(t *testing.T) {
+		entries, err := service.GetLeaderboard(ctx, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, entries, len(amounts), "users without bids are excluded")
+
+		gotAmounts := make([]int64, len(entries))
+		gotRanks := make([]int64, len(entries))
+		for i, e := range entries {
+			gotAmounts[i] = e.TotalAmountBid
+			gotRanks[i] = e.Rank
+		}
+		assert.Equal(t, []int64{3000, 3000, 1200, 800, 500}, gotAmounts)
+		assert.Equal(t, []int64{1, 1, 3, 4, 5}, gotRanks, "tied users share a rank")
+		assert.Equal(t, userIDs[2], entries[2].UserID)
+	})
+
+	t.Run("paginates with global ranks", func(t *testing.T) {
+		first, err := service.GetLeaderboard(ctx, 2, 0)
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+
+		second, err := service.GetLeaderboard(ctx, 2, 2)
+		require.NoError(t, err)
+		require.Len(t, second, 2)
+		assert.Equal(t, int64(3), second[0].Rank)
+		assert.Equal(t, int64(1200), second[0].TotalAmountBid)
+		assert.Equal(t, int64(4), second[1].Rank)
+
+		last, err := service.GetLeaderboard(ctx, 2, 4)
+		require.NoError(t, err)
+		require.Len(t, last, 1)
+		assert.Equal(t, int64(500), last[0].TotalAmountBid)
+
+		// Pages never overlap, including across the tie
+		assert.NotEqual(t, first[1].UserID, second[0].UserID)
+		assert.ElementsMatch(t, []uuid.UUID{userIDs[1], userIDs[3]}, []uuid.UUID{first[0].UserID, first[1].UserID})
+	})
+
+	t.Run("offset past the end returns no entries", func(t *testing.T) {
+		entries, err := service.GetLeaderboard(ctx, 10, len(amounts))
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("non-positive limit falls back to the default", func(t *testing.T) {
+		entries, err := service.GetLeaderboard(ctx, 0, -5)
+		require.NoError(t, err)
+		assert.Len(t, entries, len(amounts))
+	})
+}
+
+func TestService_ProcessBidPlaced_LastBidAtIsMonotonic(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+
+	txManager := database.NewPostgresTransactionManager(testDB.Pool, time.Second)
+	service := userstats.NewService(infradb.NewUserStatsRepository(testDB.Pool), txManager)
+
+	userID := uuid.New()
+	newer := time.Now().UTC().Truncate(time.Microsecond)
+	older := newer.Add(-time.Hour)
+
+	// The newer bid arrives first, then an older one is delivered late
+	require.NoError(t, service.ProcessBidPlaced(ctx, userstats.BidPlacedEvent{
+		EventID: uuid.New(), UserID: userID, Amount: 200, Timestamp: newer,
+	}))
+	require.NoError(t, service.ProcessBidPlaced(ctx, userstats.BidPlacedEvent{
+		EventID: uuid.New(), UserID: userID, Amount: 100, Timestamp: older,
+	}))
+
+	stats, err := service.GetUserStats(ctx, userID)
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.True(t, stats.LastBidAt.Equal(newer), "LastBidAt should stay at %v, got %v", newer, stats.LastBidAt)
+	assert.Equal(t, int64(2), stats.TotalBidsPlaced, "the late bid still counts")
+	assert.Equal(t, int64(300), stats.TotalAmountBid)
+}
+
+func TestService_BackfillUserStats(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+
+	txManager := database.NewPostgresTransactionManager(testDB.Pool, time.Second)
+	repo := infradb.NewUserStatsRepository(testDB.Pool)
+	service := userstats.NewService(repo, txManager)
+
+	// An existing user whose live stats saw a later bid than the backfill knows about
+	existing := uuid.New()
+	liveBidAt := time.Now().Truncate(time.Microsecond)
+	require.NoError(t, service.ProcessBidPlaced(ctx, userstats.BidPlacedEvent{
+		EventID:   uuid.New(),
+		UserID:    existing,
+		Amount:    999,
+		Timestamp: liveBidAt,
+	}))
+
+	base := liveBidAt.Add(-time.Hour)
+	stats := []*userstats.UserStats{
+		{UserID: existing, TotalBidsPlaced: 4, TotalAmountBid: 4000, LastBidAt: base},
+	}
+	for i := range 6 {
+		stats = append(stats, &userstats.UserStats{
+			UserID:          uuid.New(),
+			TotalBidsPlaced: int64(i + 1),
+			TotalAmountBid:  int64((i + 1) * 100),
+			LastBidAt:       base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	// A user with no bids yet keeps a NULL last_bid_at
+	stats = append(stats, &userstats.UserStats{UserID: uuid.New()})
+
+	require.NoError(t, service.BackfillUserStats(ctx, stats))
+
+	var rows int
+	require.NoError(t, testDB.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM user_stats").Scan(&rows))
+	assert.Equal(t, len(stats), rows, "every batched row should land")
+
+	for _, want := range stats[1 : len(stats)-1] {
+		got, err := repo.GetUserStats(ctx, want.UserID)
+		require.NoError(t, err)
+		assert.Equal(t, want.TotalBidsPlaced, got.TotalBidsPlaced)
+		assert.Equal(t, want.TotalAmountBid, got.TotalAmountBid)
+		assert.True(t, want.LastBidAt.Equal(got.LastBidAt))
+	}
+
+	got, err := repo.GetUserStats(ctx, existing)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), got.TotalBidsPlaced, "backfill replaces totals")
+	assert.Equal(t, int64(4000), got.TotalAmountBid)
+	assert.True(t, liveBidAt.Equal(got.LastBidAt), "backfill must not rewind last_bid_at")
+
+	var lastBidAt *time.Time
+	require.NoError(t, testDB.Pool.QueryRow(ctx, "SELECT last_bid_at FROM user_stats WHERE user_id = $1", stats[len(stats)-1].UserID).Scan(&lastBidAt))
+	assert.Nil(t, lastBidAt)
+}
+
+func TestService_BackfillUserStats_RejectsInvalidStats(t *testing.T) {
+	// Validation runs before any database access
+	service := userstats.NewService(nil, nil)
+
+	tests := []struct {
+		name  string
+		stats []*userstats.UserStats
+	}{
+		{name: "nil entry", stats: []*userstats.UserStats{nil}},
+		{name: "missing user", stats: []*userstats.UserStats{{TotalBidsPlaced: 1}}},
+		{name: "negative bids", stats: []*userstats.UserStats{{UserID: uuid.New(), TotalBidsPlaced: -1}}},
+		{name: "negative amount", stats: []*userstats.UserStats{{UserID: uuid.New(), TotalAmountBid: -1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.BackfillUserStats(context.Background(), tt.stats)
+			assert.ErrorIs(t, err, userstats.ErrInvalidStats)
+		})
+	}
+}
+
+func TestService_BackfillStats(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+
+	txManager := database.NewPostgresTransactionManager(testDB.Pool, time.Second)
+	repo := infradb.NewUserStatsRepository(testDB.Pool)
+	service := userstats.NewService(repo, txManager)
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	bid := func(userID uuid.UUID, amount int64, at time.Duration) userstats.BidPlacedEvent {
+		return userstats.BidPlacedEvent{EventID: uuid.New(), UserID: userID, Amount: amount, Timestamp: base.Add(at)}
+	}
+
+	// The consumer already handled one of Alice's bids before the backfill started
+	live := bid(alice, 100, 0)
+	require.NoError(t, service.ProcessBidPlaced(ctx, live))
+
+	history := []userstats.BidPlacedEvent{
+		live,
+		bid(alice, 250, 2*time.Minute),
+		bid(bob, 300, time.Minute),
+		bid(alice, 400, time.Minute), // out of order: must not rewind last_bid_at
+		bid(bob, 50, 5*time.Minute),
+		bid(carol, 1000, 3*time.Minute),
+	}
+	// The same event twice in one backfill counts once
+	history = append(history, history[2])
+
+	require.NoError(t, service.BackfillStats(ctx, history))
+	// Re-running the whole backfill changes nothing
+	require.NoError(t, service.BackfillStats(ctx, history))
+
+	tests := []struct {
+		userID    uuid.UUID
+		bids      int64
+		amount    int64
+		lastBidAt time.Time
+	}{
+		{userID: alice, bids: 3, amount: 750, lastBidAt: base.Add(2 * time.Minute)},
+		{userID: bob, bids: 2, amount: 350, lastBidAt: base.Add(5 * time.Minute)},
+		{userID: carol, bids: 1, amount: 1000, lastBidAt: base.Add(3 * time.Minute)},
+	}
+	for _, tt := range tests {
+		got, err := repo.GetUserStats(ctx, tt.userID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, tt.bids, got.TotalBidsPlaced)
+		assert.Equal(t, tt.amount, got.TotalAmountBid)
+		assert.True(t, tt.lastBidAt.Equal(got.LastBidAt), "want last bid at %v, got %v", tt.lastBidAt, got.LastBidAt)
+	}
+
+	var processed int
+	require.NoError(t, testDB.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM processed_events").Scan(&processed))
+	assert.Equal(t, 6, processed, "each distinct event is recorded once")
+
+	// Events the backfill recorded are skipped by the consumer
+	require.NoError(t, service.ProcessBidPlaced(ctx, history[5]))
+	got, err := repo.GetUserStats(ctx, carol)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got.TotalBidsPlaced)
+}
+
+func TestService_BackfillStats_RejectsInvalidEvents(t *testing.T) {
+	// Validation runs before any database access
+	service := userstats.NewService(nil, nil)
+
+	tests := []struct {
+		name  string
+		event userstats.BidPlacedEvent
+	}{
+		{name: "missing event id", event: userstats.BidPlacedEvent{UserID: uuid.New(), Amount: 100}},
+		{name: "missing user", event: userstats.BidPlacedEvent{EventID: uuid.New(), Amount: 100}},
+		{name: "negative amount", event: userstats.BidPlacedEvent{EventID: uuid.New(), UserID: uuid.New(), Amount: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.BackfillStats(context.Background(), []userstats.BidPlacedEvent{tt.event})
+			assert.ErrorIs(t, err, userstats.ErrInvalidEvent)
+		})
+	}
+}
+
+func TestService_GetUserStats(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+
+	txManager := database.NewPostgresTransactionManager(testDB.Pool, time.Second)
+	service := userstats.NewService(infradb.NewUserStatsRepository(testDB.Pool), txManager)
+
+	t.Run("existing user", func(t *testing.T) {
+		userID := uuid.New()
+		bidAt := time.Now().Truncate(time.Microsecond)
+		require.NoError(t, service.ProcessBidPlaced(ctx, userstats.BidPlacedEvent{
+			EventID: uuid.New(), UserID: userID, Amount: 450, Timestamp: bidAt,
+		}))
+
+		stats, err := service.GetUserStats(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, userID, stats.UserID)
+		assert.Equal(t, int64(1), stats.TotalBidsPlaced)
+		assert.Equal(t, int64(450), stats.TotalAmountBid)
+		assert.True(t, bidAt.Equal(stats.LastBidAt))
+	})
+
+	t.Run("created user with no bids yet", func(t *testing.T) {
+		userID := uuid.New()
+		require.NoError(t, service.ProcessUserCreated(ctx, userstats.UserCreatedEvent{
+			EventID: uuid.New(), UserID: userID, CreatedAt: time.Now(),
+		}))
+
+		stats, err := service.GetUserStats(ctx, userID)
+		require.NoError(t, err)
+		assert.Zero(t, stats.TotalBidsPlaced)
+		assert.Zero(t, stats.TotalAmountBid)
+		assert.True(t, stats.LastBidAt.IsZero())
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, err := service.GetUserStats(ctx, uuid.New())
+		assert.ErrorIs(t, err, userstats.ErrUserStatsNotFound)
+	})
+}
+
+func TestService_ProcessAuctionEnded(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	testDB := testhelpers.NewTestDatabase(t, "../../../migrations")
+	defer testDB.Close()
+
+	txManager := database.NewPostgresTransactionManager(testDB.Pool, time.Second)
+	service := userstats.NewService(infradb.NewUserStatsRepository(testDB.Pool), txManager)
+
+	winnerID := uuid.New()
+	require.NoError(t, service.ProcessBidPlaced(ctx, userstats.BidPlacedEvent{
+		EventID: uuid.New(), UserID: winnerID, Amount: 750, Timestamp: time.Now(),
+	}))
+
+	itemID := uuid.New()
+	event := userstats.AuctionEndedEvent{
+		EventID:  itemID,
+		ItemID:   itemID,
+		WinnerID: winnerID,
+		Amount:   750,
+		EndedAt:  time.Now(),
+	}
+
+	// Simulate a redelivery after a lost ack
+	require.NoError(t, service.ProcessAuctionEnded(ctx, event))
+	require.NoError(t, service.ProcessAuctionEnded(ctx, event))
+
+	// An unsold auction credits nobody
+	require.NoError(t, service.ProcessAuctionEnded(ctx, userstats.AuctionEndedEvent{
+		EventID: uuid.New(), ItemID: uuid.New(), EndedAt: time.Now(),
+	}))
+
+	stats, err := service.GetUserStats(ctx, winnerID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.TotalAuctionsWon, "the redelivered event counts once")
+	assert.Equal(t, int64(750), stats.TotalSpent)
+	assert.Equal(t, int64(1), stats.TotalBidsPlaced, "bid stats are untouched")
+	assert.Equal(t, int64(750), stats.TotalAmountBid)
+}
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE user_stats
+    ADD COLUMN total_auctions_won BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN total_spent BIGINT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE user_stats
+    DROP COLUMN IF EXISTS total_spent,
+    DROP COLUMN IF EXISTS total_auctions_won;
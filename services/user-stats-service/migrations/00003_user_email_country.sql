@@ -0,0 +1,10 @@
+-- +goose Up
+-- Normalized on write: email is lowercase, country_code is an uppercase ISO 3166-1 alpha-2 code or empty
+ALTER TABLE user_stats
+    ADD COLUMN email TEXT NOT NULL DEFAULT '',
+    ADD COLUMN country_code TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE user_stats
+    DROP COLUMN IF EXISTS country_code,
+    DROP COLUMN IF EXISTS email;